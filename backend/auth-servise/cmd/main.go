@@ -11,11 +11,16 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/attachment"
 	"github.com/Mandarinka0707/newRepoGOODarhit/internal/config"
 	"github.com/Mandarinka0707/newRepoGOODarhit/internal/controller"
 	"github.com/Mandarinka0707/newRepoGOODarhit/internal/middleware"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/notify"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/push"
 	"github.com/Mandarinka0707/newRepoGOODarhit/internal/repository"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/storage"
 	"github.com/Mandarinka0707/newRepoGOODarhit/internal/usecase"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/webhook"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-migrate/migrate/v4"
@@ -93,22 +98,69 @@ func main() {
 	userRepo := repository.NewUserRepository(db)
 	vacancyRepo := repository.NewVacancyRepository(db)
 	resumeRepo := repository.NewResumeRepository(db)
+	resumeSectionRepo := repository.NewResumeSectionRepository(db)
+	resumePrivacyRepo := repository.NewResumePrivacyRepository(db)
+	contactRequestRepo := repository.NewContactRequestRepository(db)
+	resumeAttachmentRepo := repository.NewResumeAttachmentRepository(db)
+
+	blobStore, err := newBlobStore(cfg)
+	if err != nil {
+		logger.Fatal("Failed to initialize blob storage", zap.Error(err))
+	}
+	downloadSigner := attachment.NewSigner(cfg.DownloadSecret, time.Duration(cfg.DownloadURLTTL)*time.Second)
 	applicationRepo := repository.NewApplicationRepository(db)
+	pipelineRepo := repository.NewPipelineRepository(db)
+	interviewRepo := repository.NewInterviewRepository(db)
+	applicationReviewRepo := repository.NewApplicationReviewRepository(db)
+	screeningRepo := repository.NewScreeningRepository(db)
+	messageRepo := repository.NewMessageRepository(db)
+	applicationExportRepo := repository.NewApplicationExportRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	organizationRepo := repository.NewOrganizationRepository(db)
+	verificationRepo := repository.NewVerificationRepository(db)
+	statsRepo := repository.NewStatsRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	notifier := newNotifier(cfg, logger)
 
 	// Initialize use cases
 	authConfig := &usecase.Config{
-		TokenSecret:     cfg.TokenSecret,
-		TokenExpiration: time.Duration(cfg.TokenExpiration) * time.Second,
+		TokenSecret:           cfg.TokenSecret,
+		TokenExpiration:       time.Duration(cfg.TokenExpiration) * time.Second,
+		ImpersonateExpiration: time.Duration(cfg.ImpersonateTTL) * time.Second,
 	}
 	userConfig := &usecase.UserConfig{
 		TokenSecret:     cfg.TokenSecret,
 		TokenExpiration: time.Duration(cfg.TokenExpiration) * time.Second,
 	}
-	authUsecase := usecase.NewAuthUsecase(userRepo, authConfig, logger)
-	userUsecase := usecase.NewUserUsecase(userRepo, userConfig)
-	vacancyUsecase := usecase.NewVacancyUsecase(vacancyRepo, userRepo)
-	resumeUsecase := usecase.NewResumeUsecase(resumeRepo, userRepo, applicationRepo)
-	applicationUsecase := usecase.NewApplicationUsecase(applicationRepo, userRepo, vacancyRepo, resumeRepo)
+	auditUsecase := usecase.NewAuditUsecase(auditRepo, userRepo, []byte(cfg.AuditSecret))
+	authUsecase := usecase.NewAuthUsecase(userRepo, authConfig, logger, auditUsecase)
+	userUsecase := usecase.NewUserUsecase(userRepo, userConfig, auditUsecase)
+	hub := push.NewHub()
+	notificationUsecase := usecase.NewNotificationUsecase(notificationRepo, userRepo, hub, notifier)
+	vacancyUsecase := usecase.NewVacancyUsecase(vacancyRepo, userRepo, organizationRepo, notificationUsecase, auditUsecase)
+	resumeUsecase := usecase.NewResumeUsecase(resumeRepo, resumeSectionRepo, userRepo, applicationRepo, resumePrivacyRepo, contactRequestRepo, auditUsecase)
+	contactRequestUsecase := usecase.NewContactRequestUsecase(contactRequestRepo, resumeRepo, userRepo, applicationRepo, resumePrivacyRepo)
+	resumeAttachmentUsecase := usecase.NewResumeAttachmentUsecase(
+		resumeAttachmentRepo, resumeRepo, userRepo, applicationRepo, resumePrivacyRepo, contactRequestRepo,
+		blobStore, attachment.NopScanner{}, downloadSigner,
+	)
+	applicationUsecase := usecase.NewApplicationUsecase(applicationRepo, userRepo, vacancyRepo, resumeRepo, pipelineRepo, applicationReviewRepo, screeningRepo, organizationRepo, notificationUsecase)
+	screeningUsecase := usecase.NewScreeningUsecase(screeningRepo, vacancyRepo, organizationRepo)
+	applicationReviewUsecase := usecase.NewApplicationReviewUsecase(applicationReviewRepo, applicationRepo, vacancyRepo, organizationRepo)
+	pipelineUsecase := usecase.NewPipelineUsecase(pipelineRepo, vacancyRepo, userRepo, organizationRepo)
+	interviewUsecase := usecase.NewInterviewUsecase(interviewRepo, applicationRepo, vacancyRepo, userRepo, organizationRepo, notifier)
+	applicationExportUsecase := usecase.NewApplicationExportUsecase(applicationExportRepo, blobStore, cfg.ExportThreshold)
+	webhookUsecase := usecase.NewWebhookUsecase(webhookRepo, userRepo, webhook.NewSender(10*time.Second))
+	messageUsecase := usecase.NewMessageUsecase(
+		messageRepo, applicationRepo, vacancyRepo, userRepo,
+		blobStore, attachment.NopScanner{}, downloadSigner, organizationRepo, notificationUsecase,
+	)
+	organizationUsecase := usecase.NewOrganizationUsecase(organizationRepo, userRepo, notifier)
+	verificationUsecase := usecase.NewVerificationUsecase(
+		verificationRepo, userRepo, blobStore, attachment.NopScanner{}, downloadSigner, notifier, notificationUsecase, auditUsecase,
+	)
+	statsUsecase := usecase.NewStatsUsecase(statsRepo, userRepo, time.Duration(cfg.StatsCacheTTL)*time.Second)
 
 	// Initialize controllers
 	authController := controller.NewHTTPAuthController(authUsecase)
@@ -116,17 +168,34 @@ func main() {
 	vacancyController := controller.NewVacancyController(vacancyUsecase)
 	resumeController := controller.NewResumeController(resumeUsecase)
 	applicationController := controller.NewApplicationController(applicationUsecase)
-	adminController := controller.NewAdminController(userUsecase, vacancyUsecase, resumeUsecase)
+	pipelineController := controller.NewPipelineController(pipelineUsecase)
+	interviewController := controller.NewInterviewController(interviewUsecase)
+	applicationReviewController := controller.NewApplicationReviewController(applicationReviewUsecase)
+	screeningController := controller.NewScreeningController(screeningUsecase)
+	contactRequestController := controller.NewContactRequestController(contactRequestUsecase)
+	resumeAttachmentController := controller.NewResumeAttachmentController(resumeAttachmentUsecase)
+	messageController := controller.NewMessageController(messageUsecase)
+	applicationExportController := controller.NewApplicationExportController(applicationExportUsecase)
+	webhookController := controller.NewWebhookController(webhookUsecase)
+	notificationController := controller.NewNotificationController(notificationUsecase)
+	organizationController := controller.NewOrganizationController(organizationUsecase)
+	verificationController := controller.NewVerificationController(verificationUsecase)
+	auditController := controller.NewAuditController(auditUsecase)
+	adminController := controller.NewAdminController(userUsecase, vacancyUsecase, resumeUsecase, statsUsecase)
 
 	// Initialize router
 	router := gin.Default()
+	// Обработчики передают в use case-ы *gin.Context; с этим флагом он отдает
+	// значения из контекста запроса, например данные клиента для журнала аудита
+	router.ContextWithFallback = true
+	router.Use(middleware.RequestClient())
 
 	// CORS middleware
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:4200", "http://localhost:3000"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Requested-With"},
-		ExposeHeaders:    []string{"Content-Length"},
+		ExposeHeaders:    []string{"Content-Length", "X-Impersonator-Id"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
@@ -143,7 +212,7 @@ func main() {
 
 		// User routes
 		users := api.Group("/users")
-		users.Use(middleware.AuthMiddleware(cfg.TokenSecret))
+		users.Use(middleware.AuthMiddleware(cfg.TokenSecret, authUsecase))
 		{
 			users.GET("/me", userController.GetMe)
 			users.PUT("/me", userController.UpdateMe)
@@ -151,48 +220,208 @@ func main() {
 
 		// Vacancy routes
 		vacancies := api.Group("/vacancies")
-		vacancies.Use(middleware.AuthMiddleware(cfg.TokenSecret))
+		vacancies.Use(middleware.AuthMiddleware(cfg.TokenSecret, authUsecase))
 		{
 			vacancies.POST("", vacancyController.Create)
 			vacancies.GET("", vacancyController.GetAll)
 			vacancies.GET("/:id", vacancyController.GetByID)
 			vacancies.PUT("/:id", vacancyController.Update)
 			vacancies.DELETE("/:id", vacancyController.Delete)
+			vacancies.PUT("/:id/pipeline", pipelineController.AssignToVacancy)
+			vacancies.GET("/:id/pipeline/board", applicationController.GetPipelineBoard)
+			vacancies.POST("/:id/pipeline/move", applicationController.BulkMoveToStage)
+			vacancies.GET("/:id/questions", screeningController.GetQuestions)
+			vacancies.PUT("/:id/questions", screeningController.SetQuestions)
+		}
+
+		// Hiring pipeline templates
+		pipelines := api.Group("/pipelines")
+		pipelines.Use(middleware.AuthMiddleware(cfg.TokenSecret, authUsecase))
+		{
+			pipelines.POST("", pipelineController.Create)
+			pipelines.GET("", pipelineController.List)
+			pipelines.GET("/:id", pipelineController.Get)
+			pipelines.PUT("/:id", pipelineController.Update)
+			pipelines.DELETE("/:id", pipelineController.Delete)
 		}
 
+		// Public resume links
+		api.GET("/public/resumes/:token", resumeController.GetPublicResume)
+
+		// Signed attachment downloads
+		api.GET("/attachments/:id/download", resumeAttachmentController.Download)
+		api.GET("/message-attachments/:id/download", messageController.Download)
+		api.GET("/verification-documents/:id/download", verificationController.Download)
+
 		// Resume routes
 		resumes := api.Group("/resumes")
-		resumes.Use(middleware.AuthMiddleware(cfg.TokenSecret))
+		resumes.Use(middleware.AuthMiddleware(cfg.TokenSecret, authUsecase))
 		{
 			resumes.POST("", resumeController.CreateResume)
 			resumes.GET("", resumeController.GetAllResumes)
 			resumes.GET("/my", resumeController.GetUserResumes)
+			resumes.GET("/search", resumeController.SearchCandidates)
+			resumes.POST("/import/preview", resumeController.PreviewImport)
+			resumes.POST("/import", resumeController.ImportResume)
+			resumes.GET("/blocked-employers", resumeController.GetBlockedEmployers)
+			resumes.POST("/blocked-employers", resumeController.BlockEmployer)
+			resumes.DELETE("/blocked-employers/:employerId", resumeController.UnblockEmployer)
 			resumes.GET("/:id", resumeController.GetResume)
 			resumes.PUT("/:id", resumeController.UpdateResume)
 			resumes.DELETE("/:id", resumeController.DeleteResume)
+			resumes.GET("/:id/export", resumeController.ExportResume)
+			resumes.PUT("/:id/visibility", resumeController.SetVisibility)
+			resumes.POST("/:id/contact", contactRequestController.ContactCandidate)
+			resumes.POST("/:id/attachments", resumeAttachmentController.Upload)
+			resumes.GET("/:id/attachments", resumeAttachmentController.List)
+			resumes.DELETE("/:id/attachments/:attachmentId", resumeAttachmentController.Delete)
+
+			resumes.POST("/:id/experience", resumeController.AddWorkExperience)
+			resumes.PUT("/:id/experience/:entryId", resumeController.UpdateWorkExperience)
+			resumes.DELETE("/:id/experience/:entryId", resumeController.DeleteWorkExperience)
+			resumes.POST("/:id/education", resumeController.AddEducation)
+			resumes.PUT("/:id/education/:entryId", resumeController.UpdateEducation)
+			resumes.DELETE("/:id/education/:entryId", resumeController.DeleteEducation)
+			resumes.POST("/:id/languages", resumeController.AddLanguage)
+			resumes.PUT("/:id/languages/:entryId", resumeController.UpdateLanguage)
+			resumes.DELETE("/:id/languages/:entryId", resumeController.DeleteLanguage)
+			resumes.POST("/:id/certifications", resumeController.AddCertification)
+			resumes.PUT("/:id/certifications/:entryId", resumeController.UpdateCertification)
+			resumes.DELETE("/:id/certifications/:entryId", resumeController.DeleteCertification)
 		}
 
 		// Application routes
 		applications := api.Group("/applications")
-		applications.Use(middleware.AuthMiddleware(cfg.TokenSecret))
+		applications.Use(middleware.AuthMiddleware(cfg.TokenSecret, authUsecase))
 		{
 			applications.POST("", applicationController.Create)
 			applications.GET("", applicationController.GetAll)
+			applications.GET("/tags", applicationReviewController.ListTags)
+			applications.GET("/threads", messageController.ListThreads)
+			applications.GET("/threads/unread", messageController.CountUnread)
+			applications.GET("/export", applicationExportController.Export)
+			applications.GET("/exports/:id", applicationExportController.Get)
+			applications.GET("/exports/:id/download", applicationExportController.Download)
 			applications.GET("/:id", applicationController.GetByID)
+			applications.POST("/:id/view", applicationController.MarkViewed)
 			applications.PUT("/:id/status", applicationController.UpdateStatus)
+			applications.POST("/:id/withdraw", applicationController.Withdraw)
+			applications.PUT("/:id/stage", applicationController.MoveToStage)
+			applications.POST("/:id/interviews", interviewController.Propose)
+			applications.GET("/:id/interviews", interviewController.List)
+			applications.GET("/:id/review", applicationReviewController.GetReview)
+			applications.POST("/:id/notes", applicationReviewController.AddNote)
+			applications.PUT("/:id/notes/:noteId", applicationReviewController.UpdateNote)
+			applications.DELETE("/:id/notes/:noteId", applicationReviewController.DeleteNote)
+			applications.PUT("/:id/rating", applicationReviewController.Rate)
+			applications.DELETE("/:id/rating", applicationReviewController.DeleteRating)
+			applications.PUT("/:id/tags", applicationReviewController.SetTags)
+			applications.GET("/:id/messages", messageController.List)
+			applications.POST("/:id/messages", messageController.Send)
+		}
+
+		// Webhook routes
+		webhooks := api.Group("/webhooks")
+		webhooks.Use(middleware.AuthMiddleware(cfg.TokenSecret, authUsecase))
+		{
+			webhooks.POST("", webhookController.Create)
+			webhooks.GET("", webhookController.List)
+			webhooks.GET("/deliveries/:id", webhookController.GetDelivery)
+			webhooks.POST("/deliveries/:id/redeliver", webhookController.Redeliver)
+			webhooks.GET("/:id", webhookController.Get)
+			webhooks.PUT("/:id", webhookController.Update)
+			webhooks.DELETE("/:id", webhookController.Delete)
+			webhooks.GET("/:id/deliveries", webhookController.ListDeliveries)
+		}
+
+		// Notification center
+		// Поток открывается и по токену в URL — EventSource не шлёт заголовки
+		api.GET("/notifications/stream", middleware.StreamAuthMiddleware(cfg.TokenSecret, authUsecase), notificationController.Stream)
+
+		notifications := api.Group("/notifications")
+		notifications.Use(middleware.AuthMiddleware(cfg.TokenSecret, authUsecase))
+		{
+			notifications.GET("", notificationController.List)
+			notifications.POST("/stream-token", authController.StreamToken)
+			notifications.POST("/read", notificationController.MarkAllRead)
+			notifications.GET("/preferences", notificationController.GetPreferences)
+			notifications.PUT("/preferences", notificationController.SetPreferences)
+			notifications.PUT("/:id/read", notificationController.MarkRead)
+		}
+
+		// Employer organizations
+		organizations := api.Group("/organizations")
+		organizations.Use(middleware.AuthMiddleware(cfg.TokenSecret, authUsecase))
+		{
+			organizations.POST("", organizationController.Create)
+			organizations.POST("/invites/accept", organizationController.AcceptInvite)
+			organizations.GET("/me", organizationController.Get)
+			organizations.PUT("/me", organizationController.Update)
+			organizations.GET("/me/members", organizationController.ListMembers)
+			organizations.PUT("/me/members/:id", organizationController.ChangeRole)
+			organizations.DELETE("/me/members/:id", organizationController.RemoveMember)
+			organizations.GET("/me/invites", organizationController.ListInvites)
+			organizations.POST("/me/invites", organizationController.Invite)
+			organizations.DELETE("/me/invites/:id", organizationController.RevokeInvite)
+		}
+
+		// Employer verification
+		api.POST("/verification/confirm-email", verificationController.ConfirmEmail)
+		verification := api.Group("/verification")
+		verification.Use(middleware.AuthMiddleware(cfg.TokenSecret, authUsecase))
+		{
+			verification.POST("", verificationController.Submit)
+			verification.GET("", verificationController.GetMine)
+		}
+
+		// Interview routes
+		interviews := api.Group("/interviews")
+		interviews.Use(middleware.AuthMiddleware(cfg.TokenSecret, authUsecase))
+		{
+			interviews.GET("/:id", interviewController.Get)
+			interviews.PUT("/:id", interviewController.Update)
+			interviews.POST("/:id/select", interviewController.SelectSlot)
+			interviews.POST("/:id/cancel", interviewController.Cancel)
+			interviews.GET("/:id/invite.ics", interviewController.Invite)
+		}
+
+		// Contact request routes
+		contactRequests := api.Group("/contact-requests")
+		contactRequests.Use(middleware.AuthMiddleware(cfg.TokenSecret, authUsecase))
+		{
+			contactRequests.POST("", contactRequestController.Create)
+			contactRequests.GET("", contactRequestController.List)
+			contactRequests.PUT("/:id/accept", contactRequestController.Accept)
+			contactRequests.PUT("/:id/decline", contactRequestController.Decline)
 		}
 
 		// Admin routes
 		admin := api.Group("/admin")
-		admin.Use(middleware.AuthMiddleware(cfg.TokenSecret))
+		admin.Use(middleware.AuthMiddleware(cfg.TokenSecret, authUsecase))
 		{
 			admin.GET("/users", adminController.GetAllUsers)
+			admin.GET("/users/restricted", adminController.GetRestrictedUsers)
 			admin.DELETE("/users/:id", adminController.DeleteUser)
+			admin.POST("/users/:id/restore", adminController.RestoreUser)
+			admin.POST("/users/:id/impersonate", authController.Impersonate)
+			admin.POST("/users/:id/suspend", adminController.SuspendUser)
+			admin.DELETE("/users/:id/suspend", adminController.UnsuspendUser)
+			admin.POST("/users/:id/ban", adminController.BanUser)
+			admin.DELETE("/users/:id/ban", adminController.UnbanUser)
+			admin.PUT("/users/:id/verified", adminController.SetEmployerVerified)
 			admin.DELETE("/vacancies/:id", adminController.DeleteVacancy)
 			admin.DELETE("/resumes/:id", adminController.DeleteResume)
 			admin.GET("/stats/users", adminController.GetStats)
+			admin.GET("/stats/series", adminController.GetStatsSeries)
 			admin.GET("/vacancies", adminController.GetAllVacancies)
 			admin.GET("/resumes", adminController.GetAllResumes)
+			admin.GET("/verifications", verificationController.ListQueue)
+			admin.GET("/audit", auditController.List)
+			admin.GET("/audit/verify", auditController.Verify)
+			admin.GET("/verifications/:id", verificationController.Get)
+			admin.POST("/verifications/:id/approve", verificationController.Approve)
+			admin.POST("/verifications/:id/reject", verificationController.Reject)
+			admin.POST("/verifications/:id/request-info", verificationController.RequestInfo)
 		}
 	}
 
@@ -205,6 +434,14 @@ func main() {
 		Addr:    ":" + cfg.Port,
 		Handler: router,
 	}
+	// Shutdown не ждёт потоковых ответов — закрываем открытые потоки уведомлений сами
+	srv.RegisterOnShutdown(hub.Close)
+
+	// Webhook deliveries and notification digests are sent in the background until shutdown
+	dispatchCtx, stopDispatch := context.WithCancel(context.Background())
+	defer stopDispatch()
+	go webhookUsecase.Run(dispatchCtx, 5*time.Second)
+	go notificationUsecase.RunDigests(dispatchCtx, 24*time.Hour)
 
 	// Graceful shutdown
 	go func() {
@@ -217,6 +454,7 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	logger.Info("Shutting down server...")
+	stopDispatch()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -227,6 +465,39 @@ func main() {
 	logger.Info("Server exiting")
 }
 
+func newBlobStore(cfg *config.Config) (storage.BlobStore, error) {
+	switch cfg.StorageType {
+	case "s3":
+		if cfg.S3Endpoint == "" || cfg.S3Bucket == "" {
+			return nil, fmt.Errorf("S3_ENDPOINT and S3_BUCKET are required for s3 storage")
+		}
+		return storage.NewS3Store(storage.S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+		}, nil), nil
+	case "local", "":
+		return storage.NewLocalStore(cfg.StoragePath)
+	default:
+		return nil, fmt.Errorf("unknown storage type %q", cfg.StorageType)
+	}
+}
+
+func newNotifier(cfg *config.Config, logger *zap.Logger) notify.Notifier {
+	if cfg.SMTPHost == "" {
+		return notify.NewLogNotifier(logger)
+	}
+	return notify.NewSMTPNotifier(notify.SMTPConfig{
+		Host:     cfg.SMTPHost,
+		Port:     cfg.SMTPPort,
+		Username: cfg.SMTPUsername,
+		Password: cfg.SMTPPassword,
+		From:     cfg.SMTPFrom,
+	})
+}
+
 func runMigrations(dbURL, migrationsPath string, logger *zap.Logger) error {
 	m, err := migrate.New(
 		"file://"+migrationsPath,
@@ -0,0 +1,112 @@
+// Package attachment validates uploaded resume and message files and signs
+// the links they are downloaded through.
+package attachment
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+)
+
+// MaxSize is the largest accepted attachment.
+const MaxSize = 10 << 20
+
+const (
+	ContentTypePDF  = "application/pdf"
+	ContentTypeDOCX = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	ContentTypePNG  = "image/png"
+	ContentTypeJPEG = "image/jpeg"
+)
+
+var (
+	ErrEmpty           = errors.New("file is empty")
+	ErrTooLarge        = errors.New("file is too large")
+	ErrUnsupportedType = errors.New("only PDF and DOCX files are accepted")
+	ErrInfected        = errors.New("file did not pass the virus scan")
+)
+
+// ErrUnsupportedMessageType is returned for message attachments, which may
+// also be PNG and JPEG images.
+var ErrUnsupportedMessageType = errors.New("only PDF, DOCX, PNG and JPEG files are accepted")
+
+// DetectType checks the file against its extension and returns the content
+// type to store it with. The declared MIME type of an upload is not trusted;
+// the content has to look like a PDF or a Word document.
+func DetectType(name string, data []byte) (string, error) {
+	if len(data) == 0 {
+		return "", ErrEmpty
+	}
+	if len(data) > MaxSize {
+		return "", ErrTooLarge
+	}
+
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".pdf":
+		if bytes.HasPrefix(data, []byte("%PDF-")) {
+			return ContentTypePDF, nil
+		}
+	case ".docx":
+		if isDOCX(data) {
+			return ContentTypeDOCX, nil
+		}
+	}
+	return "", ErrUnsupportedType
+}
+
+// DetectMessageType is DetectType for files attached to application
+// messages, which may also be screenshots and scans.
+func DetectMessageType(name string, data []byte) (string, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".png":
+		if len(data) > 0 && len(data) <= MaxSize && bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")) {
+			return ContentTypePNG, nil
+		}
+	case ".jpg", ".jpeg":
+		if len(data) > 0 && len(data) <= MaxSize && bytes.HasPrefix(data, []byte{0xFF, 0xD8, 0xFF}) {
+			return ContentTypeJPEG, nil
+		}
+	default:
+		contentType, err := DetectType(name, data)
+		if errors.Is(err, ErrUnsupportedType) {
+			return "", ErrUnsupportedMessageType
+		}
+		return contentType, err
+	}
+
+	if len(data) == 0 {
+		return "", ErrEmpty
+	}
+	if len(data) > MaxSize {
+		return "", ErrTooLarge
+	}
+	return "", ErrUnsupportedMessageType
+}
+
+func isDOCX(data []byte) bool {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return false
+	}
+	for _, f := range archive.File {
+		if f.Name == "word/document.xml" {
+			return true
+		}
+	}
+	return false
+}
+
+// Scanner is the hook for an antivirus. Scan returns ErrInfected (possibly
+// wrapped) for files that must be rejected.
+type Scanner interface {
+	Scan(ctx context.Context, name string, data []byte) error
+}
+
+// NopScanner accepts every file. It is used when no antivirus is configured.
+type NopScanner struct{}
+
+func (NopScanner) Scan(ctx context.Context, name string, data []byte) error {
+	return nil
+}
@@ -0,0 +1,138 @@
+package attachment
+
+import (
+	"archive/zip"
+	"bytes"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func docx(t *testing.T, part string) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	_, err := zw.Create(part)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestDetectType(t *testing.T) {
+	pdf := []byte("%PDF-1.7\n...")
+
+	tests := []struct {
+		name     string
+		fileName string
+		data     []byte
+		want     string
+		wantErr  error
+	}{
+		{"pdf", "cv.PDF", pdf, ContentTypePDF, nil},
+		{"docx", "cv.docx", docx(t, "word/document.xml"), ContentTypeDOCX, nil},
+		{"empty", "cv.pdf", nil, "", ErrEmpty},
+		{"too large", "cv.pdf", append(pdf, make([]byte, MaxSize)...), "", ErrTooLarge},
+		{"renamed executable", "cv.pdf", []byte("MZ\x90\x00"), "", ErrUnsupportedType},
+		{"pdf named docx", "cv.docx", pdf, "", ErrUnsupportedType},
+		{"zip without document", "cv.docx", docx(t, "xl/workbook.xml"), "", ErrUnsupportedType},
+		{"other extension", "cv.txt", []byte("hello"), "", ErrUnsupportedType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DetectType(tt.fileName, tt.data)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func parseLink(t *testing.T, raw string) Link {
+	u, err := url.Parse(raw)
+	require.NoError(t, err)
+	q := u.Query()
+	viewer, err := strconv.ParseInt(q.Get("viewer"), 10, 64)
+	require.NoError(t, err)
+	expires, err := strconv.ParseInt(q.Get("expires"), 10, 64)
+	require.NoError(t, err)
+	return Link{ViewerID: viewer, Expires: expires, Signature: q.Get("signature")}
+}
+
+func TestSigner(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	signer := NewSigner("secret", 15*time.Minute)
+	signer.now = func() time.Time { return now }
+
+	raw := signer.URL(7, 42)
+	assert.True(t, strings.HasPrefix(raw, "/api/v1/attachments/7/download?"))
+	link := parseLink(t, raw)
+
+	assert.NoError(t, signer.Verify(7, link))
+	assert.ErrorIs(t, signer.Verify(8, link), ErrLinkSignature)
+
+	forged := link
+	forged.ViewerID = 43
+	assert.ErrorIs(t, signer.Verify(7, forged), ErrLinkSignature)
+
+	extended := link
+	extended.Expires += 3600
+	assert.ErrorIs(t, signer.Verify(7, extended), ErrLinkSignature)
+
+	other := NewSigner("other", 15*time.Minute)
+	assert.ErrorIs(t, other.Verify(7, link), ErrLinkSignature)
+
+	signer.now = func() time.Time { return now.Add(16 * time.Minute) }
+	assert.ErrorIs(t, signer.Verify(7, link), ErrLinkExpired)
+}
+
+func TestDetectMessageType(t *testing.T) {
+	tests := []struct {
+		name     string
+		fileName string
+		data     []byte
+		want     string
+		wantErr  error
+	}{
+		{"png", "screen.PNG", []byte("\x89PNG\r\n\x1a\n..."), ContentTypePNG, nil},
+		{"jpeg", "scan.jpg", []byte{0xFF, 0xD8, 0xFF, 0xE0}, ContentTypeJPEG, nil},
+		{"pdf", "offer.pdf", []byte("%PDF-1.7\n..."), ContentTypePDF, nil},
+		{"empty image", "scan.jpeg", nil, "", ErrEmpty},
+		{"empty pdf", "offer.pdf", nil, "", ErrEmpty},
+		{"text named png", "screen.png", []byte("hello"), "", ErrUnsupportedMessageType},
+		{"other extension", "notes.txt", []byte("hello"), "", ErrUnsupportedMessageType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DetectMessageType(tt.fileName, tt.data)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSignerMessageScope(t *testing.T) {
+	signer := NewSigner("secret", 15*time.Minute)
+
+	raw := signer.MessageURL(7, 42)
+	assert.True(t, strings.HasPrefix(raw, "/api/v1/message-attachments/7/download?"))
+	link := parseLink(t, raw)
+	assert.NoError(t, signer.VerifyMessage(7, link))
+	assert.ErrorIs(t, signer.Verify(7, link), ErrLinkSignature, "message link used for a resume file")
+	assert.ErrorIs(t, signer.VerifyMessage(7, parseLink(t, signer.URL(7, 42))), ErrLinkSignature)
+}
+
+func TestSignerDocumentScope(t *testing.T) {
+	signer := NewSigner("secret", 15*time.Minute)
+
+	raw := signer.DocumentURL(7, 42)
+	assert.True(t, strings.HasPrefix(raw, "/api/v1/verification-documents/7/download?"))
+	link := parseLink(t, raw)
+	assert.NoError(t, signer.VerifyDocument(7, link))
+	assert.ErrorIs(t, signer.Verify(7, link), ErrLinkSignature, "document link used for a resume file")
+	assert.ErrorIs(t, signer.VerifyMessage(7, link), ErrLinkSignature, "document link used for a message file")
+}
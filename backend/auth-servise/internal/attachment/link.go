@@ -0,0 +1,108 @@
+package attachment
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrLinkExpired   = errors.New("download link has expired")
+	ErrLinkSignature = errors.New("invalid download link signature")
+)
+
+// Signer issues download links bound to an attachment, the user they were
+// issued to and an expiry time. Resume and message attachments and
+// verification documents are numbered separately, so their links are signed
+// under different scopes.
+type Signer struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+func NewSigner(secret string, ttl time.Duration) *Signer {
+	return &Signer{secret: []byte(secret), ttl: ttl, now: time.Now}
+}
+
+// Link is what a signed URL carries besides the attachment ID.
+type Link struct {
+	ViewerID  int64
+	Expires   int64
+	Signature string
+}
+
+// URL returns the download path for the attachment, valid for the signer's TTL.
+func (s *Signer) URL(attachmentID, viewerID int64) string {
+	expires := s.now().Add(s.ttl).Unix()
+	q := url.Values{}
+	q.Set("viewer", strconv.FormatInt(viewerID, 10))
+	q.Set("expires", strconv.FormatInt(expires, 10))
+	q.Set("signature", s.sign(scopeResume, attachmentID, viewerID, expires))
+	return fmt.Sprintf("/api/v1/attachments/%d/download?%s", attachmentID, q.Encode())
+}
+
+// MessageURL returns the download path for a file attached to an
+// application message.
+func (s *Signer) MessageURL(attachmentID, viewerID int64) string {
+	expires := s.now().Add(s.ttl).Unix()
+	q := url.Values{}
+	q.Set("viewer", strconv.FormatInt(viewerID, 10))
+	q.Set("expires", strconv.FormatInt(expires, 10))
+	q.Set("signature", s.sign(scopeMessage, attachmentID, viewerID, expires))
+	return fmt.Sprintf("/api/v1/message-attachments/%d/download?%s", attachmentID, q.Encode())
+}
+
+// DocumentURL returns the download path for a document of an employer
+// verification request.
+func (s *Signer) DocumentURL(documentID, viewerID int64) string {
+	expires := s.now().Add(s.ttl).Unix()
+	q := url.Values{}
+	q.Set("viewer", strconv.FormatInt(viewerID, 10))
+	q.Set("expires", strconv.FormatInt(expires, 10))
+	q.Set("signature", s.sign(scopeDocument, documentID, viewerID, expires))
+	return fmt.Sprintf("/api/v1/verification-documents/%d/download?%s", documentID, q.Encode())
+}
+
+// Verify checks the link was issued by this signer and has not expired.
+func (s *Signer) Verify(attachmentID int64, link Link) error {
+	return s.verify(scopeResume, attachmentID, link)
+}
+
+// VerifyMessage is Verify for links issued by MessageURL.
+func (s *Signer) VerifyMessage(attachmentID int64, link Link) error {
+	return s.verify(scopeMessage, attachmentID, link)
+}
+
+// VerifyDocument is Verify for links issued by DocumentURL.
+func (s *Signer) VerifyDocument(documentID int64, link Link) error {
+	return s.verify(scopeDocument, documentID, link)
+}
+
+const (
+	scopeResume   = "attachment"
+	scopeMessage  = "message-attachment"
+	scopeDocument = "verification-document"
+)
+
+func (s *Signer) verify(scope string, attachmentID int64, link Link) error {
+	expected := s.sign(scope, attachmentID, link.ViewerID, link.Expires)
+	if !hmac.Equal([]byte(expected), []byte(link.Signature)) {
+		return ErrLinkSignature
+	}
+	if s.now().Unix() > link.Expires {
+		return ErrLinkExpired
+	}
+	return nil
+}
+
+func (s *Signer) sign(scope string, attachmentID, viewerID, expires int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	fmt.Fprintf(mac, "%s:%d:%d:%d", scope, attachmentID, viewerID, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
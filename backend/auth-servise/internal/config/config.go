@@ -1,6 +1,9 @@
 package config
 
 import (
+	"crypto/hkdf"
+	"crypto/sha256"
+	"encoding/hex"
 	"os"
 )
 
@@ -9,6 +12,39 @@ type Config struct {
 	TokenSecret     string
 	TokenExpiration int64
 	Port            string
+
+	// Хранилище вложений резюме: "local" или "s3"
+	StorageType string
+	StoragePath string
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	// Время жизни ссылок на скачивание вложений, в секундах
+	DownloadURLTTL int64
+	// Ключ подписи ссылок на скачивание; если DOWNLOAD_URL_SECRET не задан,
+	// выводится из JWT_SECRET, но не совпадает с ним
+	DownloadSecret string
+	// Ключ цепочки журнала аудита; хранится вне базы, чтобы подделанную
+	// запись нельзя было переподписать. Если AUDIT_LOG_SECRET не задан,
+	// выводится из JWT_SECRET
+	AuditSecret string
+
+	// Почтовый сервер для уведомлений; если SMTP_HOST не задан, письма только логируются
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
+
+	// Выгрузки откликов длиннее этого числа строк готовятся в фоне
+	ExportThreshold int
+
+	// Время, на которое кешируется статистика админ-панели, в секундах
+	StatsCacheTTL int64
+	// Время жизни токена, выданного администратору для входа под пользователем, в секундах
+	ImpersonateTTL int64
 }
 
 func NewConfig() (*Config, error) {
@@ -17,10 +53,50 @@ func NewConfig() (*Config, error) {
 		TokenSecret:     getEnv("JWT_SECRET", "your-secret-key"),
 		TokenExpiration: 24 * 60 * 60, // 24 hours in seconds
 		Port:            getEnv("PORT", "8080"),
+		StorageType:     getEnv("STORAGE_TYPE", "local"),
+		StoragePath:     getEnv("STORAGE_PATH", "uploads"),
+		S3Endpoint:      getEnv("S3_ENDPOINT", ""),
+		S3Region:        getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:        getEnv("S3_BUCKET", ""),
+		S3AccessKey:     getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey:     getEnv("S3_SECRET_KEY", ""),
+		DownloadURLTTL:  15 * 60, // 15 minutes in seconds
+		SMTPHost:        getEnv("SMTP_HOST", ""),
+		SMTPPort:        getEnv("SMTP_PORT", "587"),
+		SMTPUsername:    getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:    getEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:        getEnv("SMTP_FROM", "noreply@localhost"),
+		ExportThreshold: 5000,
+		StatsCacheTTL:   60,      // 1 minute in seconds
+		ImpersonateTTL:  15 * 60, // 15 minutes in seconds
+	}
+
+	var err error
+	config.DownloadSecret, err = secretOrDerived("DOWNLOAD_URL_SECRET", config.TokenSecret, "attachment download links")
+	if err != nil {
+		return nil, err
+	}
+	config.AuditSecret, err = secretOrDerived("AUDIT_LOG_SECRET", config.TokenSecret, "audit log chain")
+	if err != nil {
+		return nil, err
 	}
 	return config, nil
 }
 
+// secretOrDerived returns the secret from the environment variable, or one
+// derived from the token secret for the given purpose, which differs from
+// the token secret and from the keys derived for other purposes.
+func secretOrDerived(key, tokenSecret, purpose string) (string, error) {
+	if secret := os.Getenv(key); secret != "" {
+		return secret, nil
+	}
+	derived, err := hkdf.Key(sha256.New, []byte(tokenSecret), nil, purpose, 32)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(derived), nil
+}
+
 func getEnv(key, defaultValue string) string {
 	value := os.Getenv(key)
 	if value == "" {
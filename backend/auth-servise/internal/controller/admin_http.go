@@ -1,10 +1,14 @@
 package controller
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
 	"github.com/Mandarinka0707/newRepoGOODarhit/internal/usecase"
 	"github.com/gin-gonic/gin"
 )
@@ -13,41 +17,50 @@ type AdminController struct {
 	userUsecase    usecase.UserUsecaseInterface
 	vacancyUsecase usecase.VacancyUsecaseInterface
 	resumeUsecase  usecase.ResumeUsecaseInterface
+	statsUsecase   usecase.StatsUsecaseInterface
 }
 
 func NewAdminController(
 	userUsecase usecase.UserUsecaseInterface,
 	vacancyUsecase usecase.VacancyUsecaseInterface,
 	resumeUsecase usecase.ResumeUsecaseInterface,
+	statsUsecase usecase.StatsUsecaseInterface,
 ) *AdminController {
 	return &AdminController{
 		userUsecase:    userUsecase,
 		vacancyUsecase: vacancyUsecase,
 		resumeUsecase:  resumeUsecase,
+		statsUsecase:   statsUsecase,
 	}
 }
 
 // GetAllUsers returns all users in the system
 func (c *AdminController) GetAllUsers(ctx *gin.Context) {
-	users, err := c.userUsecase.GetAll(ctx)
+	adminID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	users, err := c.userUsecase.GetAll(ctx, adminID.(int64))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeAccountStateError(ctx, err)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, users)
 }
 
-// DeleteUser deletes a user by ID
+// DeleteUser soft-deletes a user by ID. The account's resumes, vacancies and
+// applications are kept and the account can be restored.
 func (c *AdminController) DeleteUser(ctx *gin.Context) {
-	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+	adminID, id, ok := interviewParams(ctx)
+	if !ok {
 		return
 	}
 
-	if err := c.userUsecase.Delete(ctx, id); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if _, err := c.userUsecase.SoftDelete(ctx, adminID, id); err != nil {
+		writeAccountStateError(ctx, err)
 		return
 	}
 
@@ -56,15 +69,21 @@ func (c *AdminController) DeleteUser(ctx *gin.Context) {
 
 // DeleteVacancy deletes a vacancy by ID
 func (c *AdminController) DeleteVacancy(ctx *gin.Context) {
-	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid vacancy id"})
+	adminID, id, ok := interviewParams(ctx)
+	if !ok {
 		return
 	}
 
-	// For admin, we pass 0 as employerID to bypass the ownership check
-	if err := c.vacancyUsecase.Delete(ctx, id, 0); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	// Причина необязательна и попадает в уведомление работодателю
+	if err := c.vacancyUsecase.RemoveByModerator(ctx, adminID, id, ctx.Query("reason")); err != nil {
+		switch {
+		case errors.Is(err, entity.ErrVacancyNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, usecase.ErrPermissionDenied):
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
 		return
 	}
 
@@ -73,31 +92,90 @@ func (c *AdminController) DeleteVacancy(ctx *gin.Context) {
 
 // DeleteResume deletes a resume by ID
 func (c *AdminController) DeleteResume(ctx *gin.Context) {
-	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid resume id"})
+	adminID, id, ok := interviewParams(ctx)
+	if !ok {
 		return
 	}
 
-	if err := c.resumeUsecase.Delete(ctx, id); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.resumeUsecase.RemoveByModerator(ctx, adminID, id); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrResumeNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, usecase.ErrPermissionDenied):
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
 		return
 	}
 
 	ctx.Status(http.StatusNoContent)
 }
 
-// GetUserStats returns statistics about users
+func writeStatsError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, entity.ErrInvalidStatsRange):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrPermissionDenied):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// GetStats returns platform totals with breakdowns by role and status
 func (c *AdminController) GetStats(ctx *gin.Context) {
-	stats, err := c.userUsecase.GetStats(ctx)
+	adminID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	stats, err := c.statsUsecase.GetStats(ctx, adminID.(int64))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeStatsError(ctx, err)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, stats)
 }
 
+// GetStatsSeries returns registrations, vacancies and applications over time.
+// ?interval= is day or week, ?from= and ?to= are dates like 2024-01-31; the
+// last 30 days by day are returned by default.
+func (c *AdminController) GetStatsSeries(ctx *gin.Context) {
+	adminID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	today := time.Now().UTC()
+	r := entity.StatsRange{
+		Interval: ctx.DefaultQuery("interval", entity.StatsIntervalDay),
+		From:     today.AddDate(0, 0, -29),
+		To:       today,
+	}
+	for param, date := range map[string]*time.Time{"from": &r.From, "to": &r.To} {
+		if value := ctx.Query(param); value != "" {
+			parsed, err := time.Parse(time.DateOnly, value)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid %s date", param)})
+				return
+			}
+			*date = parsed
+		}
+	}
+
+	series, err := c.statsUsecase.GetSeries(ctx, adminID.(int64), r)
+	if err != nil {
+		writeStatsError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, series)
+}
+
 func (c *AdminController) GetAllVacancies(ctx *gin.Context) {
 	fmt.Printf("AdminController.GetAllVacancies: Starting to fetch all vacancies\n")
 	vacancies, err := c.vacancyUsecase.GetAll(ctx)
@@ -118,3 +196,158 @@ func (c *AdminController) GetAllResumes(ctx *gin.Context) {
 	}
 	ctx.JSON(200, resumes)
 }
+
+type SetVerifiedRequest struct {
+	Verified *bool `json:"verified" binding:"required"`
+}
+
+// SetEmployerVerified grants or revokes the verified status of an employer
+func (c *AdminController) SetEmployerVerified(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	adminID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req SetVerifiedRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.userUsecase.SetVerified(ctx, adminID.(int64), id, *req.Verified); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrPermissionDenied):
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		case errors.Is(err, usecase.ErrEmployerNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+type SuspendUserRequest struct {
+	Until  time.Time `json:"until" binding:"required"`
+	Reason string    `json:"reason"`
+}
+
+type BanUserRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+func writeAccountStateError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, entity.ErrInvalidAccountAction):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrUserNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrPermissionDenied):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// GetRestrictedUsers lists suspended, banned and deleted accounts. ?state=
+// narrows the list to one of them.
+func (c *AdminController) GetRestrictedUsers(ctx *gin.Context) {
+	adminID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	limit, offset, ok := parsePagination(ctx)
+	if !ok {
+		return
+	}
+
+	users, total, err := c.userUsecase.ListRestricted(ctx, adminID.(int64), ctx.Query("state"), limit, offset)
+	if err != nil {
+		writeAccountStateError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"users": users, "total": total, "limit": limit, "offset": offset})
+}
+
+// SuspendUser blocks the user until the given time.
+func (c *AdminController) SuspendUser(ctx *gin.Context) {
+	adminID, id, ok := interviewParams(ctx)
+	if !ok {
+		return
+	}
+
+	var req SuspendUserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := c.userUsecase.Suspend(ctx, adminID, id, req.Until, req.Reason)
+	if err != nil {
+		writeAccountStateError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, user)
+}
+
+func (c *AdminController) UnsuspendUser(ctx *gin.Context) {
+	c.changeAccountState(ctx, c.userUsecase.Unsuspend)
+}
+
+// BanUser blocks the user until unbanned. The reason is required.
+func (c *AdminController) BanUser(ctx *gin.Context) {
+	adminID, id, ok := interviewParams(ctx)
+	if !ok {
+		return
+	}
+
+	var req BanUserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := c.userUsecase.Ban(ctx, adminID, id, req.Reason)
+	if err != nil {
+		writeAccountStateError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, user)
+}
+
+func (c *AdminController) UnbanUser(ctx *gin.Context) {
+	c.changeAccountState(ctx, c.userUsecase.Unban)
+}
+
+// RestoreUser brings back a soft-deleted user.
+func (c *AdminController) RestoreUser(ctx *gin.Context) {
+	c.changeAccountState(ctx, c.userUsecase.Restore)
+}
+
+func (c *AdminController) changeAccountState(ctx *gin.Context, change func(ctx context.Context, adminID, userID int64) (*entity.User, error)) {
+	adminID, id, ok := interviewParams(ctx)
+	if !ok {
+		return
+	}
+
+	user, err := change(ctx, adminID, id)
+	if err != nil {
+		writeAccountStateError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, user)
+}
@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/export"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type ApplicationExportController struct {
+	uc usecase.ApplicationExportUsecaseInterface
+}
+
+func NewApplicationExportController(uc usecase.ApplicationExportUsecaseInterface) *ApplicationExportController {
+	return &ApplicationExportController{uc: uc}
+}
+
+func writeExportError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, export.ErrUnknownFormat), errors.Is(err, entity.ErrInvalidTags),
+		errors.Is(err, entity.ErrInvalidApplicationFilter):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, entity.ErrExportNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, entity.ErrExportNotReady):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrReadOnlySession):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// Export downloads the employer's applications, filtered like the listing,
+// as ?format=csv (the default) or xlsx. Large exports are answered with 202
+// and the export job, whose status has the download link once it is ready.
+func (c *ApplicationExportController) Export(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	filter, ok := bindEmployerFilter(ctx)
+	if !ok {
+		return
+	}
+	format := ctx.DefaultQuery("format", export.FormatCSV)
+
+	job, err := c.uc.Export(ctx, userID.(int64), format, filter, func(fileName string) io.Writer {
+		ctx.Header("Content-Type", export.TableContentType(format))
+		ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+		ctx.Header("Cache-Control", "private, no-store")
+		ctx.Status(http.StatusOK)
+		return ctx.Writer
+	})
+	if err != nil {
+		// Если файл уже начали отдавать, ответить ошибкой нельзя
+		if ctx.Writer.Written() {
+			fmt.Printf("Failed to stream applications export: %v\n", err)
+			return
+		}
+		writeExportError(ctx, err)
+		return
+	}
+	if job != nil {
+		ctx.JSON(http.StatusAccepted, job)
+	}
+}
+
+// Get returns the status of a background export.
+func (c *ApplicationExportController) Get(ctx *gin.Context) {
+	userID, id, ok := interviewParams(ctx)
+	if !ok {
+		return
+	}
+
+	job, err := c.uc.Get(ctx, userID, id)
+	if err != nil {
+		writeExportError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, job)
+}
+
+func (c *ApplicationExportController) Download(ctx *gin.Context) {
+	userID, id, ok := interviewParams(ctx)
+	if !ok {
+		return
+	}
+
+	job, body, err := c.uc.Download(ctx, userID, id)
+	if err != nil {
+		writeExportError(ctx, err)
+		return
+	}
+	defer body.Close()
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", job.FileName))
+	ctx.Header("Cache-Control", "private, no-store")
+	ctx.DataFromReader(http.StatusOK, job.Size, export.TableContentType(job.Format), body, nil)
+}
@@ -1,10 +1,12 @@
 package controller
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
 	"github.com/Mandarinka0707/newRepoGOODarhit/internal/usecase"
@@ -22,8 +24,20 @@ func NewApplicationController(applicationUsecase usecase.ApplicationUsecaseInter
 }
 
 type CreateApplicationRequest struct {
-	VacancyID int64 `json:"vacancy_id" binding:"required"`
-	ResumeID  int64 `json:"resume_id" binding:"required"`
+	VacancyID   int64                    `json:"vacancy_id" binding:"required"`
+	ResumeID    int64                    `json:"resume_id" binding:"required"`
+	CoverLetter string                   `json:"cover_letter" binding:"max=20000"`
+	Answers     []ScreeningAnswerRequest `json:"answers"`
+}
+
+// ScreeningAnswerRequest answers one screening question of the vacancy; only
+// the field matching the question type is used.
+type ScreeningAnswerRequest struct {
+	QuestionID int64    `json:"question_id" binding:"required"`
+	Boolean    *bool    `json:"boolean"`
+	Number     *float64 `json:"number"`
+	Text       string   `json:"text"`
+	Options    []string `json:"options"`
 }
 
 func (c *ApplicationController) Create(ctx *gin.Context) {
@@ -46,17 +60,35 @@ func (c *ApplicationController) Create(ctx *gin.Context) {
 	fmt.Printf("User ID from context: %v\n", userID)
 
 	application := &entity.Application{
-		UserID:    userID.(int64),
-		VacancyID: req.VacancyID,
-		ResumeID:  req.ResumeID,
-		Status:    "pending",
+		UserID:      userID.(int64),
+		VacancyID:   req.VacancyID,
+		ResumeID:    req.ResumeID,
+		Status:      entity.ApplicationStatusPending,
+		CoverLetter: req.CoverLetter,
+	}
+	for _, answer := range req.Answers {
+		application.Answers = append(application.Answers, &entity.ScreeningAnswer{
+			QuestionID: &answer.QuestionID,
+			Bool:       answer.Boolean,
+			Number:     answer.Number,
+			Text:       answer.Text,
+			Options:    answer.Options,
+		})
 	}
 
 	fmt.Printf("Creating application: %+v\n", application)
 
 	if err := c.applicationUsecase.Create(ctx, application); err != nil {
 		fmt.Printf("Error creating application: %v\n", err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		switch {
+		case errors.Is(err, entity.ErrInvalidScreening), errors.Is(err, entity.ErrScreeningAnswersRequired),
+			errors.Is(err, entity.ErrInvalidCoverLetter):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, entity.ErrAlreadyApplied), errors.Is(err, entity.ErrReapplyTooEarly):
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
 		return
 	}
 
@@ -64,6 +96,43 @@ func (c *ApplicationController) Create(ctx *gin.Context) {
 	ctx.JSON(http.StatusCreated, application)
 }
 
+func writeApplicationError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, entity.ErrApplicationNotFound), errors.Is(err, entity.ErrVacancyNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrPermissionDenied):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+	case errors.Is(err, usecase.ErrReadOnlySession):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrInvalidStatusTransition):
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, entity.ErrApplicationStatusConflict):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// applicationParams reads the current user and the :id of the application.
+// It writes the error response itself and reports whether the handler may
+// go on.
+func applicationParams(ctx *gin.Context) (userID, id int64, ok bool) {
+	value, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return 0, 0, false
+	}
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return 0, 0, false
+	}
+
+	return value.(int64), id, true
+}
+
+// GetByID returns the application together with its status timeline.
 func (c *ApplicationController) GetByID(ctx *gin.Context) {
 	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
 	if err != nil {
@@ -71,19 +140,24 @@ func (c *ApplicationController) GetByID(ctx *gin.Context) {
 		return
 	}
 
-	application, err := c.applicationUsecase.GetByID(ctx, id)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
 		return
 	}
-	if application == nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "application not found"})
+
+	application, err := c.applicationUsecase.GetByID(ctx, id, userID.(int64))
+	if err != nil {
+		writeApplicationError(ctx, err)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, application)
 }
 
+// GetAll lists the current user's applications. With employer_id it lists
+// a page of applications to the employer's vacancies instead; see
+// listEmployerApplications.
 func (c *ApplicationController) GetAll(ctx *gin.Context) {
 	userID, exists := ctx.Get("user_id")
 	if !exists {
@@ -91,40 +165,25 @@ func (c *ApplicationController) GetAll(ctx *gin.Context) {
 		return
 	}
 
-	// Получаем параметры запроса
-	employerID := ctx.Query("employer_id")
-	vacancyIDs := ctx.Query("vacancy_ids")
-
-	var applications []*entity.Application
-	var err error
-
-	if employerID != "" {
-		// Если указан employer_id, получаем отклики для вакансий работодателя
+	if employerID := ctx.Query("employer_id"); employerID != "" {
 		employerIDInt, err := strconv.ParseInt(employerID, 10, 64)
 		if err != nil {
 			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid employer_id"})
 			return
 		}
 
-		var vacancyIDsInt []int64
-		if vacancyIDs != "" {
-			// Если указаны vacancy_ids, разбиваем строку на массив
-			for _, id := range strings.Split(vacancyIDs, ",") {
-				idInt, err := strconv.ParseInt(strings.TrimSpace(id), 10, 64)
-				if err != nil {
-					ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid vacancy_ids"})
-					return
-				}
-				vacancyIDsInt = append(vacancyIDsInt, idInt)
-			}
+		// Отклики работодателя вместе с заметками и оценками видит только он сам
+		if employerIDInt != userID.(int64) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
 		}
 
-		applications, err = c.applicationUsecase.GetByEmployerID(ctx, employerIDInt, vacancyIDsInt)
-	} else {
-		// Иначе получаем отклики текущего пользователя
-		applications, err = c.applicationUsecase.GetAll(ctx, userID.(int64))
+		c.listEmployerApplications(ctx, employerIDInt)
+		return
 	}
 
+	// Иначе получаем отклики текущего пользователя
+	applications, err := c.applicationUsecase.GetAll(ctx, userID.(int64))
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -133,8 +192,118 @@ func (c *ApplicationController) GetAll(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, applications)
 }
 
+// MarkViewed marks a pending application as viewed. The employer's client
+// calls it when the application is opened.
+func (c *ApplicationController) MarkViewed(ctx *gin.Context) {
+	userID, id, ok := applicationParams(ctx)
+	if !ok {
+		return
+	}
+
+	application, err := c.applicationUsecase.MarkViewed(ctx, id, userID)
+	if err != nil {
+		writeApplicationError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"id": application.ID, "status": application.Status})
+}
+
+type EmployerApplicationsQuery struct {
+	// VacancyIDs, Statuses and Tags are comma-separated lists.
+	VacancyIDs string   `form:"vacancy_ids"`
+	Statuses   string   `form:"status"`
+	From       string   `form:"from"`
+	To         string   `form:"to"`
+	Tags       string   `form:"tags"`
+	MinRating  *float64 `form:"min_rating"`
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// bindEmployerFilter reads the employer application filter from the query.
+// from and to take YYYY-MM-DD dates and include the whole day. It writes a
+// 400 response and returns ok=false on bad input.
+func bindEmployerFilter(ctx *gin.Context) (entity.ApplicationFilter, bool) {
+	var query EmployerApplicationsQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return entity.ApplicationFilter{}, false
+	}
+
+	filter := entity.ApplicationFilter{
+		Statuses:  splitList(query.Statuses),
+		Tags:      splitList(query.Tags),
+		MinRating: query.MinRating,
+	}
+	for _, id := range splitList(query.VacancyIDs) {
+		vacancyID, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid vacancy_ids"})
+			return entity.ApplicationFilter{}, false
+		}
+		filter.VacancyIDs = append(filter.VacancyIDs, vacancyID)
+	}
+	if query.From != "" {
+		from, err := time.Parse(sectionDateLayout, query.From)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "from must be YYYY-MM-DD"})
+			return entity.ApplicationFilter{}, false
+		}
+		filter.CreatedFrom = &from
+	}
+	if query.To != "" {
+		to, err := time.Parse(sectionDateLayout, query.To)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "to must be YYYY-MM-DD"})
+			return entity.ApplicationFilter{}, false
+		}
+		to = to.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		filter.CreatedTo = &to
+	}
+	return filter, true
+}
+
+// listEmployerApplications serves a page of the employer's applications.
+func (c *ApplicationController) listEmployerApplications(ctx *gin.Context, employerID int64) {
+	filter, ok := bindEmployerFilter(ctx)
+	if !ok {
+		return
+	}
+	limit, offset, ok := parsePagination(ctx)
+	if !ok {
+		return
+	}
+
+	applications, total, err := c.applicationUsecase.GetByEmployerID(ctx, employerID, filter, limit, offset)
+	if err != nil {
+		if errors.Is(err, entity.ErrInvalidTags) || errors.Is(err, entity.ErrInvalidApplicationFilter) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"applications": applications,
+		"total":        total,
+		"limit":        limit,
+		"offset":       offset,
+	})
+}
+
 type UpdateStatusRequest struct {
-	Status string `json:"status" binding:"required"`
+	Status  string `json:"status" binding:"required,oneof=pending viewed shortlisted interview offer hired rejected withdrawn"`
+	Comment string `json:"comment" binding:"max=2000"`
 }
 
 func (c *ApplicationController) UpdateStatus(ctx *gin.Context) {
@@ -156,10 +325,128 @@ func (c *ApplicationController) UpdateStatus(ctx *gin.Context) {
 		return
 	}
 
-	if err := c.applicationUsecase.UpdateStatus(ctx, id, userID.(int64), req.Status); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.applicationUsecase.UpdateStatus(ctx, id, userID.(int64), req.Status, req.Comment); err != nil {
+		writeApplicationError(ctx, err)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{"message": "status updated successfully"})
 }
+
+type WithdrawApplicationRequest struct {
+	Reason string `json:"reason" binding:"max=2000"`
+}
+
+// Withdraw takes back a pending application on behalf of the applicant.
+func (c *ApplicationController) Withdraw(ctx *gin.Context) {
+	userID, id, ok := applicationParams(ctx)
+	if !ok {
+		return
+	}
+
+	var req WithdrawApplicationRequest
+	// Причина необязательна, тело запроса может отсутствовать
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if err := c.applicationUsecase.Withdraw(ctx, id, userID, req.Reason); err != nil {
+		writeApplicationError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "application withdrawn successfully"})
+}
+
+// GetPipelineBoard returns the vacancy's applications grouped by pipeline
+// stage for the kanban view.
+func (c *ApplicationController) GetPipelineBoard(ctx *gin.Context) {
+	vacancyID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	board, err := c.applicationUsecase.GetPipelineBoard(ctx, userID.(int64), vacancyID)
+	if err != nil {
+		writePipelineError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, board)
+}
+
+type MoveToStageRequest struct {
+	StageID int64 `json:"stage_id" binding:"required"`
+}
+
+func (c *ApplicationController) MoveToStage(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req MoveToStageRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if err := c.applicationUsecase.MoveToStage(ctx, id, userID.(int64), req.StageID); err != nil {
+		writePipelineError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "application moved successfully"})
+}
+
+type BulkMoveToStageRequest struct {
+	ApplicationIDs []int64 `json:"application_ids" binding:"required,min=1,max=500"`
+	StageID        int64   `json:"stage_id" binding:"required"`
+}
+
+// BulkMoveToStage moves several applications of the vacancy to one stage.
+// Closed applications are skipped; the response reports how many were moved.
+func (c *ApplicationController) BulkMoveToStage(ctx *gin.Context) {
+	vacancyID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req BulkMoveToStageRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	moved, err := c.applicationUsecase.BulkMoveToStage(ctx, userID.(int64), vacancyID, req.ApplicationIDs, req.StageID)
+	if err != nil {
+		writePipelineError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"moved": moved})
+}
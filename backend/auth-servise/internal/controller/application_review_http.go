@@ -0,0 +1,204 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type ApplicationReviewController struct {
+	uc usecase.ApplicationReviewUsecaseInterface
+}
+
+func NewApplicationReviewController(uc usecase.ApplicationReviewUsecaseInterface) *ApplicationReviewController {
+	return &ApplicationReviewController{uc: uc}
+}
+
+type NoteRequest struct {
+	Body string `json:"body" binding:"required"`
+}
+
+type RatingRequest struct {
+	Rating int `json:"rating" binding:"required,min=1,max=5"`
+}
+
+type TagsRequest struct {
+	Tags []string `json:"tags" binding:"required"`
+}
+
+func writeReviewError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, entity.ErrApplicationNoteNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, entity.ErrInvalidNote), errors.Is(err, entity.ErrInvalidRating), errors.Is(err, entity.ErrInvalidTags):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		writeApplicationError(ctx, err)
+	}
+}
+
+// reviewParams reads the current user, the application id and, if withNote
+// is set, the note id from the path.
+func reviewParams(ctx *gin.Context, withNote bool) (userID, applicationID, noteID int64, ok bool) {
+	userID, applicationID, ok = interviewParams(ctx)
+	if !ok || !withNote {
+		return userID, applicationID, 0, ok
+	}
+
+	noteID, err := strconv.ParseInt(ctx.Param("noteId"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid note id"})
+		return 0, 0, 0, false
+	}
+	return userID, applicationID, noteID, true
+}
+
+// GetReview returns the notes, ratings and tags of the application.
+func (c *ApplicationReviewController) GetReview(ctx *gin.Context) {
+	userID, applicationID, _, ok := reviewParams(ctx, false)
+	if !ok {
+		return
+	}
+
+	review, err := c.uc.GetReview(ctx, userID, applicationID)
+	if err != nil {
+		writeReviewError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, review)
+}
+
+func (c *ApplicationReviewController) AddNote(ctx *gin.Context) {
+	userID, applicationID, _, ok := reviewParams(ctx, false)
+	if !ok {
+		return
+	}
+
+	var req NoteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	note, err := c.uc.AddNote(ctx, userID, applicationID, req.Body)
+	if err != nil {
+		writeReviewError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, note)
+}
+
+func (c *ApplicationReviewController) UpdateNote(ctx *gin.Context) {
+	userID, applicationID, noteID, ok := reviewParams(ctx, true)
+	if !ok {
+		return
+	}
+
+	var req NoteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	note, err := c.uc.UpdateNote(ctx, userID, applicationID, noteID, req.Body)
+	if err != nil {
+		writeReviewError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, note)
+}
+
+func (c *ApplicationReviewController) DeleteNote(ctx *gin.Context) {
+	userID, applicationID, noteID, ok := reviewParams(ctx, true)
+	if !ok {
+		return
+	}
+
+	if err := c.uc.DeleteNote(ctx, userID, applicationID, noteID); err != nil {
+		writeReviewError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "note deleted successfully"})
+}
+
+// Rate sets the current user's rating of the applicant.
+func (c *ApplicationReviewController) Rate(ctx *gin.Context) {
+	userID, applicationID, _, ok := reviewParams(ctx, false)
+	if !ok {
+		return
+	}
+
+	var req RatingRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.uc.Rate(ctx, userID, applicationID, req.Rating); err != nil {
+		writeReviewError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "rating saved successfully"})
+}
+
+func (c *ApplicationReviewController) DeleteRating(ctx *gin.Context) {
+	userID, applicationID, _, ok := reviewParams(ctx, false)
+	if !ok {
+		return
+	}
+
+	if err := c.uc.DeleteRating(ctx, userID, applicationID); err != nil {
+		writeReviewError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "rating deleted successfully"})
+}
+
+// SetTags replaces the application tags.
+func (c *ApplicationReviewController) SetTags(ctx *gin.Context) {
+	userID, applicationID, _, ok := reviewParams(ctx, false)
+	if !ok {
+		return
+	}
+
+	var req TagsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tags, err := c.uc.SetTags(ctx, userID, applicationID, req.Tags)
+	if err != nil {
+		writeReviewError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"tags": tags})
+}
+
+// ListTags returns the tags the employer has used so far.
+func (c *ApplicationReviewController) ListTags(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	tags, err := c.uc.ListTags(ctx, userID.(int64))
+	if err != nil {
+		writeReviewError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"tags": tags})
+}
@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type AuditController struct {
+	uc usecase.AuditUsecaseInterface
+}
+
+func NewAuditController(uc usecase.AuditUsecaseInterface) *AuditController {
+	return &AuditController{uc: uc}
+}
+
+func writeAuditError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrPermissionDenied):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// parseAuditFilter reads ?actor_id=, ?action=, ?target_type=, ?target_id=,
+// ?from= and ?to=. Times are RFC 3339 or dates like 2024-01-31. It writes a
+// 400 response and returns ok=false on bad input.
+func parseAuditFilter(ctx *gin.Context) (filter entity.AuditFilter, ok bool) {
+	filter.Action = ctx.Query("action")
+	filter.TargetType = ctx.Query("target_type")
+
+	for param, id := range map[string]*int64{"actor_id": &filter.ActorID, "target_id": &filter.TargetID} {
+		if value := ctx.Query(param); value != "" {
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || parsed <= 0 {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid %s", param)})
+				return filter, false
+			}
+			*id = parsed
+		}
+	}
+
+	for param, t := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		if value := ctx.Query(param); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				parsed, err = time.Parse(time.DateOnly, value)
+			}
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid %s time", param)})
+				return filter, false
+			}
+			*t = parsed
+		}
+	}
+
+	return filter, true
+}
+
+// List returns a page of the audit log, newest first.
+func (c *AuditController) List(ctx *gin.Context) {
+	adminID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	filter, ok := parseAuditFilter(ctx)
+	if !ok {
+		return
+	}
+	limit, offset, ok := parsePagination(ctx)
+	if !ok {
+		return
+	}
+
+	entries, total, err := c.uc.List(ctx, adminID.(int64), filter, limit, offset)
+	if err != nil {
+		writeAuditError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"entries": entries, "total": total, "limit": limit, "offset": offset})
+}
+
+// Verify checks the hash chain of the whole audit log.
+func (c *AuditController) Verify(ctx *gin.Context) {
+	adminID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	result, err := c.uc.Verify(ctx, adminID.(int64))
+	if err != nil {
+		writeAuditError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
@@ -2,11 +2,13 @@
 package controller
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
 
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
 	"github.com/Mandarinka0707/newRepoGOODarhit/internal/usecase"
 	"github.com/gin-gonic/gin"
 )
@@ -103,6 +105,10 @@ func (c *HTTPAuthController) Login(ctx *gin.Context) {
 	ucResp, err := c.uc.Login(ctx.Request.Context(), ucReq)
 	if err != nil {
 		fmt.Printf("Login error from usecase: %v\n", err)
+		if isAccountRestricted(err) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -149,6 +155,71 @@ func (ctrl *HTTPAuthController) GetUser(ctx *gin.Context) {
 	})
 }
 
+// isAccountRestricted reports whether err means the account is suspended or
+// banned. Deleted accounts get the same answer as unknown ones.
+func isAccountRestricted(err error) bool {
+	return errors.Is(err, entity.ErrAccountSuspended) || errors.Is(err, entity.ErrAccountBanned)
+}
+
+type ImpersonateRequest struct {
+	Reason string `json:"reason"`
+}
+
+// Impersonate issues the admin a short-lived read-only token for the user
+// identified by :id, e.g. to look into a problem the user reported.
+func (c *HTTPAuthController) Impersonate(ctx *gin.Context) {
+	adminID, id, ok := interviewParams(ctx)
+	if !ok {
+		return
+	}
+
+	// Причина необязательна и попадает в журнал аудита
+	var req ImpersonateRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	resp, err := c.uc.Impersonate(ctx, adminID, id, req.Reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrPermissionDenied):
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		case errors.Is(err, usecase.ErrUserNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case isAccountRestricted(err), errors.Is(err, entity.ErrAccountDeleted):
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, resp)
+}
+
+// StreamToken issues a one-minute token for opening the notification stream
+// with EventSource, which cannot send the Authorization header:
+// new EventSource("/api/notifications/stream?token=" + token).
+func (c *HTTPAuthController) StreamToken(ctx *gin.Context) {
+	userID := ctx.GetInt64("user_id")
+
+	resp, err := c.uc.StreamToken(ctx, userID)
+	if err != nil {
+		switch {
+		case isAccountRestricted(err), errors.Is(err, entity.ErrAccountDeleted):
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, resp)
+}
+
 func (c *HTTPAuthController) AuthMiddleware() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authHeader := ctx.GetHeader("Authorization")
@@ -167,6 +238,11 @@ func (c *HTTPAuthController) AuthMiddleware() gin.HandlerFunc {
 
 		token := parts[1]
 		userID, err := c.uc.ValidateToken(ctx, &usecase.ValidateTokenRequest{Token: token})
+		if isAccountRestricted(err) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			ctx.Abort()
+			return
+		}
 		if err != nil {
 			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
 			ctx.Abort()
@@ -0,0 +1,141 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type ContactRequestController struct {
+	uc usecase.ContactRequestUsecaseInterface
+}
+
+func NewContactRequestController(uc usecase.ContactRequestUsecaseInterface) *ContactRequestController {
+	return &ContactRequestController{uc: uc}
+}
+
+type CreateContactRequestRequest struct {
+	ResumeID int64  `json:"resume_id" binding:"required"`
+	Message  string `json:"message" binding:"max=2000"`
+}
+
+func writeContactRequestError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, entity.ErrContactRequestExists), errors.Is(err, usecase.ErrContactRequestAnswered):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, entity.ErrContactRequestNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrEmployerNotVerified):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		writeResumeError(ctx, err)
+	}
+}
+
+func (c *ContactRequestController) Create(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req CreateContactRequestRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	request, err := c.uc.Create(ctx, userID.(int64), req.ResumeID, req.Message)
+	if err != nil {
+		writeContactRequestError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, request)
+}
+
+type ContactCandidateRequest struct {
+	Message string `json:"message" binding:"max=2000"`
+}
+
+// ContactCandidate is the "contact candidate" action of the candidate search:
+// it asks the owner of the resume to share their contacts.
+func (c *ContactRequestController) ContactCandidate(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	resumeID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req ContactCandidateRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	request, err := c.uc.Create(ctx, userID.(int64), resumeID, req.Message)
+	if err != nil {
+		writeContactRequestError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, request)
+}
+
+func (c *ContactRequestController) List(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	requests, err := c.uc.List(ctx, userID.(int64))
+	if err != nil {
+		writeContactRequestError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, requests)
+}
+
+func (c *ContactRequestController) Accept(ctx *gin.Context) {
+	c.respond(ctx, true)
+}
+
+func (c *ContactRequestController) Decline(ctx *gin.Context) {
+	c.respond(ctx, false)
+}
+
+func (c *ContactRequestController) respond(ctx *gin.Context, accept bool) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	request, err := c.uc.Respond(ctx, userID.(int64), id, accept)
+	if err != nil {
+		writeContactRequestError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request)
+}
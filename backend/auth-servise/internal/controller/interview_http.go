@@ -0,0 +1,230 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type InterviewController struct {
+	uc usecase.InterviewUsecaseInterface
+}
+
+func NewInterviewController(uc usecase.InterviewUsecaseInterface) *InterviewController {
+	return &InterviewController{uc: uc}
+}
+
+type InterviewDetailsRequest struct {
+	Title           string   `json:"title" binding:"required,max=255"`
+	DurationMinutes int      `json:"duration_minutes" binding:"required"`
+	Location        string   `json:"location" binding:"max=500"`
+	VideoLink       string   `json:"video_link" binding:"max=1000"`
+	Interviewers    []string `json:"interviewers"`
+	Notes           string   `json:"notes" binding:"max=5000"`
+}
+
+func (r *InterviewDetailsRequest) toEntity() *entity.Interview {
+	return &entity.Interview{
+		Title:           r.Title,
+		DurationMinutes: r.DurationMinutes,
+		Location:        r.Location,
+		VideoLink:       r.VideoLink,
+		Interviewers:    r.Interviewers,
+		Notes:           r.Notes,
+	}
+}
+
+type ProposeInterviewRequest struct {
+	InterviewDetailsRequest
+	// Slots are RFC 3339 start times offered to the candidate.
+	Slots []time.Time `json:"slots" binding:"required,min=1"`
+}
+
+type UpdateInterviewRequest struct {
+	InterviewDetailsRequest
+	// StartsAt reschedules a scheduled interview.
+	StartsAt *time.Time `json:"starts_at"`
+}
+
+type SelectSlotRequest struct {
+	SlotID int64 `json:"slot_id" binding:"required"`
+}
+
+type CancelInterviewRequest struct {
+	Reason string `json:"reason" binding:"max=2000"`
+}
+
+func writeInterviewError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, entity.ErrInterviewNotFound), errors.Is(err, entity.ErrInterviewSlotNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, entity.ErrInvalidInterview):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, entity.ErrInterviewConflict), errors.Is(err, entity.ErrInterviewStatusConflict),
+		errors.Is(err, usecase.ErrInterviewNotScheduled), errors.Is(err, usecase.ErrApplicationNotShortlisted):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		writeApplicationError(ctx, err)
+	}
+}
+
+// interviewParams reads the current user and the id from the path.
+func interviewParams(ctx *gin.Context) (userID, id int64, ok bool) {
+	value, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return 0, 0, false
+	}
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return 0, 0, false
+	}
+
+	return value.(int64), id, true
+}
+
+// Propose offers interview slots for the application identified by :id.
+func (c *InterviewController) Propose(ctx *gin.Context) {
+	userID, applicationID, ok := interviewParams(ctx)
+	if !ok {
+		return
+	}
+
+	var req ProposeInterviewRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	interview := req.toEntity()
+	if err := c.uc.Propose(ctx, userID, applicationID, interview, req.Slots); err != nil {
+		writeInterviewError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, interview)
+}
+
+// List returns the interviews of the application identified by :id.
+func (c *InterviewController) List(ctx *gin.Context) {
+	userID, applicationID, ok := interviewParams(ctx)
+	if !ok {
+		return
+	}
+
+	interviews, err := c.uc.List(ctx, userID, applicationID)
+	if err != nil {
+		writeInterviewError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, interviews)
+}
+
+func (c *InterviewController) Get(ctx *gin.Context) {
+	userID, id, ok := interviewParams(ctx)
+	if !ok {
+		return
+	}
+
+	interview, err := c.uc.Get(ctx, userID, id)
+	if err != nil {
+		writeInterviewError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, interview)
+}
+
+// SelectSlot lets the candidate pick one of the proposed slots.
+func (c *InterviewController) SelectSlot(ctx *gin.Context) {
+	userID, id, ok := interviewParams(ctx)
+	if !ok {
+		return
+	}
+
+	var req SelectSlotRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	interview, err := c.uc.SelectSlot(ctx, userID, id, req.SlotID)
+	if err != nil {
+		writeInterviewError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, interview)
+}
+
+func (c *InterviewController) Update(ctx *gin.Context) {
+	userID, id, ok := interviewParams(ctx)
+	if !ok {
+		return
+	}
+
+	var req UpdateInterviewRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	changes := req.toEntity()
+	changes.ID = id
+	changes.StartsAt = req.StartsAt
+	interview, err := c.uc.Update(ctx, userID, changes)
+	if err != nil {
+		writeInterviewError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, interview)
+}
+
+func (c *InterviewController) Cancel(ctx *gin.Context) {
+	userID, id, ok := interviewParams(ctx)
+	if !ok {
+		return
+	}
+
+	var req CancelInterviewRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	interview, err := c.uc.Cancel(ctx, userID, id, req.Reason)
+	if err != nil {
+		writeInterviewError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, interview)
+}
+
+// Invite downloads the interview as an .ics file.
+func (c *InterviewController) Invite(ctx *gin.Context) {
+	userID, id, ok := interviewParams(ctx)
+	if !ok {
+		return
+	}
+
+	data, err := c.uc.Invite(ctx, userID, id)
+	if err != nil {
+		writeInterviewError(ctx, err)
+		return
+	}
+
+	ctx.Header("Content-Disposition", `attachment; filename="interview-`+strconv.FormatInt(id, 10)+`.ics"`)
+	ctx.Data(http.StatusOK, "text/calendar; charset=utf-8", data)
+}
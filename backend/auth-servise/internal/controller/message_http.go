@@ -0,0 +1,223 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/attachment"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type MessageController struct {
+	uc usecase.MessageUsecaseInterface
+}
+
+func NewMessageController(uc usecase.MessageUsecaseInterface) *MessageController {
+	return &MessageController{uc: uc}
+}
+
+type SendMessageRequest struct {
+	Body string `json:"body" binding:"required"`
+}
+
+func writeMessageError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, entity.ErrInvalidMessage):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, attachment.ErrUnsupportedMessageType):
+		ctx.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+	case errors.Is(err, entity.ErrMessageAttachmentNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, attachment.ErrTooLarge), errors.Is(err, attachment.ErrEmpty),
+		errors.Is(err, attachment.ErrInfected), errors.Is(err, attachment.ErrLinkExpired),
+		errors.Is(err, attachment.ErrLinkSignature):
+		writeAttachmentError(ctx, err)
+	default:
+		writeApplicationError(ctx, err)
+	}
+}
+
+// ListThreads returns the user's conversations with unread counts.
+func (c *MessageController) ListThreads(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	limit, offset, ok := parsePagination(ctx)
+	if !ok {
+		return
+	}
+
+	threads, err := c.uc.ListThreads(ctx, userID.(int64), limit, offset)
+	if err != nil {
+		writeMessageError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"threads": threads, "limit": limit, "offset": offset})
+}
+
+// CountUnread returns the number of unread messages over all threads.
+func (c *MessageController) CountUnread(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	count, err := c.uc.CountUnread(ctx, userID.(int64))
+	if err != nil {
+		writeMessageError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"unread": count})
+}
+
+// List returns the messages of the application's thread. Older messages
+// are paged with the before query parameter, the id of the oldest message
+// already loaded.
+func (c *MessageController) List(ctx *gin.Context) {
+	userID, applicationID, ok := interviewParams(ctx)
+	if !ok {
+		return
+	}
+	limit, _, ok := parsePagination(ctx)
+	if !ok {
+		return
+	}
+	var beforeID int64
+	if value := ctx.Query("before"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed <= 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid before"})
+			return
+		}
+		beforeID = parsed
+	}
+
+	messages, err := c.uc.List(ctx, userID, applicationID, beforeID, limit)
+	if err != nil {
+		writeMessageError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"messages": messages})
+}
+
+// Send posts a message. A message with files is sent as multipart form
+// data with the text in the field "body" and the files in "files".
+func (c *MessageController) Send(ctx *gin.Context) {
+	userID, applicationID, ok := interviewParams(ctx)
+	if !ok {
+		return
+	}
+
+	var body string
+	var files []usecase.MessageFile
+	if ctx.ContentType() == "multipart/form-data" {
+		body, files, ok = readMessageForm(ctx)
+		if !ok {
+			return
+		}
+	} else {
+		var req SendMessageRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		body = req.Body
+	}
+
+	message, err := c.uc.Send(ctx, userID, applicationID, body, files)
+	if err != nil {
+		writeMessageError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, message)
+}
+
+// readMessageForm reads the text and the files of a multipart message. It
+// writes a 4xx response and returns ok=false on bad input.
+func readMessageForm(ctx *gin.Context) (body string, files []usecase.MessageFile, ok bool) {
+	// Leave room for the multipart envelope around the files themselves.
+	limit := int64(entity.MaxMessageAttachments)*attachment.MaxSize + 1<<20
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, limit)
+	form, err := ctx.MultipartForm()
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			writeAttachmentError(ctx, attachment.ErrTooLarge)
+			return "", nil, false
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid form"})
+		return "", nil, false
+	}
+
+	if values := form.Value["body"]; len(values) > 0 {
+		body = values[0]
+	}
+	headers := form.File["files"]
+	if len(headers) > entity.MaxMessageAttachments {
+		writeMessageError(ctx, fmt.Errorf("%w: at most %d files are allowed", entity.ErrInvalidMessage, entity.MaxMessageAttachments))
+		return "", nil, false
+	}
+
+	for _, header := range headers {
+		if header.Size > attachment.MaxSize {
+			writeAttachmentError(ctx, attachment.ErrTooLarge)
+			return "", nil, false
+		}
+		f, err := header.Open()
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "failed to read file"})
+			return "", nil, false
+		}
+		data, err := io.ReadAll(io.LimitReader(f, attachment.MaxSize+1))
+		f.Close()
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "failed to read file"})
+			return "", nil, false
+		}
+		files = append(files, usecase.MessageFile{Name: header.Filename, Data: data})
+	}
+	return body, files, true
+}
+
+// Download streams a message attachment behind a signed link. The link
+// itself is the credential, so the route is not behind the auth middleware.
+func (c *MessageController) Download(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	viewerID, err1 := strconv.ParseInt(ctx.Query("viewer"), 10, 64)
+	expires, err2 := strconv.ParseInt(ctx.Query("expires"), 10, 64)
+	if err1 != nil || err2 != nil || ctx.Query("signature") == "" {
+		writeAttachmentError(ctx, attachment.ErrLinkSignature)
+		return
+	}
+
+	item, body, err := c.uc.Download(ctx, id, attachment.Link{
+		ViewerID:  viewerID,
+		Expires:   expires,
+		Signature: ctx.Query("signature"),
+	})
+	if err != nil {
+		writeMessageError(ctx, err)
+		return
+	}
+	defer body.Close()
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", item.FileName))
+	ctx.Header("Cache-Control", "private, no-store")
+	ctx.DataFromReader(http.StatusOK, item.Size, item.ContentType, body, nil)
+}
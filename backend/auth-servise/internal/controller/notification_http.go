@@ -0,0 +1,179 @@
+package controller
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+// streamKeepAlive is how often an idle stream gets a comment, so proxies do
+// not close it.
+const streamKeepAlive = 25 * time.Second
+
+type NotificationController struct {
+	uc usecase.NotificationUsecaseInterface
+}
+
+func NewNotificationController(uc usecase.NotificationUsecaseInterface) *NotificationController {
+	return &NotificationController{uc: uc}
+}
+
+type NotificationPreferencesRequest struct {
+	Preferences []*entity.NotificationPreference `json:"preferences" binding:"required,min=1"`
+}
+
+func writeNotificationError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, entity.ErrNotificationNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, entity.ErrInvalidNotificationPreference):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// List returns a page of the user's notifications, newest first, and the
+// number of unread ones. ?unread=true leaves only unread notifications.
+func (c *NotificationController) List(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	limit, offset, ok := parsePagination(ctx)
+	if !ok {
+		return
+	}
+
+	notifications, unread, err := c.uc.List(ctx, userID.(int64), ctx.Query("unread") == "true", limit, offset)
+	if err != nil {
+		writeNotificationError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"notifications": notifications, "unread": unread})
+}
+
+func (c *NotificationController) MarkRead(ctx *gin.Context) {
+	userID, id, ok := interviewParams(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.uc.MarkRead(ctx, userID, id); err != nil {
+		writeNotificationError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c *NotificationController) MarkAllRead(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	marked, err := c.uc.MarkAllRead(ctx, userID.(int64))
+	if err != nil {
+		writeNotificationError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"marked": marked})
+}
+
+func (c *NotificationController) GetPreferences(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	preferences, err := c.uc.GetPreferences(ctx, userID.(int64))
+	if err != nil {
+		writeNotificationError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, preferences)
+}
+
+// SetPreferences changes the channels of the given notification types.
+// Types missing from the request keep their settings.
+func (c *NotificationController) SetPreferences(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req NotificationPreferencesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	preferences, err := c.uc.SetPreferences(ctx, userID.(int64), req.Preferences)
+	if err != nil {
+		writeNotificationError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, preferences)
+}
+
+// Stream pushes new notifications as server-sent events named
+// "notification" until the client disconnects or the server shuts down.
+// Clients that can send headers use the usual Authorization header; the
+// browser EventSource API passes a token from POST
+// /notifications/stream-token in the "token" query parameter instead.
+func (c *NotificationController) Stream(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	notifications, unsubscribe, err := c.uc.Subscribe(ctx, userID.(int64))
+	if err != nil {
+		if errors.Is(err, usecase.ErrReadOnlySession) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer unsubscribe()
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	// Nginx не должен буферизовать поток
+	ctx.Header("X-Accel-Buffering", "no")
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Request.Context().Done():
+			return false
+		case notification, ok := <-notifications:
+			if !ok {
+				return false
+			}
+			ctx.SSEvent("notification", notification)
+			return true
+		case <-keepAlive.C:
+			_, err := io.WriteString(w, ": keep-alive\n\n")
+			return err == nil
+		}
+	})
+}
@@ -0,0 +1,256 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type OrganizationController struct {
+	uc usecase.OrganizationUsecaseInterface
+}
+
+func NewOrganizationController(uc usecase.OrganizationUsecaseInterface) *OrganizationController {
+	return &OrganizationController{uc: uc}
+}
+
+type OrganizationRequest struct {
+	Name        string `json:"name" binding:"required"`
+	LogoURL     string `json:"logo_url"`
+	Description string `json:"description"`
+	Website     string `json:"website"`
+	Size        string `json:"size"`
+}
+
+func (r *OrganizationRequest) organization() *entity.Organization {
+	return &entity.Organization{
+		Name:        r.Name,
+		LogoURL:     r.LogoURL,
+		Description: r.Description,
+		Website:     r.Website,
+		Size:        r.Size,
+	}
+}
+
+type MemberRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+type OrganizationInviteRequest struct {
+	Email string `json:"email" binding:"required"`
+	Role  string `json:"role" binding:"required"`
+}
+
+type AcceptInviteRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+func writeOrganizationError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, entity.ErrOrganizationNotFound), errors.Is(err, entity.ErrInviteNotFound),
+		errors.Is(err, usecase.ErrUserNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, entity.ErrInvalidOrganization):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, entity.ErrAlreadyInOrganization), errors.Is(err, entity.ErrLastOwner):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, entity.ErrInviteExpired):
+		ctx.JSON(http.StatusGone, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrPermissionDenied):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// Create sets up an organization owned by the employer.
+func (c *OrganizationController) Create(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req OrganizationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	organization := req.organization()
+	if err := c.uc.Create(ctx, userID.(int64), organization); err != nil {
+		writeOrganizationError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, organization)
+}
+
+// Get returns the organization of the current user.
+func (c *OrganizationController) Get(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	organization, err := c.uc.Get(ctx, userID.(int64))
+	if err != nil {
+		writeOrganizationError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, organization)
+}
+
+func (c *OrganizationController) Update(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req OrganizationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	organization, err := c.uc.Update(ctx, userID.(int64), req.organization())
+	if err != nil {
+		writeOrganizationError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, organization)
+}
+
+func (c *OrganizationController) ListMembers(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	members, err := c.uc.ListMembers(ctx, userID.(int64))
+	if err != nil {
+		writeOrganizationError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, members)
+}
+
+func (c *OrganizationController) ChangeRole(ctx *gin.Context) {
+	userID, memberID, ok := interviewParams(ctx)
+	if !ok {
+		return
+	}
+
+	var req MemberRoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.uc.ChangeRole(ctx, userID, memberID, req.Role); err != nil {
+		writeOrganizationError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// RemoveMember takes a member out of the organization; members remove
+// themselves to leave it.
+func (c *OrganizationController) RemoveMember(ctx *gin.Context) {
+	userID, memberID, ok := interviewParams(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.uc.RemoveMember(ctx, userID, memberID); err != nil {
+		writeOrganizationError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c *OrganizationController) Invite(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req OrganizationInviteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	invite := &entity.OrganizationInvite{Email: req.Email, Role: req.Role}
+	if err := c.uc.Invite(ctx, userID.(int64), invite); err != nil {
+		writeOrganizationError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, invite)
+}
+
+func (c *OrganizationController) ListInvites(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	invites, err := c.uc.ListInvites(ctx, userID.(int64))
+	if err != nil {
+		writeOrganizationError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, invites)
+}
+
+func (c *OrganizationController) RevokeInvite(ctx *gin.Context) {
+	userID, id, ok := interviewParams(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.uc.RevokeInvite(ctx, userID, id); err != nil {
+		writeOrganizationError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// AcceptInvite joins the organization with the token from the invite email.
+func (c *OrganizationController) AcceptInvite(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req AcceptInviteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	member, err := c.uc.AcceptInvite(ctx, userID.(int64), req.Token)
+	if err != nil {
+		writeOrganizationError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, member)
+}
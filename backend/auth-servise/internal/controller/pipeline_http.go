@@ -0,0 +1,186 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type PipelineController struct {
+	uc usecase.PipelineUsecaseInterface
+}
+
+func NewPipelineController(uc usecase.PipelineUsecaseInterface) *PipelineController {
+	return &PipelineController{uc: uc}
+}
+
+type PipelineStageRequest struct {
+	// ID is set for existing stages when updating a pipeline.
+	ID   int64  `json:"id"`
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+type PipelineRequest struct {
+	Name   string                 `json:"name" binding:"required,max=255"`
+	Stages []PipelineStageRequest `json:"stages" binding:"required,min=1,dive"`
+}
+
+func (r *PipelineRequest) toEntity() *entity.Pipeline {
+	pipeline := &entity.Pipeline{Name: r.Name}
+	for _, stage := range r.Stages {
+		pipeline.Stages = append(pipeline.Stages, &entity.PipelineStage{ID: stage.ID, Name: stage.Name})
+	}
+	return pipeline
+}
+
+type AssignPipelineRequest struct {
+	// PipelineID is null to detach the pipeline from the vacancy.
+	PipelineID *int64 `json:"pipeline_id"`
+}
+
+func writePipelineError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, entity.ErrPipelineNotFound), errors.Is(err, entity.ErrPipelineStageNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, entity.ErrInvalidPipeline):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrApplicationClosed):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		writeApplicationError(ctx, err)
+	}
+}
+
+// pipelineParams reads the current user and the pipeline id from the path.
+func pipelineParams(ctx *gin.Context) (userID, pipelineID int64, ok bool) {
+	value, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return 0, 0, false
+	}
+
+	pipelineID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return 0, 0, false
+	}
+
+	return value.(int64), pipelineID, true
+}
+
+func (c *PipelineController) Create(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req PipelineRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	pipeline := req.toEntity()
+	if err := c.uc.Create(ctx, userID.(int64), pipeline); err != nil {
+		writePipelineError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, pipeline)
+}
+
+func (c *PipelineController) List(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	pipelines, err := c.uc.List(ctx, userID.(int64))
+	if err != nil {
+		writePipelineError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pipelines)
+}
+
+func (c *PipelineController) Get(ctx *gin.Context) {
+	userID, pipelineID, ok := pipelineParams(ctx)
+	if !ok {
+		return
+	}
+
+	pipeline, err := c.uc.Get(ctx, userID, pipelineID)
+	if err != nil {
+		writePipelineError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pipeline)
+}
+
+// Update renames the pipeline and replaces its stages. Stages sent with an id
+// are kept in the new order; stages missing from the request are removed and
+// their applications go to the first stage.
+func (c *PipelineController) Update(ctx *gin.Context) {
+	userID, pipelineID, ok := pipelineParams(ctx)
+	if !ok {
+		return
+	}
+
+	var req PipelineRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	pipeline := req.toEntity()
+	pipeline.ID = pipelineID
+	if err := c.uc.Update(ctx, userID, pipeline); err != nil {
+		writePipelineError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pipeline)
+}
+
+func (c *PipelineController) Delete(ctx *gin.Context) {
+	userID, pipelineID, ok := pipelineParams(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.uc.Delete(ctx, userID, pipelineID); err != nil {
+		writePipelineError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "pipeline deleted successfully"})
+}
+
+// AssignToVacancy sets the pipeline used by the vacancy.
+func (c *PipelineController) AssignToVacancy(ctx *gin.Context) {
+	userID, vacancyID, ok := pipelineParams(ctx)
+	if !ok {
+		return
+	}
+
+	var req AssignPipelineRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.uc.AssignToVacancy(ctx, userID, vacancyID, req.PipelineID); err != nil {
+		writePipelineError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "pipeline assigned successfully"})
+}
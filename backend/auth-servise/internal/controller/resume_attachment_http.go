@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/attachment"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type ResumeAttachmentController struct {
+	uc usecase.ResumeAttachmentUsecaseInterface
+}
+
+func NewResumeAttachmentController(uc usecase.ResumeAttachmentUsecaseInterface) *ResumeAttachmentController {
+	return &ResumeAttachmentController{uc: uc}
+}
+
+func writeAttachmentError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, attachment.ErrTooLarge):
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case errors.Is(err, attachment.ErrUnsupportedType):
+		ctx.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+	case errors.Is(err, attachment.ErrEmpty):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, attachment.ErrInfected):
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, attachment.ErrLinkExpired), errors.Is(err, attachment.ErrLinkSignature):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, entity.ErrAttachmentNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		writeResumeError(ctx, err)
+	}
+}
+
+// Upload attaches a PDF or DOCX file sent as the multipart field "file".
+func (c *ResumeAttachmentController) Upload(ctx *gin.Context) {
+	userID, resumeID, _, ok := sectionParams(ctx, false)
+	if !ok {
+		return
+	}
+
+	// Leave room for the multipart envelope around the file itself.
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, attachment.MaxSize+1<<20)
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			writeAttachmentError(ctx, attachment.ErrTooLarge)
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	if file.Size > attachment.MaxSize {
+		writeAttachmentError(ctx, attachment.ErrTooLarge)
+		return
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "failed to read file"})
+		return
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, attachment.MaxSize+1))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "failed to read file"})
+		return
+	}
+
+	item, err := c.uc.Upload(ctx, userID, resumeID, file.Filename, data)
+	if err != nil {
+		writeAttachmentError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, item)
+}
+
+func (c *ResumeAttachmentController) List(ctx *gin.Context) {
+	userID, resumeID, _, ok := sectionParams(ctx, false)
+	if !ok {
+		return
+	}
+
+	items, err := c.uc.List(ctx, userID, resumeID)
+	if err != nil {
+		writeAttachmentError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, items)
+}
+
+func (c *ResumeAttachmentController) Delete(ctx *gin.Context) {
+	userID, resumeID, _, ok := sectionParams(ctx, false)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseInt(ctx.Param("attachmentId"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid attachment id"})
+		return
+	}
+
+	if err := c.uc.Delete(ctx, userID, resumeID, id); err != nil {
+		writeAttachmentError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// Download streams the file behind a signed link. The link itself is the
+// credential, so the route is not behind the auth middleware.
+func (c *ResumeAttachmentController) Download(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	viewerID, err1 := strconv.ParseInt(ctx.Query("viewer"), 10, 64)
+	expires, err2 := strconv.ParseInt(ctx.Query("expires"), 10, 64)
+	if err1 != nil || err2 != nil || ctx.Query("signature") == "" {
+		writeAttachmentError(ctx, attachment.ErrLinkSignature)
+		return
+	}
+
+	item, body, err := c.uc.Download(ctx, id, attachment.Link{
+		ViewerID:  viewerID,
+		Expires:   expires,
+		Signature: ctx.Query("signature"),
+	})
+	if err != nil {
+		writeAttachmentError(ctx, err)
+		return
+	}
+	defer body.Close()
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", item.FileName))
+	ctx.Header("Cache-Control", "private, no-store")
+	ctx.DataFromReader(http.StatusOK, item.Size, item.ContentType, body, nil)
+}
@@ -1,11 +1,16 @@
 package controller
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/export"
 	"github.com/Mandarinka0707/newRepoGOODarhit/internal/usecase"
 	"github.com/gin-gonic/gin"
 )
@@ -19,20 +24,23 @@ func NewResumeController(uc usecase.ResumeUsecaseInterface) *ResumeController {
 }
 
 type CreateResumeRequest struct {
-	Title       string   `json:"title" binding:"required"`
-	Description string   `json:"description" binding:"required"`
-	Skills      []string `json:"skills" binding:"required"`
-	Experience  string   `json:"experience" binding:"required"`
-	Education   string   `json:"education" binding:"required"`
+	Title            string   `json:"title" binding:"required"`
+	Description      string   `json:"description" binding:"required"`
+	Skills           []string `json:"skills" binding:"required"`
+	LegacyExperience string   `json:"legacy_experience"`
+	LegacyEducation  string   `json:"legacy_education"`
+	Location         string   `json:"location" binding:"max=255"`
+	Visibility       string   `json:"visibility" binding:"omitempty,oneof=private applied employers public"`
 }
 
 type UpdateResumeRequest struct {
-	Title       string   `json:"title" binding:"required"`
-	Description string   `json:"description" binding:"required"`
-	Skills      []string `json:"skills" binding:"required"`
-	Experience  string   `json:"experience" binding:"required"`
-	Education   string   `json:"education" binding:"required"`
-	Status      string   `json:"status" binding:"required"`
+	Title            string   `json:"title" binding:"required"`
+	Description      string   `json:"description" binding:"required"`
+	Skills           []string `json:"skills" binding:"required"`
+	LegacyExperience string   `json:"legacy_experience"`
+	LegacyEducation  string   `json:"legacy_education"`
+	Location         string   `json:"location" binding:"max=255"`
+	Status           string   `json:"status" binding:"required"`
 }
 
 func (c *ResumeController) CreateResume(ctx *gin.Context) {
@@ -49,13 +57,15 @@ func (c *ResumeController) CreateResume(ctx *gin.Context) {
 	}
 
 	resume := &entity.Resume{
-		UserID:      userID.(int64),
-		Title:       req.Title,
-		Description: req.Description,
-		Skills:      req.Skills,
-		Experience:  req.Experience,
-		Education:   req.Education,
-		Status:      "active",
+		UserID:           userID.(int64),
+		Title:            req.Title,
+		Description:      req.Description,
+		Skills:           req.Skills,
+		LegacyExperience: req.LegacyExperience,
+		LegacyEducation:  req.LegacyEducation,
+		Location:         req.Location,
+		Status:           "active",
+		Visibility:       req.Visibility,
 	}
 
 	if err := c.uc.CreateResume(ctx, resume); err != nil {
@@ -74,25 +84,19 @@ func (c *ResumeController) GetResume(ctx *gin.Context) {
 		return
 	}
 
-	if _, exists := ctx.Get("user_id"); !exists {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
 		return
 	}
 
-	fmt.Printf("Fetching resume with ID: %d\n", id)
-	resume, err := c.uc.GetResume(ctx, id)
+	// Резюме, которое пользователю не видно, отдаем как несуществующее
+	resume, err := c.uc.ViewResume(ctx, userID.(int64), id)
 	if err != nil {
-		fmt.Printf("Error fetching resume: %v\n", err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to get resume: %v", err)})
-		return
-	}
-	if resume == nil {
-		fmt.Printf("Resume not found with ID: %d\n", id)
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "resume not found"})
+		writeResumeError(ctx, err)
 		return
 	}
 
-	fmt.Printf("Successfully fetched resume: %+v\n", resume)
 	ctx.JSON(http.StatusOK, resume)
 }
 
@@ -149,14 +153,15 @@ func (c *ResumeController) UpdateResume(ctx *gin.Context) {
 	}
 
 	resume := &entity.Resume{
-		ID:          id,
-		UserID:      userID.(int64),
-		Title:       req.Title,
-		Description: req.Description,
-		Skills:      req.Skills,
-		Experience:  req.Experience,
-		Education:   req.Education,
-		Status:      req.Status,
+		ID:               id,
+		UserID:           userID.(int64),
+		Title:            req.Title,
+		Description:      req.Description,
+		Skills:           req.Skills,
+		LegacyExperience: req.LegacyExperience,
+		LegacyEducation:  req.LegacyEducation,
+		Location:         req.Location,
+		Status:           req.Status,
 	}
 
 	if err := c.uc.UpdateResume(ctx, resume); err != nil {
@@ -220,3 +225,122 @@ func (c *ResumeController) GetAllResumes(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, resumes)
 }
+
+type SearchCandidatesQuery struct {
+	Skills        string   `form:"skills"`
+	Match         string   `form:"match" binding:"omitempty,oneof=any all"`
+	MinExperience *float64 `form:"min_experience" binding:"omitempty,min=0"`
+	MaxExperience *float64 `form:"max_experience" binding:"omitempty,min=0"`
+	Education     string   `form:"education"`
+	Location      string   `form:"location"`
+	UpdatedSince  string   `form:"updated_since"`
+}
+
+// SearchCandidates looks through resumes visible to the employer. skills is
+// a comma-separated list; match=all requires every skill, otherwise any one
+// is enough. updated_since takes a YYYY-MM-DD date.
+func (c *ResumeController) SearchCandidates(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var query SearchCandidatesQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter := entity.ResumeSearchFilter{
+		MatchAll:      query.Match == "all",
+		MinExperience: query.MinExperience,
+		MaxExperience: query.MaxExperience,
+		Education:     strings.TrimSpace(query.Education),
+		Location:      strings.TrimSpace(query.Location),
+	}
+	for _, skill := range strings.Split(query.Skills, ",") {
+		if skill = strings.TrimSpace(skill); skill != "" {
+			filter.Skills = append(filter.Skills, skill)
+		}
+	}
+	if query.UpdatedSince != "" {
+		since, err := time.Parse(sectionDateLayout, query.UpdatedSince)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "updated_since must be YYYY-MM-DD"})
+			return
+		}
+		filter.UpdatedSince = &since
+	}
+
+	limit, offset, ok := parsePagination(ctx)
+	if !ok {
+		return
+	}
+
+	resumes, total, err := c.uc.SearchCandidates(ctx, userID.(int64), filter, limit, offset)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrPermissionDenied):
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "only employers can search resumes"})
+		case errors.Is(err, usecase.ErrEmployerNotVerified):
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to search resumes: %v", err)})
+		}
+		return
+	}
+	if resumes == nil {
+		resumes = []*entity.Resume{}
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"resumes": resumes,
+		"total":   total,
+		"limit":   limit,
+		"offset":  offset,
+	})
+}
+
+// ExportResume renders the resume as a downloadable file for its owner,
+// admins and employers who received it in an application.
+// Query parameters: format=pdf|docx (default pdf), template=classic|modern|compact.
+func (c *ResumeController) ExportResume(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	format := ctx.DefaultQuery("format", export.FormatPDF)
+	if format != export.FormatPDF && format != export.FormatDOCX {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "format must be pdf or docx"})
+		return
+	}
+
+	resume, owner, err := c.uc.GetResumeForExport(ctx, userID.(int64), id)
+	if err != nil {
+		writeResumeError(ctx, err)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := export.RenderResume(&buf, resume, owner, format, ctx.Query("template")); err != nil {
+		if errors.Is(err, export.ErrUnknownTemplate) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to export resume: %v", err)})
+		return
+	}
+
+	filename := fmt.Sprintf("resume-%d.%s", resume.ID, format)
+	ctx.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	ctx.Data(http.StatusOK, export.ContentType(format), buf.Bytes())
+}
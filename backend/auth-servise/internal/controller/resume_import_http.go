@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/resumeimport"
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportSize limits uploaded JSON Resume documents and LinkedIn archives.
+const maxImportSize = 10 << 20
+
+// readImport parses the uploaded document. The source is taken from the
+// "source" query parameter; the document comes either as the multipart
+// field "file" or, for JSON Resume, as the raw request body.
+func readImport(ctx *gin.Context) (*resumeimport.Result, bool) {
+	source := ctx.Query("source")
+	if source != resumeimport.SourceJSONResume && source != resumeimport.SourceLinkedIn {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "source must be jsonresume or linkedin"})
+		return nil, false
+	}
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxImportSize)
+
+	var (
+		data   []byte
+		maxErr *http.MaxBytesError
+	)
+	file, err := ctx.FormFile("file")
+	switch {
+	case err == nil:
+		f, err := file.Open()
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "failed to read file"})
+			return nil, false
+		}
+		defer f.Close()
+		data, err = io.ReadAll(f)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "failed to read file"})
+			return nil, false
+		}
+	case errors.As(err, &maxErr):
+		// Тело уже вычитано до лимита, дальше читать нечего
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "document is too large"})
+		return nil, false
+	case source == resumeimport.SourceJSONResume:
+		data, err = io.ReadAll(ctx.Request.Body)
+		if errors.As(err, &maxErr) {
+			ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "document is too large"})
+			return nil, false
+		}
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "failed to read document"})
+			return nil, false
+		}
+	}
+	if len(data) == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return nil, false
+	}
+
+	var result *resumeimport.Result
+	switch source {
+	case resumeimport.SourceJSONResume:
+		result, err = resumeimport.ParseJSONResume(bytes.NewReader(data))
+	case resumeimport.SourceLinkedIn:
+		result, err = resumeimport.ParseLinkedInArchive(bytes.NewReader(data), int64(len(data)))
+	}
+	if err != nil {
+		if errors.Is(err, resumeimport.ErrInvalidDocument) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return nil, false
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to import resume: %v", err)})
+		return nil, false
+	}
+	return result, true
+}
+
+// PreviewImport shows how an uploaded document maps onto a resume without
+// saving anything.
+func (c *ResumeController) PreviewImport(ctx *gin.Context) {
+	if _, exists := ctx.Get("user_id"); !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	result, ok := readImport(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// ImportResume creates a resume from an uploaded document. The response
+// lists the fields that could not be mapped, same as the preview.
+func (c *ResumeController) ImportResume(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	result, ok := readImport(ctx)
+	if !ok {
+		return
+	}
+	if result.Resume.Title == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "document has no title or positions to name the resume after"})
+		return
+	}
+
+	if err := c.uc.ImportResume(ctx, userID.(int64), result.Resume); err != nil {
+		writeResumeError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, result)
+}
@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SetVisibilityRequest struct {
+	Visibility string `json:"visibility" binding:"required,oneof=private applied employers public"`
+}
+
+type BlockEmployerRequest struct {
+	EmployerID int64 `json:"employer_id" binding:"required"`
+}
+
+// SetVisibility changes who can see the resume. The response carries the
+// public link token when visibility is public.
+func (c *ResumeController) SetVisibility(ctx *gin.Context) {
+	userID, resumeID, _, ok := sectionParams(ctx, false)
+	if !ok {
+		return
+	}
+
+	var req SetVisibilityRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resume, err := c.uc.SetVisibility(ctx, userID, resumeID, req.Visibility)
+	if err != nil {
+		writeResumeError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, resume)
+}
+
+// GetPublicResume serves a resume by its public link. No authentication is
+// required and contacts are never included.
+func (c *ResumeController) GetPublicResume(ctx *gin.Context) {
+	resume, err := c.uc.GetPublicResume(ctx, ctx.Param("token"))
+	if err != nil {
+		writeResumeError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, resume)
+}
+
+func (c *ResumeController) GetBlockedEmployers(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	blocked, err := c.uc.GetBlockedEmployers(ctx, userID.(int64))
+	if err != nil {
+		writeResumeError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, blocked)
+}
+
+func (c *ResumeController) BlockEmployer(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req BlockEmployerRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.uc.BlockEmployer(ctx, userID.(int64), req.EmployerID); err != nil {
+		writeResumeError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"message": "employer blocked"})
+}
+
+func (c *ResumeController) UnblockEmployer(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	employerID, err := strconv.ParseInt(ctx.Param("employerId"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid employer id"})
+		return
+	}
+
+	if err := c.uc.UnblockEmployer(ctx, userID.(int64), employerID); err != nil {
+		writeResumeError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
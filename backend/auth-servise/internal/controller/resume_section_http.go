@@ -0,0 +1,414 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+// Dates in section requests are plain calendar dates.
+const sectionDateLayout = "2006-01-02"
+
+type WorkExperienceRequest struct {
+	Company     string `json:"company" binding:"required"`
+	Title       string `json:"title" binding:"required"`
+	StartDate   string `json:"start_date" binding:"required"`
+	EndDate     string `json:"end_date"`
+	Description string `json:"description"`
+}
+
+type EducationRequest struct {
+	Institution  string `json:"institution" binding:"required"`
+	Degree       string `json:"degree"`
+	FieldOfStudy string `json:"field_of_study"`
+	StartYear    int    `json:"start_year" binding:"required,min=1900,max=2100"`
+	EndYear      *int   `json:"end_year" binding:"omitempty,min=1900,max=2100"`
+}
+
+type LanguageRequest struct {
+	Language string `json:"language" binding:"required"`
+	Level    string `json:"level" binding:"required,oneof=A1 A2 B1 B2 C1 C2 native"`
+}
+
+type CertificationRequest struct {
+	Name          string `json:"name" binding:"required"`
+	Issuer        string `json:"issuer"`
+	IssuedAt      string `json:"issued_at"`
+	ExpiresAt     string `json:"expires_at"`
+	CredentialURL string `json:"credential_url" binding:"omitempty,url"`
+}
+
+func (req *WorkExperienceRequest) toEntity(resumeID int64) (*entity.WorkExperience, error) {
+	start, err := time.Parse(sectionDateLayout, req.StartDate)
+	if err != nil {
+		return nil, errors.New("invalid start_date, expected YYYY-MM-DD")
+	}
+	end, err := parseOptionalDate(req.EndDate)
+	if err != nil {
+		return nil, errors.New("invalid end_date, expected YYYY-MM-DD")
+	}
+	if end != nil && end.Before(start) {
+		return nil, errors.New("end_date must not be before start_date")
+	}
+	return &entity.WorkExperience{
+		ResumeID:    resumeID,
+		Company:     req.Company,
+		Title:       req.Title,
+		StartDate:   start,
+		EndDate:     end,
+		Description: req.Description,
+	}, nil
+}
+
+func (req *EducationRequest) toEntity(resumeID int64) (*entity.EducationEntry, error) {
+	if req.EndYear != nil && *req.EndYear < req.StartYear {
+		return nil, errors.New("end_year must not be before start_year")
+	}
+	return &entity.EducationEntry{
+		ResumeID:     resumeID,
+		Institution:  req.Institution,
+		Degree:       req.Degree,
+		FieldOfStudy: req.FieldOfStudy,
+		StartYear:    req.StartYear,
+		EndYear:      req.EndYear,
+	}, nil
+}
+
+func (req *CertificationRequest) toEntity(resumeID int64) (*entity.Certification, error) {
+	issued, err := parseOptionalDate(req.IssuedAt)
+	if err != nil {
+		return nil, errors.New("invalid issued_at, expected YYYY-MM-DD")
+	}
+	expires, err := parseOptionalDate(req.ExpiresAt)
+	if err != nil {
+		return nil, errors.New("invalid expires_at, expected YYYY-MM-DD")
+	}
+	return &entity.Certification{
+		ResumeID:      resumeID,
+		Name:          req.Name,
+		Issuer:        req.Issuer,
+		IssuedAt:      issued,
+		ExpiresAt:     expires,
+		CredentialURL: req.CredentialURL,
+	}, nil
+}
+
+func parseOptionalDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(sectionDateLayout, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// sectionParams reads the resume id, the optional entry id and the current
+// user from the request. It writes the error response itself and returns
+// ok=false when something is missing.
+func sectionParams(ctx *gin.Context, withEntry bool) (userID, resumeID, entryID int64, ok bool) {
+	uid, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return 0, 0, 0, false
+	}
+
+	resumeID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return 0, 0, 0, false
+	}
+
+	if withEntry {
+		entryID, err = strconv.ParseInt(ctx.Param("entryId"), 10, 64)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid entry id"})
+			return 0, 0, 0, false
+		}
+	}
+
+	return uid.(int64), resumeID, entryID, true
+}
+
+func writeResumeError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrResumeNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "resume not found"})
+	case errors.Is(err, entity.ErrResumeSectionNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrPermissionDenied):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+	case errors.Is(err, usecase.ErrInvalidVisibility):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrEmployerNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func (c *ResumeController) AddWorkExperience(ctx *gin.Context) {
+	userID, resumeID, _, ok := sectionParams(ctx, false)
+	if !ok {
+		return
+	}
+
+	var req WorkExperienceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	item, err := req.toEntity(resumeID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.uc.AddWorkExperience(ctx, userID, item); err != nil {
+		writeResumeError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, item)
+}
+
+func (c *ResumeController) UpdateWorkExperience(ctx *gin.Context) {
+	userID, resumeID, entryID, ok := sectionParams(ctx, true)
+	if !ok {
+		return
+	}
+
+	var req WorkExperienceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	item, err := req.toEntity(resumeID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	item.ID = entryID
+
+	if err := c.uc.UpdateWorkExperience(ctx, userID, item); err != nil {
+		writeResumeError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, item)
+}
+
+func (c *ResumeController) DeleteWorkExperience(ctx *gin.Context) {
+	userID, resumeID, entryID, ok := sectionParams(ctx, true)
+	if !ok {
+		return
+	}
+
+	if err := c.uc.DeleteWorkExperience(ctx, userID, resumeID, entryID); err != nil {
+		writeResumeError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c *ResumeController) AddEducation(ctx *gin.Context) {
+	userID, resumeID, _, ok := sectionParams(ctx, false)
+	if !ok {
+		return
+	}
+
+	var req EducationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	item, err := req.toEntity(resumeID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.uc.AddEducation(ctx, userID, item); err != nil {
+		writeResumeError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, item)
+}
+
+func (c *ResumeController) UpdateEducation(ctx *gin.Context) {
+	userID, resumeID, entryID, ok := sectionParams(ctx, true)
+	if !ok {
+		return
+	}
+
+	var req EducationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	item, err := req.toEntity(resumeID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	item.ID = entryID
+
+	if err := c.uc.UpdateEducation(ctx, userID, item); err != nil {
+		writeResumeError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, item)
+}
+
+func (c *ResumeController) DeleteEducation(ctx *gin.Context) {
+	userID, resumeID, entryID, ok := sectionParams(ctx, true)
+	if !ok {
+		return
+	}
+
+	if err := c.uc.DeleteEducation(ctx, userID, resumeID, entryID); err != nil {
+		writeResumeError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c *ResumeController) AddLanguage(ctx *gin.Context) {
+	userID, resumeID, _, ok := sectionParams(ctx, false)
+	if !ok {
+		return
+	}
+
+	var req LanguageRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item := &entity.LanguageSkill{
+		ResumeID: resumeID,
+		Language: req.Language,
+		Level:    req.Level,
+	}
+	if err := c.uc.AddLanguage(ctx, userID, item); err != nil {
+		writeResumeError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, item)
+}
+
+func (c *ResumeController) UpdateLanguage(ctx *gin.Context) {
+	userID, resumeID, entryID, ok := sectionParams(ctx, true)
+	if !ok {
+		return
+	}
+
+	var req LanguageRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item := &entity.LanguageSkill{
+		ID:       entryID,
+		ResumeID: resumeID,
+		Language: req.Language,
+		Level:    req.Level,
+	}
+	if err := c.uc.UpdateLanguage(ctx, userID, item); err != nil {
+		writeResumeError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, item)
+}
+
+func (c *ResumeController) DeleteLanguage(ctx *gin.Context) {
+	userID, resumeID, entryID, ok := sectionParams(ctx, true)
+	if !ok {
+		return
+	}
+
+	if err := c.uc.DeleteLanguage(ctx, userID, resumeID, entryID); err != nil {
+		writeResumeError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c *ResumeController) AddCertification(ctx *gin.Context) {
+	userID, resumeID, _, ok := sectionParams(ctx, false)
+	if !ok {
+		return
+	}
+
+	var req CertificationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	item, err := req.toEntity(resumeID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.uc.AddCertification(ctx, userID, item); err != nil {
+		writeResumeError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, item)
+}
+
+func (c *ResumeController) UpdateCertification(ctx *gin.Context) {
+	userID, resumeID, entryID, ok := sectionParams(ctx, true)
+	if !ok {
+		return
+	}
+
+	var req CertificationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	item, err := req.toEntity(resumeID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	item.ID = entryID
+
+	if err := c.uc.UpdateCertification(ctx, userID, item); err != nil {
+		writeResumeError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, item)
+}
+
+func (c *ResumeController) DeleteCertification(ctx *gin.Context) {
+	userID, resumeID, entryID, ok := sectionParams(ctx, true)
+	if !ok {
+		return
+	}
+
+	if err := c.uc.DeleteCertification(ctx, userID, resumeID, entryID); err != nil {
+		writeResumeError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
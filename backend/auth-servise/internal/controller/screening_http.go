@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type ScreeningController struct {
+	uc usecase.ScreeningUsecaseInterface
+}
+
+func NewScreeningController(uc usecase.ScreeningUsecaseInterface) *ScreeningController {
+	return &ScreeningController{uc: uc}
+}
+
+type ScreeningQuestionRequest struct {
+	// ID is set for existing questions so that answers stay linked to them.
+	ID       int64    `json:"id"`
+	Type     string   `json:"type" binding:"required"`
+	Text     string   `json:"text" binding:"required,max=1000"`
+	Options  []string `json:"options" binding:"max=20,dive,max=255"`
+	Required bool     `json:"required"`
+	// KnockoutAction is "reject", "flag" or empty.
+	KnockoutAction  string   `json:"knockout_action"`
+	ExpectedBool    *bool    `json:"expected_bool"`
+	AcceptedOptions []string `json:"accepted_options"`
+	MinValue        *float64 `json:"min_value"`
+	MaxValue        *float64 `json:"max_value"`
+}
+
+type ScreeningQuestionsRequest struct {
+	Questions []ScreeningQuestionRequest `json:"questions" binding:"dive"`
+}
+
+func (r *ScreeningQuestionsRequest) toEntity() []*entity.ScreeningQuestion {
+	questions := make([]*entity.ScreeningQuestion, 0, len(r.Questions))
+	for _, q := range r.Questions {
+		questions = append(questions, &entity.ScreeningQuestion{
+			ID:              q.ID,
+			Type:            q.Type,
+			Text:            q.Text,
+			Options:         q.Options,
+			Required:        q.Required,
+			KnockoutAction:  q.KnockoutAction,
+			ExpectedBool:    q.ExpectedBool,
+			AcceptedOptions: q.AcceptedOptions,
+			MinValue:        q.MinValue,
+			MaxValue:        q.MaxValue,
+		})
+	}
+	return questions
+}
+
+func writeScreeningError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, entity.ErrInvalidScreening), errors.Is(err, entity.ErrScreeningAnswersRequired):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		writeApplicationError(ctx, err)
+	}
+}
+
+// GetQuestions returns the screening questions of the vacancy.
+func (c *ScreeningController) GetQuestions(ctx *gin.Context) {
+	userID, vacancyID, ok := interviewParams(ctx)
+	if !ok {
+		return
+	}
+
+	questions, err := c.uc.GetQuestions(ctx, userID, vacancyID)
+	if err != nil {
+		writeScreeningError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"questions": questions})
+}
+
+// SetQuestions replaces the screening questions of the vacancy.
+func (c *ScreeningController) SetQuestions(ctx *gin.Context) {
+	userID, vacancyID, ok := interviewParams(ctx)
+	if !ok {
+		return
+	}
+
+	var req ScreeningQuestionsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	questions, err := c.uc.SetQuestions(ctx, userID, vacancyID, req.toEntity())
+	if err != nil {
+		writeScreeningError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"questions": questions})
+}
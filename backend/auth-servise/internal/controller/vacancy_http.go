@@ -29,6 +29,8 @@ type CreateVacancyRequest struct {
 	Company          string   `json:"company" binding:"required"`
 	Skills           []string `json:"skills"`
 	Education        string   `json:"education"`
+	AllowReapply     bool     `json:"allowReapply"`
+	ReapplyAfterDays int      `json:"reapplyAfterDays" binding:"min=0,max=365"`
 }
 
 type UpdateVacancyRequest struct {
@@ -43,6 +45,8 @@ type UpdateVacancyRequest struct {
 	Skills           []string `json:"skills"`
 	Education        string   `json:"education"`
 	Status           string   `json:"status" binding:"required"`
+	AllowReapply     bool     `json:"allowReapply"`
+	ReapplyAfterDays int      `json:"reapplyAfterDays" binding:"min=0,max=365"`
 }
 
 // Create godoc
@@ -90,6 +94,8 @@ func (c *VacancyController) Create(ctx *gin.Context) {
 		Status:           "active",
 		Skills:           req.Skills,
 		Education:        req.Education,
+		AllowReapply:     req.AllowReapply,
+		ReapplyAfterDays: req.ReapplyAfterDays,
 	}
 
 	if err := c.uc.Create(ctx.Request.Context(), vacancy); err != nil {
@@ -208,6 +214,8 @@ func (c *VacancyController) Update(ctx *gin.Context) {
 		Status:           req.Status,
 		Skills:           req.Skills,
 		Education:        req.Education,
+		AllowReapply:     req.AllowReapply,
+		ReapplyAfterDays: req.ReapplyAfterDays,
 	}
 
 	if err := c.uc.Update(ctx.Request.Context(), vacancy); err != nil {
@@ -0,0 +1,247 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/attachment"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type VerificationController struct {
+	uc usecase.VerificationUsecaseInterface
+}
+
+func NewVerificationController(uc usecase.VerificationUsecaseInterface) *VerificationController {
+	return &VerificationController{uc: uc}
+}
+
+type ConfirmCompanyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type VerificationReviewRequest struct {
+	Comment string `json:"comment"`
+}
+
+func writeVerificationError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, entity.ErrVerificationNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, entity.ErrInvalidVerification):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, entity.ErrVerificationPending), errors.Is(err, entity.ErrAlreadyVerified),
+		errors.Is(err, entity.ErrVerificationClosed):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, attachment.ErrUnsupportedMessageType):
+		ctx.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrPermissionDenied):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+	default:
+		writeAttachmentError(ctx, err)
+	}
+}
+
+// Submit sends the employer's verification request as multipart form data:
+// the fields "registration_number" and "company_email" and the documents in
+// "documents". Resubmitting a request sent back for more information keeps
+// the documents already attached.
+func (c *VerificationController) Submit(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	limit := int64(entity.MaxVerificationDocuments)*attachment.MaxSize + 1<<20
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, limit)
+	form, err := ctx.MultipartForm()
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			writeAttachmentError(ctx, attachment.ErrTooLarge)
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid form"})
+		return
+	}
+
+	verification := &entity.EmployerVerification{
+		RegistrationNumber: ctx.PostForm("registration_number"),
+		CompanyEmail:       ctx.PostForm("company_email"),
+	}
+	headers := form.File["documents"]
+	if len(headers) > entity.MaxVerificationDocuments {
+		writeVerificationError(ctx, fmt.Errorf("%w: at most %d documents are allowed", entity.ErrInvalidVerification, entity.MaxVerificationDocuments))
+		return
+	}
+	files := make([]usecase.DocumentFile, 0, len(headers))
+	for _, header := range headers {
+		if header.Size > attachment.MaxSize {
+			writeAttachmentError(ctx, attachment.ErrTooLarge)
+			return
+		}
+		f, err := header.Open()
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "failed to read file"})
+			return
+		}
+		data, err := io.ReadAll(io.LimitReader(f, attachment.MaxSize+1))
+		f.Close()
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "failed to read file"})
+			return
+		}
+		files = append(files, usecase.DocumentFile{Name: header.Filename, Data: data})
+	}
+
+	verification, err = c.uc.Submit(ctx, userID.(int64), verification, files)
+	if err != nil {
+		writeVerificationError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, verification)
+}
+
+// GetMine returns the employer's latest verification request.
+func (c *VerificationController) GetMine(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	verification, err := c.uc.GetMine(ctx, userID.(int64))
+	if err != nil {
+		writeVerificationError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, verification)
+}
+
+// ConfirmEmail confirms the company email with the token sent to it. The
+// token is the credential, so the route is not behind the auth middleware.
+func (c *VerificationController) ConfirmEmail(ctx *gin.Context) {
+	var req ConfirmCompanyEmailRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.uc.ConfirmEmail(ctx, req.Token); err != nil {
+		writeVerificationError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListQueue returns the admin review queue. ?status= picks another status
+// than pending.
+func (c *VerificationController) ListQueue(ctx *gin.Context) {
+	adminID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	limit, offset, ok := parsePagination(ctx)
+	if !ok {
+		return
+	}
+
+	verifications, total, err := c.uc.ListQueue(ctx, adminID.(int64), ctx.Query("status"), limit, offset)
+	if err != nil {
+		writeVerificationError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"verifications": verifications, "total": total, "limit": limit, "offset": offset})
+}
+
+func (c *VerificationController) Get(ctx *gin.Context) {
+	adminID, id, ok := interviewParams(ctx)
+	if !ok {
+		return
+	}
+
+	verification, err := c.uc.Get(ctx, adminID, id)
+	if err != nil {
+		writeVerificationError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, verification)
+}
+
+func (c *VerificationController) Approve(ctx *gin.Context) {
+	c.review(ctx, entity.VerificationApproved)
+}
+
+func (c *VerificationController) Reject(ctx *gin.Context) {
+	c.review(ctx, entity.VerificationRejected)
+}
+
+func (c *VerificationController) RequestInfo(ctx *gin.Context) {
+	c.review(ctx, entity.VerificationInfoRequested)
+}
+
+func (c *VerificationController) review(ctx *gin.Context, status string) {
+	adminID, id, ok := interviewParams(ctx)
+	if !ok {
+		return
+	}
+
+	// Комментарий для одобрения необязателен, поэтому тело может быть пустым
+	var req VerificationReviewRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	verification, err := c.uc.Review(ctx, adminID, id, status, req.Comment)
+	if err != nil {
+		writeVerificationError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, verification)
+}
+
+// Download streams a verification document behind a signed link.
+func (c *VerificationController) Download(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	viewerID, err1 := strconv.ParseInt(ctx.Query("viewer"), 10, 64)
+	expires, err2 := strconv.ParseInt(ctx.Query("expires"), 10, 64)
+	if err1 != nil || err2 != nil || ctx.Query("signature") == "" {
+		writeAttachmentError(ctx, attachment.ErrLinkSignature)
+		return
+	}
+
+	document, body, err := c.uc.DownloadDocument(ctx, id, attachment.Link{
+		ViewerID:  viewerID,
+		Expires:   expires,
+		Signature: ctx.Query("signature"),
+	})
+	if err != nil {
+		writeVerificationError(ctx, err)
+		return
+	}
+	defer body.Close()
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", document.FileName))
+	ctx.Header("Cache-Control", "private, no-store")
+	ctx.DataFromReader(http.StatusOK, document.Size, document.ContentType, body, nil)
+}
@@ -0,0 +1,184 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookController struct {
+	uc usecase.WebhookUsecaseInterface
+}
+
+func NewWebhookController(uc usecase.WebhookUsecaseInterface) *WebhookController {
+	return &WebhookController{uc: uc}
+}
+
+type WebhookEndpointRequest struct {
+	URL    string   `json:"url" binding:"required,max=2048"`
+	Events []string `json:"events" binding:"required,min=1"`
+	// Active is only read when updating; new endpoints are active.
+	Active *bool `json:"active"`
+}
+
+func writeWebhookError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, entity.ErrWebhookNotFound), errors.Is(err, entity.ErrWebhookDeliveryNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, entity.ErrInvalidWebhook):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrPermissionDenied):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// Create registers an endpoint. The response has the signing secret, which
+// is not shown again.
+func (c *WebhookController) Create(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req WebhookEndpointRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	endpoint := &entity.WebhookEndpoint{URL: req.URL, Events: req.Events}
+	if err := c.uc.CreateEndpoint(ctx, userID.(int64), endpoint); err != nil {
+		writeWebhookError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, endpoint)
+}
+
+func (c *WebhookController) List(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	endpoints, err := c.uc.ListEndpoints(ctx, userID.(int64))
+	if err != nil {
+		writeWebhookError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"endpoints": endpoints, "events": entity.WebhookEvents})
+}
+
+func (c *WebhookController) Get(ctx *gin.Context) {
+	userID, id, ok := interviewParams(ctx)
+	if !ok {
+		return
+	}
+
+	endpoint, err := c.uc.GetEndpoint(ctx, userID, id)
+	if err != nil {
+		writeWebhookError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, endpoint)
+}
+
+func (c *WebhookController) Update(ctx *gin.Context) {
+	userID, id, ok := interviewParams(ctx)
+	if !ok {
+		return
+	}
+
+	var req WebhookEndpointRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	endpoint := &entity.WebhookEndpoint{ID: id, URL: req.URL, Events: req.Events, Active: true}
+	if req.Active != nil {
+		endpoint.Active = *req.Active
+	}
+	if err := c.uc.UpdateEndpoint(ctx, userID, endpoint); err != nil {
+		writeWebhookError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, endpoint)
+}
+
+func (c *WebhookController) Delete(ctx *gin.Context) {
+	userID, id, ok := interviewParams(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.uc.DeleteEndpoint(ctx, userID, id); err != nil {
+		writeWebhookError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListDeliveries returns the endpoint's delivery log, newest first,
+// optionally filtered by ?status=pending|succeeded|failed.
+func (c *WebhookController) ListDeliveries(ctx *gin.Context) {
+	userID, id, ok := interviewParams(ctx)
+	if !ok {
+		return
+	}
+	limit, offset, ok := parsePagination(ctx)
+	if !ok {
+		return
+	}
+
+	deliveries, err := c.uc.ListDeliveries(ctx, userID, id, ctx.Query("status"), limit, offset)
+	if err != nil {
+		writeWebhookError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, deliveries)
+}
+
+// GetDelivery returns the delivery with the log of its attempts.
+func (c *WebhookController) GetDelivery(ctx *gin.Context) {
+	userID, id, ok := interviewParams(ctx)
+	if !ok {
+		return
+	}
+
+	delivery, err := c.uc.GetDelivery(ctx, userID, id)
+	if err != nil {
+		writeWebhookError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, delivery)
+}
+
+// Redeliver sends the delivery's event to the endpoint again.
+func (c *WebhookController) Redeliver(ctx *gin.Context) {
+	userID, id, ok := interviewParams(ctx)
+	if !ok {
+		return
+	}
+
+	delivery, err := c.uc.Redeliver(ctx, userID, id)
+	if err != nil {
+		writeWebhookError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, delivery)
+}
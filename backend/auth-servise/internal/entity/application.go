@@ -1,16 +1,119 @@
 package entity
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 type Application struct {
-	ID             int64     `json:"id" db:"id"`
-	UserID         int64     `json:"user_id" db:"user_id"`
-	VacancyID      int64     `json:"vacancy_id" db:"vacancy_id"`
-	ResumeID       int64     `json:"resume_id" db:"resume_id"`
-	Status         string    `json:"status" db:"status"`
-	CreatedAt      time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
-	ApplicantName  string    `json:"applicant_name" db:"-"`
-	ApplicantEmail string    `json:"applicant_email" db:"-"`
-	Resume         *Resume   `json:"resume,omitempty" db:"-"`
+	ID             int64                      `json:"id" db:"id"`
+	UserID         int64                      `json:"user_id" db:"user_id"`
+	VacancyID      int64                      `json:"vacancy_id" db:"vacancy_id"`
+	ResumeID       int64                      `json:"resume_id" db:"resume_id"`
+	Status         string                     `json:"status" db:"status"`
+	StageID        *int64                     `json:"stage_id,omitempty" db:"stage_id"`
+	Flagged        bool                       `json:"screening_flagged" db:"screening_flagged"`
+	CoverLetter    string                     `json:"cover_letter,omitempty" db:"cover_letter"`
+	CreatedAt      time.Time                  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time                  `json:"updated_at" db:"updated_at"`
+	ApplicantName  string                     `json:"applicant_name" db:"-"`
+	ApplicantEmail string                     `json:"applicant_email" db:"-"`
+	Resume         *Resume                    `json:"resume,omitempty" db:"-"`
+	Timeline       []*ApplicationStatusChange `json:"timeline,omitempty" db:"-"`
+	Review         *ApplicationReviewSummary  `json:"review,omitempty" db:"-"`
+	Answers        []*ScreeningAnswer         `json:"answers,omitempty" db:"-"`
+}
+
+// Application statuses.
+const (
+	ApplicationStatusPending     = "pending"
+	ApplicationStatusViewed      = "viewed"
+	ApplicationStatusShortlisted = "shortlisted"
+	ApplicationStatusInterview   = "interview"
+	ApplicationStatusOffer       = "offer"
+	ApplicationStatusHired       = "hired"
+	ApplicationStatusRejected    = "rejected"
+	ApplicationStatusWithdrawn   = "withdrawn"
+)
+
+// ApplicationStatusChange is one entry of an application's timeline.
+// FromStatus is empty for the entry created with the application.
+type ApplicationStatusChange struct {
+	ID            int64     `json:"id" db:"id"`
+	ApplicationID int64     `json:"application_id" db:"application_id"`
+	FromStatus    string    `json:"from_status" db:"from_status"`
+	ToStatus      string    `json:"to_status" db:"to_status"`
+	ActorID       *int64    `json:"actor_id,omitempty" db:"actor_id"`
+	ActorName     string    `json:"actor_name,omitempty" db:"actor_name"`
+	Comment       string    `json:"comment" db:"comment"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// employerTransitions are the moves the vacancy owner may make.
+var employerTransitions = map[string][]string{
+	ApplicationStatusPending:     {ApplicationStatusViewed, ApplicationStatusShortlisted, ApplicationStatusInterview, ApplicationStatusRejected},
+	ApplicationStatusViewed:      {ApplicationStatusShortlisted, ApplicationStatusInterview, ApplicationStatusRejected},
+	ApplicationStatusShortlisted: {ApplicationStatusInterview, ApplicationStatusOffer, ApplicationStatusRejected},
+	ApplicationStatusInterview:   {ApplicationStatusShortlisted, ApplicationStatusOffer, ApplicationStatusRejected},
+	ApplicationStatusOffer:       {ApplicationStatusHired, ApplicationStatusRejected},
+}
+
+// jobseekerTransitions are the moves the applicant may make: withdrawing
+// while the application is still open.
+var jobseekerTransitions = map[string][]string{
+	ApplicationStatusPending:     {ApplicationStatusWithdrawn},
+	ApplicationStatusViewed:      {ApplicationStatusWithdrawn},
+	ApplicationStatusShortlisted: {ApplicationStatusWithdrawn},
+	ApplicationStatusInterview:   {ApplicationStatusWithdrawn},
+	ApplicationStatusOffer:       {ApplicationStatusWithdrawn},
+}
+
+var applicationStatusNames = map[string]string{
+	ApplicationStatusPending:     "На рассмотрении",
+	ApplicationStatusViewed:      "Просмотрен",
+	ApplicationStatusShortlisted: "В шорт-листе",
+	ApplicationStatusInterview:   "Интервью",
+	ApplicationStatusOffer:       "Предложение",
+	ApplicationStatusHired:       "Принят",
+	ApplicationStatusRejected:    "Отказ",
+	ApplicationStatusWithdrawn:   "Отозван",
+}
+
+// ApplicationStatusName is the status as shown to people, e.g. in messages
+// and exports. Unknown statuses are returned as is.
+func ApplicationStatusName(status string) string {
+	if name, ok := applicationStatusNames[status]; ok {
+		return name
+	}
+	return status
+}
+
+func IsValidApplicationStatus(status string) bool {
+	switch status {
+	case ApplicationStatusPending, ApplicationStatusViewed, ApplicationStatusShortlisted,
+		ApplicationStatusInterview, ApplicationStatusOffer, ApplicationStatusHired,
+		ApplicationStatusRejected, ApplicationStatusWithdrawn:
+		return true
+	}
+	return false
+}
+
+// IsFinalApplicationStatus reports whether no further transitions are possible.
+func IsFinalApplicationStatus(status string) bool {
+	return status == ApplicationStatusHired || status == ApplicationStatusRejected || status == ApplicationStatusWithdrawn
+}
+
+// CanTransitionApplication reports whether a user with the given role may
+// move an application from one status to another. Admins may make any move
+// available to either party.
+func CanTransitionApplication(role UserRole, from, to string) bool {
+	switch role {
+	case RoleEmployer:
+		return slices.Contains(employerTransitions[from], to)
+	case RoleJobseeker:
+		return slices.Contains(jobseekerTransitions[from], to)
+	case RoleAdmin:
+		return slices.Contains(employerTransitions[from], to) || slices.Contains(jobseekerTransitions[from], to)
+	}
+	return false
 }
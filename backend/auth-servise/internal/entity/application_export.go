@@ -0,0 +1,47 @@
+package entity
+
+import "time"
+
+// Export job statuses.
+const (
+	ExportStatusPending = "pending"
+	ExportStatusRunning = "running"
+	ExportStatusDone    = "done"
+	ExportStatusFailed  = "failed"
+)
+
+// ApplicationExportRow is one application as written to a spreadsheet.
+type ApplicationExportRow struct {
+	ApplicationID   int64
+	ApplicantName   string
+	ApplicantEmail  string
+	VacancyTitle    string
+	Status          string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	ResumeTitle     string
+	Skills          []string
+	ExperienceYears float64
+	Tags            []string
+	AverageRating   *float64
+	Notes           []string
+}
+
+// ApplicationExport is a spreadsheet of the employer's applications that is
+// too large to stream in the request and is generated in the background.
+// DownloadURL is set once the file is ready.
+type ApplicationExport struct {
+	ID          int64             `json:"id" db:"id"`
+	EmployerID  int64             `json:"employer_id" db:"employer_id"`
+	Format      string            `json:"format" db:"format"`
+	Filter      ApplicationFilter `json:"filter" db:"-"`
+	Status      string            `json:"status" db:"status"`
+	RowCount    int               `json:"row_count" db:"row_count"`
+	FileName    string            `json:"file_name" db:"file_name"`
+	StorageKey  string            `json:"-" db:"storage_key"`
+	Size        int64             `json:"size" db:"size"`
+	Error       string            `json:"error,omitempty" db:"error"`
+	CreatedAt   time.Time         `json:"created_at" db:"created_at"`
+	FinishedAt  *time.Time        `json:"finished_at,omitempty" db:"finished_at"`
+	DownloadURL string            `json:"download_url,omitempty" db:"-"`
+}
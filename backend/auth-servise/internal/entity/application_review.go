@@ -0,0 +1,117 @@
+package entity
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	MinRating = 1
+	MaxRating = 5
+
+	MaxApplicationTags = 20
+	MaxTagLength       = 50
+)
+
+// ApplicationNote is a private note the employer's staff leave on an
+// application. It is never shown to the applicant.
+type ApplicationNote struct {
+	ID            int64     `json:"id" db:"id"`
+	ApplicationID int64     `json:"application_id" db:"application_id"`
+	AuthorID      int64     `json:"author_id" db:"author_id"`
+	AuthorName    string    `json:"author_name" db:"author_name"`
+	Body          string    `json:"body" db:"body"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ApplicationRating is one reviewer's score of an applicant.
+type ApplicationRating struct {
+	ApplicationID int64     `json:"application_id" db:"application_id"`
+	ReviewerID    int64     `json:"reviewer_id" db:"reviewer_id"`
+	ReviewerName  string    `json:"reviewer_name" db:"reviewer_name"`
+	Rating        int       `json:"rating" db:"rating"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ApplicationReviewSummary is what the employer listing shows about the
+// reviews of an application.
+type ApplicationReviewSummary struct {
+	Tags          []string `json:"tags"`
+	AverageRating *float64 `json:"average_rating,omitempty"`
+	RatingsCount  int      `json:"ratings_count"`
+}
+
+// ApplicationReview holds everything the employer's staff recorded about an
+// application.
+type ApplicationReview struct {
+	ApplicationID int64                `json:"application_id"`
+	Notes         []*ApplicationNote   `json:"notes"`
+	Ratings       []*ApplicationRating `json:"ratings"`
+	ApplicationReviewSummary
+}
+
+// ApplicationFilter narrows the employer application listing. Zero values
+// mean "any".
+type ApplicationFilter struct {
+	VacancyIDs []int64  `json:"vacancy_ids,omitempty"`
+	Statuses   []string `json:"statuses,omitempty"`
+	// CreatedFrom and CreatedTo bound the application date, inclusive.
+	CreatedFrom *time.Time `json:"created_from,omitempty"`
+	CreatedTo   *time.Time `json:"created_to,omitempty"`
+	// Tags keeps applications that have all of the tags.
+	Tags      []string `json:"tags,omitempty"`
+	MinRating *float64 `json:"min_rating,omitempty"`
+}
+
+// Validate normalizes the tags and checks the statuses and the date range.
+func (f *ApplicationFilter) Validate() error {
+	tags, err := NormalizeTags(f.Tags)
+	if err != nil {
+		return err
+	}
+	f.Tags = tags
+	for _, status := range f.Statuses {
+		if !IsValidApplicationStatus(status) {
+			return fmt.Errorf("%w: unknown status %q", ErrInvalidApplicationFilter, status)
+		}
+	}
+	if f.CreatedFrom != nil && f.CreatedTo != nil && f.CreatedFrom.After(*f.CreatedTo) {
+		return fmt.Errorf("%w: date range is reversed", ErrInvalidApplicationFilter)
+	}
+	if f.MinRating != nil && (*f.MinRating < MinRating || *f.MinRating > MaxRating) {
+		return fmt.Errorf("%w: min rating must be between %d and %d", ErrInvalidApplicationFilter, MinRating, MaxRating)
+	}
+	return nil
+}
+
+func ValidateRating(rating int) error {
+	if rating < MinRating || rating > MaxRating {
+		return ErrInvalidRating
+	}
+	return nil
+}
+
+// NormalizeTags trims and lower-cases tags and removes duplicates, keeping
+// the original order.
+func NormalizeTags(tags []string) ([]string, error) {
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.Join(strings.Fields(tag), " "))
+		if tag == "" || slices.Contains(result, tag) {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > MaxTagLength {
+			return nil, fmt.Errorf("%w: tag %q is longer than %d characters", ErrInvalidTags, tag, MaxTagLength)
+		}
+		result = append(result, tag)
+	}
+	if len(result) > MaxApplicationTags {
+		return nil, fmt.Errorf("%w: at most %d tags are allowed", ErrInvalidTags, MaxApplicationTags)
+	}
+	return result, nil
+}
@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tags, err := NormalizeTags([]string{" Strong  Go ", "", "strong go", "Remote"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"strong go", "remote"}, tags)
+
+	_, err = NormalizeTags([]string{strings.Repeat("я", MaxTagLength+1)})
+	assert.ErrorIs(t, err, ErrInvalidTags)
+
+	many := make([]string, MaxApplicationTags+1)
+	for i := range many {
+		many[i] = strings.Repeat("t", i+1)
+	}
+	_, err = NormalizeTags(many)
+	assert.ErrorIs(t, err, ErrInvalidTags)
+}
+
+func TestValidateRating(t *testing.T) {
+	assert.NoError(t, ValidateRating(1))
+	assert.NoError(t, ValidateRating(5))
+	assert.ErrorIs(t, ValidateRating(0), ErrInvalidRating)
+	assert.ErrorIs(t, ValidateRating(6), ErrInvalidRating)
+}
+
+func TestApplicationFilterValidate(t *testing.T) {
+	rating := func(v float64) *float64 { return &v }
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	filter := ApplicationFilter{
+		Statuses:    []string{ApplicationStatusPending, ApplicationStatusViewed},
+		CreatedFrom: &from,
+		CreatedTo:   &to,
+		Tags:        []string{" Go ", "go", "Remote"},
+		MinRating:   rating(4),
+	}
+	require.NoError(t, filter.Validate())
+	assert.Equal(t, []string{"go", "remote"}, filter.Tags)
+
+	invalid := []ApplicationFilter{
+		{Statuses: []string{"accepted"}},
+		{CreatedFrom: &to, CreatedTo: &from},
+		{MinRating: rating(0.5)},
+		{MinRating: rating(6)},
+	}
+	for _, filter := range invalid {
+		assert.ErrorIs(t, filter.Validate(), ErrInvalidApplicationFilter)
+	}
+
+	filter = ApplicationFilter{Tags: []string{strings.Repeat("t", MaxTagLength+1)}}
+	assert.ErrorIs(t, filter.Validate(), ErrInvalidTags)
+}
@@ -0,0 +1,84 @@
+package entity
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxCoverLetterLength limits the cover letter, in characters.
+const MaxCoverLetterLength = 5000
+
+var (
+	htmlTagPattern    = regexp.MustCompile(`<[^>]*>`)
+	blankLinesPattern = regexp.MustCompile(`\n{3,}`)
+)
+
+// NormalizeCoverLetter turns the cover letter into plain text: markup and
+// control characters are removed, line endings unified and runs of blank
+// lines collapsed. An empty letter is allowed.
+func NormalizeCoverLetter(letter string) (string, error) {
+	if !utf8.ValidString(letter) {
+		return "", fmt.Errorf("%w: not valid UTF-8", ErrInvalidCoverLetter)
+	}
+
+	letter = plainText(letter)
+	if utf8.RuneCountInString(letter) > MaxCoverLetterLength {
+		return "", fmt.Errorf("%w: at most %d characters are allowed", ErrInvalidCoverLetter, MaxCoverLetterLength)
+	}
+	return letter, nil
+}
+
+// plainText removes markup and control characters, unifies line endings,
+// trims trailing spaces and collapses runs of blank lines.
+func plainText(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = htmlTagPattern.ReplaceAllString(text, "")
+	text = strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\t' {
+			return r
+		}
+		if unicode.IsControl(r) || r == '\uFEFF' {
+			return -1
+		}
+		return r
+	}, text)
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRightFunc(line, unicode.IsSpace)
+	}
+	text = blankLinesPattern.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+	return strings.TrimSpace(text)
+}
+
+// CheckReapply decides whether the jobseeker may apply to the vacancy given
+// their previous applications to it. Applying again is only possible when
+// every previous application was withdrawn, the vacancy allows it and its
+// waiting period since the last withdrawal has passed.
+func CheckReapply(vacancy *Vacancy, previous []*Application, now time.Time) error {
+	var lastWithdrawal time.Time
+	for _, application := range previous {
+		if application.VacancyID != vacancy.ID {
+			continue
+		}
+		if application.Status != ApplicationStatusWithdrawn || !vacancy.AllowReapply {
+			return ErrAlreadyApplied
+		}
+		if application.UpdatedAt.After(lastWithdrawal) {
+			lastWithdrawal = application.UpdatedAt
+		}
+	}
+
+	if lastWithdrawal.IsZero() {
+		return nil
+	}
+	allowedAt := lastWithdrawal.AddDate(0, 0, vacancy.ReapplyAfterDays)
+	if now.Before(allowedAt) {
+		return fmt.Errorf("%w: possible after %s", ErrReapplyTooEarly, allowedAt.Format(time.RFC3339))
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeCoverLetter(t *testing.T) {
+	letter, err := NormalizeCoverLetter("  <p>Hello,</p>\r\n<script>alert(1)</script>\x00I am   \n\n\n\n\tinterested.  ")
+	require.NoError(t, err)
+	assert.Equal(t, "Hello,\nalert(1)I am\n\n\tinterested.", letter)
+
+	letter, err = NormalizeCoverLetter("")
+	require.NoError(t, err)
+	assert.Empty(t, letter)
+
+	_, err = NormalizeCoverLetter(strings.Repeat("я", MaxCoverLetterLength+1))
+	assert.ErrorIs(t, err, ErrInvalidCoverLetter)
+
+	_, err = NormalizeCoverLetter("bad \xff byte")
+	assert.ErrorIs(t, err, ErrInvalidCoverLetter)
+}
+
+func TestCheckReapply(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	vacancy := &Vacancy{ID: 1, AllowReapply: true, ReapplyAfterDays: 7}
+	withdrawn := &Application{VacancyID: 1, Status: ApplicationStatusWithdrawn, UpdatedAt: now.AddDate(0, 0, -10)}
+	other := &Application{VacancyID: 2, Status: ApplicationStatusPending}
+
+	assert.NoError(t, CheckReapply(vacancy, nil, now))
+	assert.NoError(t, CheckReapply(vacancy, []*Application{other}, now))
+	assert.NoError(t, CheckReapply(vacancy, []*Application{withdrawn, other}, now))
+
+	recent := &Application{VacancyID: 1, Status: ApplicationStatusWithdrawn, UpdatedAt: now.AddDate(0, 0, -2)}
+	assert.ErrorIs(t, CheckReapply(vacancy, []*Application{withdrawn, recent}, now), ErrReapplyTooEarly)
+
+	pending := &Application{VacancyID: 1, Status: ApplicationStatusPending}
+	assert.ErrorIs(t, CheckReapply(vacancy, []*Application{withdrawn, pending}, now), ErrAlreadyApplied)
+
+	rejected := &Application{VacancyID: 1, Status: ApplicationStatusRejected}
+	assert.ErrorIs(t, CheckReapply(vacancy, []*Application{rejected}, now), ErrAlreadyApplied)
+
+	strict := &Vacancy{ID: 1}
+	assert.ErrorIs(t, CheckReapply(strict, []*Application{withdrawn}, now), ErrAlreadyApplied)
+}
@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanTransitionApplication(t *testing.T) {
+	tests := []struct {
+		name string
+		role UserRole
+		from string
+		to   string
+		want bool
+	}{
+		{"employer views", RoleEmployer, ApplicationStatusPending, ApplicationStatusViewed, true},
+		{"employer shortlists", RoleEmployer, ApplicationStatusViewed, ApplicationStatusShortlisted, true},
+		{"employer makes offer after interview", RoleEmployer, ApplicationStatusInterview, ApplicationStatusOffer, true},
+		{"employer hires after offer", RoleEmployer, ApplicationStatusOffer, ApplicationStatusHired, true},
+		{"employer cannot hire without offer", RoleEmployer, ApplicationStatusPending, ApplicationStatusHired, false},
+		{"employer cannot withdraw", RoleEmployer, ApplicationStatusPending, ApplicationStatusWithdrawn, false},
+		{"employer cannot reopen rejected", RoleEmployer, ApplicationStatusRejected, ApplicationStatusPending, false},
+		{"jobseeker withdraws", RoleJobseeker, ApplicationStatusInterview, ApplicationStatusWithdrawn, true},
+		{"jobseeker cannot hire themselves", RoleJobseeker, ApplicationStatusOffer, ApplicationStatusHired, false},
+		{"jobseeker cannot withdraw after hire", RoleJobseeker, ApplicationStatusHired, ApplicationStatusWithdrawn, false},
+		{"admin can do employer moves", RoleAdmin, ApplicationStatusOffer, ApplicationStatusHired, true},
+		{"admin can withdraw", RoleAdmin, ApplicationStatusPending, ApplicationStatusWithdrawn, true},
+		{"admin cannot leave final status", RoleAdmin, ApplicationStatusWithdrawn, ApplicationStatusPending, false},
+		{"unknown status", RoleEmployer, ApplicationStatusPending, "accepted", false},
+		{"unknown role", UserRole("guest"), ApplicationStatusPending, ApplicationStatusViewed, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CanTransitionApplication(tt.role, tt.from, tt.to))
+		})
+	}
+}
+
+func TestFinalStatusesHaveNoTransitions(t *testing.T) {
+	statuses := []string{
+		ApplicationStatusPending, ApplicationStatusViewed, ApplicationStatusShortlisted, ApplicationStatusInterview,
+		ApplicationStatusOffer, ApplicationStatusHired, ApplicationStatusRejected, ApplicationStatusWithdrawn,
+	}
+	for _, from := range statuses {
+		assert.True(t, IsValidApplicationStatus(from))
+		if !IsFinalApplicationStatus(from) {
+			continue
+		}
+		for _, to := range statuses {
+			assert.False(t, CanTransitionApplication(RoleAdmin, from, to), "%s -> %s", from, to)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// ResumeAttachment is an uploaded file (PDF or DOCX CV) attached to a resume.
+// DownloadURL is a signed, expiring link issued to the current viewer.
+type ResumeAttachment struct {
+	ID          int64     `json:"id" db:"id"`
+	ResumeID    int64     `json:"resume_id" db:"resume_id"`
+	FileName    string    `json:"file_name" db:"file_name"`
+	ContentType string    `json:"content_type" db:"content_type"`
+	Size        int64     `json:"size" db:"size"`
+	StorageKey  string    `json:"-" db:"storage_key"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	DownloadURL string    `json:"download_url,omitempty" db:"-"`
+}
@@ -0,0 +1,44 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditEntryHashChain(t *testing.T) {
+	adminID, userID := int64(1), int64(7)
+	key := []byte("audit key")
+	first := &AuditEntry{
+		ActorID:    &adminID,
+		Action:     AuditUserBan,
+		TargetType: AuditTargetUser,
+		TargetID:   &userID,
+		Before:     AuditSnapshot(&User{ID: userID, Password: "secret"}),
+		IP:         "10.0.0.1",
+		CreatedAt:  time.Date(2024, 5, 1, 12, 0, 0, 123000, time.UTC),
+	}
+	first.Hash = first.ComputeHash(key)
+	assert.Len(t, first.Hash, 64)
+	assert.NotContains(t, string(first.Before), "secret", "snapshots follow the JSON encoding of the entity")
+
+	moscow := time.FixedZone("MSK", 3*60*60)
+	reread := *first
+	reread.CreatedAt = first.CreatedAt.In(moscow)
+	assert.Equal(t, first.Hash, reread.ComputeHash(key), "the time zone the time is read in does not matter")
+
+	empty := *first
+	empty.After = []byte{}
+	assert.Equal(t, first.Hash, empty.ComputeHash(key), "an empty snapshot is the same as none")
+
+	second := &AuditEntry{Action: AuditLogin, PrevHash: first.Hash, CreatedAt: first.CreatedAt}
+	hash := second.ComputeHash(key)
+	second.PrevHash = ""
+	assert.NotEqual(t, hash, second.ComputeHash(key), "the previous hash is covered")
+
+	tampered := *first
+	tampered.IP = "10.0.0.2"
+	assert.NotEqual(t, first.Hash, tampered.ComputeHash(key))
+	assert.NotEqual(t, first.Hash, first.ComputeHash([]byte("other key")), "the hash cannot be recomputed without the key")
+}
@@ -0,0 +1,33 @@
+package entity
+
+import "time"
+
+// Contact request statuses.
+const (
+	ContactRequestPending  = "pending"
+	ContactRequestAccepted = "accepted"
+	ContactRequestDeclined = "declined"
+)
+
+// ContactRequest is an employer asking a candidate to share their contact
+// information. Contacts stay hidden until the candidate accepts.
+type ContactRequest struct {
+	ID            int64      `json:"id" db:"id"`
+	EmployerID    int64      `json:"employer_id" db:"employer_id"`
+	JobseekerID   int64      `json:"jobseeker_id" db:"jobseeker_id"`
+	ResumeID      *int64     `json:"resume_id,omitempty" db:"resume_id"`
+	Message       string     `json:"message" db:"message"`
+	Status        string     `json:"status" db:"status"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	RespondedAt   *time.Time `json:"responded_at,omitempty" db:"responded_at"`
+	EmployerName  string     `json:"employer_name,omitempty" db:"employer_name"`
+	JobseekerName string     `json:"jobseeker_name,omitempty" db:"jobseeker_name"`
+}
+
+// BlockedEmployer is an employer the candidate hides their resumes from.
+type BlockedEmployer struct {
+	UserID       int64     `json:"user_id" db:"user_id"`
+	EmployerID   int64     `json:"employer_id" db:"employer_id"`
+	EmployerName string    `json:"employer_name" db:"employer_name"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
@@ -1,5 +1,158 @@
 package entity
 
+import "errors"
+
 type ErrorResponse struct {
 	Error string `json:"error"`
 }
+
+// ErrResumeSectionNotFound is returned when a resume section entry does not
+// exist or belongs to another resume.
+var ErrResumeSectionNotFound = errors.New("resume section entry not found")
+
+var (
+	// ErrContactRequestExists is returned when the employer already asked
+	// the candidate for contacts.
+	ErrContactRequestExists = errors.New("contact request already exists")
+	// ErrContactRequestNotFound is returned when a contact request does not
+	// exist or is addressed to someone else.
+	ErrContactRequestNotFound = errors.New("contact request not found")
+)
+
+// ErrAttachmentNotFound is returned when an attachment does not exist or
+// belongs to another resume.
+var ErrAttachmentNotFound = errors.New("attachment not found")
+
+var (
+	ErrApplicationNotFound = errors.New("application not found")
+	// ErrApplicationStatusConflict is returned when the application status
+	// changed between reading it and applying a transition.
+	ErrApplicationStatusConflict = errors.New("application status was changed concurrently")
+)
+
+// ErrVacancyNotFound is returned when a vacancy does not exist.
+var ErrVacancyNotFound = errors.New("vacancy not found")
+
+var (
+	ErrPipelineNotFound = errors.New("pipeline not found")
+	// ErrPipelineStageNotFound is returned when a stage does not belong to
+	// the pipeline assigned to the vacancy.
+	ErrPipelineStageNotFound = errors.New("pipeline stage not found")
+	ErrInvalidPipeline       = errors.New("invalid pipeline")
+)
+
+var (
+	ErrInterviewNotFound     = errors.New("interview not found")
+	ErrInterviewSlotNotFound = errors.New("interview slot not found")
+	ErrInvalidInterview      = errors.New("invalid interview")
+	// ErrInterviewConflict is returned when the time overlaps another
+	// scheduled interview of the candidate or one of the interviewers.
+	ErrInterviewConflict = errors.New("interview time is already booked")
+	// ErrInterviewStatusConflict is returned when the interview was
+	// scheduled or cancelled in the meantime.
+	ErrInterviewStatusConflict = errors.New("interview status was changed concurrently")
+)
+
+var (
+	// ErrApplicationNoteNotFound is returned when a note does not exist,
+	// belongs to another application or was written by someone else.
+	ErrApplicationNoteNotFound = errors.New("note not found")
+	ErrInvalidNote             = errors.New("invalid note")
+	ErrInvalidRating           = errors.New("rating must be between 1 and 5")
+	ErrInvalidTags             = errors.New("invalid tags")
+)
+
+// ErrInvalidApplicationFilter is returned for unknown statuses, a reversed
+// date range or an out of range rating in the application listing filter.
+var ErrInvalidApplicationFilter = errors.New("invalid application filter")
+
+var (
+	ErrInvalidScreening = errors.New("invalid screening questions")
+	// ErrScreeningAnswersRequired is returned when an application misses
+	// the answer to a required screening question.
+	ErrScreeningAnswersRequired = errors.New("screening answers are required")
+)
+
+var (
+	ErrInvalidCoverLetter = errors.New("invalid cover letter")
+	// ErrAlreadyApplied is returned when the jobseeker has an application
+	// to the vacancy that was not withdrawn, or the vacancy does not allow
+	// applying again.
+	ErrAlreadyApplied = errors.New("user already applied for this vacancy")
+	// ErrReapplyTooEarly is returned when the jobseeker withdrew recently
+	// and the vacancy's waiting period has not passed yet.
+	ErrReapplyTooEarly = errors.New("too early to apply for this vacancy again")
+)
+
+var (
+	// ErrInvalidMessage is returned for an empty or too long message or one
+	// with too many attachments.
+	ErrInvalidMessage = errors.New("invalid message")
+	// ErrMessageAttachmentNotFound is returned when a message attachment
+	// does not exist.
+	ErrMessageAttachmentNotFound = errors.New("message attachment not found")
+)
+
+var (
+	ErrExportNotFound = errors.New("export not found")
+	// ErrExportNotReady is returned when downloading an export that is
+	// still being generated or failed.
+	ErrExportNotReady = errors.New("export is not ready")
+)
+
+var (
+	ErrInvalidWebhook          = errors.New("invalid webhook endpoint")
+	ErrWebhookNotFound         = errors.New("webhook endpoint not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+)
+
+var (
+	ErrNotificationNotFound = errors.New("notification not found")
+	// ErrInvalidNotificationPreference is returned for an unknown
+	// notification type or a conflicting choice of channels.
+	ErrInvalidNotificationPreference = errors.New("invalid notification preference")
+)
+
+var (
+	ErrInvalidOrganization  = errors.New("invalid organization")
+	ErrOrganizationNotFound = errors.New("organization not found")
+	// ErrAlreadyInOrganization is returned when an account that already
+	// belongs to an organization creates or joins another one.
+	ErrAlreadyInOrganization = errors.New("user already belongs to an organization")
+	ErrInviteNotFound        = errors.New("invite not found")
+	// ErrInviteExpired is returned for an invite that expired or was
+	// already accepted.
+	ErrInviteExpired = errors.New("invite has expired")
+	// ErrLastOwner is returned when the only owner of an organization would
+	// leave or lose the role.
+	ErrLastOwner = errors.New("organization must have an owner")
+)
+
+var (
+	ErrInvalidVerification  = errors.New("invalid verification request")
+	ErrVerificationNotFound = errors.New("verification request not found")
+	// ErrVerificationPending is returned when the employer submits a new
+	// request while another one is waiting for review.
+	ErrVerificationPending = errors.New("verification request is already under review")
+	ErrAlreadyVerified     = errors.New("employer is already verified")
+	// ErrVerificationClosed is returned when an admin acts on a request that
+	// is no longer waiting for review.
+	ErrVerificationClosed = errors.New("verification request is not under review")
+)
+
+var (
+	// ErrInvalidStatsRange is returned for an unknown interval or a date
+	// range that is reversed or has too many points.
+	ErrInvalidStatsRange = errors.New("invalid statistics range")
+)
+
+var (
+	// ErrAccountSuspended, ErrAccountBanned and ErrAccountDeleted are
+	// returned when a restricted account signs in or uses its token.
+	ErrAccountSuspended = errors.New("account is suspended")
+	ErrAccountBanned    = errors.New("account is banned")
+	ErrAccountDeleted   = errors.New("account is deleted")
+	// ErrInvalidAccountAction is returned for a suspension that ends in the
+	// past, a ban without a reason or a state change the account is not in.
+	ErrInvalidAccountAction = errors.New("invalid account action")
+)
@@ -0,0 +1,140 @@
+package entity
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Interview statuses.
+const (
+	// InterviewStatusProposed means the employer offered slots and the
+	// candidate has not picked one yet.
+	InterviewStatusProposed  = "proposed"
+	InterviewStatusScheduled = "scheduled"
+	InterviewStatusCancelled = "cancelled"
+)
+
+const (
+	MinInterviewDuration = 15
+	MaxInterviewDuration = 480
+	MaxInterviewSlots    = 10
+	MaxInterviewers      = 10
+)
+
+// Interview is a meeting with the applicant. Interviewers are e-mail
+// addresses; they are invited along with the candidate and are checked for
+// double-booking.
+type Interview struct {
+	ID              int64            `json:"id" db:"id"`
+	ApplicationID   int64            `json:"application_id" db:"application_id"`
+	EmployerID      int64            `json:"employer_id" db:"employer_id"`
+	CandidateID     int64            `json:"candidate_id" db:"candidate_id"`
+	Title           string           `json:"title" db:"title"`
+	DurationMinutes int              `json:"duration_minutes" db:"duration_minutes"`
+	Location        string           `json:"location" db:"location"`
+	VideoLink       string           `json:"video_link" db:"video_link"`
+	Interviewers    []string         `json:"interviewers" db:"interviewers"`
+	Notes           string           `json:"notes" db:"notes"`
+	Status          string           `json:"status" db:"status"`
+	StartsAt        *time.Time       `json:"starts_at,omitempty" db:"starts_at"`
+	EndsAt          *time.Time       `json:"ends_at,omitempty" db:"ends_at"`
+	Sequence        int              `json:"sequence" db:"sequence"`
+	Slots           []*InterviewSlot `json:"slots" db:"-"`
+	CreatedAt       time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+type InterviewSlot struct {
+	ID          int64     `json:"id" db:"id"`
+	InterviewID int64     `json:"interview_id" db:"interview_id"`
+	StartsAt    time.Time `json:"starts_at" db:"starts_at"`
+}
+
+// Duration returns the interview length.
+func (i *Interview) Duration() time.Duration {
+	return time.Duration(i.DurationMinutes) * time.Minute
+}
+
+// UID is the stable iCalendar UID of the interview invite.
+func (i *Interview) UID() string {
+	return fmt.Sprintf("interview-%d@job-search-platform", i.ID)
+}
+
+// Slot returns the proposed slot with the given id or nil.
+func (i *Interview) Slot(id int64) *InterviewSlot {
+	for _, slot := range i.Slots {
+		if slot.ID == id {
+			return slot
+		}
+	}
+	return nil
+}
+
+// Validate normalizes the interview details: trims the text fields,
+// lower-cases and deduplicates interviewer addresses and checks the
+// duration and the video link.
+func (i *Interview) Validate() error {
+	i.Title = strings.TrimSpace(i.Title)
+	i.Location = strings.TrimSpace(i.Location)
+	i.VideoLink = strings.TrimSpace(i.VideoLink)
+	if i.Title == "" {
+		return fmt.Errorf("%w: title is required", ErrInvalidInterview)
+	}
+	if i.DurationMinutes < MinInterviewDuration || i.DurationMinutes > MaxInterviewDuration {
+		return fmt.Errorf("%w: duration must be between %d and %d minutes", ErrInvalidInterview, MinInterviewDuration, MaxInterviewDuration)
+	}
+	if i.VideoLink != "" {
+		u, err := url.Parse(i.VideoLink)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("%w: video link must be an http(s) URL", ErrInvalidInterview)
+		}
+	}
+
+	interviewers := make([]string, 0, len(i.Interviewers))
+	for _, value := range i.Interviewers {
+		addr, err := mail.ParseAddress(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("%w: invalid interviewer address %q", ErrInvalidInterview, value)
+		}
+		email := strings.ToLower(addr.Address)
+		if !slices.Contains(interviewers, email) {
+			interviewers = append(interviewers, email)
+		}
+	}
+	if len(interviewers) > MaxInterviewers {
+		return fmt.Errorf("%w: at most %d interviewers are allowed", ErrInvalidInterview, MaxInterviewers)
+	}
+	i.Interviewers = interviewers
+	return nil
+}
+
+// ValidateSlots checks proposed start times: at least one, no more than
+// MaxInterviewSlots, all in the future and distinct. The slots are returned
+// sorted.
+func ValidateSlots(slots []time.Time, now time.Time) ([]time.Time, error) {
+	if len(slots) == 0 {
+		return nil, fmt.Errorf("%w: at least one slot is required", ErrInvalidInterview)
+	}
+	if len(slots) > MaxInterviewSlots {
+		return nil, fmt.Errorf("%w: at most %d slots are allowed", ErrInvalidInterview, MaxInterviewSlots)
+	}
+
+	sorted := make([]time.Time, len(slots))
+	for n, slot := range slots {
+		if !slot.After(now) {
+			return nil, fmt.Errorf("%w: slot %s is in the past", ErrInvalidInterview, slot.Format(time.RFC3339))
+		}
+		sorted[n] = slot.UTC()
+	}
+	slices.SortFunc(sorted, func(a, b time.Time) int { return a.Compare(b) })
+	for n := 1; n < len(sorted); n++ {
+		if sorted[n].Equal(sorted[n-1]) {
+			return nil, fmt.Errorf("%w: duplicate slot %s", ErrInvalidInterview, sorted[n].Format(time.RFC3339))
+		}
+	}
+	return sorted, nil
+}
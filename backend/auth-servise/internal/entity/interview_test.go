@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterviewValidate(t *testing.T) {
+	interview := &Interview{
+		Title:           "  Tech interview ",
+		DurationMinutes: 60,
+		VideoLink:       " https://meet.example/abc ",
+		Interviewers:    []string{"Lead <Lead@Acme.example>", "lead@acme.example", " cto@acme.example "},
+	}
+	require.NoError(t, interview.Validate())
+	assert.Equal(t, "Tech interview", interview.Title)
+	assert.Equal(t, "https://meet.example/abc", interview.VideoLink)
+	assert.Equal(t, []string{"lead@acme.example", "cto@acme.example"}, interview.Interviewers)
+
+	invalid := map[string]*Interview{
+		"no title":        {DurationMinutes: 60},
+		"too short":       {Title: "Call", DurationMinutes: 5},
+		"too long":        {Title: "Call", DurationMinutes: 600},
+		"bad link":        {Title: "Call", DurationMinutes: 30, VideoLink: "javascript:alert(1)"},
+		"bad interviewer": {Title: "Call", DurationMinutes: 30, Interviewers: []string{"not an email"}},
+	}
+	for name, interview := range invalid {
+		assert.ErrorIs(t, interview.Validate(), ErrInvalidInterview, name)
+	}
+}
+
+func TestValidateSlots(t *testing.T) {
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	msk := time.FixedZone("MSK", 3*60*60)
+
+	slots, err := ValidateSlots([]time.Time{
+		time.Date(2024, time.March, 3, 10, 0, 0, 0, time.UTC),
+		time.Date(2024, time.March, 2, 10, 0, 0, 0, msk),
+	}, now)
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{
+		time.Date(2024, time.March, 2, 7, 0, 0, 0, time.UTC),
+		time.Date(2024, time.March, 3, 10, 0, 0, 0, time.UTC),
+	}, slots)
+
+	_, err = ValidateSlots(nil, now)
+	assert.ErrorIs(t, err, ErrInvalidInterview)
+
+	_, err = ValidateSlots([]time.Time{now.Add(-time.Hour)}, now)
+	assert.ErrorIs(t, err, ErrInvalidInterview)
+
+	_, err = ValidateSlots([]time.Time{
+		time.Date(2024, time.March, 2, 10, 0, 0, 0, msk),
+		time.Date(2024, time.March, 2, 7, 0, 0, 0, time.UTC),
+	}, now)
+	assert.ErrorIs(t, err, ErrInvalidInterview)
+
+	tooMany := make([]time.Time, MaxInterviewSlots+1)
+	for i := range tooMany {
+		tooMany[i] = now.Add(time.Duration(i+1) * time.Hour)
+	}
+	_, err = ValidateSlots(tooMany, now)
+	assert.ErrorIs(t, err, ErrInvalidInterview)
+}
@@ -0,0 +1,99 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+	"unicode/utf8"
+)
+
+// Message kinds. System messages are written by the service itself, e.g.
+// when the application status changes, and have no sender.
+const (
+	MessageKindUser   = "user"
+	MessageKindSystem = "system"
+)
+
+const (
+	// MaxMessageLength limits the message body, in characters.
+	MaxMessageLength = 5000
+	// MaxMessageAttachments limits the files attached to one message.
+	MaxMessageAttachments = 5
+	// OfflineAfter is how long after the last visit to a thread a
+	// participant is considered offline and gets new messages by email.
+	OfflineAfter = 5 * time.Minute
+)
+
+// ApplicationMessage is a message in the conversation about an application
+// between the applicant and the vacancy owner.
+type ApplicationMessage struct {
+	ID            int64                `json:"id" db:"id"`
+	ApplicationID int64                `json:"application_id" db:"application_id"`
+	SenderID      *int64               `json:"sender_id,omitempty" db:"sender_id"`
+	SenderName    string               `json:"sender_name,omitempty" db:"sender_name"`
+	Kind          string               `json:"kind" db:"kind"`
+	Body          string               `json:"body" db:"body"`
+	CreatedAt     time.Time            `json:"created_at" db:"created_at"`
+	Attachments   []*MessageAttachment `json:"attachments" db:"-"`
+}
+
+// MessageAttachment is a file sent with a message. DownloadURL is a signed,
+// expiring link issued to the current viewer.
+type MessageAttachment struct {
+	ID            int64     `json:"id" db:"id"`
+	MessageID     int64     `json:"message_id" db:"message_id"`
+	ApplicationID int64     `json:"-" db:"application_id"`
+	FileName      string    `json:"file_name" db:"file_name"`
+	ContentType   string    `json:"content_type" db:"content_type"`
+	Size          int64     `json:"size" db:"size"`
+	StorageKey    string    `json:"-" db:"storage_key"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	DownloadURL   string    `json:"download_url,omitempty" db:"-"`
+}
+
+// MessageThread is the summary of one conversation in the user's inbox.
+type MessageThread struct {
+	ApplicationID     int64      `json:"application_id" db:"application_id"`
+	VacancyID         int64      `json:"vacancy_id" db:"vacancy_id"`
+	VacancyTitle      string     `json:"vacancy_title" db:"vacancy_title"`
+	ApplicantName     string     `json:"applicant_name" db:"applicant_name"`
+	ApplicationStatus string     `json:"application_status" db:"application_status"`
+	LastMessage       string     `json:"last_message" db:"last_message"`
+	LastMessageAt     *time.Time `json:"last_message_at,omitempty" db:"last_message_at"`
+	UnreadCount       int        `json:"unread_count" db:"unread_count"`
+}
+
+// NormalizeMessage turns the message body into plain text the same way as
+// a cover letter. An empty body is allowed when files are attached.
+func NormalizeMessage(body string, attachments int) (string, error) {
+	if !utf8.ValidString(body) {
+		return "", fmt.Errorf("%w: not valid UTF-8", ErrInvalidMessage)
+	}
+	if attachments > MaxMessageAttachments {
+		return "", fmt.Errorf("%w: at most %d files are allowed", ErrInvalidMessage, MaxMessageAttachments)
+	}
+
+	body = plainText(body)
+	if body == "" && attachments == 0 {
+		return "", fmt.Errorf("%w: message is empty", ErrInvalidMessage)
+	}
+	if utf8.RuneCountInString(body) > MaxMessageLength {
+		return "", fmt.Errorf("%w: at most %d characters are allowed", ErrInvalidMessage, MaxMessageLength)
+	}
+	return body, nil
+}
+
+// IsOffline reports whether a participant last seen at lastSeen is away
+// from the thread. A participant who never opened it is offline.
+func IsOffline(lastSeen *time.Time, now time.Time) bool {
+	return lastSeen == nil || now.Sub(*lastSeen) > OfflineAfter
+}
+
+// StatusChangeMessage is the body of the system message posted to the
+// thread when the application moves to status to.
+func StatusChangeMessage(to, comment string) string {
+	body := fmt.Sprintf("Статус отклика изменен: «%s»", ApplicationStatusName(to))
+	if comment != "" {
+		body += "\n\n" + comment
+	}
+	return body
+}
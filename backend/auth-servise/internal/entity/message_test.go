@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeMessage(t *testing.T) {
+	body, err := NormalizeMessage("  <b>Hello</b>\r\n\r\n\r\n\r\nSee attached  ", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello\n\nSee attached", body)
+
+	body, err = NormalizeMessage(" ", 1)
+	require.NoError(t, err)
+	assert.Empty(t, body, "files without text")
+
+	invalid := []struct {
+		body        string
+		attachments int
+	}{
+		{" \n ", 0},
+		{"\xff", 0},
+		{strings.Repeat("я", MaxMessageLength+1), 0},
+		{"files", MaxMessageAttachments + 1},
+	}
+	for _, tt := range invalid {
+		_, err := NormalizeMessage(tt.body, tt.attachments)
+		assert.ErrorIs(t, err, ErrInvalidMessage)
+	}
+}
+
+func TestIsOffline(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	recent := now.Add(-time.Minute)
+	old := now.Add(-OfflineAfter - time.Second)
+
+	assert.True(t, IsOffline(nil, now))
+	assert.False(t, IsOffline(&recent, now))
+	assert.True(t, IsOffline(&old, now))
+}
+
+func TestStatusChangeMessage(t *testing.T) {
+	assert.Equal(t, "Статус отклика изменен: «Интервью»", StatusChangeMessage(ApplicationStatusInterview, ""))
+	assert.Equal(t, "Статус отклика изменен: «Отказ»\n\nНе хватает опыта", StatusChangeMessage(ApplicationStatusRejected, "Не хватает опыта"))
+}
@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Notification types.
+const (
+	NotificationApplicationStatus    = "application.status_changed"
+	NotificationApplicationWithdrawn = "application.withdrawn"
+	NotificationMessage              = "message.new"
+	NotificationVacancyModerated     = "vacancy.moderated"
+	NotificationVerification         = "employer.verification"
+)
+
+// NotificationTypes lists every type in the order the settings show them.
+var NotificationTypes = []string{
+	NotificationApplicationStatus,
+	NotificationApplicationWithdrawn,
+	NotificationMessage,
+	NotificationVacancyModerated,
+	NotificationVerification,
+}
+
+// Notification is an event shown to the user in the notification center.
+// SkipEmail is set by the publisher when an immediate email would be
+// redundant, e.g. the recipient is reading the thread right now.
+type Notification struct {
+	ID        int64      `json:"id" db:"id"`
+	UserID    int64      `json:"user_id" db:"user_id"`
+	Type      string     `json:"type" db:"type"`
+	Title     string     `json:"title" db:"title"`
+	Body      string     `json:"body" db:"body"`
+	Link      string     `json:"link,omitempty" db:"link"`
+	ReadAt    *time.Time `json:"read_at,omitempty" db:"read_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	SkipEmail bool       `json:"-" db:"-"`
+}
+
+// NotificationPreference is how the user receives notifications of one
+// type: in the notification center, by email right away and in the daily
+// digest email.
+type NotificationPreference struct {
+	Type   string `json:"type" db:"type"`
+	InApp  bool   `json:"in_app" db:"in_app"`
+	Email  bool   `json:"email" db:"email"`
+	Digest bool   `json:"digest" db:"digest"`
+}
+
+// DefaultNotificationPreference is used until the user changes the settings
+// of the type.
+func DefaultNotificationPreference(notificationType string) *NotificationPreference {
+	return &NotificationPreference{Type: notificationType, InApp: true, Email: true}
+}
+
+func (p *NotificationPreference) Validate() error {
+	if !slices.Contains(NotificationTypes, p.Type) {
+		return fmt.Errorf("%w: unknown notification type %q", ErrInvalidNotificationPreference, p.Type)
+	}
+	if p.Email && p.Digest {
+		return fmt.Errorf("%w: choose either email or digest for %s", ErrInvalidNotificationPreference, p.Type)
+	}
+	return nil
+}
+
+// NotificationDigest is the unsent digest notifications of one user.
+type NotificationDigest struct {
+	UserID        int64
+	Notifications []*Notification
+}
@@ -0,0 +1,116 @@
+package entity
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Organization member roles. Owners manage the organization and its
+// members, recruiters manage vacancies and applications, viewers only read
+// them.
+const (
+	OrganizationRoleOwner     = "owner"
+	OrganizationRoleRecruiter = "recruiter"
+	OrganizationRoleViewer    = "viewer"
+)
+
+// OrganizationSizes are the allowed company sizes, by number of employees.
+var OrganizationSizes = []string{"1-10", "11-50", "51-200", "201-1000", "1000+"}
+
+// InviteTTL is how long an invite to an organization can be accepted.
+const InviteTTL = 7 * 24 * time.Hour
+
+// Organization is a company whose employer accounts share vacancies and
+// applications.
+type Organization struct {
+	ID          int64     `json:"id" db:"id"`
+	Name        string    `json:"name" db:"name"`
+	LogoURL     string    `json:"logo_url" db:"logo_url"`
+	Description string    `json:"description" db:"description"`
+	Website     string    `json:"website" db:"website"`
+	Size        string    `json:"size" db:"size"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Validate trims the profile and checks the name, the links and the size.
+func (o *Organization) Validate() error {
+	o.Name = strings.TrimSpace(o.Name)
+	o.Description = strings.TrimSpace(o.Description)
+	o.LogoURL = strings.TrimSpace(o.LogoURL)
+	o.Website = strings.TrimSpace(o.Website)
+
+	if o.Name == "" || len(o.Name) > 255 {
+		return fmt.Errorf("%w: name is required and must be at most 255 bytes", ErrInvalidOrganization)
+	}
+	if len(o.Description) > 5000 {
+		return fmt.Errorf("%w: description is too long", ErrInvalidOrganization)
+	}
+	for field, link := range map[string]string{"logo_url": o.LogoURL, "website": o.Website} {
+		if link == "" {
+			continue
+		}
+		u, err := url.Parse(link)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("%w: %s must be an absolute http or https URL", ErrInvalidOrganization, field)
+		}
+	}
+	if o.Size != "" && !slices.Contains(OrganizationSizes, o.Size) {
+		return fmt.Errorf("%w: size must be one of %s", ErrInvalidOrganization, strings.Join(OrganizationSizes, ", "))
+	}
+	return nil
+}
+
+// OrganizationMember is an employer account in an organization. An account
+// belongs to at most one organization.
+type OrganizationMember struct {
+	OrganizationID int64     `json:"organization_id" db:"organization_id"`
+	UserID         int64     `json:"user_id" db:"user_id"`
+	Name           string    `json:"name" db:"name"`
+	Email          string    `json:"email" db:"email"`
+	Role           string    `json:"role" db:"role"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+}
+
+// OrganizationInvite is an invitation sent by email to join an
+// organization. Token is only sent in the email.
+type OrganizationInvite struct {
+	ID             int64      `json:"id" db:"id"`
+	OrganizationID int64      `json:"organization_id" db:"organization_id"`
+	Email          string     `json:"email" db:"email"`
+	Role           string     `json:"role" db:"role"`
+	Token          string     `json:"-" db:"token"`
+	InvitedBy      int64      `json:"invited_by" db:"invited_by"`
+	ExpiresAt      time.Time  `json:"expires_at" db:"expires_at"`
+	AcceptedAt     *time.Time `json:"accepted_at,omitempty" db:"accepted_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+}
+
+// Validate normalizes the email and checks the role. Owners are not
+// invited; an owner promotes a member instead.
+func (i *OrganizationInvite) Validate() error {
+	address, err := mail.ParseAddress(strings.TrimSpace(i.Email))
+	if err != nil {
+		return fmt.Errorf("%w: invalid email", ErrInvalidOrganization)
+	}
+	i.Email = strings.ToLower(address.Address)
+	if i.Role != OrganizationRoleRecruiter && i.Role != OrganizationRoleViewer {
+		return fmt.Errorf("%w: role must be recruiter or viewer", ErrInvalidOrganization)
+	}
+	return nil
+}
+
+// IsValidOrganizationRole reports whether role is a member role.
+func IsValidOrganizationRole(role string) bool {
+	return role == OrganizationRoleOwner || role == OrganizationRoleRecruiter || role == OrganizationRoleViewer
+}
+
+// CanManageVacancies reports whether a member with the role may change
+// vacancies and applications of the organization.
+func CanManageVacancies(role string) bool {
+	return role == OrganizationRoleOwner || role == OrganizationRoleRecruiter
+}
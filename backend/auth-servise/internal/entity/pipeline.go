@@ -0,0 +1,110 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaxPipelineStages limits the number of stages in a pipeline template.
+const MaxPipelineStages = 20
+
+// Pipeline is an employer's hiring pipeline template. A vacancy uses at most
+// one pipeline; its applications are placed on the pipeline's stages.
+type Pipeline struct {
+	ID         int64            `json:"id" db:"id"`
+	EmployerID int64            `json:"employer_id" db:"employer_id"`
+	Name       string           `json:"name" db:"name"`
+	Stages     []*PipelineStage `json:"stages" db:"-"`
+	CreatedAt  time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+type PipelineStage struct {
+	ID         int64  `json:"id" db:"id"`
+	PipelineID int64  `json:"pipeline_id" db:"pipeline_id"`
+	Name       string `json:"name" db:"name"`
+	Position   int    `json:"position" db:"position"`
+}
+
+// PipelineColumn is one stage of the kanban board with its applications.
+type PipelineColumn struct {
+	Stage        *PipelineStage `json:"stage"`
+	Applications []*Application `json:"applications"`
+}
+
+// PipelineBoard groups the applications of a vacancy by pipeline stage.
+type PipelineBoard struct {
+	VacancyID int64             `json:"vacancy_id"`
+	Pipeline  *Pipeline         `json:"pipeline"`
+	Columns   []*PipelineColumn `json:"columns"`
+}
+
+// Validate trims the pipeline and stage names, checks that they are set and
+// that stage names are unique, and numbers the stages in the given order.
+func (p *Pipeline) Validate() error {
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidPipeline)
+	}
+	if len(p.Stages) == 0 {
+		return fmt.Errorf("%w: at least one stage is required", ErrInvalidPipeline)
+	}
+	if len(p.Stages) > MaxPipelineStages {
+		return fmt.Errorf("%w: at most %d stages are allowed", ErrInvalidPipeline, MaxPipelineStages)
+	}
+
+	seen := make(map[string]bool, len(p.Stages))
+	for i, stage := range p.Stages {
+		stage.Name = strings.TrimSpace(stage.Name)
+		if stage.Name == "" {
+			return fmt.Errorf("%w: stage name is required", ErrInvalidPipeline)
+		}
+		key := strings.ToLower(stage.Name)
+		if seen[key] {
+			return fmt.Errorf("%w: duplicate stage %q", ErrInvalidPipeline, stage.Name)
+		}
+		seen[key] = true
+		stage.Position = i
+	}
+	return nil
+}
+
+// Stage returns the stage with the given id or nil.
+func (p *Pipeline) Stage(id int64) *PipelineStage {
+	for _, stage := range p.Stages {
+		if stage.ID == id {
+			return stage
+		}
+	}
+	return nil
+}
+
+// NewPipelineBoard lays the applications out on the pipeline stages. An
+// application that is not on any of the stages yet is shown on the first one.
+func NewPipelineBoard(vacancyID int64, pipeline *Pipeline, applications []*Application) *PipelineBoard {
+	board := &PipelineBoard{
+		VacancyID: vacancyID,
+		Pipeline:  pipeline,
+		Columns:   make([]*PipelineColumn, len(pipeline.Stages)),
+	}
+	index := make(map[int64]int, len(pipeline.Stages))
+	for i, stage := range pipeline.Stages {
+		board.Columns[i] = &PipelineColumn{Stage: stage, Applications: []*Application{}}
+		index[stage.ID] = i
+	}
+	if len(board.Columns) == 0 {
+		return board
+	}
+
+	for _, application := range applications {
+		column := 0
+		if application.StageID != nil {
+			if i, ok := index[*application.StageID]; ok {
+				column = i
+			}
+		}
+		board.Columns[column].Applications = append(board.Columns[column].Applications, application)
+	}
+	return board
+}
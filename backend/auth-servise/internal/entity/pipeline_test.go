@@ -0,0 +1,76 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPipelineValidate(t *testing.T) {
+	pipeline := &Pipeline{
+		Name: " Engineering ",
+		Stages: []*PipelineStage{
+			{Name: "Screening"},
+			{Name: " Tech interview "},
+			{Name: "Offer"},
+		},
+	}
+	require.NoError(t, pipeline.Validate())
+	assert.Equal(t, "Engineering", pipeline.Name)
+	assert.Equal(t, "Tech interview", pipeline.Stages[1].Name)
+	for i, stage := range pipeline.Stages {
+		assert.Equal(t, i, stage.Position)
+	}
+
+	invalid := []*Pipeline{
+		{Name: " ", Stages: []*PipelineStage{{Name: "Screening"}}},
+		{Name: "Empty"},
+		{Name: "Blank stage", Stages: []*PipelineStage{{Name: "  "}}},
+		{Name: "Duplicate", Stages: []*PipelineStage{{Name: "Offer"}, {Name: "offer"}}},
+	}
+	for _, p := range invalid {
+		assert.ErrorIs(t, p.Validate(), ErrInvalidPipeline, p.Name)
+	}
+
+	tooMany := &Pipeline{Name: "Long"}
+	for i := 0; i <= MaxPipelineStages; i++ {
+		tooMany.Stages = append(tooMany.Stages, &PipelineStage{Name: string(rune('a' + i))})
+	}
+	assert.ErrorIs(t, tooMany.Validate(), ErrInvalidPipeline)
+}
+
+func TestNewPipelineBoard(t *testing.T) {
+	stage := func(id int64) *int64 { return &id }
+	pipeline := &Pipeline{
+		ID: 1,
+		Stages: []*PipelineStage{
+			{ID: 10, Name: "Screening"},
+			{ID: 11, Name: "Interview"},
+		},
+	}
+	applications := []*Application{
+		{ID: 1, StageID: stage(11)},
+		{ID: 2},
+		{ID: 3, StageID: stage(10)},
+		{ID: 4, StageID: stage(99)},
+	}
+
+	board := NewPipelineBoard(5, pipeline, applications)
+	assert.Equal(t, int64(5), board.VacancyID)
+	require.Len(t, board.Columns, 2)
+
+	ids := func(column *PipelineColumn) []int64 {
+		var result []int64
+		for _, application := range column.Applications {
+			result = append(result, application.ID)
+		}
+		return result
+	}
+	assert.Equal(t, []int64{2, 3, 4}, ids(board.Columns[0]))
+	assert.Equal(t, []int64{1}, ids(board.Columns[1]))
+
+	empty := NewPipelineBoard(5, pipeline, nil)
+	assert.NotNil(t, empty.Columns[1].Applications)
+	assert.Empty(t, empty.Columns[1].Applications)
+}
@@ -1,16 +1,251 @@
 package entity
 
-import "time"
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
 
 type Resume struct {
-	ID          int64     `json:"id" db:"id"`
-	UserID      int64     `json:"user_id" db:"user_id"`
-	Title       string    `json:"title" db:"title"`
-	Description string    `json:"description" db:"description"`
-	Skills      []string  `json:"skills" db:"skills" swaggertype:"array,string"`
-	Experience  string    `json:"experience" db:"experience"`
-	Education   string    `json:"education" db:"education"`
-	Status      string    `json:"status" db:"status"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	ID                   int64             `json:"id" db:"id"`
+	UserID               int64             `json:"user_id" db:"user_id"`
+	Title                string            `json:"title" db:"title"`
+	Description          string            `json:"description" db:"description"`
+	Skills               []string          `json:"skills" db:"skills" swaggertype:"array,string"`
+	Location             string            `json:"location" db:"location"`
+	LegacyExperience     string            `json:"legacy_experience" db:"legacy_experience"`
+	LegacyEducation      string            `json:"legacy_education" db:"legacy_education"`
+	Status               string            `json:"status" db:"status"`
+	Visibility           string            `json:"visibility" db:"visibility"`
+	PublicToken          string            `json:"public_token,omitempty" db:"public_token"`
+	CreatedAt            time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time         `json:"updated_at" db:"updated_at"`
+	WorkExperience       []*WorkExperience `json:"work_experience" db:"-"`
+	Education            []*EducationEntry `json:"education" db:"-"`
+	Languages            []*LanguageSkill  `json:"languages" db:"-"`
+	Certifications       []*Certification  `json:"certifications" db:"-"`
+	TotalExperienceYears float64           `json:"total_experience_years" db:"experience_years"`
+	Contact              *ResumeContact    `json:"contact,omitempty" db:"-"`
+}
+
+// ResumeSearchFilter narrows the candidate search. Zero values mean "any".
+type ResumeSearchFilter struct {
+	Skills []string
+	// MatchAll requires every skill instead of any one of them.
+	MatchAll      bool
+	MinExperience *float64
+	MaxExperience *float64
+	// Education matches the degree or field of study of any education entry.
+	Education    string
+	Location     string
+	UpdatedSince *time.Time
+}
+
+// ResumeContact is the owner's contact information. It is only attached to
+// a resume for viewers allowed to reach the candidate.
+type ResumeContact struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// Resume visibility levels.
+const (
+	// VisibilityPrivate hides the resume from everyone but the owner.
+	VisibilityPrivate = "private"
+	// VisibilityApplied shows the resume to employers the owner applied to.
+	VisibilityApplied = "applied"
+	// VisibilityEmployers shows the resume to all verified employers.
+	VisibilityEmployers = "employers"
+	// VisibilityPublic additionally makes the resume reachable by its public link.
+	VisibilityPublic = "public"
+)
+
+func IsValidVisibility(visibility string) bool {
+	switch visibility {
+	case VisibilityPrivate, VisibilityApplied, VisibilityEmployers, VisibilityPublic:
+		return true
+	}
+	return false
+}
+
+// ResumeViewer describes a user looking at someone else's resume.
+type ResumeViewer struct {
+	UserID   int64
+	Role     string
+	Verified bool
+	// ReceivedResume is set when the resume was sent in an application to
+	// the viewer's vacancy.
+	ReceivedResume bool
+	// AppliedTo is set when the owner applied to any of the viewer's vacancies.
+	AppliedTo bool
+	// Blocked is set when the owner put the viewer on their blocklist.
+	Blocked bool
+	// ContactAccepted is set when the owner accepted the viewer's contact request.
+	ContactAccepted bool
+}
+
+// Access reports whether the viewer may see the resume and the owner's
+// contact information. A resume sent in an application stays visible to
+// that employer regardless of visibility and blocklist.
+func (r *Resume) Access(v ResumeViewer) (visible, contact bool) {
+	if v.UserID == r.UserID || v.Role == string(RoleAdmin) || v.ReceivedResume {
+		return true, true
+	}
+	if v.Blocked {
+		return false, false
+	}
+
+	contact = v.AppliedTo || v.ContactAccepted
+	switch r.Visibility {
+	case VisibilityApplied:
+		visible = v.AppliedTo
+	case VisibilityEmployers:
+		visible = v.Role == string(RoleEmployer) && v.Verified
+	case VisibilityPublic:
+		visible = true
+	}
+	if !visible {
+		return false, false
+	}
+	return visible, contact
+}
+
+// CanExport reports whether the viewer may download the resume as a file.
+// Unlike viewing, that needs the resume itself: only the owner, admins and
+// employers it was sent to in an application get it.
+func (r *Resume) CanExport(v ResumeViewer) bool {
+	return v.UserID == r.UserID || v.Role == string(RoleAdmin) || v.ReceivedResume
+}
+
+// WorkExperience is a single position held by the resume owner.
+// EndDate is nil for the current position.
+type WorkExperience struct {
+	ID          int64      `json:"id" db:"id"`
+	ResumeID    int64      `json:"resume_id" db:"resume_id"`
+	Company     string     `json:"company" db:"company"`
+	Title       string     `json:"title" db:"title"`
+	StartDate   time.Time  `json:"start_date" db:"start_date"`
+	EndDate     *time.Time `json:"end_date,omitempty" db:"end_date"`
+	Description string     `json:"description" db:"description"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// EducationEntry is a degree or course of study. EndYear is nil while studying.
+type EducationEntry struct {
+	ID           int64     `json:"id" db:"id"`
+	ResumeID     int64     `json:"resume_id" db:"resume_id"`
+	Institution  string    `json:"institution" db:"institution"`
+	Degree       string    `json:"degree" db:"degree"`
+	FieldOfStudy string    `json:"field_of_study" db:"field_of_study"`
+	StartYear    int       `json:"start_year" db:"start_year"`
+	EndYear      *int      `json:"end_year,omitempty" db:"end_year"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type LanguageSkill struct {
+	ID        int64     `json:"id" db:"id"`
+	ResumeID  int64     `json:"resume_id" db:"resume_id"`
+	Language  string    `json:"language" db:"language"`
+	Level     string    `json:"level" db:"level"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type Certification struct {
+	ID            int64      `json:"id" db:"id"`
+	ResumeID      int64      `json:"resume_id" db:"resume_id"`
+	Name          string     `json:"name" db:"name"`
+	Issuer        string     `json:"issuer" db:"issuer"`
+	IssuedAt      *time.Time `json:"issued_at,omitempty" db:"issued_at"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	CredentialURL string     `json:"credential_url" db:"credential_url"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Language proficiency levels (CEFR plus native speaker).
+const (
+	LanguageLevelA1     = "A1"
+	LanguageLevelA2     = "A2"
+	LanguageLevelB1     = "B1"
+	LanguageLevelB2     = "B2"
+	LanguageLevelC1     = "C1"
+	LanguageLevelC2     = "C2"
+	LanguageLevelNative = "native"
+)
+
+// TotalExperienceYears returns the length of work history in years, rounded
+// to one decimal. Overlapping positions are counted once and open-ended
+// positions run until now.
+func TotalExperienceYears(positions []*WorkExperience, now time.Time) float64 {
+	type interval struct{ start, end time.Time }
+
+	intervals := make([]interval, 0, len(positions))
+	for _, p := range positions {
+		end := now
+		if p.EndDate != nil {
+			end = *p.EndDate
+		}
+		if !end.After(p.StartDate) {
+			continue
+		}
+		intervals = append(intervals, interval{start: p.StartDate, end: end})
+	}
+	if len(intervals) == 0 {
+		return 0
+	}
+
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i].start.Before(intervals[j].start)
+	})
+
+	var total time.Duration
+	current := intervals[0]
+	for _, next := range intervals[1:] {
+		if next.start.After(current.end) {
+			total += current.end.Sub(current.start)
+			current = next
+			continue
+		}
+		if next.end.After(current.end) {
+			current.end = next.end
+		}
+	}
+	total += current.end.Sub(current.start)
+
+	years := total.Hours() / 24 / 365.25
+	return math.Round(years*10) / 10
+}
+
+// NormalizeSkills trims skill names, drops empty ones and removes
+// case-insensitive duplicates, keeping the first spelling. The result is never nil.
+func NormalizeSkills(skills []string) []string {
+	result := make([]string, 0, len(skills))
+	seen := make(map[string]struct{}, len(skills))
+	for _, skill := range skills {
+		skill = strings.TrimSpace(skill)
+		if skill == "" {
+			continue
+		}
+		key := strings.ToLower(skill)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		result = append(result, skill)
+	}
+	return result
+}
+
+// SkillKeys returns the lower-cased normalized skills, the form skill search
+// compares.
+func SkillKeys(skills []string) []string {
+	keys := NormalizeSkills(skills)
+	for i, skill := range keys {
+		keys[i] = strings.ToLower(skill)
+	}
+	return keys
 }
@@ -0,0 +1,143 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func datePtr(y int, m time.Month, d int) *time.Time {
+	t := date(y, m, d)
+	return &t
+}
+
+func TestTotalExperienceYears(t *testing.T) {
+	now := date(2024, time.January, 1)
+
+	tests := []struct {
+		name      string
+		positions []*WorkExperience
+		want      float64
+	}{
+		{
+			name: "no positions",
+			want: 0,
+		},
+		{
+			name: "single closed position",
+			positions: []*WorkExperience{
+				{StartDate: date(2018, time.January, 1), EndDate: datePtr(2020, time.January, 1)},
+			},
+			want: 2,
+		},
+		{
+			name: "current position runs until now",
+			positions: []*WorkExperience{
+				{StartDate: date(2021, time.January, 1)},
+			},
+			want: 3,
+		},
+		{
+			name: "overlapping positions are counted once",
+			positions: []*WorkExperience{
+				{StartDate: date(2016, time.January, 1), EndDate: datePtr(2019, time.January, 1)},
+				{StartDate: date(2018, time.January, 1), EndDate: datePtr(2020, time.January, 1)},
+			},
+			want: 4,
+		},
+		{
+			name: "gaps are not counted",
+			positions: []*WorkExperience{
+				{StartDate: date(2022, time.January, 1), EndDate: datePtr(2023, time.January, 1)},
+				{StartDate: date(2015, time.January, 1), EndDate: datePtr(2016, time.July, 1)},
+			},
+			want: 2.5,
+		},
+		{
+			name: "position nested in another",
+			positions: []*WorkExperience{
+				{StartDate: date(2010, time.January, 1), EndDate: datePtr(2015, time.January, 1)},
+				{StartDate: date(2011, time.January, 1), EndDate: datePtr(2012, time.January, 1)},
+			},
+			want: 5,
+		},
+		{
+			name: "future position is ignored",
+			positions: []*WorkExperience{
+				{StartDate: date(2025, time.January, 1)},
+			},
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, TotalExperienceYears(tt.positions, now))
+		})
+	}
+}
+
+func TestNormalizeSkills(t *testing.T) {
+	assert.Equal(t, []string{}, NormalizeSkills(nil))
+	assert.Equal(t,
+		[]string{"Go", "C, C++", `say "hi"`},
+		NormalizeSkills([]string{" Go ", "", "go", "C, C++", `say "hi"`, "  "}),
+	)
+}
+
+func TestSkillKeys(t *testing.T) {
+	assert.Equal(t, []string{}, SkillKeys(nil))
+	assert.Equal(t, []string{"go", "postgresql"}, SkillKeys([]string{" Go ", "GO", "PostgreSQL"}))
+}
+
+func TestResumeAccess(t *testing.T) {
+	const ownerID = 1
+	employer := ResumeViewer{UserID: 2, Role: string(RoleEmployer)}
+	verified := employer
+	verified.Verified = true
+
+	tests := []struct {
+		name        string
+		visibility  string
+		viewer      ResumeViewer
+		wantVisible bool
+		wantContact bool
+	}{
+		{"owner sees private resume", VisibilityPrivate, ResumeViewer{UserID: ownerID}, true, true},
+		{"admin sees private resume", VisibilityPrivate, ResumeViewer{UserID: 3, Role: string(RoleAdmin)}, true, true},
+		{"private hidden from verified employer", VisibilityPrivate, verified, false, false},
+		{"received resume stays visible", VisibilityPrivate, ResumeViewer{UserID: 2, Role: string(RoleEmployer), ReceivedResume: true, Blocked: true}, true, true},
+		{"applied visible to employer applied to", VisibilityApplied, ResumeViewer{UserID: 2, Role: string(RoleEmployer), AppliedTo: true}, true, true},
+		{"applied hidden from other employers", VisibilityApplied, verified, false, false},
+		{"employers hidden from unverified employer", VisibilityEmployers, employer, false, false},
+		{"employers visible to verified employer without contacts", VisibilityEmployers, verified, true, false},
+		{"employers hidden from jobseekers", VisibilityEmployers, ResumeViewer{UserID: 4, Role: string(RoleJobseeker)}, false, false},
+		{"accepted contact request reveals contacts", VisibilityEmployers, ResumeViewer{UserID: 2, Role: string(RoleEmployer), Verified: true, ContactAccepted: true}, true, true},
+		{"public visible to anyone", VisibilityPublic, ResumeViewer{UserID: 4, Role: string(RoleJobseeker)}, true, false},
+		{"blocked employer sees nothing", VisibilityPublic, ResumeViewer{UserID: 2, Role: string(RoleEmployer), Verified: true, Blocked: true}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resume := &Resume{UserID: ownerID, Visibility: tt.visibility}
+			visible, contact := resume.Access(tt.viewer)
+			assert.Equal(t, tt.wantVisible, visible)
+			assert.Equal(t, tt.wantContact, contact)
+		})
+	}
+}
+
+func TestResumeCanExport(t *testing.T) {
+	resume := &Resume{UserID: 1, Visibility: VisibilityPublic}
+
+	assert.True(t, resume.CanExport(ResumeViewer{UserID: 1}))
+	assert.True(t, resume.CanExport(ResumeViewer{UserID: 3, Role: string(RoleAdmin)}))
+	assert.True(t, resume.CanExport(ResumeViewer{UserID: 2, Role: string(RoleEmployer), ReceivedResume: true}))
+	assert.False(t, resume.CanExport(ResumeViewer{UserID: 2, Role: string(RoleEmployer), Verified: true, ContactAccepted: true}),
+		"seeing a public resume and its contacts does not allow downloading it")
+}
@@ -0,0 +1,324 @@
+package entity
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// Screening question types.
+const (
+	QuestionTypeYesNo        = "yes_no"
+	QuestionTypeSingleChoice = "single_choice"
+	QuestionTypeMultiChoice  = "multi_choice"
+	QuestionTypeNumber       = "number"
+	QuestionTypeText         = "text"
+)
+
+// What happens to an applicant who fails a knock-out question.
+const (
+	KnockoutNone   = "none"
+	KnockoutReject = "reject"
+	KnockoutFlag   = "flag"
+)
+
+const (
+	MaxScreeningQuestions = 20
+	MaxQuestionOptions    = 20
+	MaxAnswerTextLength   = 2000
+)
+
+// ScreeningQuestion is a question applicants answer when applying to a
+// vacancy. A knock-out question also defines the acceptable answers:
+// ExpectedBool for yes/no, AcceptedOptions for choices (one of them for a
+// single choice, all of them for a multi choice) and MinValue/MaxValue for
+// numbers. Free text questions cannot be knock-out.
+type ScreeningQuestion struct {
+	ID              int64    `json:"id" db:"id"`
+	VacancyID       int64    `json:"vacancy_id" db:"vacancy_id"`
+	Position        int      `json:"position" db:"position"`
+	Type            string   `json:"type" db:"type"`
+	Text            string   `json:"text" db:"text"`
+	Options         []string `json:"options,omitempty" db:"options"`
+	Required        bool     `json:"required" db:"required"`
+	KnockoutAction  string   `json:"knockout_action,omitempty" db:"knockout_action"`
+	ExpectedBool    *bool    `json:"expected_bool,omitempty" db:"expected_bool"`
+	AcceptedOptions []string `json:"accepted_options,omitempty" db:"accepted_options"`
+	MinValue        *float64 `json:"min_value,omitempty" db:"min_value"`
+	MaxValue        *float64 `json:"max_value,omitempty" db:"max_value"`
+}
+
+// ScreeningAnswer is an applicant's answer. Only the field matching the
+// question type is set. The question text and type are copied so that the
+// answer still makes sense after the vacancy questions change.
+type ScreeningAnswer struct {
+	ApplicationID int64    `json:"application_id" db:"application_id"`
+	QuestionID    *int64   `json:"question_id" db:"question_id"`
+	QuestionText  string   `json:"question_text" db:"question_text"`
+	QuestionType  string   `json:"question_type" db:"question_type"`
+	Bool          *bool    `json:"boolean,omitempty" db:"bool_value"`
+	Number        *float64 `json:"number,omitempty" db:"number_value"`
+	Text          string   `json:"text,omitempty" db:"text_value"`
+	Options       []string `json:"options,omitempty" db:"options"`
+	// Passed is only shown to the employer.
+	Passed *bool `json:"passed,omitempty" db:"passed"`
+}
+
+// ScreeningResult is the outcome of checking an application's answers.
+type ScreeningResult struct {
+	Answers  []*ScreeningAnswer
+	Rejected bool
+	Flagged  bool
+}
+
+func isValidQuestionType(t string) bool {
+	switch t {
+	case QuestionTypeYesNo, QuestionTypeSingleChoice, QuestionTypeMultiChoice, QuestionTypeNumber, QuestionTypeText:
+		return true
+	}
+	return false
+}
+
+func (q *ScreeningQuestion) isChoice() bool {
+	return q.Type == QuestionTypeSingleChoice || q.Type == QuestionTypeMultiChoice
+}
+
+// IsKnockout reports whether failing the question affects the application.
+func (q *ScreeningQuestion) IsKnockout() bool {
+	return q.KnockoutAction == KnockoutReject || q.KnockoutAction == KnockoutFlag
+}
+
+// Validate normalizes the question and checks that the knock-out rule fits
+// the question type.
+func (q *ScreeningQuestion) Validate() error {
+	q.Text = strings.TrimSpace(q.Text)
+	if q.Text == "" {
+		return fmt.Errorf("%w: question text is required", ErrInvalidScreening)
+	}
+	if !isValidQuestionType(q.Type) {
+		return fmt.Errorf("%w: unknown question type %q", ErrInvalidScreening, q.Type)
+	}
+	if q.KnockoutAction == "" {
+		q.KnockoutAction = KnockoutNone
+	}
+	if q.KnockoutAction != KnockoutNone && !q.IsKnockout() {
+		return fmt.Errorf("%w: unknown knock-out action %q", ErrInvalidScreening, q.KnockoutAction)
+	}
+
+	options := make([]string, 0, len(q.Options))
+	for _, option := range q.Options {
+		option = strings.TrimSpace(option)
+		if option != "" && !slices.Contains(options, option) {
+			options = append(options, option)
+		}
+	}
+	if q.isChoice() {
+		if len(options) < 2 || len(options) > MaxQuestionOptions {
+			return fmt.Errorf("%w: choice questions need 2 to %d options", ErrInvalidScreening, MaxQuestionOptions)
+		}
+		q.Options = options
+	} else {
+		q.Options = []string{}
+	}
+
+	// Оставляем только те условия отсева, которые подходят к типу вопроса
+	accepted := q.AcceptedOptions
+	q.AcceptedOptions = []string{}
+	expected, minValue, maxValue := q.ExpectedBool, q.MinValue, q.MaxValue
+	q.ExpectedBool, q.MinValue, q.MaxValue = nil, nil, nil
+	if !q.IsKnockout() {
+		return nil
+	}
+
+	switch q.Type {
+	case QuestionTypeYesNo:
+		if expected == nil {
+			return fmt.Errorf("%w: expected answer is required for a knock-out yes/no question", ErrInvalidScreening)
+		}
+		q.ExpectedBool = expected
+	case QuestionTypeSingleChoice, QuestionTypeMultiChoice:
+		for _, option := range accepted {
+			option = strings.TrimSpace(option)
+			if !slices.Contains(q.Options, option) {
+				return fmt.Errorf("%w: accepted option %q is not one of the options", ErrInvalidScreening, option)
+			}
+			if !slices.Contains(q.AcceptedOptions, option) {
+				q.AcceptedOptions = append(q.AcceptedOptions, option)
+			}
+		}
+		if len(q.AcceptedOptions) == 0 {
+			return fmt.Errorf("%w: accepted options are required for a knock-out choice question", ErrInvalidScreening)
+		}
+	case QuestionTypeNumber:
+		if minValue == nil && maxValue == nil {
+			return fmt.Errorf("%w: min or max value is required for a knock-out number question", ErrInvalidScreening)
+		}
+		if minValue != nil && maxValue != nil && *minValue > *maxValue {
+			return fmt.Errorf("%w: min value is greater than max value", ErrInvalidScreening)
+		}
+		q.MinValue, q.MaxValue = minValue, maxValue
+	case QuestionTypeText:
+		return fmt.Errorf("%w: text questions cannot be knock-out", ErrInvalidScreening)
+	}
+	return nil
+}
+
+// PublicView returns the question without its knock-out rule, as shown to
+// applicants.
+func (q *ScreeningQuestion) PublicView() *ScreeningQuestion {
+	return &ScreeningQuestion{
+		ID:        q.ID,
+		VacancyID: q.VacancyID,
+		Position:  q.Position,
+		Type:      q.Type,
+		Text:      q.Text,
+		Options:   q.Options,
+		Required:  q.Required,
+	}
+}
+
+// answered reports whether the answer has a value for the question type.
+func (q *ScreeningQuestion) answered(a *ScreeningAnswer) bool {
+	switch q.Type {
+	case QuestionTypeYesNo:
+		return a.Bool != nil
+	case QuestionTypeNumber:
+		return a.Number != nil
+	case QuestionTypeText:
+		return a.Text != ""
+	default:
+		return len(a.Options) > 0
+	}
+}
+
+// normalizeAnswer keeps only the value matching the question type and
+// checks it.
+func (q *ScreeningQuestion) normalizeAnswer(a *ScreeningAnswer) error {
+	answer := ScreeningAnswer{
+		ApplicationID: a.ApplicationID,
+		QuestionID:    a.QuestionID,
+		QuestionText:  q.Text,
+		QuestionType:  q.Type,
+		Options:       []string{},
+	}
+	switch q.Type {
+	case QuestionTypeYesNo:
+		answer.Bool = a.Bool
+	case QuestionTypeNumber:
+		answer.Number = a.Number
+	case QuestionTypeText:
+		answer.Text = strings.TrimSpace(a.Text)
+		if utf8.RuneCountInString(answer.Text) > MaxAnswerTextLength {
+			return fmt.Errorf("%w: answer to %q is too long", ErrInvalidScreening, q.Text)
+		}
+	default:
+		for _, option := range a.Options {
+			if !slices.Contains(q.Options, option) {
+				return fmt.Errorf("%w: %q is not an option of %q", ErrInvalidScreening, option, q.Text)
+			}
+			if !slices.Contains(answer.Options, option) {
+				answer.Options = append(answer.Options, option)
+			}
+		}
+		if q.Type == QuestionTypeSingleChoice && len(answer.Options) > 1 {
+			return fmt.Errorf("%w: only one option can be chosen for %q", ErrInvalidScreening, q.Text)
+		}
+	}
+	*a = answer
+	return nil
+}
+
+// passes reports whether the answer satisfies the knock-out rule. An
+// unanswered knock-out question fails.
+func (q *ScreeningQuestion) passes(a *ScreeningAnswer) bool {
+	if !q.IsKnockout() {
+		return true
+	}
+	if a == nil || !q.answered(a) {
+		return false
+	}
+	switch q.Type {
+	case QuestionTypeYesNo:
+		return *a.Bool == *q.ExpectedBool
+	case QuestionTypeSingleChoice:
+		return slices.Contains(q.AcceptedOptions, a.Options[0])
+	case QuestionTypeMultiChoice:
+		for _, option := range q.AcceptedOptions {
+			if !slices.Contains(a.Options, option) {
+				return false
+			}
+		}
+		return true
+	case QuestionTypeNumber:
+		return (q.MinValue == nil || *a.Number >= *q.MinValue) && (q.MaxValue == nil || *a.Number <= *q.MaxValue)
+	}
+	return true
+}
+
+// ValidateQuestions normalizes the questions of a vacancy and numbers them
+// in the given order.
+func ValidateQuestions(questions []*ScreeningQuestion) error {
+	if len(questions) > MaxScreeningQuestions {
+		return fmt.Errorf("%w: at most %d questions are allowed", ErrInvalidScreening, MaxScreeningQuestions)
+	}
+	for i, question := range questions {
+		if err := question.Validate(); err != nil {
+			return err
+		}
+		question.Position = i
+	}
+	return nil
+}
+
+// EvaluateScreening checks the answers against the vacancy questions: every
+// required question must be answered, answers must fit the question type
+// and may only refer to the vacancy questions. Failing a knock-out question
+// rejects or flags the application.
+func EvaluateScreening(questions []*ScreeningQuestion, answers []*ScreeningAnswer) (*ScreeningResult, error) {
+	byQuestion := make(map[int64]*ScreeningAnswer, len(answers))
+	for _, answer := range answers {
+		if answer.QuestionID == nil {
+			return nil, fmt.Errorf("%w: question id is required", ErrInvalidScreening)
+		}
+		if _, ok := byQuestion[*answer.QuestionID]; ok {
+			return nil, fmt.Errorf("%w: question %d is answered twice", ErrInvalidScreening, *answer.QuestionID)
+		}
+		byQuestion[*answer.QuestionID] = answer
+	}
+
+	result := &ScreeningResult{Answers: []*ScreeningAnswer{}}
+	for _, question := range questions {
+		answer, ok := byQuestion[question.ID]
+		delete(byQuestion, question.ID)
+		if ok {
+			if err := question.normalizeAnswer(answer); err != nil {
+				return nil, err
+			}
+			if !question.answered(answer) {
+				ok = false
+			}
+		}
+		if !ok && question.Required {
+			return nil, fmt.Errorf("%w: answer to %q is required", ErrScreeningAnswersRequired, question.Text)
+		}
+
+		passed := question.passes(answer)
+		if !passed {
+			switch question.KnockoutAction {
+			case KnockoutReject:
+				result.Rejected = true
+			case KnockoutFlag:
+				result.Flagged = true
+			}
+		}
+		if ok {
+			answer.Passed = &passed
+			result.Answers = append(result.Answers, answer)
+		}
+	}
+	for id := range byQuestion {
+		return nil, fmt.Errorf("%w: question %d does not belong to the vacancy", ErrInvalidScreening, id)
+	}
+	return result, nil
+}
@@ -0,0 +1,144 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func boolPtr(v bool) *bool        { return &v }
+func floatPtr(v float64) *float64 { return &v }
+func int64Ptr(v int64) *int64     { return &v }
+
+func TestScreeningQuestionValidate(t *testing.T) {
+	q := &ScreeningQuestion{
+		Type:            QuestionTypeMultiChoice,
+		Text:            "  Languages  ",
+		Options:         []string{"Go", " Go", "", "Rust"},
+		KnockoutAction:  KnockoutReject,
+		AcceptedOptions: []string{"Go"},
+		MinValue:        floatPtr(1),
+	}
+	require.NoError(t, q.Validate())
+	assert.Equal(t, "Languages", q.Text)
+	assert.Equal(t, []string{"Go", "Rust"}, q.Options)
+	assert.Equal(t, []string{"Go"}, q.AcceptedOptions)
+	assert.Nil(t, q.MinValue, "rules of other types are dropped")
+
+	plain := &ScreeningQuestion{Type: QuestionTypeText, Text: "Why us?", ExpectedBool: boolPtr(true)}
+	require.NoError(t, plain.Validate())
+	assert.Equal(t, KnockoutNone, plain.KnockoutAction)
+	assert.Nil(t, plain.ExpectedBool)
+
+	invalid := []*ScreeningQuestion{
+		{Type: QuestionTypeYesNo, Text: " "},
+		{Type: "date", Text: "When?"},
+		{Type: QuestionTypeYesNo, Text: "Relocate?", KnockoutAction: "drop"},
+		{Type: QuestionTypeYesNo, Text: "Relocate?", KnockoutAction: KnockoutReject},
+		{Type: QuestionTypeSingleChoice, Text: "Level", Options: []string{"Junior"}},
+		{Type: QuestionTypeSingleChoice, Text: "Level", Options: []string{"Junior", "Senior"}, KnockoutAction: KnockoutFlag},
+		{Type: QuestionTypeSingleChoice, Text: "Level", Options: []string{"Junior", "Senior"}, KnockoutAction: KnockoutFlag, AcceptedOptions: []string{"Lead"}},
+		{Type: QuestionTypeNumber, Text: "Years", KnockoutAction: KnockoutReject},
+		{Type: QuestionTypeNumber, Text: "Years", KnockoutAction: KnockoutReject, MinValue: floatPtr(5), MaxValue: floatPtr(1)},
+		{Type: QuestionTypeText, Text: "Why us?", KnockoutAction: KnockoutReject},
+	}
+	for _, q := range invalid {
+		assert.ErrorIs(t, q.Validate(), ErrInvalidScreening, "%+v", q)
+	}
+}
+
+func TestScreeningQuestionPublicView(t *testing.T) {
+	q := &ScreeningQuestion{ID: 1, Type: QuestionTypeYesNo, Text: "Relocate?", Required: true, KnockoutAction: KnockoutReject, ExpectedBool: boolPtr(true)}
+	view := q.PublicView()
+	assert.Equal(t, "Relocate?", view.Text)
+	assert.True(t, view.Required)
+	assert.Empty(t, view.KnockoutAction)
+	assert.Nil(t, view.ExpectedBool)
+}
+
+func screeningQuestions(t *testing.T) []*ScreeningQuestion {
+	questions := []*ScreeningQuestion{
+		{ID: 1, Type: QuestionTypeYesNo, Text: "Work permit?", Required: true, KnockoutAction: KnockoutReject, ExpectedBool: boolPtr(true)},
+		{ID: 2, Type: QuestionTypeSingleChoice, Text: "Level", Options: []string{"Junior", "Middle", "Senior"}, KnockoutAction: KnockoutFlag, AcceptedOptions: []string{"Middle", "Senior"}},
+		{ID: 3, Type: QuestionTypeMultiChoice, Text: "Languages", Options: []string{"Go", "Rust", "SQL"}, KnockoutAction: KnockoutFlag, AcceptedOptions: []string{"Go", "SQL"}},
+		{ID: 4, Type: QuestionTypeNumber, Text: "Years", KnockoutAction: KnockoutReject, MinValue: floatPtr(2)},
+		{ID: 5, Type: QuestionTypeText, Text: "Why us?"},
+	}
+	require.NoError(t, ValidateQuestions(questions))
+	return questions
+}
+
+func TestEvaluateScreeningPasses(t *testing.T) {
+	result, err := EvaluateScreening(screeningQuestions(t), []*ScreeningAnswer{
+		{QuestionID: int64Ptr(1), Bool: boolPtr(true), Text: "ignored"},
+		{QuestionID: int64Ptr(2), Options: []string{"Senior"}},
+		{QuestionID: int64Ptr(3), Options: []string{"SQL", "Go", "Go"}},
+		{QuestionID: int64Ptr(4), Number: floatPtr(5)},
+		{QuestionID: int64Ptr(5), Text: "  Great team  "},
+	})
+	require.NoError(t, err)
+	assert.False(t, result.Rejected)
+	assert.False(t, result.Flagged)
+	require.Len(t, result.Answers, 5)
+
+	assert.Equal(t, "Work permit?", result.Answers[0].QuestionText)
+	assert.Empty(t, result.Answers[0].Text)
+	assert.Equal(t, []string{"SQL", "Go"}, result.Answers[2].Options)
+	assert.Equal(t, "Great team", result.Answers[4].Text)
+	for _, answer := range result.Answers {
+		require.NotNil(t, answer.Passed)
+		assert.True(t, *answer.Passed)
+	}
+}
+
+func TestEvaluateScreeningKnockout(t *testing.T) {
+	result, err := EvaluateScreening(screeningQuestions(t), []*ScreeningAnswer{
+		{QuestionID: int64Ptr(1), Bool: boolPtr(true)},
+		{QuestionID: int64Ptr(3), Options: []string{"Go", "Rust"}},
+	})
+	require.NoError(t, err)
+	assert.True(t, result.Rejected, "unanswered knock-out number question")
+	assert.True(t, result.Flagged)
+	require.Len(t, result.Answers, 2)
+	assert.False(t, *result.Answers[1].Passed)
+
+	result, err = EvaluateScreening(screeningQuestions(t), []*ScreeningAnswer{
+		{QuestionID: int64Ptr(1), Bool: boolPtr(false)},
+		{QuestionID: int64Ptr(2), Options: []string{"Senior"}},
+		{QuestionID: int64Ptr(3), Options: []string{"Go", "SQL"}},
+		{QuestionID: int64Ptr(4), Number: floatPtr(2)},
+	})
+	require.NoError(t, err)
+	assert.True(t, result.Rejected)
+	assert.False(t, result.Flagged)
+}
+
+func TestEvaluateScreeningErrors(t *testing.T) {
+	questions := screeningQuestions(t)
+
+	_, err := EvaluateScreening(questions, nil)
+	assert.ErrorIs(t, err, ErrScreeningAnswersRequired)
+
+	_, err = EvaluateScreening(questions, []*ScreeningAnswer{{QuestionID: int64Ptr(1), Number: floatPtr(1)}})
+	assert.ErrorIs(t, err, ErrScreeningAnswersRequired, "answer of the wrong type")
+
+	cases := [][]*ScreeningAnswer{
+		{{Bool: boolPtr(true)}},
+		{{QuestionID: int64Ptr(1), Bool: boolPtr(true)}, {QuestionID: int64Ptr(1), Bool: boolPtr(true)}},
+		{{QuestionID: int64Ptr(1), Bool: boolPtr(true)}, {QuestionID: int64Ptr(9), Text: "?"}},
+		{{QuestionID: int64Ptr(1), Bool: boolPtr(true)}, {QuestionID: int64Ptr(2), Options: []string{"Lead"}}},
+		{{QuestionID: int64Ptr(1), Bool: boolPtr(true)}, {QuestionID: int64Ptr(2), Options: []string{"Middle", "Senior"}}},
+	}
+	for _, answers := range cases {
+		_, err := EvaluateScreening(questions, answers)
+		assert.ErrorIs(t, err, ErrInvalidScreening)
+	}
+}
+
+func TestEvaluateScreeningWithoutQuestions(t *testing.T) {
+	result, err := EvaluateScreening([]*ScreeningQuestion{}, nil)
+	require.NoError(t, err)
+	assert.Empty(t, result.Answers)
+	assert.False(t, result.Rejected)
+}
@@ -0,0 +1,70 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// Statistics series intervals.
+const (
+	StatsIntervalDay  = "day"
+	StatsIntervalWeek = "week"
+)
+
+// MaxStatsPoints limits the number of points in one series.
+const MaxStatsPoints = 366
+
+// StatsRange is the period of a series. From and To are dates in UTC and
+// both are included.
+type StatsRange struct {
+	Interval string    `json:"interval"`
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+}
+
+// Validate checks the interval and the dates and moves From to the start
+// of its day or week (weeks start on Monday) and To to the start of its day.
+func (r *StatsRange) Validate() error {
+	r.From = r.From.UTC().Truncate(24 * time.Hour)
+	r.To = r.To.UTC().Truncate(24 * time.Hour)
+	if r.To.Before(r.From) {
+		return fmt.Errorf("%w: from is after to", ErrInvalidStatsRange)
+	}
+
+	step := 24 * time.Hour
+	switch r.Interval {
+	case StatsIntervalDay:
+	case StatsIntervalWeek:
+		step = 7 * step
+		r.From = r.From.AddDate(0, 0, -(int(r.From.Weekday())+6)%7)
+	default:
+		return fmt.Errorf("%w: interval must be %s or %s", ErrInvalidStatsRange, StatsIntervalDay, StatsIntervalWeek)
+	}
+	if points := int(r.To.Sub(r.From)/step) + 1; points > MaxStatsPoints {
+		return fmt.Errorf("%w: at most %d points per series", ErrInvalidStatsRange, MaxStatsPoints)
+	}
+	return nil
+}
+
+// End is the first moment after the range.
+func (r *StatsRange) End() time.Time {
+	return r.To.AddDate(0, 0, 1)
+}
+
+// StatsPoint is what happened on the platform during one day or week
+// starting at Start.
+type StatsPoint struct {
+	Start         time.Time `json:"start" db:"bucket"`
+	Registrations int       `json:"registrations" db:"registrations"`
+	Employers     int       `json:"employers" db:"employers"`
+	Jobseekers    int       `json:"jobseekers" db:"jobseekers"`
+	Vacancies     int       `json:"vacancies" db:"vacancies"`
+	Applications  int       `json:"applications" db:"applications"`
+}
+
+// StatsSeries is a series of points covering the range, one per interval,
+// with empty intervals included.
+type StatsSeries struct {
+	StatsRange
+	Points []*StatsPoint `json:"points"`
+}
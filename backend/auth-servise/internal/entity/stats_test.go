@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsRangeValidate(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	r := &StatsRange{
+		Interval: StatsIntervalWeek,
+		From:     time.Date(2024, 3, 14, 1, 0, 0, 0, moscow), // 13 марта по UTC, среда
+		To:       time.Date(2024, 3, 20, 18, 30, 0, 0, time.UTC),
+	}
+	require.NoError(t, r.Validate())
+	assert.Equal(t, time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC), r.From, "weeks start on Monday")
+	assert.Equal(t, time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC), r.To)
+	assert.Equal(t, time.Date(2024, 3, 21, 0, 0, 0, 0, time.UTC), r.End())
+
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	invalid := []*StatsRange{
+		{Interval: "month", From: day, To: day},
+		{Interval: StatsIntervalDay, From: day.AddDate(0, 0, 1), To: day},
+		{Interval: StatsIntervalDay, From: day, To: day.AddDate(0, 0, MaxStatsPoints)},
+	}
+	for _, r := range invalid {
+		assert.ErrorIs(t, r.Validate(), ErrInvalidStatsRange, "%+v", r)
+	}
+
+	yearOfWeeks := &StatsRange{Interval: StatsIntervalWeek, From: day, To: day.AddDate(1, 0, 0)}
+	assert.NoError(t, yearOfWeeks.Validate())
+}
@@ -1,17 +1,67 @@
 package entity
 
 import (
+	"fmt"
 	"time"
 )
 
 type User struct {
-	ID        int64     `json:"id" db:"id"`
-	Email     string    `json:"email" db:"email"`
-	Password  string    `json:"-" db:"password"`
-	Name      string    `json:"name" db:"name"`
-	Role      string    `json:"role" db:"role"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	ID         int64     `json:"id" db:"id"`
+	Email      string    `json:"email" db:"email"`
+	Password   string    `json:"-" db:"password"`
+	Name       string    `json:"name" db:"name"`
+	Role       string    `json:"role" db:"role"`
+	IsVerified bool      `json:"is_verified" db:"is_verified"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+
+	// Ограничения, наложенные администратором
+	SuspendedUntil   *time.Time `json:"suspended_until,omitempty" db:"suspended_until"`
+	SuspensionReason string     `json:"suspension_reason,omitempty" db:"suspension_reason"`
+	BannedAt         *time.Time `json:"banned_at,omitempty" db:"banned_at"`
+	BanReason        string     `json:"ban_reason,omitempty" db:"ban_reason"`
+	DeletedAt        *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+}
+
+// Account states, from the most to the least restrictive.
+const (
+	AccountDeleted   = "deleted"
+	AccountBanned    = "banned"
+	AccountSuspended = "suspended"
+	AccountActive    = "active"
+)
+
+// AccountState is the most restrictive state of the account at now. A
+// suspension lifts by itself when SuspendedUntil passes.
+func (u *User) AccountState(now time.Time) string {
+	switch {
+	case u.DeletedAt != nil:
+		return AccountDeleted
+	case u.BannedAt != nil:
+		return AccountBanned
+	case u.SuspendedUntil != nil && now.Before(*u.SuspendedUntil):
+		return AccountSuspended
+	default:
+		return AccountActive
+	}
+}
+
+// CheckAccess returns why the account may not sign in or use its tokens at
+// now, or nil if it may.
+func (u *User) CheckAccess(now time.Time) error {
+	switch u.AccountState(now) {
+	case AccountDeleted:
+		return ErrAccountDeleted
+	case AccountBanned:
+		if u.BanReason == "" {
+			return ErrAccountBanned
+		}
+		return fmt.Errorf("%w: %s", ErrAccountBanned, u.BanReason)
+	case AccountSuspended:
+		return fmt.Errorf("%w until %s", ErrAccountSuspended, u.SuspendedUntil.UTC().Format(time.RFC3339))
+	default:
+		return nil
+	}
 }
 
 type UserRole string
@@ -22,11 +72,17 @@ const (
 	RoleAdmin     UserRole = "admin"
 )
 
+// UserStats are the platform totals for the admin dashboard, with the users
+// broken down by role and the rest by status.
 type UserStats struct {
-	TotalUsers        int `json:"total_users"`
-	TotalEmployers    int `json:"total_employers"`
-	TotalJobseekers   int `json:"total_jobseekers"`
-	TotalVacancies    int `json:"total_vacancies"`
-	TotalResumes      int `json:"total_resumes"`
-	TotalApplications int `json:"total_applications"`
+	TotalUsers           int            `json:"total_users"`
+	TotalEmployers       int            `json:"total_employers"`
+	TotalJobseekers      int            `json:"total_jobseekers"`
+	TotalVacancies       int            `json:"total_vacancies"`
+	TotalResumes         int            `json:"total_resumes"`
+	TotalApplications    int            `json:"total_applications"`
+	UsersByRole          map[string]int `json:"users_by_role"`
+	VacanciesByStatus    map[string]int `json:"vacancies_by_status"`
+	ResumesByStatus      map[string]int `json:"resumes_by_status"`
+	ApplicationsByStatus map[string]int `json:"applications_by_status"`
 }
@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserAccountState(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	later := now.Add(time.Hour)
+	earlier := now.Add(-time.Hour)
+
+	user := &User{}
+	assert.Equal(t, AccountActive, user.AccountState(now))
+	assert.NoError(t, user.CheckAccess(now))
+
+	user.SuspendedUntil = &earlier
+	assert.Equal(t, AccountActive, user.AccountState(now), "the suspension is over")
+
+	user.SuspendedUntil = &later
+	assert.Equal(t, AccountSuspended, user.AccountState(now))
+	assert.ErrorIs(t, user.CheckAccess(now), ErrAccountSuspended)
+
+	user.BannedAt, user.BanReason = &earlier, "спам"
+	assert.Equal(t, AccountBanned, user.AccountState(now), "a ban outranks a suspension")
+	err := user.CheckAccess(now)
+	assert.ErrorIs(t, err, ErrAccountBanned)
+	assert.Contains(t, err.Error(), "спам")
+
+	user.DeletedAt = &now
+	assert.Equal(t, AccountDeleted, user.AccountState(now))
+	assert.ErrorIs(t, user.CheckAccess(now), ErrAccountDeleted)
+}
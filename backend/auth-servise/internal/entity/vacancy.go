@@ -2,9 +2,15 @@ package entity
 
 import "time"
 
+// Vacancy is a job posting. If AllowReapply is set, a jobseeker who withdrew
+// an application may apply again once ReapplyAfterDays have passed.
+// EmployerID is the account that posted it; if OrganizationID is set, the
+// organization's members share it. EmployerVerified is the verified badge
+// of the employer and is only read.
 type Vacancy struct {
 	ID               int64     `db:"id"`
 	EmployerID       int64     `db:"employer_id"`
+	OrganizationID   *int64    `db:"organization_id"`
 	Title            string    `db:"title"`
 	Description      string    `db:"description"`
 	Requirements     string    `db:"requirements"`
@@ -16,6 +22,9 @@ type Vacancy struct {
 	Status           string    `db:"status"`
 	Skills           []string  `db:"skills"`
 	Education        string    `db:"education"`
+	AllowReapply     bool      `db:"allow_reapply"`
+	ReapplyAfterDays int       `db:"reapply_after_days"`
 	CreatedAt        time.Time `db:"created_at"`
 	UpdatedAt        time.Time `db:"updated_at"`
+	EmployerVerified bool      `db:"employer_verified"`
 }
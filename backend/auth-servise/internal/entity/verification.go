@@ -0,0 +1,97 @@
+package entity
+
+import (
+	"fmt"
+	"net/mail"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Verification request statuses. A request waits for review while pending;
+// an admin may send it back to the employer as info_requested, and the
+// employer resubmits it with the missing details.
+const (
+	VerificationPending       = "pending"
+	VerificationInfoRequested = "info_requested"
+	VerificationApproved      = "approved"
+	VerificationRejected      = "rejected"
+)
+
+// MaxVerificationDocuments limits the documents attached to one request.
+const MaxVerificationDocuments = 10
+
+// freeEmailDomains are public mail services. An address there says nothing
+// about the company, so it does not count as a domain email.
+var freeEmailDomains = []string{
+	"gmail.com", "yahoo.com", "outlook.com", "hotmail.com", "icloud.com",
+	"mail.ru", "inbox.ru", "list.ru", "bk.ru", "yandex.ru", "ya.ru", "rambler.ru",
+}
+
+// EmployerVerification is an employer's request to be verified: the
+// company's registration number (ИНН or ОГРН), an address on the company's
+// domain and supporting documents.
+type EmployerVerification struct {
+	ID                 int64                   `json:"id" db:"id"`
+	EmployerID         int64                   `json:"employer_id" db:"employer_id"`
+	EmployerName       string                  `json:"employer_name,omitempty" db:"employer_name"`
+	EmployerEmail      string                  `json:"employer_email,omitempty" db:"employer_email"`
+	RegistrationNumber string                  `json:"registration_number" db:"registration_number"`
+	CompanyEmail       string                  `json:"company_email" db:"company_email"`
+	EmailToken         string                  `json:"-" db:"email_token"`
+	EmailConfirmedAt   *time.Time              `json:"email_confirmed_at,omitempty" db:"email_confirmed_at"`
+	Status             string                  `json:"status" db:"status"`
+	AdminComment       string                  `json:"admin_comment" db:"admin_comment"`
+	ReviewedBy         *int64                  `json:"reviewed_by,omitempty" db:"reviewed_by"`
+	ReviewedAt         *time.Time              `json:"reviewed_at,omitempty" db:"reviewed_at"`
+	CreatedAt          time.Time               `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time               `json:"updated_at" db:"updated_at"`
+	Documents          []*VerificationDocument `json:"documents" db:"-"`
+}
+
+// Validate normalizes the registration number and the email and checks
+// them. The number is a 10 or 12 digit ИНН or a 13 or 15 digit ОГРН.
+func (v *EmployerVerification) Validate() error {
+	v.RegistrationNumber = strings.TrimSpace(v.RegistrationNumber)
+	switch len(v.RegistrationNumber) {
+	case 10, 12, 13, 15:
+	default:
+		return fmt.Errorf("%w: registration number must have 10, 12, 13 or 15 digits", ErrInvalidVerification)
+	}
+	for _, r := range v.RegistrationNumber {
+		if r < '0' || r > '9' {
+			return fmt.Errorf("%w: registration number must have only digits", ErrInvalidVerification)
+		}
+	}
+
+	address, err := mail.ParseAddress(strings.TrimSpace(v.CompanyEmail))
+	if err != nil {
+		return fmt.Errorf("%w: invalid company email", ErrInvalidVerification)
+	}
+	v.CompanyEmail = strings.ToLower(address.Address)
+	_, domain, _ := strings.Cut(v.CompanyEmail, "@")
+	if slices.Contains(freeEmailDomains, domain) {
+		return fmt.Errorf("%w: company email must be on the company's domain", ErrInvalidVerification)
+	}
+	return nil
+}
+
+// Open reports whether the request still waits for the admin or the
+// employer.
+func (v *EmployerVerification) Open() bool {
+	return v.Status == VerificationPending || v.Status == VerificationInfoRequested
+}
+
+// VerificationDocument is a file supporting a verification request, e.g. a
+// scan of the registration certificate. DownloadURL is a signed, expiring
+// link issued to the current viewer.
+type VerificationDocument struct {
+	ID             int64     `json:"id" db:"id"`
+	VerificationID int64     `json:"verification_id" db:"verification_id"`
+	FileName       string    `json:"file_name" db:"file_name"`
+	ContentType    string    `json:"content_type" db:"content_type"`
+	Size           int64     `json:"size" db:"size"`
+	StorageKey     string    `json:"-" db:"storage_key"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	DownloadURL    string    `json:"download_url,omitempty" db:"-"`
+}
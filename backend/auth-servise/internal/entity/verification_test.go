@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmployerVerificationValidate(t *testing.T) {
+	v := &EmployerVerification{RegistrationNumber: " 7707083893 ", CompanyEmail: "HR@Acme.ru"}
+	require.NoError(t, v.Validate())
+	assert.Equal(t, "7707083893", v.RegistrationNumber)
+	assert.Equal(t, "hr@acme.ru", v.CompanyEmail)
+
+	invalid := []*EmployerVerification{
+		{RegistrationNumber: "12345", CompanyEmail: "hr@acme.ru"},
+		{RegistrationNumber: "77070838A3", CompanyEmail: "hr@acme.ru"},
+		{RegistrationNumber: "1027700132195", CompanyEmail: "not an email"},
+		{RegistrationNumber: "1027700132195", CompanyEmail: "acme.hr@gmail.com"},
+		{RegistrationNumber: "1027700132195", CompanyEmail: "acme@Yandex.ru"},
+	}
+	for _, v := range invalid {
+		assert.ErrorIs(t, v.Validate(), ErrInvalidVerification, "%+v", v)
+	}
+}
@@ -0,0 +1,218 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Webhook events an endpoint can subscribe to.
+const (
+	EventApplicationCreated       = "application.created"
+	EventApplicationStatusChanged = "application.status_changed"
+	EventVacancyCreated           = "vacancy.created"
+	EventVacancyUpdated           = "vacancy.updated"
+	// EventVacancyClosed is sent when an active vacancy gets another status.
+	EventVacancyClosed  = "vacancy.closed"
+	EventVacancyDeleted = "vacancy.deleted"
+)
+
+// WebhookEvents lists every event in the order they are documented.
+var WebhookEvents = []string{
+	EventApplicationCreated,
+	EventApplicationStatusChanged,
+	EventVacancyCreated,
+	EventVacancyUpdated,
+	EventVacancyClosed,
+	EventVacancyDeleted,
+}
+
+// Delivery statuses. A pending delivery is retried until it succeeds or
+// runs out of attempts.
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusSucceeded = "succeeded"
+	DeliveryStatusFailed    = "failed"
+)
+
+const (
+	// MaxWebhookAttempts is how many times a delivery is tried.
+	MaxWebhookAttempts = 8
+	// webhookBaseBackoff is the wait before the second attempt; every next
+	// wait is twice as long, up to webhookMaxBackoff.
+	webhookBaseBackoff = 30 * time.Second
+	webhookMaxBackoff  = 6 * time.Hour
+)
+
+// WebhookEndpoint is an employer's URL that receives events. Secret signs
+// the payloads; it is only shown when the endpoint is created.
+type WebhookEndpoint struct {
+	ID         int64     `json:"id" db:"id"`
+	EmployerID int64     `json:"employer_id" db:"employer_id"`
+	URL        string    `json:"url" db:"url"`
+	Secret     string    `json:"secret,omitempty" db:"secret"`
+	Events     []string  `json:"events" db:"events"`
+	Active     bool      `json:"active" db:"active"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Validate checks the URL and the events. The events are deduplicated.
+// URLs pointing at local or private hosts are rejected here for a clear
+// error; the sender checks the resolved address again when connecting.
+func (e *WebhookEndpoint) Validate() error {
+	e.URL = strings.TrimSpace(e.URL)
+	u, err := url.Parse(e.URL)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" {
+		return fmt.Errorf("%w: url must be an absolute https URL", ErrInvalidWebhook)
+	}
+	if u.User != nil {
+		return fmt.Errorf("%w: url must not contain credentials", ErrInvalidWebhook)
+	}
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("%w: url must point to a public host", ErrInvalidWebhook)
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !PublicAddress(addr) {
+		return fmt.Errorf("%w: url must point to a public host", ErrInvalidWebhook)
+	}
+
+	if len(e.Events) == 0 {
+		return fmt.Errorf("%w: subscribe to at least one event", ErrInvalidWebhook)
+	}
+	events := make([]string, 0, len(e.Events))
+	for _, event := range e.Events {
+		if !slices.Contains(WebhookEvents, event) {
+			return fmt.Errorf("%w: unknown event %q", ErrInvalidWebhook, event)
+		}
+		if !slices.Contains(events, event) {
+			events = append(events, event)
+		}
+	}
+	e.Events = events
+	return nil
+}
+
+// nonPublicPrefixes are special-purpose ranges that netip does not classify
+// as private or local but that must not be reachable through webhooks.
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"), // NAT операторов связи
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"), // NAT64 может вести во внутреннюю сеть
+}
+
+// PublicAddress reports whether webhooks may connect to the address. Loopback,
+// private, link-local (including the 169.254.169.254 cloud metadata service),
+// multicast and other special-purpose addresses are not public.
+func PublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// WebhookEvent is the JSON body posted to the endpoints.
+type WebhookEvent struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// ApplicationEventData is the data of application events. The applicant's
+// personal data is not sent; the receiver fetches it through the API.
+type ApplicationEventData struct {
+	ApplicationID int64  `json:"application_id"`
+	VacancyID     int64  `json:"vacancy_id"`
+	UserID        int64  `json:"user_id"`
+	Status        string `json:"status"`
+	FromStatus    string `json:"from_status,omitempty"`
+	Comment       string `json:"comment,omitempty"`
+}
+
+// VacancyEventData is the data of vacancy events.
+type VacancyEventData struct {
+	VacancyID int64  `json:"vacancy_id"`
+	Title     string `json:"title"`
+	Status    string `json:"status"`
+}
+
+// WebhookDelivery is one event queued for one endpoint. URL and Secret are
+// copied from the endpoint when the delivery is picked up for sending.
+type WebhookDelivery struct {
+	ID             int64             `json:"id" db:"id"`
+	EndpointID     int64             `json:"endpoint_id" db:"endpoint_id"`
+	Event          string            `json:"event" db:"event"`
+	Payload        json.RawMessage   `json:"payload" db:"payload"`
+	Status         string            `json:"status" db:"status"`
+	Attempts       int               `json:"attempts" db:"attempts"`
+	NextAttemptAt  *time.Time        `json:"next_attempt_at,omitempty" db:"next_attempt_at"`
+	LastStatusCode *int              `json:"last_status_code,omitempty" db:"last_status_code"`
+	LastError      string            `json:"last_error,omitempty" db:"last_error"`
+	RedeliveryOf   *int64            `json:"redelivery_of,omitempty" db:"redelivery_of"`
+	CreatedAt      time.Time         `json:"created_at" db:"created_at"`
+	DeliveredAt    *time.Time        `json:"delivered_at,omitempty" db:"delivered_at"`
+	URL            string            `json:"-" db:"url"`
+	Secret         string            `json:"-" db:"secret"`
+	AttemptLog     []*WebhookAttempt `json:"attempt_log,omitempty" db:"-"`
+}
+
+// WebhookAttempt is the log entry of one try to deliver.
+type WebhookAttempt struct {
+	ID         int64     `json:"id" db:"id"`
+	DeliveryID int64     `json:"delivery_id" db:"delivery_id"`
+	Number     int       `json:"number" db:"number"`
+	StatusCode *int      `json:"status_code,omitempty" db:"status_code"`
+	Error      string    `json:"error,omitempty" db:"error"`
+	Response   string    `json:"response,omitempty" db:"response"`
+	DurationMS int64     `json:"duration_ms" db:"duration_ms"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// Succeeded reports whether the endpoint accepted the delivery.
+func (a *WebhookAttempt) Succeeded() bool {
+	return a.Error == "" && a.StatusCode != nil && *a.StatusCode >= 200 && *a.StatusCode < 300
+}
+
+// WebhookBackoff returns how long to wait after the given failed attempt.
+func WebhookBackoff(attempt int) time.Duration {
+	wait := webhookBaseBackoff
+	for i := 1; i < attempt && wait < webhookMaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, webhookMaxBackoff)
+}
+
+// Record applies the outcome of an attempt: the delivery succeeds, is
+// scheduled for a retry with exponential backoff or fails for good.
+func (d *WebhookDelivery) Record(attempt *WebhookAttempt, now time.Time) {
+	d.Attempts = attempt.Number
+	d.LastStatusCode = attempt.StatusCode
+	d.LastError = attempt.Error
+	d.NextAttemptAt = nil
+
+	switch {
+	case attempt.Succeeded():
+		d.Status = DeliveryStatusSucceeded
+		d.DeliveredAt = &now
+	case d.Attempts >= MaxWebhookAttempts:
+		d.Status = DeliveryStatusFailed
+	default:
+		d.Status = DeliveryStatusPending
+		next := now.Add(WebhookBackoff(d.Attempts))
+		d.NextAttemptAt = &next
+	}
+}
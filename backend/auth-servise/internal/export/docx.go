@@ -0,0 +1,133 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A DOCX file is a zip of WordprocessingML parts. Only the three parts Word
+// requires are written; formatting is applied directly to the runs.
+const (
+	docxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>
+</Types>`
+
+	docxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>
+</Relationships>`
+
+	docxDocumentHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>`
+
+	docxDocumentFooter = `<w:sectPr><w:pgSz w:w="11906" w:h="16838"/>` +
+		`<w:pgMar w:top="1020" w:right="1020" w:bottom="1020" w:left="1020" w:header="708" w:footer="708" w:gutter="0"/>` +
+		`</w:sectPr></w:body></w:document>`
+)
+
+type docxRun struct {
+	size   float64
+	bold   bool
+	italic bool
+	color  [3]int
+}
+
+func renderDOCX(w io.Writer, blocks []block, tpl Template) error {
+	var body bytes.Buffer
+	body.WriteString(docxDocumentHeader)
+
+	gray := [3]int{90, 90, 90}
+	black := [3]int{0, 0, 0}
+
+	for _, b := range blocks {
+		switch b.kind {
+		case blockName:
+			writeDocxParagraph(&body, b.text, docxRun{size: tpl.NameSize, bold: true, color: tpl.Accent}, 0, false)
+		case blockSubtitle:
+			writeDocxParagraph(&body, b.text, docxRun{size: tpl.HeadingSize, color: [3]int{40, 40, 40}}, 0, false)
+		case blockContact:
+			writeDocxParagraph(&body, b.text, docxRun{size: tpl.BodySize, color: gray}, 0, false)
+		case blockHeading:
+			text := b.text
+			if tpl.UppercaseHeadings {
+				text = strings.ToUpper(text)
+			}
+			writeDocxParagraph(&body, text, docxRun{size: tpl.HeadingSize, bold: true, color: tpl.Accent}, 240, tpl.HeadingRule)
+		case blockEntryTitle:
+			writeDocxParagraph(&body, b.text, docxRun{size: tpl.BodySize, bold: true, color: black}, 120, false)
+		case blockEntryMeta:
+			writeDocxParagraph(&body, b.text, docxRun{size: tpl.BodySize - 1, italic: true, color: [3]int{100, 100, 100}}, 0, false)
+		case blockParagraph:
+			writeDocxParagraph(&body, b.text, docxRun{size: tpl.BodySize, color: black}, 0, false)
+		}
+	}
+	body.WriteString(docxDocumentFooter)
+
+	zw := zip.NewWriter(w)
+	parts := []struct {
+		name    string
+		content []byte
+	}{
+		{"[Content_Types].xml", []byte(docxContentTypes)},
+		{"_rels/.rels", []byte(docxRels)},
+		{"word/document.xml", body.Bytes()},
+	}
+	for _, part := range parts {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", part.name, err)
+		}
+		if _, err := f.Write(part.content); err != nil {
+			return fmt.Errorf("failed to write %s: %w", part.name, err)
+		}
+	}
+	return zw.Close()
+}
+
+// writeDocxParagraph writes one paragraph; spaceBefore is in twentieths of a
+// point and rule draws a bottom border under the paragraph.
+func writeDocxParagraph(buf *bytes.Buffer, text string, run docxRun, spaceBefore int, rule bool) {
+	buf.WriteString("<w:p><w:pPr>")
+	fmt.Fprintf(buf, `<w:spacing w:before="%d" w:after="60"/>`, spaceBefore)
+	if rule {
+		fmt.Fprintf(buf, `<w:pBdr><w:bottom w:val="single" w:sz="6" w:space="1" w:color="%s"/></w:pBdr>`, hexColor(run.color))
+	}
+	buf.WriteString("</w:pPr>")
+
+	var props strings.Builder
+	props.WriteString(`<w:rPr><w:rFonts w:ascii="Calibri" w:hAnsi="Calibri" w:cs="Calibri"/>`)
+	if run.bold {
+		props.WriteString("<w:b/>")
+	}
+	if run.italic {
+		props.WriteString("<w:i/>")
+	}
+	fmt.Fprintf(&props, `<w:color w:val="%s"/>`, hexColor(run.color))
+	// Word measures font size in half-points.
+	fmt.Fprintf(&props, `<w:sz w:val="%d"/>`, int(run.size*2))
+	props.WriteString("</w:rPr>")
+
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	for i, line := range strings.Split(text, "\n") {
+		buf.WriteString("<w:r>")
+		buf.WriteString(props.String())
+		if i > 0 {
+			buf.WriteString("<w:br/>")
+		}
+		buf.WriteString(`<w:t xml:space="preserve">`)
+		_ = xml.EscapeText(buf, []byte(line))
+		buf.WriteString("</w:t></w:r>")
+	}
+	buf.WriteString("</w:p>")
+}
+
+func hexColor(c [3]int) string {
+	return fmt.Sprintf("%02X%02X%02X", c[0], c[1], c[2])
+}
@@ -0,0 +1,76 @@
+package export
+
+import (
+	"io"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// The Go fonts are embedded so Cyrillic text renders without any font files
+// on the host.
+const pdfFont = "go"
+
+func renderPDF(w io.Writer, blocks []block, tpl Template) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes(pdfFont, "", goregular.TTF)
+	pdf.AddUTF8FontFromBytes(pdfFont, "B", gobold.TTF)
+	pdf.AddUTF8FontFromBytes(pdfFont, "I", goitalic.TTF)
+	pdf.SetMargins(18, 18, 18)
+	pdf.SetAutoPageBreak(true, 18)
+	pdf.AddPage()
+
+	left, _, right, _ := pdf.GetMargins()
+	pageWidth, _ := pdf.GetPageSize()
+	contentWidth := pageWidth - left - right
+	lineHeight := func(size float64) float64 { return size * 0.5 }
+
+	for _, b := range blocks {
+		switch b.kind {
+		case blockName:
+			pdf.SetFont(pdfFont, "B", tpl.NameSize)
+			pdf.SetTextColor(tpl.Accent[0], tpl.Accent[1], tpl.Accent[2])
+			pdf.MultiCell(contentWidth, lineHeight(tpl.NameSize), b.text, "", "L", false)
+		case blockSubtitle:
+			pdf.SetFont(pdfFont, "", tpl.HeadingSize)
+			pdf.SetTextColor(40, 40, 40)
+			pdf.MultiCell(contentWidth, lineHeight(tpl.HeadingSize), b.text, "", "L", false)
+		case blockContact:
+			pdf.SetFont(pdfFont, "", tpl.BodySize)
+			pdf.SetTextColor(90, 90, 90)
+			pdf.MultiCell(contentWidth, lineHeight(tpl.BodySize), b.text, "", "L", false)
+		case blockHeading:
+			text := b.text
+			if tpl.UppercaseHeadings {
+				text = strings.ToUpper(text)
+			}
+			pdf.Ln(lineHeight(tpl.BodySize))
+			pdf.SetFont(pdfFont, "B", tpl.HeadingSize)
+			pdf.SetTextColor(tpl.Accent[0], tpl.Accent[1], tpl.Accent[2])
+			pdf.MultiCell(contentWidth, lineHeight(tpl.HeadingSize), text, "", "L", false)
+			if tpl.HeadingRule {
+				y := pdf.GetY() + 0.5
+				pdf.SetDrawColor(tpl.Accent[0], tpl.Accent[1], tpl.Accent[2])
+				pdf.Line(left, y, left+contentWidth, y)
+				pdf.Ln(1.5)
+			}
+		case blockEntryTitle:
+			pdf.SetFont(pdfFont, "B", tpl.BodySize)
+			pdf.SetTextColor(0, 0, 0)
+			pdf.MultiCell(contentWidth, lineHeight(tpl.BodySize), b.text, "", "L", false)
+		case blockEntryMeta:
+			pdf.SetFont(pdfFont, "I", tpl.BodySize-1)
+			pdf.SetTextColor(100, 100, 100)
+			pdf.MultiCell(contentWidth, lineHeight(tpl.BodySize), b.text, "", "L", false)
+		case blockParagraph:
+			pdf.SetFont(pdfFont, "", tpl.BodySize)
+			pdf.SetTextColor(0, 0, 0)
+			pdf.MultiCell(contentWidth, lineHeight(tpl.BodySize), b.text, "", "L", false)
+		}
+	}
+
+	return pdf.Output(w)
+}
@@ -0,0 +1,222 @@
+// Package export renders domain objects into downloadable documents.
+package export
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+)
+
+const (
+	FormatPDF  = "pdf"
+	FormatDOCX = "docx"
+)
+
+var (
+	ErrUnknownFormat   = errors.New("unknown export format")
+	ErrUnknownTemplate = errors.New("unknown resume template")
+)
+
+// Template controls the look of a rendered resume. Sizes are in points.
+type Template struct {
+	Name              string
+	Accent            [3]int
+	NameSize          float64
+	HeadingSize       float64
+	BodySize          float64
+	UppercaseHeadings bool
+	HeadingRule       bool
+}
+
+const DefaultTemplate = "classic"
+
+var templates = map[string]Template{
+	"classic": {
+		Name:        "classic",
+		Accent:      [3]int{0, 0, 0},
+		NameSize:    20,
+		HeadingSize: 13,
+		BodySize:    10.5,
+		HeadingRule: true,
+	},
+	"modern": {
+		Name:              "modern",
+		Accent:            [3]int{25, 103, 210},
+		NameSize:          24,
+		HeadingSize:       12,
+		BodySize:          10.5,
+		UppercaseHeadings: true,
+	},
+	"compact": {
+		Name:        "compact",
+		Accent:      [3]int{60, 60, 60},
+		NameSize:    16,
+		HeadingSize: 11,
+		BodySize:    9,
+		HeadingRule: true,
+	},
+}
+
+// TemplateNames lists the selectable resume templates.
+func TemplateNames() []string {
+	return []string{"classic", "modern", "compact"}
+}
+
+// ContentType returns the MIME type for a format.
+func ContentType(format string) string {
+	switch format {
+	case FormatPDF:
+		return "application/pdf"
+	case FormatDOCX:
+		return "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	}
+	return "application/octet-stream"
+}
+
+// RenderResume writes the resume of owner in the given format and template.
+// An empty template name selects DefaultTemplate.
+func RenderResume(w io.Writer, resume *entity.Resume, owner *entity.User, format, templateName string) error {
+	if templateName == "" {
+		templateName = DefaultTemplate
+	}
+	tpl, ok := templates[templateName]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownTemplate, templateName)
+	}
+
+	blocks := resumeBlocks(resume, owner)
+
+	switch format {
+	case FormatPDF:
+		return renderPDF(w, blocks, tpl)
+	case FormatDOCX:
+		return renderDOCX(w, blocks, tpl)
+	}
+	return fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+}
+
+type blockKind int
+
+const (
+	blockName blockKind = iota
+	blockSubtitle
+	blockContact
+	blockHeading
+	blockEntryTitle
+	blockEntryMeta
+	blockParagraph
+)
+
+// block is a format-independent piece of the rendered document. Both the PDF
+// and the DOCX writers walk the same list so the templates stay in sync.
+type block struct {
+	kind blockKind
+	text string
+}
+
+func resumeBlocks(resume *entity.Resume, owner *entity.User) []block {
+	var blocks []block
+	add := func(kind blockKind, text string) {
+		if strings.TrimSpace(text) != "" {
+			blocks = append(blocks, block{kind: kind, text: text})
+		}
+	}
+
+	if owner != nil {
+		add(blockName, owner.Name)
+	}
+	add(blockSubtitle, resume.Title)
+	if owner != nil {
+		add(blockContact, owner.Email)
+	}
+
+	if resume.Description != "" {
+		add(blockHeading, "О себе")
+		add(blockParagraph, resume.Description)
+	}
+
+	if len(resume.Skills) > 0 {
+		add(blockHeading, "Навыки")
+		add(blockParagraph, strings.Join(resume.Skills, ", "))
+	}
+
+	if len(resume.WorkExperience) > 0 || resume.LegacyExperience != "" {
+		heading := "Опыт работы"
+		if resume.TotalExperienceYears > 0 {
+			heading += " — " + formatYears(resume.TotalExperienceYears)
+		}
+		add(blockHeading, heading)
+		for _, item := range resume.WorkExperience {
+			add(blockEntryTitle, joinNonEmpty(" — ", item.Title, item.Company))
+			add(blockEntryMeta, formatPeriod(item.StartDate, item.EndDate))
+			add(blockParagraph, item.Description)
+		}
+		add(blockParagraph, resume.LegacyExperience)
+	}
+
+	if len(resume.Education) > 0 || resume.LegacyEducation != "" {
+		add(blockHeading, "Образование")
+		for _, item := range resume.Education {
+			add(blockEntryTitle, item.Institution)
+			add(blockEntryMeta, joinNonEmpty(", ", item.Degree, item.FieldOfStudy, formatYearRange(item.StartYear, item.EndYear)))
+		}
+		add(blockParagraph, resume.LegacyEducation)
+	}
+
+	if len(resume.Languages) > 0 {
+		add(blockHeading, "Языки")
+		for _, item := range resume.Languages {
+			add(blockParagraph, item.Language+" — "+item.Level)
+		}
+	}
+
+	if len(resume.Certifications) > 0 {
+		add(blockHeading, "Сертификаты")
+		for _, item := range resume.Certifications {
+			add(blockEntryTitle, joinNonEmpty(", ", item.Name, item.Issuer))
+			if item.IssuedAt != nil {
+				add(blockEntryMeta, item.IssuedAt.Format("01.2006"))
+			}
+			add(blockParagraph, item.CredentialURL)
+		}
+	}
+
+	return blocks
+}
+
+func formatPeriod(start time.Time, end *time.Time) string {
+	to := "по настоящее время"
+	if end != nil {
+		to = end.Format("01.2006")
+	}
+	return start.Format("01.2006") + " — " + to
+}
+
+func formatYearRange(start int, end *int) string {
+	if start == 0 {
+		return ""
+	}
+	if end == nil {
+		return strconv.Itoa(start) + " — н.в."
+	}
+	return strconv.Itoa(start) + " — " + strconv.Itoa(*end)
+}
+
+func formatYears(years float64) string {
+	return strconv.FormatFloat(years, 'f', -1, 64) + " г."
+}
+
+func joinNonEmpty(sep string, parts ...string) string {
+	nonEmpty := parts[:0:0]
+	for _, p := range parts {
+		if strings.TrimSpace(p) != "" {
+			nonEmpty = append(nonEmpty, p)
+		}
+	}
+	return strings.Join(nonEmpty, sep)
+}
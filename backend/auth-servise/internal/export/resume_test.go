@@ -0,0 +1,81 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testResume() (*entity.Resume, *entity.User) {
+	end := time.Date(2022, time.March, 1, 0, 0, 0, 0, time.UTC)
+	resume := &entity.Resume{
+		Title:       "Go-разработчик",
+		Description: "Backend <services> & APIs",
+		Skills:      []string{"Go", "PostgreSQL"},
+		WorkExperience: []*entity.WorkExperience{
+			{Company: "Acme", Title: "Developer", StartDate: time.Date(2019, time.May, 1, 0, 0, 0, 0, time.UTC), EndDate: &end},
+		},
+		Education: []*entity.EducationEntry{
+			{Institution: "МГУ", Degree: "Бакалавр", StartYear: 2015},
+		},
+		Languages:            []*entity.LanguageSkill{{Language: "English", Level: "B2"}},
+		TotalExperienceYears: 2.8,
+	}
+	owner := &entity.User{Name: "Иван Петров", Email: "ivan@example.com"}
+	return resume, owner
+}
+
+func TestRenderResumePDF(t *testing.T) {
+	resume, owner := testResume()
+	for _, name := range TemplateNames() {
+		var buf bytes.Buffer
+		require.NoError(t, RenderResume(&buf, resume, owner, FormatPDF, name), name)
+		assert.True(t, bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")), name)
+	}
+}
+
+func TestRenderResumeDOCX(t *testing.T) {
+	resume, owner := testResume()
+
+	var buf bytes.Buffer
+	require.NoError(t, RenderResume(&buf, resume, owner, FormatDOCX, ""))
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	files := map[string]*zip.File{}
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+	require.Contains(t, files, "[Content_Types].xml")
+	require.Contains(t, files, "_rels/.rels")
+	require.Contains(t, files, "word/document.xml")
+
+	rc, err := files["word/document.xml"].Open()
+	require.NoError(t, err)
+	defer rc.Close()
+	document, err := io.ReadAll(rc)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(document), "Иван Петров")
+	assert.Contains(t, string(document), "Backend &lt;services&gt; &amp; APIs")
+	assert.Contains(t, string(document), "05.2019 — 03.2022")
+	assert.Contains(t, string(document), "Опыт работы — 2.8 г.")
+}
+
+func TestRenderResumeErrors(t *testing.T) {
+	resume, owner := testResume()
+
+	err := RenderResume(io.Discard, resume, owner, "odt", "")
+	assert.True(t, errors.Is(err, ErrUnknownFormat))
+
+	err = RenderResume(io.Discard, resume, owner, FormatPDF, "fancy")
+	assert.True(t, errors.Is(err, ErrUnknownTemplate))
+}
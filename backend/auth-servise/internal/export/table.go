@@ -0,0 +1,202 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// TableWriter writes a spreadsheet row by row, so a large export is never
+// held in memory. Close must be called to finish the file.
+type TableWriter interface {
+	WriteRow(cells []string) error
+	Close() error
+}
+
+// NewTableWriter returns a writer of the given format, csv or xlsx.
+func NewTableWriter(w io.Writer, format string) (TableWriter, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w)
+	case FormatXLSX:
+		return newXLSXWriter(w)
+	}
+	return nil, ErrUnknownFormat
+}
+
+// TableContentType returns the MIME type of a spreadsheet format.
+func TableContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	// Без BOM Excel открывает UTF-8 как однобайтовую кодировку
+	if _, err := io.WriteString(w, "\uFEFF"); err != nil {
+		return nil, err
+	}
+	return &csvWriter{w: csv.NewWriter(w)}, nil
+}
+
+func (c *csvWriter) WriteRow(cells []string) error {
+	escaped := make([]string, len(cells))
+	for i, cell := range cells {
+		escaped[i] = escapeFormula(cell)
+	}
+	return c.w.Write(escaped)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// escapeFormula keeps a spreadsheet from evaluating text typed by users,
+// such as a name starting with "=", as a formula.
+func escapeFormula(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
+
+// An XLSX file is a zip of SpreadsheetML parts. The worksheet is the last
+// part, so rows are written to the archive as they come. Cells hold inline
+// strings, which need no shared string table and are never formulas.
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+</Types>`
+
+	xlsxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets>
+</workbook>`
+
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+</Relationships>`
+
+	xlsxSheetHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+
+	xlsxSheetFooter = `</sheetData></worksheet>`
+)
+
+type xlsxWriter struct {
+	zw    *zip.Writer
+	sheet *bufio.Writer
+	row   int
+}
+
+func newXLSXWriter(w io.Writer) (*xlsxWriter, error) {
+	zw := zip.NewWriter(w)
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	}
+	for _, p := range parts {
+		f, err := zw.Create(p.name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s: %w", p.name, err)
+		}
+		if _, err := io.WriteString(f, p.content); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", p.name, err)
+		}
+	}
+
+	f, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create worksheet: %w", err)
+	}
+	sheet := bufio.NewWriter(f)
+	sheet.WriteString(xlsxSheetHeader)
+	return &xlsxWriter{zw: zw, sheet: sheet}, nil
+}
+
+func (x *xlsxWriter) WriteRow(cells []string) error {
+	x.row++
+	x.sheet.WriteString(`<row r="` + strconv.Itoa(x.row) + `">`)
+	for _, cell := range cells {
+		x.sheet.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+		// EscapeText заменяет недопустимые в XML символы на U+FFFD
+		if err := xml.EscapeText(x.sheet, []byte(cell)); err != nil {
+			return err
+		}
+		x.sheet.WriteString(`</t></is></c>`)
+	}
+	_, err := x.sheet.WriteString(`</row>`)
+	return err
+}
+
+func (x *xlsxWriter) Close() error {
+	x.sheet.WriteString(xlsxSheetFooter)
+	if err := x.sheet.Flush(); err != nil {
+		return fmt.Errorf("failed to write worksheet: %w", err)
+	}
+	return x.zw.Close()
+}
+
+// ApplicationHeader is the first row of an application export.
+var ApplicationHeader = []string{
+	"ID отклика", "Кандидат", "Email", "Вакансия", "Статус", "Дата отклика", "Обновлен",
+	"Резюме", "Навыки", "Опыт, лет", "Теги", "Средняя оценка", "Заметки",
+}
+
+const exportTimeLayout = "2006-01-02 15:04"
+
+// ApplicationRecord renders an application as a row under ApplicationHeader.
+func ApplicationRecord(row *entity.ApplicationExportRow) []string {
+	rating := ""
+	if row.AverageRating != nil {
+		rating = strconv.FormatFloat(*row.AverageRating, 'f', 1, 64)
+	}
+	return []string{
+		strconv.FormatInt(row.ApplicationID, 10),
+		row.ApplicantName,
+		row.ApplicantEmail,
+		row.VacancyTitle,
+		entity.ApplicationStatusName(row.Status),
+		row.CreatedAt.Format(exportTimeLayout),
+		row.UpdatedAt.Format(exportTimeLayout),
+		row.ResumeTitle,
+		strings.Join(row.Skills, ", "),
+		strconv.FormatFloat(row.ExperienceYears, 'f', -1, 64),
+		strings.Join(row.Tags, ", "),
+		rating,
+		strings.Join(row.Notes, "\n\n"),
+	}
+}
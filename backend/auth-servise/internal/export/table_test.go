@@ -0,0 +1,97 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testExportRow() *entity.ApplicationExportRow {
+	rating := 4.5
+	return &entity.ApplicationExportRow{
+		ApplicationID:   12,
+		ApplicantName:   "=HYPERLINK(\"http://evil\")",
+		ApplicantEmail:  "ivan@example.com",
+		VacancyTitle:    "Go-разработчик",
+		Status:          entity.ApplicationStatusInterview,
+		CreatedAt:       time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC),
+		UpdatedAt:       time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC),
+		ResumeTitle:     "Backend <developer>",
+		Skills:          []string{"Go", "SQL"},
+		ExperienceYears: 2.8,
+		Tags:            []string{"strong"},
+		AverageRating:   &rating,
+		Notes:           []string{"Хорошее интервью", "Ждем решения"},
+	}
+}
+
+func TestApplicationRecord(t *testing.T) {
+	record := ApplicationRecord(testExportRow())
+	require.Len(t, record, len(ApplicationHeader))
+	assert.Equal(t, "12", record[0])
+	assert.Equal(t, "Интервью", record[4])
+	assert.Equal(t, "2024-05-01 09:30", record[5])
+	assert.Equal(t, "Go, SQL", record[8])
+	assert.Equal(t, "2.8", record[9])
+	assert.Equal(t, "4.5", record[11])
+	assert.Equal(t, "Хорошее интервью\n\nЖдем решения", record[12])
+}
+
+func TestCSVTable(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewTableWriter(&buf, FormatCSV)
+	require.NoError(t, err)
+	require.NoError(t, w.WriteRow(ApplicationHeader))
+	require.NoError(t, w.WriteRow(ApplicationRecord(testExportRow())))
+	require.NoError(t, w.Close())
+
+	data := buf.String()
+	require.True(t, strings.HasPrefix(data, "\uFEFF"))
+	rows, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(data, "\uFEFF"))).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, ApplicationHeader, rows[0])
+	assert.Equal(t, `'=HYPERLINK("http://evil")`, rows[1][1], "formulas are not evaluated")
+	assert.Equal(t, "Хорошее интервью\n\nЖдем решения", rows[1][12])
+}
+
+func TestXLSXTable(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewTableWriter(&buf, FormatXLSX)
+	require.NoError(t, err)
+	require.NoError(t, w.WriteRow(ApplicationHeader))
+	require.NoError(t, w.WriteRow(ApplicationRecord(testExportRow())))
+	require.NoError(t, w.Close())
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	parts := map[string]string{}
+	for _, f := range archive.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+		parts[f.Name] = string(content)
+	}
+
+	require.Contains(t, parts, "xl/workbook.xml")
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, sheet, `<row r="2">`)
+	assert.Contains(t, sheet, "Backend &lt;developer&gt;")
+	assert.Contains(t, sheet, "Go-разработчик")
+	assert.True(t, strings.HasSuffix(sheet, "</sheetData></worksheet>"))
+}
+
+func TestTableUnknownFormat(t *testing.T) {
+	_, err := NewTableWriter(io.Discard, "ods")
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
@@ -0,0 +1,139 @@
+// Package ical builds RFC 5545 calendar invites for interviews.
+package ical
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Methods of an iTIP message (RFC 5546).
+const (
+	MethodRequest = "REQUEST"
+	MethodCancel  = "CANCEL"
+)
+
+const (
+	productID = "-//Job Search Platform//Interviews//RU"
+	timeUTC   = "20060102T150405Z"
+	// maxLineOctets is the longest content line allowed before folding.
+	maxLineOctets = 75
+)
+
+type Attendee struct {
+	Name  string
+	Email string
+}
+
+// Event is a single meeting. UID must stay the same across updates and
+// cancellations; Sequence is increased every time the event changes.
+type Event struct {
+	UID         string
+	Sequence    int
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	Organizer   Attendee
+	Attendees   []Attendee
+	// Stamp is the DTSTAMP value; the current time is used when it is zero.
+	Stamp time.Time
+}
+
+// ContentType returns the MIME type of an invite sent with the method.
+func ContentType(method string) string {
+	return "text/calendar; charset=utf-8; method=" + method
+}
+
+// Invite renders a VCALENDAR with the event. MethodRequest is used both for
+// the first invite and for updates, MethodCancel cancels the event.
+func Invite(method string, event Event) []byte {
+	stamp := event.Stamp
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+
+	var b bytes.Buffer
+	w := func(name, value string) { writeLine(&b, name+":"+value) }
+
+	w("BEGIN", "VCALENDAR")
+	w("VERSION", "2.0")
+	w("PRODID", productID)
+	w("CALSCALE", "GREGORIAN")
+	w("METHOD", method)
+	w("BEGIN", "VEVENT")
+	w("UID", escapeText(event.UID))
+	w("SEQUENCE", fmt.Sprint(event.Sequence))
+	w("DTSTAMP", stamp.UTC().Format(timeUTC))
+	w("DTSTART", event.Start.UTC().Format(timeUTC))
+	w("DTEND", event.End.UTC().Format(timeUTC))
+	w("SUMMARY", escapeText(event.Summary))
+	if event.Description != "" {
+		w("DESCRIPTION", escapeText(event.Description))
+	}
+	if event.Location != "" {
+		w("LOCATION", escapeText(event.Location))
+	}
+	if event.URL != "" {
+		w("URL", event.URL)
+	}
+	if event.Organizer.Email != "" {
+		writeLine(&b, "ORGANIZER"+commonName(event.Organizer.Name)+":mailto:"+event.Organizer.Email)
+	}
+	for _, attendee := range event.Attendees {
+		writeLine(&b, "ATTENDEE"+commonName(attendee.Name)+
+			";ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION;RSVP=TRUE:mailto:"+attendee.Email)
+	}
+	if method == MethodCancel {
+		w("STATUS", "CANCELLED")
+	} else {
+		w("STATUS", "CONFIRMED")
+	}
+	w("END", "VEVENT")
+	w("END", "VCALENDAR")
+
+	return b.Bytes()
+}
+
+func commonName(name string) string {
+	if name == "" {
+		return ""
+	}
+	// В параметрах запрещены кавычки, поэтому просто убираем их
+	return `;CN="` + strings.ReplaceAll(name, `"`, "") + `"`
+}
+
+// escapeText escapes a TEXT value (RFC 5545, section 3.3.11).
+func escapeText(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(s)
+}
+
+// writeLine writes a content line terminated by CRLF, folding it so that no
+// line is longer than 75 octets. Multi-byte characters are never split.
+func writeLine(b *bytes.Buffer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// Продолжение начинается с пробела, который тоже считается
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
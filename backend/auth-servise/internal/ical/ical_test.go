@@ -0,0 +1,78 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testEvent() Event {
+	start := time.Date(2024, time.March, 5, 9, 30, 0, 0, time.FixedZone("MSK", 3*60*60))
+	return Event{
+		UID:         "interview-7@jobs.example",
+		Sequence:    2,
+		Start:       start,
+		End:         start.Add(time.Hour),
+		Summary:     "Интервью: Go developer",
+		Description: "Line one\nLine two; with, separators",
+		Location:    "Office, room 3",
+		URL:         "https://meet.example/abc",
+		Organizer:   Attendee{Name: "Acme HR", Email: "hr@acme.example"},
+		Attendees: []Attendee{
+			{Name: `Ivan "The" Candidate`, Email: "ivan@example.com"},
+			{Email: "lead@acme.example"},
+		},
+		Stamp: time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func unfold(s string) string {
+	return strings.ReplaceAll(s, "\r\n ", "")
+}
+
+func TestInviteRequest(t *testing.T) {
+	data := string(Invite(MethodRequest, testEvent()))
+
+	assert.True(t, strings.HasPrefix(data, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(data, "END:VEVENT\r\nEND:VCALENDAR\r\n"))
+
+	content := unfold(data)
+	for _, line := range []string{
+		"METHOD:REQUEST",
+		"UID:interview-7@jobs.example",
+		"SEQUENCE:2",
+		"DTSTAMP:20240301T120000Z",
+		"DTSTART:20240305T063000Z",
+		"DTEND:20240305T073000Z",
+		"SUMMARY:Интервью: Go developer",
+		`DESCRIPTION:Line one\nLine two\; with\, separators`,
+		`LOCATION:Office\, room 3`,
+		"URL:https://meet.example/abc",
+		`ORGANIZER;CN="Acme HR":mailto:hr@acme.example`,
+		`ATTENDEE;CN="Ivan The Candidate";ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION;RSVP=TRUE:mailto:ivan@example.com`,
+		"ATTENDEE;ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION;RSVP=TRUE:mailto:lead@acme.example",
+		"STATUS:CONFIRMED",
+	} {
+		assert.Contains(t, content, "\r\n"+line+"\r\n")
+	}
+}
+
+func TestInviteCancel(t *testing.T) {
+	content := unfold(string(Invite(MethodCancel, testEvent())))
+	assert.Contains(t, content, "\r\nMETHOD:CANCEL\r\n")
+	assert.Contains(t, content, "\r\nSTATUS:CANCELLED\r\n")
+}
+
+func TestInviteFoldsLongLines(t *testing.T) {
+	event := testEvent()
+	event.Description = strings.Repeat("Описание ", 40)
+	data := string(Invite(MethodRequest, event))
+
+	for _, line := range strings.Split(strings.TrimSuffix(data, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), maxLineOctets)
+		assert.True(t, strings.ToValidUTF8(line, "?") == line, "line splits a character: %q", line)
+	}
+	assert.Contains(t, unfold(data), "DESCRIPTION:"+escapeText(event.Description)+"\r\n")
+}
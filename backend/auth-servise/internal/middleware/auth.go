@@ -1,14 +1,31 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"strconv"
 	"strings"
 
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/usecase"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt"
 )
 
-func AuthMiddleware(tokenSecret string) gin.HandlerFunc {
+// AccountChecker reports whether the account behind a valid token may still
+// use the API.
+type AccountChecker interface {
+	CheckAccount(ctx context.Context, userID int64) error
+}
+
+// AuthMiddleware accepts a valid token of an account that is not suspended,
+// banned or deleted. Impersonation tokens, which carry the admin in the
+// "act" claim, only allow reading: the admin sees what the user sees but
+// cannot change or delete anything on their behalf. Their request context
+// carries the admin (usecase.WithImpersonator), so that reading does not
+// mark anything as read or viewed either.
+func AuthMiddleware(tokenSecret string, accounts AccountChecker) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -42,12 +59,113 @@ func AuthMiddleware(tokenSecret string) gin.HandlerFunc {
 			c.Abort()
 			return
 		}
+		// Токены с областью действия годятся только для своего маршрута
+		if _, scoped := claims["scope"]; scoped {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+			c.Abort()
+			return
+		}
+
+		if act, impersonated := claims["act"]; impersonated {
+			impersonatorID, ok := actorID(act)
+			if !ok {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token claims"})
+				c.Abort()
+				return
+			}
+			if !isSafeMethod(c.Request.Method) {
+				c.JSON(http.StatusForbidden, gin.H{"error": "not allowed while impersonating a user"})
+				c.Abort()
+				return
+			}
+			// Фронтенд показывает по этому заголовку, что сессия чужая
+			c.Header("X-Impersonator-Id", strconv.FormatInt(impersonatorID, 10))
+			c.Set("impersonator_id", impersonatorID)
+			c.Request = c.Request.WithContext(usecase.WithImpersonator(c.Request.Context(), impersonatorID))
+		}
+
+		if err := accounts.CheckAccount(c, int64(userID)); err != nil {
+			writeAccountError(c, err)
+			c.Abort()
+			return
+		}
 
 		c.Set("user_id", int64(userID))
 		c.Next()
 	}
 }
 
+// StreamAuthMiddleware authenticates the notification stream. Besides the
+// Authorization header it accepts a stream token from the "token" query
+// parameter, because the browser EventSource API cannot send headers. Such
+// tokens are issued by POST /notifications/stream-token, live for a minute
+// and open nothing but the stream.
+func StreamAuthMiddleware(tokenSecret string, accounts AccountChecker) gin.HandlerFunc {
+	headerAuth := AuthMiddleware(tokenSecret, accounts)
+	return func(c *gin.Context) {
+		token := c.Query("token")
+		if token == "" {
+			headerAuth(c)
+			return
+		}
+
+		claims := jwt.MapClaims{}
+		_, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+			return []byte(tokenSecret), nil
+		})
+		if err != nil || claims["scope"] != usecase.StreamTokenScope {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+			c.Abort()
+			return
+		}
+
+		userID, ok := claims["user_id"].(float64)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token claims"})
+			c.Abort()
+			return
+		}
+
+		if err := accounts.CheckAccount(c, int64(userID)); err != nil {
+			writeAccountError(c, err)
+			c.Abort()
+			return
+		}
+
+		c.Set("user_id", int64(userID))
+		c.Next()
+	}
+}
+
+// actorID reads the admin ID from the "act" claim of an impersonation token.
+func actorID(act interface{}) (int64, bool) {
+	claim, ok := act.(map[string]interface{})
+	if !ok {
+		return 0, false
+	}
+	sub, ok := claim["sub"].(string)
+	if !ok {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(sub, 10, 64)
+	return id, err == nil
+}
+
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+func writeAccountError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, entity.ErrAccountDeleted):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, entity.ErrAccountSuspended), errors.Is(err, entity.ErrAccountBanned):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
 func AdminAuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userID, exists := c.Get("user_id")
@@ -69,3 +187,12 @@ func AdminAuthMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// RequestClient puts the client's IP and user agent into the request
+// context, so that audit entries recorded while handling it carry them.
+func RequestClient() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(usecase.WithClient(c.Request.Context(), c.ClientIP(), c.Request.UserAgent()))
+		c.Next()
+	}
+}
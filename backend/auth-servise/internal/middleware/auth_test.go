@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type accountStates map[int64]error
+
+func (s accountStates) CheckAccount(ctx context.Context, userID int64) error {
+	return s[userID]
+}
+
+func signedToken(t *testing.T, claims jwt.MapClaims) string {
+	claims["exp"] = time.Now().Add(time.Hour).Unix()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+	require.NoError(t, err)
+	return token
+}
+
+func authRouter(accounts AccountChecker) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(AuthMiddleware("secret", accounts))
+	handler := func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"user_id": c.GetInt64("user_id"), "impersonator_id": c.GetInt64("impersonator_id")})
+	}
+	router.GET("/resource", handler)
+	router.DELETE("/resource", handler)
+	return router
+}
+
+func serve(router *gin.Engine, method, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/resource", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAuthMiddlewareRejectsRestrictedAccounts(t *testing.T) {
+	router := authRouter(accountStates{
+		2: entity.ErrAccountBanned,
+		3: entity.ErrAccountDeleted,
+	})
+
+	assert.Equal(t, http.StatusOK, serve(router, http.MethodGet, signedToken(t, jwt.MapClaims{"user_id": 1})).Code)
+	assert.Equal(t, http.StatusForbidden, serve(router, http.MethodGet, signedToken(t, jwt.MapClaims{"user_id": 2})).Code)
+	assert.Equal(t, http.StatusUnauthorized, serve(router, http.MethodGet, signedToken(t, jwt.MapClaims{"user_id": 3})).Code)
+}
+
+func TestAuthMiddlewareKeepsImpersonationReadOnly(t *testing.T) {
+	router := authRouter(accountStates{})
+	token := signedToken(t, jwt.MapClaims{"user_id": 5, "act": map[string]interface{}{"sub": "1"}})
+
+	w := serve(router, http.MethodGet, token)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"user_id": 5, "impersonator_id": 1}`, w.Body.String())
+	assert.Equal(t, "1", w.Header().Get("X-Impersonator-Id"))
+
+	assert.Equal(t, http.StatusForbidden, serve(router, http.MethodDelete, token).Code)
+	assert.Equal(t, http.StatusOK, serve(router, http.MethodDelete, signedToken(t, jwt.MapClaims{"user_id": 5})).Code)
+
+	forged := signedToken(t, jwt.MapClaims{"user_id": 5, "act": "admin"})
+	assert.Equal(t, http.StatusUnauthorized, serve(router, http.MethodGet, forged).Code)
+}
+
+func TestStreamAuthMiddlewareAcceptsStreamTokensOnlyInTheQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/stream", StreamAuthMiddleware("secret", accountStates{2: entity.ErrAccountBanned}), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"user_id": c.GetInt64("user_id")})
+	})
+	router.GET("/resource", AuthMiddleware("secret", accountStates{}), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	get := func(target, header string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if header != "" {
+			req.Header.Set("Authorization", "Bearer "+header)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	stream := signedToken(t, jwt.MapClaims{"user_id": 1, "scope": usecase.StreamTokenScope})
+	w := get("/stream?token="+stream, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"user_id": 1}`, w.Body.String())
+
+	assert.Equal(t, http.StatusOK, get("/stream", signedToken(t, jwt.MapClaims{"user_id": 1})).Code)
+	assert.Equal(t, http.StatusUnauthorized, get("/stream?token="+signedToken(t, jwt.MapClaims{"user_id": 1}), "").Code)
+	assert.Equal(t, http.StatusForbidden, get("/stream?token="+signedToken(t, jwt.MapClaims{"user_id": 2, "scope": usecase.StreamTokenScope}), "").Code)
+	assert.Equal(t, http.StatusUnauthorized, get("/resource", stream).Code)
+}
@@ -0,0 +1,50 @@
+// Package notify delivers messages such as interview invites to users.
+package notify
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// Attachment is a file sent along with a message.
+type Attachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+type Message struct {
+	To          []string
+	Subject     string
+	Body        string
+	Attachments []Attachment
+}
+
+// Notifier sends messages to their recipients.
+type Notifier interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// LogNotifier only logs messages. It is used when no mail server is
+// configured.
+type LogNotifier struct {
+	logger *zap.Logger
+}
+
+func NewLogNotifier(logger *zap.Logger) *LogNotifier {
+	return &LogNotifier{logger: logger}
+}
+
+func (n *LogNotifier) Send(ctx context.Context, msg Message) error {
+	names := make([]string, len(msg.Attachments))
+	for i, a := range msg.Attachments {
+		names[i] = a.Name
+	}
+	n.logger.Info("notification",
+		zap.Strings("to", msg.To),
+		zap.String("subject", msg.Subject),
+		zap.Strings("attachments", names),
+	)
+	return nil
+}
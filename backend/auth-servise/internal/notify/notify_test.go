@@ -0,0 +1,88 @@
+package notify
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSMTPNotifierSend(t *testing.T) {
+	var (
+		gotAddr string
+		gotTo   []string
+		gotData []byte
+	)
+	n := NewSMTPNotifier(SMTPConfig{Host: "mail.example", Port: "587", From: "jobs@example.com"})
+	n.now = func() time.Time { return time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC) }
+	n.send = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotTo, gotData = addr, to, msg
+		return nil
+	}
+
+	invite := "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"
+	err := n.Send(context.Background(), Message{
+		To:      []string{"ivan@example.com", "lead@acme.example"},
+		Subject: "Приглашение на интервью",
+		Body:    "Здравствуйте!",
+		Attachments: []Attachment{
+			{Name: "invite.ics", ContentType: "text/calendar; charset=utf-8; method=REQUEST", Data: []byte(invite)},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "mail.example:587", gotAddr)
+	assert.Equal(t, []string{"ivan@example.com", "lead@acme.example"}, gotTo)
+
+	msg, err := mail.ReadMessage(strings.NewReader(string(gotData)))
+	require.NoError(t, err)
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Приглашение на интервью", subject)
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/mixed", mediaType)
+
+	var parts []string
+	var bodies []string
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		assert.Equal(t, "base64", part.Header.Get("Content-Transfer-Encoding"))
+		data, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, part))
+		require.NoError(t, err)
+		parts = append(parts, part.Header.Get("Content-Type")+"|"+part.FileName())
+		bodies = append(bodies, string(data))
+	}
+
+	assert.Equal(t, []string{
+		"text/plain; charset=utf-8|",
+		"text/calendar; charset=utf-8; method=REQUEST|",
+		"text/calendar; charset=utf-8; method=REQUEST|invite.ics",
+	}, parts)
+	assert.Equal(t, []string{"Здравствуйте!", invite, invite}, bodies)
+}
+
+func TestSMTPNotifierRejectsHeaderInjection(t *testing.T) {
+	n := NewSMTPNotifier(SMTPConfig{Host: "mail.example", Port: "25", From: "jobs@example.com"})
+	n.send = func(string, smtp.Auth, string, []string, []byte) error {
+		t.Fatal("message must not be sent")
+		return nil
+	}
+
+	err := n.Send(context.Background(), Message{To: []string{"a@example.com\r\nBcc: x@example.com"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,127 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// SMTPNotifier sends messages as multipart e-mails.
+type SMTPNotifier struct {
+	cfg  SMTPConfig
+	send func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+	now  func() time.Time
+}
+
+func NewSMTPNotifier(cfg SMTPConfig) *SMTPNotifier {
+	return &SMTPNotifier{cfg: cfg, send: smtp.SendMail, now: time.Now}
+}
+
+func (n *SMTPNotifier) Send(ctx context.Context, msg Message) error {
+	if len(msg.To) == 0 {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	data, err := buildMIME(n.cfg.From, msg, n.now())
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if n.cfg.Username != "" {
+		auth = smtp.PlainAuth("", n.cfg.Username, n.cfg.Password, n.cfg.Host)
+	}
+	addr := net.JoinHostPort(n.cfg.Host, n.cfg.Port)
+	if err := n.send(addr, auth, n.cfg.From, msg.To, data); err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+	return nil
+}
+
+// buildMIME renders the message as multipart/mixed with a plain text part
+// followed by the attachments. Calendar attachments are also added inline
+// so that mail clients show the invite buttons.
+func buildMIME(from string, msg Message, now time.Time) ([]byte, error) {
+	for _, addr := range append([]string{from}, msg.To...) {
+		if strings.ContainsAny(addr, "\r\n") {
+			return nil, fmt.Errorf("invalid address %q", addr)
+		}
+	}
+
+	boundary, err := newBoundary()
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	header := func(name, value string) { fmt.Fprintf(&b, "%s: %s\r\n", name, value) }
+	header("From", from)
+	header("To", strings.Join(msg.To, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header("Date", now.Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+	header("Content-Type", `multipart/mixed; boundary="`+boundary+`"`)
+	b.WriteString("\r\n")
+
+	part := func(contentType, disposition string, data []byte) {
+		fmt.Fprintf(&b, "--%s\r\n", boundary)
+		fmt.Fprintf(&b, "Content-Type: %s\r\n", contentType)
+		fmt.Fprintf(&b, "Content-Transfer-Encoding: base64\r\n")
+		if disposition != "" {
+			fmt.Fprintf(&b, "Content-Disposition: %s\r\n", disposition)
+		}
+		b.WriteString("\r\n")
+		writeBase64(&b, data)
+	}
+
+	part("text/plain; charset=utf-8", "", []byte(msg.Body))
+	for _, a := range msg.Attachments {
+		if strings.HasPrefix(a.ContentType, "text/calendar") {
+			part(a.ContentType, "", a.Data)
+		}
+		name := mime.QEncoding.Encode("utf-8", strings.ReplaceAll(a.Name, `"`, ""))
+		part(a.ContentType, `attachment; filename="`+name+`"`, a.Data)
+	}
+	fmt.Fprintf(&b, "--%s--\r\n", boundary)
+
+	return b.Bytes(), nil
+}
+
+func newBoundary() (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate boundary: %w", err)
+	}
+	return "b-" + hex.EncodeToString(buf), nil
+}
+
+// writeBase64 writes data base64-encoded in 76 character lines.
+func writeBase64(b *bytes.Buffer, data []byte) {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		b.WriteString(encoded[:76])
+		b.WriteString("\r\n")
+		encoded = encoded[76:]
+	}
+	b.WriteString(encoded)
+	b.WriteString("\r\n")
+}
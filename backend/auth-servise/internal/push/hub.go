@@ -0,0 +1,93 @@
+// Package push delivers notifications to users connected to the event
+// stream in real time.
+package push
+
+import (
+	"sync"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+)
+
+// bufferSize is how many notifications wait for a slow connection before
+// new ones are dropped. Dropped notifications stay in the notification
+// center.
+const bufferSize = 16
+
+// Hub keeps the open streams of this instance of the service. A user may
+// have several, one per browser tab.
+type Hub struct {
+	mu      sync.RWMutex
+	streams map[int64]map[chan *entity.Notification]struct{}
+	closed  bool
+}
+
+func NewHub() *Hub {
+	return &Hub{streams: map[int64]map[chan *entity.Notification]struct{}{}}
+}
+
+// Subscribe opens a stream for the user. The returned function closes it
+// and must be called when the connection ends. After Close the stream is
+// returned already closed.
+func (h *Hub) Subscribe(userID int64) (<-chan *entity.Notification, func()) {
+	ch := make(chan *entity.Notification, bufferSize)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	if h.streams[userID] == nil {
+		h.streams[userID] = map[chan *entity.Notification]struct{}{}
+	}
+	h.streams[userID][ch] = struct{}{}
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		// Канал уже закрыт, если поток закончился раньше — из-за Close
+		if _, open := h.streams[userID][ch]; !open {
+			return
+		}
+		delete(h.streams[userID], ch)
+		if len(h.streams[userID]) == 0 {
+			delete(h.streams, userID)
+		}
+		close(ch)
+	}
+}
+
+// Close ends every open stream and refuses new ones. The HTTP server
+// calls it on shutdown: it does not wait for streaming responses, which
+// otherwise only end when the client disconnects.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for userID, streams := range h.streams {
+		for ch := range streams {
+			close(ch)
+		}
+		delete(h.streams, userID)
+	}
+}
+
+// Publish sends the notification to every stream of its user without
+// blocking.
+func (h *Hub) Publish(notification *entity.Notification) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for ch := range h.streams[notification.UserID] {
+		select {
+		case ch <- notification:
+		default:
+		}
+	}
+}
+
+// Online reports whether the user has an open stream.
+func (h *Hub) Online(userID int64) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.streams[userID]) > 0
+}
@@ -0,0 +1,60 @@
+package push
+
+import (
+	"testing"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHubDeliversToEveryStreamOfTheUser(t *testing.T) {
+	hub := NewHub()
+	first, closeFirst := hub.Subscribe(1)
+	second, closeSecond := hub.Subscribe(1)
+	other, closeOther := hub.Subscribe(2)
+	defer closeOther()
+
+	hub.Publish(&entity.Notification{ID: 10, UserID: 1})
+
+	assert.Equal(t, int64(10), (<-first).ID)
+	assert.Equal(t, int64(10), (<-second).ID)
+	assert.Empty(t, other)
+
+	assert.True(t, hub.Online(1))
+	closeFirst()
+	closeFirst()
+	assert.True(t, hub.Online(1))
+	closeSecond()
+	assert.False(t, hub.Online(1))
+
+	_, open := <-first
+	assert.False(t, open)
+}
+
+func TestHubDropsWhenStreamIsFull(t *testing.T) {
+	hub := NewHub()
+	stream, closeStream := hub.Subscribe(1)
+	defer closeStream()
+
+	for i := 0; i < bufferSize+5; i++ {
+		hub.Publish(&entity.Notification{ID: int64(i), UserID: 1})
+	}
+	assert.Len(t, stream, bufferSize)
+}
+
+func TestHubCloseEndsOpenStreams(t *testing.T) {
+	hub := NewHub()
+	stream, closeStream := hub.Subscribe(1)
+
+	hub.Close()
+	_, open := <-stream
+	assert.False(t, open)
+	assert.False(t, hub.Online(1))
+	closeStream()
+
+	late, closeLate := hub.Subscribe(1)
+	defer closeLate()
+	_, open = <-late
+	assert.False(t, open)
+	hub.Publish(&entity.Notification{ID: 1, UserID: 1})
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type ApplicationExportRepositoryInterface interface {
+	Count(ctx context.Context, employerID int64, filter entity.ApplicationFilter) (int, error)
+	Stream(ctx context.Context, employerID int64, filter entity.ApplicationFilter, fn func(row *entity.ApplicationExportRow) error) error
+	Create(ctx context.Context, export *entity.ApplicationExport) error
+	GetByID(ctx context.Context, id int64) (*entity.ApplicationExport, error)
+	MarkRunning(ctx context.Context, id int64) error
+	Finish(ctx context.Context, export *entity.ApplicationExport) error
+	Fail(ctx context.Context, id int64, message string) error
+}
+
+type ApplicationExportRepository struct {
+	db *sqlx.DB
+}
+
+func NewApplicationExportRepository(db *sqlx.DB) *ApplicationExportRepository {
+	return &ApplicationExportRepository{db: db}
+}
+
+// Count returns the number of applications an export with the filter has.
+func (r *ApplicationExportRepository) Count(ctx context.Context, employerID int64, filter entity.ApplicationFilter) (int, error) {
+	conditions, args := employerApplicationConditions(employerID, filter)
+	query := `
+		SELECT COUNT(*)
+		FROM applications a
+		JOIN vacancies v ON v.id = a.vacancy_id
+		WHERE ` + strings.Join(conditions, "\n\t\t\tAND ")
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, query, args...); err != nil {
+		return 0, fmt.Errorf("failed to count applications to export: %w", err)
+	}
+	return count, nil
+}
+
+// Stream reads the matching applications with their reviews in one query
+// and passes them to fn one by one, newest first, so the result set is
+// never held in memory. An error from fn stops the export.
+func (r *ApplicationExportRepository) Stream(ctx context.Context, employerID int64, filter entity.ApplicationFilter, fn func(row *entity.ApplicationExportRow) error) error {
+	conditions, args := employerApplicationConditions(employerID, filter)
+	query := `
+		SELECT a.id, u.name, u.email, v.title, a.status, a.created_at, a.updated_at,
+			res.title, res.skills, resume_experience_years(res.id),
+			ARRAY(
+				SELECT t.tag FROM application_tags t WHERE t.application_id = a.id ORDER BY t.tag
+			) AS tags,
+			(
+				SELECT AVG(ar.rating)::float8 FROM application_ratings ar WHERE ar.application_id = a.id
+			) AS average_rating,
+			ARRAY(
+				SELECT COALESCE(nu.name, '') || ': ' || n.body
+				FROM application_notes n
+				LEFT JOIN users nu ON nu.id = n.author_id
+				WHERE n.application_id = a.id
+				ORDER BY n.created_at, n.id
+			) AS notes
+		FROM applications a
+		JOIN vacancies v ON v.id = a.vacancy_id
+		JOIN users u ON u.id = a.user_id
+		JOIN resumes res ON res.id = a.resume_id
+		WHERE ` + strings.Join(conditions, "\n\t\t\tAND ") + `
+		ORDER BY a.created_at DESC, a.id DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to export applications: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		row := &entity.ApplicationExportRow{}
+		err := rows.Scan(
+			&row.ApplicationID, &row.ApplicantName, &row.ApplicantEmail, &row.VacancyTitle, &row.Status,
+			&row.CreatedAt, &row.UpdatedAt,
+			&row.ResumeTitle, pq.Array(&row.Skills), &row.ExperienceYears,
+			pq.Array(&row.Tags), &row.AverageRating, pq.Array(&row.Notes),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to scan exported application: %w", err)
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to export applications: %w", err)
+	}
+	return nil
+}
+
+func (r *ApplicationExportRepository) Create(ctx context.Context, export *entity.ApplicationExport) error {
+	filter, err := json.Marshal(export.Filter)
+	if err != nil {
+		return fmt.Errorf("failed to encode export filter: %w", err)
+	}
+
+	export.Status = entity.ExportStatusPending
+	export.CreatedAt = time.Now()
+	err = r.db.QueryRowContext(ctx, `
+		INSERT INTO application_exports (employer_id, format, filter, status, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id`,
+		export.EmployerID, export.Format, filter, export.Status, export.CreatedAt,
+	).Scan(&export.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create export: %w", err)
+	}
+	return nil
+}
+
+func (r *ApplicationExportRepository) GetByID(ctx context.Context, id int64) (*entity.ApplicationExport, error) {
+	query := `
+		SELECT id, employer_id, format, filter, status, row_count, file_name, storage_key, size, error,
+			created_at, finished_at
+		FROM application_exports
+		WHERE id = $1`
+
+	var export entity.ApplicationExport
+	var filter []byte
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&export.ID, &export.EmployerID, &export.Format, &filter, &export.Status, &export.RowCount,
+		&export.FileName, &export.StorageKey, &export.Size, &export.Error,
+		&export.CreatedAt, &export.FinishedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get export: %w", err)
+	}
+	if err := json.Unmarshal(filter, &export.Filter); err != nil {
+		return nil, fmt.Errorf("failed to decode export filter: %w", err)
+	}
+	return &export, nil
+}
+
+func (r *ApplicationExportRepository) MarkRunning(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE application_exports SET status = $1 WHERE id = $2`,
+		entity.ExportStatusRunning, id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update export: %w", err)
+	}
+	return nil
+}
+
+// Finish records the generated file and marks the export done.
+func (r *ApplicationExportRepository) Finish(ctx context.Context, export *entity.ApplicationExport) error {
+	now := time.Now()
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE application_exports
+		SET status = $1, row_count = $2, file_name = $3, storage_key = $4, size = $5, finished_at = $6
+		WHERE id = $7`,
+		entity.ExportStatusDone, export.RowCount, export.FileName, export.StorageKey, export.Size, now, export.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to finish export: %w", err)
+	}
+	export.Status = entity.ExportStatusDone
+	export.FinishedAt = &now
+	return nil
+}
+
+func (r *ApplicationExportRepository) Fail(ctx context.Context, id int64, message string) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE application_exports SET status = $1, error = $2, finished_at = $3 WHERE id = $4`,
+		entity.ExportStatusFailed, message, time.Now(), id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update export: %w", err)
+	}
+	return nil
+}
@@ -2,21 +2,31 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type ApplicationRepositoryInterface interface {
 	Create(ctx context.Context, application *entity.Application) error
 	GetByID(ctx context.Context, id int64) (*entity.Application, error)
 	GetAll(ctx context.Context, userID int64) ([]*entity.Application, error)
+	GetByUserAndVacancy(ctx context.Context, userID, vacancyID int64) ([]*entity.Application, error)
 	GetByVacancyID(ctx context.Context, vacancyID int64) ([]*entity.Application, error)
+	ListByEmployer(ctx context.Context, employerID int64, filter entity.ApplicationFilter, limit, offset int) ([]*entity.Application, int, error)
 	Update(ctx context.Context, application *entity.Application) error
 	Delete(ctx context.Context, id int64) error
 	DeleteByResumeID(ctx context.Context, resumeID int64) error
+	EmployerHasResume(ctx context.Context, employerID, resumeID int64) (bool, error)
+	EmployerHasApplicant(ctx context.Context, employerID, userID int64) (bool, error)
+	ChangeStatus(ctx context.Context, id int64, from, to string, actorID int64, comment string) error
+	GetStatusHistory(ctx context.Context, id int64) ([]*entity.ApplicationStatusChange, error)
 }
 
 type ApplicationRepository struct {
@@ -31,9 +41,16 @@ func (r *ApplicationRepository) Create(ctx context.Context, application *entity.
 	fmt.Printf("ApplicationRepository.Create called with application: %+v\n", application)
 
 	query := `
-		INSERT INTO applications (user_id, vacancy_id, resume_id, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id`
+		INSERT INTO applications (user_id, vacancy_id, resume_id, status, screening_flagged, cover_letter, stage_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $7, $8, (
+			SELECT s.id
+			FROM pipeline_stages s
+			JOIN vacancies v ON v.pipeline_id = s.pipeline_id
+			WHERE v.id = $2
+			ORDER BY s.position
+			LIMIT 1
+		), $5, $6)
+		RETURNING id, stage_id, (SELECT employer_id FROM vacancies WHERE id = $2)`
 
 	now := time.Now()
 	application.CreatedAt = now
@@ -48,7 +65,14 @@ func (r *ApplicationRepository) Create(ctx context.Context, application *entity.
 		"updated_at": application.UpdatedAt,
 	})
 
-	err := r.db.QueryRowContext(
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var employerID int64
+	err = tx.QueryRowContext(
 		ctx,
 		query,
 		application.UserID,
@@ -57,25 +81,60 @@ func (r *ApplicationRepository) Create(ctx context.Context, application *entity.
 		application.Status,
 		application.CreatedAt,
 		application.UpdatedAt,
-	).Scan(&application.ID)
+		application.Flagged,
+		application.CoverLetter,
+	).Scan(&application.ID, &application.StageID, &employerID)
 
 	if err != nil {
 		fmt.Printf("Error creating application in database: %v\n", err)
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "applications_user_vacancy_key" {
+			return entity.ErrAlreadyApplied
+		}
 		return fmt.Errorf("failed to create application: %w", err)
 	}
 
+	// Первая запись истории — подача отклика соискателем
+	if err := insertStatusChange(ctx, tx, application.ID, "", application.Status, application.UserID, "", application.CreatedAt); err != nil {
+		return err
+	}
+
+	for _, answer := range application.Answers {
+		answer.ApplicationID = application.ID
+		if err := insertScreeningAnswer(ctx, tx, answer); err != nil {
+			return err
+		}
+	}
+
+	err = enqueueWebhook(ctx, tx, employerID, entity.EventApplicationCreated, entity.ApplicationEventData{
+		ApplicationID: application.ID,
+		VacancyID:     application.VacancyID,
+		UserID:        application.UserID,
+		Status:        application.Status,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit application: %w", err)
+	}
+
 	fmt.Printf("Application created successfully with ID: %d\n", application.ID)
 	return nil
 }
 
 func (r *ApplicationRepository) GetByID(ctx context.Context, id int64) (*entity.Application, error) {
 	query := `
-		SELECT id, user_id, vacancy_id, resume_id, status, created_at, updated_at
+		SELECT id, user_id, vacancy_id, resume_id, status, stage_id, screening_flagged, cover_letter, created_at, updated_at
 		FROM applications
 		WHERE id = $1`
 
 	application := &entity.Application{}
 	err := r.db.GetContext(ctx, application, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get application: %w", err)
 	}
@@ -85,7 +144,7 @@ func (r *ApplicationRepository) GetByID(ctx context.Context, id int64) (*entity.
 
 func (r *ApplicationRepository) GetAll(ctx context.Context, userID int64) ([]*entity.Application, error) {
 	query := `
-		SELECT id, user_id, vacancy_id, resume_id, status, created_at, updated_at
+		SELECT id, user_id, vacancy_id, resume_id, status, stage_id, screening_flagged, cover_letter, created_at, updated_at
 		FROM applications
 		WHERE user_id = $1
 		ORDER BY created_at DESC`
@@ -99,25 +158,171 @@ func (r *ApplicationRepository) GetAll(ctx context.Context, userID int64) ([]*en
 	return applications, nil
 }
 
-func (r *ApplicationRepository) GetByVacancyID(ctx context.Context, vacancyID int64) ([]*entity.Application, error) {
-	fmt.Printf("GetByVacancyID: Starting query for vacancy ID=%d\n", vacancyID)
+// GetByUserAndVacancy returns the user's applications to the vacancy: at
+// most one open application and any number of withdrawn ones.
+func (r *ApplicationRepository) GetByUserAndVacancy(ctx context.Context, userID, vacancyID int64) ([]*entity.Application, error) {
 	query := `
-		SELECT id, user_id, vacancy_id, resume_id, status, created_at, updated_at
+		SELECT id, user_id, vacancy_id, resume_id, status, stage_id, screening_flagged, cover_letter, created_at, updated_at
 		FROM applications
-		WHERE vacancy_id = $1
+		WHERE user_id = $1 AND vacancy_id = $2
 		ORDER BY created_at DESC`
 
-	var applications []*entity.Application
-	err := r.db.SelectContext(ctx, &applications, query, vacancyID)
+	applications := []*entity.Application{}
+	if err := r.db.SelectContext(ctx, &applications, query, userID, vacancyID); err != nil {
+		return nil, fmt.Errorf("failed to get user applications to vacancy: %w", err)
+	}
+	return applications, nil
+}
+
+// GetByVacancyID returns the applications to the vacancy, newest first,
+// together with the applicant's name and email.
+func (r *ApplicationRepository) GetByVacancyID(ctx context.Context, vacancyID int64) ([]*entity.Application, error) {
+	query := `
+		SELECT a.id, a.user_id, a.vacancy_id, a.resume_id, a.status, a.stage_id, a.screening_flagged,
+			a.cover_letter, a.created_at, a.updated_at,
+			u.name, u.email
+		FROM applications a
+		JOIN users u ON u.id = a.user_id
+		WHERE a.vacancy_id = $1
+		ORDER BY a.created_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, vacancyID)
 	if err != nil {
-		fmt.Printf("GetByVacancyID: Error executing query: %v\n", err)
+		return nil, fmt.Errorf("failed to get applications by vacancy ID: %w", err)
+	}
+	defer rows.Close()
+
+	applications := []*entity.Application{}
+	for rows.Next() {
+		a := &entity.Application{}
+		err := rows.Scan(
+			&a.ID, &a.UserID, &a.VacancyID, &a.ResumeID, &a.Status, &a.StageID, &a.Flagged,
+			&a.CoverLetter, &a.CreatedAt, &a.UpdatedAt,
+			&a.ApplicantName, &a.ApplicantEmail,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan application: %w", err)
+		}
+		applications = append(applications, a)
+	}
+	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("failed to get applications by vacancy ID: %w", err)
 	}
 
-	fmt.Printf("GetByVacancyID: Found %d applications for vacancy ID=%d\n", len(applications), vacancyID)
 	return applications, nil
 }
 
+// ListByEmployer returns a page of applications to the employer's vacancies,
+// newest first, together with the applicant and the resume, and the total
+// number of matching applications. Everything comes from one query.
+func (r *ApplicationRepository) ListByEmployer(ctx context.Context, employerID int64, filter entity.ApplicationFilter, limit, offset int) ([]*entity.Application, int, error) {
+	conditions, args := employerApplicationConditions(employerID, filter)
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	// Общее число строк считаем оконной функцией, чтобы обойтись одним запросом.
+	// Публичную ссылку на резюме работодателю не отдаем, поэтому ее не читаем
+	query := `
+		SELECT a.id, a.user_id, a.vacancy_id, a.resume_id, a.status, a.stage_id, a.screening_flagged,
+			a.cover_letter, a.created_at, a.updated_at,
+			u.name, u.email,
+			res.id, res.user_id, res.title, COALESCE(res.description, ''), res.skills, res.location,
+			resume_experience_years(res.id), COALESCE(res.legacy_experience, ''), COALESCE(res.legacy_education, ''),
+			res.status, res.visibility, res.created_at, res.updated_at,
+			COUNT(*) OVER () AS total
+		FROM applications a
+		JOIN vacancies v ON v.id = a.vacancy_id
+		JOIN users u ON u.id = a.user_id
+		JOIN resumes res ON res.id = a.resume_id
+		WHERE ` + strings.Join(conditions, "\n\t\t\tAND ") + `
+		ORDER BY a.created_at DESC, a.id DESC
+		LIMIT ` + arg(limit) + ` OFFSET ` + arg(offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list employer applications: %w", err)
+	}
+	defer rows.Close()
+
+	applications := []*entity.Application{}
+	total := 0
+	for rows.Next() {
+		a := &entity.Application{Resume: &entity.Resume{}}
+		res := a.Resume
+		err := rows.Scan(
+			&a.ID, &a.UserID, &a.VacancyID, &a.ResumeID, &a.Status, &a.StageID, &a.Flagged,
+			&a.CoverLetter, &a.CreatedAt, &a.UpdatedAt,
+			&a.ApplicantName, &a.ApplicantEmail,
+			&res.ID, &res.UserID, &res.Title, &res.Description, pq.Array(&res.Skills), &res.Location,
+			&res.TotalExperienceYears, &res.LegacyExperience, &res.LegacyEducation,
+			&res.Status, &res.Visibility, &res.CreatedAt, &res.UpdatedAt,
+			&total,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan employer application: %w", err)
+		}
+		if res.Skills == nil {
+			res.Skills = []string{}
+		}
+		applications = append(applications, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to list employer applications: %w", err)
+	}
+
+	// За пределами последней страницы строк нет, и итог приходится считать отдельно
+	if len(applications) == 0 && offset > 0 {
+		countQuery := `
+			SELECT COUNT(*)
+			FROM applications a
+			JOIN vacancies v ON v.id = a.vacancy_id
+			WHERE ` + strings.Join(conditions, "\n\t\t\tAND ")
+		if err := r.db.GetContext(ctx, &total, countQuery, args[:len(args)-2]...); err != nil {
+			return nil, 0, fmt.Errorf("failed to count employer applications: %w", err)
+		}
+	}
+
+	return applications, total, nil
+}
+
+// employerApplicationConditions builds the WHERE conditions selecting the
+// applications to the employer's and their organization's vacancies that
+// match the filter. The query has to join vacancies as v to applications as a.
+func employerApplicationConditions(employerID int64, filter entity.ApplicationFilter) ([]string, []interface{}) {
+	args := []interface{}{employerID}
+	conditions := []string{vacancyAccessCondition("v", "$1")}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if len(filter.VacancyIDs) > 0 {
+		conditions = append(conditions, "a.vacancy_id = ANY("+arg(pq.Array(filter.VacancyIDs))+")")
+	}
+	if len(filter.Statuses) > 0 {
+		conditions = append(conditions, "a.status = ANY("+arg(pq.Array(filter.Statuses))+")")
+	}
+	if filter.CreatedFrom != nil {
+		conditions = append(conditions, "a.created_at >= "+arg(*filter.CreatedFrom))
+	}
+	if filter.CreatedTo != nil {
+		conditions = append(conditions, "a.created_at <= "+arg(*filter.CreatedTo))
+	}
+	if len(filter.Tags) > 0 {
+		conditions = append(conditions, arg(pq.Array(filter.Tags))+`::text[] <@ ARRAY(
+				SELECT t.tag FROM application_tags t WHERE t.application_id = a.id
+			)`)
+	}
+	if filter.MinRating != nil {
+		conditions = append(conditions, `(
+				SELECT AVG(ar.rating) FROM application_ratings ar WHERE ar.application_id = a.id
+			) >= `+arg(*filter.MinRating))
+	}
+	return conditions, args
+}
+
 func (r *ApplicationRepository) Update(ctx context.Context, application *entity.Application) error {
 	query := `
 		UPDATE applications
@@ -182,3 +387,114 @@ func (r *ApplicationRepository) DeleteByResumeID(ctx context.Context, resumeID i
 
 	return nil
 }
+
+// EmployerHasResume reports whether the resume was sent in an application to
+// one of the vacancies the employer has access to.
+func (r *ApplicationRepository) EmployerHasResume(ctx context.Context, employerID, resumeID int64) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM applications a
+			JOIN vacancies v ON v.id = a.vacancy_id
+			WHERE a.resume_id = $1 AND ` + vacancyAccessCondition("v", "$2") + `
+		)`
+
+	var exists bool
+	if err := r.db.GetContext(ctx, &exists, query, resumeID, employerID); err != nil {
+		return false, fmt.Errorf("failed to check employer access to resume: %w", err)
+	}
+
+	return exists, nil
+}
+
+// EmployerHasApplicant reports whether the user applied to any of the
+// vacancies the employer has access to.
+func (r *ApplicationRepository) EmployerHasApplicant(ctx context.Context, employerID, userID int64) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM applications a
+			JOIN vacancies v ON v.id = a.vacancy_id
+			WHERE a.user_id = $1 AND ` + vacancyAccessCondition("v", "$2") + `
+		)`
+
+	var exists bool
+	if err := r.db.GetContext(ctx, &exists, query, userID, employerID); err != nil {
+		return false, fmt.Errorf("failed to check employer applicants: %w", err)
+	}
+
+	return exists, nil
+}
+
+// insertStatusChange records a transition. A zero actorID marks a change made
+// by the system, e.g. an automatic rejection.
+func insertStatusChange(ctx context.Context, tx *sqlx.Tx, applicationID int64, from, to string, actorID int64, comment string, at time.Time) error {
+	query := `
+		INSERT INTO application_status_history (application_id, from_status, to_status, actor_id, comment, created_at)
+		VALUES ($1, $2, $3, NULLIF($4::bigint, 0), $5, $6)`
+
+	if _, err := tx.ExecContext(ctx, query, applicationID, from, to, actorID, comment, at); err != nil {
+		return fmt.Errorf("failed to record status change: %w", err)
+	}
+	return nil
+}
+
+// ChangeStatus moves the application from one status to another, records
+// the transition and posts it to the application's message thread. The
+// update only applies if the status is still from, so two concurrent
+// transitions cannot both succeed.
+func (r *ApplicationRepository) ChangeStatus(ctx context.Context, id int64, from, to string, actorID int64, comment string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	event := entity.ApplicationEventData{ApplicationID: id, Status: to, FromStatus: from, Comment: comment}
+	var employerID int64
+	err = tx.QueryRowContext(ctx, `
+		UPDATE applications a SET status = $1, updated_at = $2
+		FROM vacancies v
+		WHERE v.id = a.vacancy_id AND a.id = $3 AND a.status = $4
+		RETURNING a.vacancy_id, a.user_id, v.employer_id`,
+		to, now, id, from,
+	).Scan(&event.VacancyID, &event.UserID, &employerID)
+	if err == sql.ErrNoRows {
+		return entity.ErrApplicationStatusConflict
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update application status: %w", err)
+	}
+
+	if err := insertStatusChange(ctx, tx, id, from, to, actorID, comment, now); err != nil {
+		return err
+	}
+	if err := insertSystemMessage(ctx, tx, id, entity.StatusChangeMessage(to, comment), now); err != nil {
+		return err
+	}
+	if err := enqueueWebhook(ctx, tx, employerID, entity.EventApplicationStatusChanged, event); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit status change: %w", err)
+	}
+	return nil
+}
+
+func (r *ApplicationRepository) GetStatusHistory(ctx context.Context, id int64) ([]*entity.ApplicationStatusChange, error) {
+	query := `
+		SELECT h.id, h.application_id, h.from_status, h.to_status, h.actor_id,
+			COALESCE(u.name, '') AS actor_name, h.comment, h.created_at
+		FROM application_status_history h
+		LEFT JOIN users u ON u.id = h.actor_id
+		WHERE h.application_id = $1
+		ORDER BY h.created_at, h.id`
+
+	history := []*entity.ApplicationStatusChange{}
+	if err := r.db.SelectContext(ctx, &history, query, id); err != nil {
+		return nil, fmt.Errorf("failed to get application status history: %w", err)
+	}
+	return history, nil
+}
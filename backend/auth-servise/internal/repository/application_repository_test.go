@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplicationCreateDuplicate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	r := NewApplicationRepository(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO applications`).
+		WillReturnError(&pq.Error{Code: "23505", Constraint: "applications_user_vacancy_key"})
+	mock.ExpectRollback()
+
+	err = r.Create(context.Background(), &entity.Application{UserID: 1, VacancyID: 2, ResumeID: 3, Status: entity.ApplicationStatusPending})
+	assert.ErrorIs(t, err, entity.ErrAlreadyApplied)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestApplicationGetByVacancyIDJoinsApplicants(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	r := NewApplicationRepository(sqlx.NewDb(db, "sqlmock"))
+
+	now := time.Now()
+	mock.ExpectQuery(`FROM applications a\s+JOIN users u ON u.id = a.user_id\s+WHERE a.vacancy_id = \$1`).
+		WithArgs(int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "user_id", "vacancy_id", "resume_id", "status", "stage_id", "screening_flagged",
+			"cover_letter", "created_at", "updated_at", "name", "email",
+		}).AddRow(1, 3, 2, 4, entity.ApplicationStatusPending, nil, false, "", now, now, "Анна", "anna@example.com"))
+
+	applications, err := r.GetByVacancyID(context.Background(), 2)
+	require.NoError(t, err)
+	require.Len(t, applications, 1)
+	assert.Equal(t, "Анна", applications[0].ApplicantName)
+	assert.Equal(t, "anna@example.com", applications[0].ApplicantEmail)
+	assert.NoError(t, mock.ExpectationsWereMet(), "applicants come from the same query")
+}
+
+// openTestDB connects to the database in TEST_DATABASE_URL and applies the
+// migrations. Tests that need a real PostgreSQL are skipped without it;
+// make test-integration starts one from docker-compose.test.yml.
+func openTestDB(t *testing.T) *sqlx.DB {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set, run make test-integration")
+	}
+
+	m, err := migrate.New("file://../../migrations", url)
+	require.NoError(t, err)
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		require.NoError(t, err)
+	}
+
+	db, err := sqlx.Connect("postgres", url)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestApplicationCreateConcurrent(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	suffix := time.Now().UnixNano()
+
+	var employerID, jobseekerID, vacancyID, resumeID int64
+	require.NoError(t, db.GetContext(ctx, &employerID,
+		`INSERT INTO users (email, password, name, role) VALUES ($1, 'x', 'Employer', 'employer') RETURNING id`,
+		fmt.Sprintf("employer-%d@example.com", suffix)))
+	require.NoError(t, db.GetContext(ctx, &jobseekerID,
+		`INSERT INTO users (email, password, name, role) VALUES ($1, 'x', 'Jobseeker', 'jobseeker') RETURNING id`,
+		fmt.Sprintf("jobseeker-%d@example.com", suffix)))
+	require.NoError(t, db.GetContext(ctx, &vacancyID, `
+		INSERT INTO vacancies (employer_id, title, salary, location, employment_type, company)
+		VALUES ($1, 'Go developer', 1, 'Remote', 'full', 'Acme') RETURNING id`, employerID))
+	require.NoError(t, db.GetContext(ctx, &resumeID,
+		`INSERT INTO resumes (user_id, title, location) VALUES ($1, 'Go developer', 'Remote') RETURNING id`, jobseekerID))
+	t.Cleanup(func() {
+		db.Exec(`DELETE FROM users WHERE id IN ($1, $2)`, employerID, jobseekerID)
+	})
+
+	r := NewApplicationRepository(db)
+	const attempts = 20
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	errs := make(chan error, attempts)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			errs <- r.Create(ctx, &entity.Application{
+				UserID:    jobseekerID,
+				VacancyID: vacancyID,
+				ResumeID:  resumeID,
+				Status:    entity.ApplicationStatusPending,
+			})
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(errs)
+
+	created := 0
+	for err := range errs {
+		if err == nil {
+			created++
+			continue
+		}
+		assert.ErrorIs(t, err, entity.ErrAlreadyApplied)
+	}
+	assert.Equal(t, 1, created)
+
+	applications, err := r.GetByUserAndVacancy(ctx, jobseekerID, vacancyID)
+	require.NoError(t, err)
+	assert.Len(t, applications, 1)
+}
+
+func TestApplicationChangeStatusPostsSystemMessage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	r := NewApplicationRepository(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE applications a SET status`).
+		WithArgs(entity.ApplicationStatusRejected, sqlmock.AnyArg(), int64(7), entity.ApplicationStatusViewed).
+		WillReturnRows(sqlmock.NewRows([]string{"vacancy_id", "user_id", "employer_id"}).AddRow(2, 1, 5))
+	mock.ExpectExec(`INSERT INTO application_status_history`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO application_messages`).
+		WithArgs(int64(7), entity.MessageKindSystem, "Статус отклика изменен: «Отказ»\n\nНе хватает опыта", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO webhook_deliveries`).
+		WithArgs(int64(5), entity.EventApplicationStatusChanged, sqlmock.AnyArg(), entity.DeliveryStatusPending, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err = r.ChangeStatus(context.Background(), 7, entity.ApplicationStatusViewed, entity.ApplicationStatusRejected, 3, "Не хватает опыта")
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
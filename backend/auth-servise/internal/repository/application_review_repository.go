@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type ApplicationReviewRepositoryInterface interface {
+	AddNote(ctx context.Context, note *entity.ApplicationNote) error
+	UpdateNote(ctx context.Context, note *entity.ApplicationNote) error
+	DeleteNote(ctx context.Context, applicationID, id, authorID int64) error
+	GetNotes(ctx context.Context, applicationID int64) ([]*entity.ApplicationNote, error)
+	SetRating(ctx context.Context, applicationID, reviewerID int64, rating int) error
+	DeleteRating(ctx context.Context, applicationID, reviewerID int64) error
+	GetRatings(ctx context.Context, applicationID int64) ([]*entity.ApplicationRating, error)
+	SetTags(ctx context.Context, applicationID int64, tags []string, actorID int64) error
+	GetEmployerTags(ctx context.Context, employerID int64) ([]string, error)
+	GetSummaries(ctx context.Context, applicationIDs []int64) (map[int64]*entity.ApplicationReviewSummary, error)
+}
+
+type ApplicationReviewRepository struct {
+	db *sqlx.DB
+}
+
+func NewApplicationReviewRepository(db *sqlx.DB) *ApplicationReviewRepository {
+	return &ApplicationReviewRepository{db: db}
+}
+
+func (r *ApplicationReviewRepository) AddNote(ctx context.Context, note *entity.ApplicationNote) error {
+	query := `
+		INSERT INTO application_notes (application_id, author_id, body, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id`
+
+	now := time.Now()
+	note.CreatedAt = now
+	note.UpdatedAt = now
+
+	err := r.db.QueryRowContext(ctx, query, note.ApplicationID, note.AuthorID, note.Body, now, now).Scan(&note.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create note: %w", err)
+	}
+	return nil
+}
+
+// UpdateNote changes the text of a note. Only the author can edit it.
+func (r *ApplicationReviewRepository) UpdateNote(ctx context.Context, note *entity.ApplicationNote) error {
+	query := `
+		UPDATE application_notes
+		SET body = $1, updated_at = $2
+		WHERE id = $3 AND application_id = $4 AND author_id = $5
+		RETURNING created_at`
+
+	note.UpdatedAt = time.Now()
+	err := r.db.QueryRowContext(ctx, query, note.Body, note.UpdatedAt, note.ID, note.ApplicationID, note.AuthorID).
+		Scan(&note.CreatedAt)
+	if err == sql.ErrNoRows {
+		return entity.ErrApplicationNoteNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update note: %w", err)
+	}
+	return nil
+}
+
+func (r *ApplicationReviewRepository) DeleteNote(ctx context.Context, applicationID, id, authorID int64) error {
+	query := `DELETE FROM application_notes WHERE id = $1 AND application_id = $2 AND author_id = $3`
+
+	result, err := r.db.ExecContext(ctx, query, id, applicationID, authorID)
+	if err != nil {
+		return fmt.Errorf("failed to delete note: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return entity.ErrApplicationNoteNotFound
+	}
+	return nil
+}
+
+func (r *ApplicationReviewRepository) GetNotes(ctx context.Context, applicationID int64) ([]*entity.ApplicationNote, error) {
+	query := `
+		SELECT n.id, n.application_id, n.author_id, u.name AS author_name, n.body, n.created_at, n.updated_at
+		FROM application_notes n
+		JOIN users u ON u.id = n.author_id
+		WHERE n.application_id = $1
+		ORDER BY n.created_at, n.id`
+
+	notes := []*entity.ApplicationNote{}
+	if err := r.db.SelectContext(ctx, &notes, query, applicationID); err != nil {
+		return nil, fmt.Errorf("failed to get notes: %w", err)
+	}
+	return notes, nil
+}
+
+// SetRating stores the reviewer's rating, replacing a previous one.
+func (r *ApplicationReviewRepository) SetRating(ctx context.Context, applicationID, reviewerID int64, rating int) error {
+	query := `
+		INSERT INTO application_ratings (application_id, reviewer_id, rating, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $4)
+		ON CONFLICT (application_id, reviewer_id)
+		DO UPDATE SET rating = EXCLUDED.rating, updated_at = EXCLUDED.updated_at`
+
+	if _, err := r.db.ExecContext(ctx, query, applicationID, reviewerID, rating, time.Now()); err != nil {
+		return fmt.Errorf("failed to set rating: %w", err)
+	}
+	return nil
+}
+
+func (r *ApplicationReviewRepository) DeleteRating(ctx context.Context, applicationID, reviewerID int64) error {
+	query := `DELETE FROM application_ratings WHERE application_id = $1 AND reviewer_id = $2`
+	if _, err := r.db.ExecContext(ctx, query, applicationID, reviewerID); err != nil {
+		return fmt.Errorf("failed to delete rating: %w", err)
+	}
+	return nil
+}
+
+func (r *ApplicationReviewRepository) GetRatings(ctx context.Context, applicationID int64) ([]*entity.ApplicationRating, error) {
+	query := `
+		SELECT r.application_id, r.reviewer_id, u.name AS reviewer_name, r.rating, r.created_at, r.updated_at
+		FROM application_ratings r
+		JOIN users u ON u.id = r.reviewer_id
+		WHERE r.application_id = $1
+		ORDER BY r.created_at`
+
+	ratings := []*entity.ApplicationRating{}
+	if err := r.db.SelectContext(ctx, &ratings, query, applicationID); err != nil {
+		return nil, fmt.Errorf("failed to get ratings: %w", err)
+	}
+	return ratings, nil
+}
+
+// SetTags replaces the tags of the application.
+func (r *ApplicationReviewRepository) SetTags(ctx context.Context, applicationID int64, tags []string, actorID int64) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx,
+		`DELETE FROM application_tags WHERE application_id = $1 AND NOT (tag = ANY($2))`,
+		applicationID, pq.Array(tags),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete tags: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO application_tags (application_id, tag, created_by, created_at)
+		SELECT $1, tag, $3, $4 FROM unnest($2::text[]) AS tag
+		ON CONFLICT (application_id, tag) DO NOTHING`,
+		applicationID, pq.Array(tags), actorID, time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add tags: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit tags: %w", err)
+	}
+	return nil
+}
+
+// GetEmployerTags returns the tags used on applications to the vacancies the
+// employer has access to, most used first.
+func (r *ApplicationReviewRepository) GetEmployerTags(ctx context.Context, employerID int64) ([]string, error) {
+	query := `
+		SELECT t.tag
+		FROM application_tags t
+		JOIN applications a ON a.id = t.application_id
+		JOIN vacancies v ON v.id = a.vacancy_id
+		WHERE ` + vacancyAccessCondition("v", "$1") + `
+		GROUP BY t.tag
+		ORDER BY COUNT(*) DESC, t.tag`
+
+	tags := []string{}
+	if err := r.db.SelectContext(ctx, &tags, query, employerID); err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+	return tags, nil
+}
+
+// GetSummaries returns the tags and the average rating of each of the
+// applications that has any.
+func (r *ApplicationReviewRepository) GetSummaries(ctx context.Context, applicationIDs []int64) (map[int64]*entity.ApplicationReviewSummary, error) {
+	summaries := make(map[int64]*entity.ApplicationReviewSummary)
+	if len(applicationIDs) == 0 {
+		return summaries, nil
+	}
+
+	get := func(id int64) *entity.ApplicationReviewSummary {
+		summary, ok := summaries[id]
+		if !ok {
+			summary = &entity.ApplicationReviewSummary{Tags: []string{}}
+			summaries[id] = summary
+		}
+		return summary
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT application_id, array_agg(tag ORDER BY tag)
+		FROM application_tags
+		WHERE application_id = ANY($1)
+		GROUP BY application_id`,
+		pq.Array(applicationIDs),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int64
+		var tags []string
+		if err := rows.Scan(&id, pq.Array(&tags)); err != nil {
+			return nil, fmt.Errorf("failed to scan tags: %w", err)
+		}
+		get(id).Tags = tags
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+
+	rows, err = r.db.QueryContext(ctx, `
+		SELECT application_id, AVG(rating)::float8, COUNT(*)
+		FROM application_ratings
+		WHERE application_id = ANY($1)
+		GROUP BY application_id`,
+		pq.Array(applicationIDs),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ratings: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int64
+		var average float64
+		var count int
+		if err := rows.Scan(&id, &average, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan ratings: %w", err)
+		}
+		summary := get(id)
+		summary.AverageRating = &average
+		summary.RatingsCount = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get ratings: %w", err)
+	}
+
+	return summaries, nil
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+type AuditRepositoryInterface interface {
+	Append(ctx context.Context, key []byte, entries []*entity.AuditEntry) error
+	List(ctx context.Context, filter entity.AuditFilter, limit, offset int) ([]*entity.AuditEntry, int, error)
+	ListAfter(ctx context.Context, afterID int64, limit int) ([]*entity.AuditEntry, error)
+}
+
+type AuditRepository struct {
+	db *sqlx.DB
+}
+
+func NewAuditRepository(db *sqlx.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+const auditColumns = `id, actor_id, action, target_type, target_id,
+	COALESCE(before_state::text, '') AS before_state, COALESCE(after_state::text, '') AS after_state,
+	ip, user_agent, created_at, prev_hash, hash`
+
+// Append links the entries to the last one and to each other, hashing them
+// with key, and stores them in one transaction. Appends are serialized so
+// that the chain never forks; appending several entries at once takes the
+// lock once for all of them.
+func (r *AuditRepository) Append(ctx context.Context, key []byte, entries []*entity.AuditEntry) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// EXCLUSIVE не мешает чтению журнала, но блокирует параллельные вставки
+	if _, err := tx.ExecContext(ctx, `LOCK TABLE audit_log IN EXCLUSIVE MODE`); err != nil {
+		return fmt.Errorf("failed to lock audit log: %w", err)
+	}
+
+	var prevHash string
+	err = tx.GetContext(ctx, &prevHash, `SELECT hash FROM audit_log ORDER BY id DESC LIMIT 1`)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to get last audit entry: %w", err)
+	}
+
+	query := `
+		INSERT INTO audit_log (actor_id, action, target_type, target_id, before_state, after_state,
+			ip, user_agent, created_at, prev_hash, hash)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id`
+	for _, entry := range entries {
+		entry.PrevHash = prevHash
+		entry.Hash = entry.ComputeHash(key)
+		err = tx.QueryRowContext(ctx, query,
+			entry.ActorID, entry.Action, entry.TargetType, entry.TargetID,
+			auditJSON(entry.Before), auditJSON(entry.After),
+			entry.IP, entry.UserAgent, entry.CreatedAt, entry.PrevHash, entry.Hash,
+		).Scan(&entry.ID)
+		if err != nil {
+			return fmt.Errorf("failed to append audit entry: %w", err)
+		}
+		prevHash = entry.Hash
+	}
+
+	return tx.Commit()
+}
+
+// auditJSON passes a snapshot as text, so that the JSON column keeps it byte
+// for byte as it was hashed.
+func auditJSON(snapshot []byte) interface{} {
+	if len(snapshot) == 0 {
+		return nil
+	}
+	return string(snapshot)
+}
+
+// List returns a page of entries matching the filter, newest first, and the
+// total number of matching entries.
+func (r *AuditRepository) List(ctx context.Context, filter entity.AuditFilter, limit, offset int) ([]*entity.AuditEntry, int, error) {
+	conditions := []string{"TRUE"}
+	args := []interface{}{}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.ActorID != 0 {
+		conditions = append(conditions, "actor_id = "+arg(filter.ActorID))
+	}
+	if filter.Action != "" {
+		conditions = append(conditions, "action = "+arg(filter.Action))
+	}
+	if filter.TargetType != "" {
+		conditions = append(conditions, "target_type = "+arg(filter.TargetType))
+	}
+	if filter.TargetID != 0 {
+		conditions = append(conditions, "target_id = "+arg(filter.TargetID))
+	}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, "created_at >= "+arg(filter.From))
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, "created_at < "+arg(filter.To))
+	}
+	where := strings.Join(conditions, "\n\t\t\tAND ")
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM audit_log WHERE `+where, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit entries: %w", err)
+	}
+
+	query := `
+		SELECT ` + auditColumns + `
+		FROM audit_log
+		WHERE ` + where + `
+		ORDER BY id DESC
+		LIMIT ` + arg(limit) + ` OFFSET ` + arg(offset)
+	entries := []*entity.AuditEntry{}
+	if err := r.db.SelectContext(ctx, &entries, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+	return entries, total, nil
+}
+
+// ListAfter returns up to limit entries following afterID in chain order.
+func (r *AuditRepository) ListAfter(ctx context.Context, afterID int64, limit int) ([]*entity.AuditEntry, error) {
+	query := `
+		SELECT ` + auditColumns + `
+		FROM audit_log
+		WHERE id > $1
+		ORDER BY id
+		LIMIT $2`
+	entries := []*entity.AuditEntry{}
+	if err := r.db.SelectContext(ctx, &entries, query, afterID, limit); err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+	return entries, nil
+}
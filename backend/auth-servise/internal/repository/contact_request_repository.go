@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type ContactRequestRepositoryInterface interface {
+	Create(ctx context.Context, request *entity.ContactRequest) error
+	GetByID(ctx context.Context, id int64) (*entity.ContactRequest, error)
+	GetByEmployerID(ctx context.Context, employerID int64) ([]*entity.ContactRequest, error)
+	GetByJobseekerID(ctx context.Context, jobseekerID int64) ([]*entity.ContactRequest, error)
+	UpdateStatus(ctx context.Context, id int64, status string) error
+	IsAccepted(ctx context.Context, employerID, jobseekerID int64) (bool, error)
+}
+
+type ContactRequestRepository struct {
+	db *sqlx.DB
+}
+
+func NewContactRequestRepository(db *sqlx.DB) *ContactRequestRepository {
+	return &ContactRequestRepository{db: db}
+}
+
+// The candidate's name is part of their contacts, so the employer only sees
+// it once the request is accepted.
+const contactRequestSelect = `
+		SELECT
+			cr.id,
+			cr.employer_id,
+			cr.jobseeker_id,
+			cr.resume_id,
+			cr.message,
+			cr.status,
+			cr.created_at,
+			cr.responded_at,
+			e.name AS employer_name,
+			CASE WHEN cr.status = 'accepted' THEN j.name ELSE '' END AS jobseeker_name
+		FROM contact_requests cr
+		JOIN users e ON e.id = cr.employer_id
+		JOIN users j ON j.id = cr.jobseeker_id`
+
+func (r *ContactRequestRepository) Create(ctx context.Context, request *entity.ContactRequest) error {
+	query := `
+		INSERT INTO contact_requests (employer_id, jobseeker_id, resume_id, message, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`
+
+	request.Status = entity.ContactRequestPending
+	request.CreatedAt = time.Now()
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		request.EmployerID,
+		request.JobseekerID,
+		request.ResumeID,
+		request.Message,
+		request.Status,
+		request.CreatedAt,
+	).Scan(&request.ID)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return entity.ErrContactRequestExists
+		}
+		return fmt.Errorf("failed to create contact request: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ContactRequestRepository) GetByID(ctx context.Context, id int64) (*entity.ContactRequest, error) {
+	var request entity.ContactRequest
+	err := r.db.GetContext(ctx, &request, contactRequestSelect+` WHERE cr.id = $1`, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contact request: %w", err)
+	}
+	return &request, nil
+}
+
+func (r *ContactRequestRepository) GetByEmployerID(ctx context.Context, employerID int64) ([]*entity.ContactRequest, error) {
+	requests := []*entity.ContactRequest{}
+	query := contactRequestSelect + ` WHERE cr.employer_id = $1 ORDER BY cr.created_at DESC`
+	if err := r.db.SelectContext(ctx, &requests, query, employerID); err != nil {
+		return nil, fmt.Errorf("failed to get contact requests: %w", err)
+	}
+	return requests, nil
+}
+
+func (r *ContactRequestRepository) GetByJobseekerID(ctx context.Context, jobseekerID int64) ([]*entity.ContactRequest, error) {
+	requests := []*entity.ContactRequest{}
+	query := contactRequestSelect + ` WHERE cr.jobseeker_id = $1 ORDER BY cr.created_at DESC`
+	if err := r.db.SelectContext(ctx, &requests, query, jobseekerID); err != nil {
+		return nil, fmt.Errorf("failed to get contact requests: %w", err)
+	}
+	return requests, nil
+}
+
+func (r *ContactRequestRepository) UpdateStatus(ctx context.Context, id int64, status string) error {
+	query := `
+		UPDATE contact_requests
+		SET status = $1, responded_at = $2
+		WHERE id = $3`
+
+	result, err := r.db.ExecContext(ctx, query, status, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update contact request: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return entity.ErrContactRequestNotFound
+	}
+
+	return nil
+}
+
+// IsAccepted reports whether the candidate shared their contacts with the employer.
+func (r *ContactRequestRepository) IsAccepted(ctx context.Context, employerID, jobseekerID int64) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM contact_requests
+			WHERE employer_id = $1 AND jobseeker_id = $2 AND status = 'accepted'
+		)`
+
+	var accepted bool
+	if err := r.db.GetContext(ctx, &accepted, query, employerID, jobseekerID); err != nil {
+		return false, fmt.Errorf("failed to check contact request: %w", err)
+	}
+	return accepted, nil
+}
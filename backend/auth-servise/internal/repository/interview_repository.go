@@ -0,0 +1,358 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type InterviewRepositoryInterface interface {
+	Create(ctx context.Context, interview *entity.Interview) error
+	GetByID(ctx context.Context, id int64) (*entity.Interview, error)
+	GetByApplicationID(ctx context.Context, applicationID int64) ([]*entity.Interview, error)
+	FindConflicts(ctx context.Context, candidateID int64, interviewers []string, start, end time.Time, excludeID int64) ([]*entity.Interview, error)
+	Schedule(ctx context.Context, interview *entity.Interview, start time.Time) error
+	Update(ctx context.Context, interview *entity.Interview) error
+	Cancel(ctx context.Context, interview *entity.Interview) error
+}
+
+type InterviewRepository struct {
+	db *sqlx.DB
+}
+
+func NewInterviewRepository(db *sqlx.DB) *InterviewRepository {
+	return &InterviewRepository{db: db}
+}
+
+const interviewColumns = `
+	id, application_id, employer_id, candidate_id, title, duration_minutes,
+	location, video_link, interviewers, notes, status, starts_at, ends_at,
+	sequence, created_at, updated_at`
+
+// interviewScheduleLock serializes scheduling so that two overlapping
+// interviews cannot be booked at the same time.
+const interviewScheduleLock = 350001
+
+func scanInterview(row rowScanner) (*entity.Interview, error) {
+	interview := &entity.Interview{}
+	var interviewers []string
+	err := row.Scan(
+		&interview.ID,
+		&interview.ApplicationID,
+		&interview.EmployerID,
+		&interview.CandidateID,
+		&interview.Title,
+		&interview.DurationMinutes,
+		&interview.Location,
+		&interview.VideoLink,
+		pq.Array(&interviewers),
+		&interview.Notes,
+		&interview.Status,
+		&interview.StartsAt,
+		&interview.EndsAt,
+		&interview.Sequence,
+		&interview.CreatedAt,
+		&interview.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if interviewers == nil {
+		interviewers = []string{}
+	}
+	interview.Interviewers = interviewers
+	return interview, nil
+}
+
+func (r *InterviewRepository) Create(ctx context.Context, interview *entity.Interview) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	interview.CreatedAt = now
+	interview.UpdatedAt = now
+
+	query := `
+		INSERT INTO interviews (
+			application_id, employer_id, candidate_id, title, duration_minutes,
+			location, video_link, interviewers, notes, status, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id`
+
+	err = tx.QueryRowContext(ctx, query,
+		interview.ApplicationID,
+		interview.EmployerID,
+		interview.CandidateID,
+		interview.Title,
+		interview.DurationMinutes,
+		interview.Location,
+		interview.VideoLink,
+		pq.Array(interview.Interviewers),
+		interview.Notes,
+		interview.Status,
+		now,
+		now,
+	).Scan(&interview.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create interview: %w", err)
+	}
+
+	for _, slot := range interview.Slots {
+		slot.InterviewID = interview.ID
+		err := tx.QueryRowContext(ctx,
+			`INSERT INTO interview_slots (interview_id, starts_at) VALUES ($1, $2) RETURNING id`,
+			interview.ID, slot.StartsAt,
+		).Scan(&slot.ID)
+		if err != nil {
+			return fmt.Errorf("failed to create interview slot: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit interview: %w", err)
+	}
+	return nil
+}
+
+func (r *InterviewRepository) GetByID(ctx context.Context, id int64) (*entity.Interview, error) {
+	query := `SELECT ` + interviewColumns + ` FROM interviews WHERE id = $1`
+
+	interview, err := scanInterview(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get interview: %w", err)
+	}
+
+	if err := r.loadSlots(ctx, []*entity.Interview{interview}); err != nil {
+		return nil, err
+	}
+	return interview, nil
+}
+
+func (r *InterviewRepository) GetByApplicationID(ctx context.Context, applicationID int64) ([]*entity.Interview, error) {
+	query := `
+		SELECT ` + interviewColumns + `
+		FROM interviews
+		WHERE application_id = $1
+		ORDER BY created_at DESC, id DESC`
+
+	interviews, err := r.query(ctx, r.db, query, applicationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get interviews: %w", err)
+	}
+	if err := r.loadSlots(ctx, interviews); err != nil {
+		return nil, err
+	}
+	return interviews, nil
+}
+
+func (r *InterviewRepository) query(ctx context.Context, q sqlx.QueryerContext, query string, args ...interface{}) ([]*entity.Interview, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	interviews := []*entity.Interview{}
+	for rows.Next() {
+		interview, err := scanInterview(rows)
+		if err != nil {
+			return nil, err
+		}
+		interviews = append(interviews, interview)
+	}
+	return interviews, rows.Err()
+}
+
+func (r *InterviewRepository) loadSlots(ctx context.Context, interviews []*entity.Interview) error {
+	if len(interviews) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(interviews))
+	byID := make(map[int64]*entity.Interview, len(interviews))
+	for i, interview := range interviews {
+		ids[i] = interview.ID
+		interview.Slots = []*entity.InterviewSlot{}
+		byID[interview.ID] = interview
+	}
+
+	query := `
+		SELECT id, interview_id, starts_at
+		FROM interview_slots
+		WHERE interview_id = ANY($1)
+		ORDER BY starts_at`
+
+	var slots []*entity.InterviewSlot
+	if err := r.db.SelectContext(ctx, &slots, query, pq.Array(ids)); err != nil {
+		return fmt.Errorf("failed to get interview slots: %w", err)
+	}
+	for _, slot := range slots {
+		interview := byID[slot.InterviewID]
+		interview.Slots = append(interview.Slots, slot)
+	}
+	return nil
+}
+
+// FindConflicts returns the scheduled interviews overlapping [start, end)
+// that involve the candidate or any of the interviewers.
+func (r *InterviewRepository) FindConflicts(ctx context.Context, candidateID int64, interviewers []string, start, end time.Time, excludeID int64) ([]*entity.Interview, error) {
+	conflicts, err := r.findConflicts(ctx, r.db, candidateID, interviewers, start, end, excludeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find interview conflicts: %w", err)
+	}
+	return conflicts, nil
+}
+
+func (r *InterviewRepository) findConflicts(ctx context.Context, q sqlx.QueryerContext, candidateID int64, interviewers []string, start, end time.Time, excludeID int64) ([]*entity.Interview, error) {
+	query := `
+		SELECT ` + interviewColumns + `
+		FROM interviews
+		WHERE status = 'scheduled' AND id <> $1
+			AND starts_at < $3 AND ends_at > $2
+			AND (candidate_id = $4 OR interviewers && $5::text[])
+		ORDER BY starts_at`
+
+	return r.query(ctx, q, query, excludeID, start, end, candidateID, pq.Array(interviewers))
+}
+
+// lockAndCheck takes the scheduling lock and fails with
+// entity.ErrInterviewConflict if the interview would overlap another one.
+func (r *InterviewRepository) lockAndCheck(ctx context.Context, tx *sqlx.Tx, interview *entity.Interview, start, end time.Time) error {
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, interviewScheduleLock); err != nil {
+		return fmt.Errorf("failed to lock interview schedule: %w", err)
+	}
+
+	conflicts, err := r.findConflicts(ctx, tx, interview.CandidateID, interview.Interviewers, start, end, interview.ID)
+	if err != nil {
+		return fmt.Errorf("failed to find interview conflicts: %w", err)
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("%w: overlaps interview %d at %s", entity.ErrInterviewConflict,
+			conflicts[0].ID, conflicts[0].StartsAt.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// Schedule books a proposed interview at the chosen time. It fails with
+// entity.ErrInterviewConflict on double-booking and with
+// entity.ErrInterviewStatusConflict if the interview is no longer proposed.
+func (r *InterviewRepository) Schedule(ctx context.Context, interview *entity.Interview, start time.Time) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	end := start.Add(interview.Duration())
+	if err := r.lockAndCheck(ctx, tx, interview, start, end); err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE interviews
+		SET status = 'scheduled', starts_at = $1, ends_at = $2,
+			sequence = sequence + 1, updated_at = $3
+		WHERE id = $4 AND status = 'proposed'
+		RETURNING sequence, updated_at`
+
+	err = tx.QueryRowContext(ctx, query, start, end, time.Now(), interview.ID).
+		Scan(&interview.Sequence, &interview.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return entity.ErrInterviewStatusConflict
+	}
+	if err != nil {
+		return fmt.Errorf("failed to schedule interview: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit interview: %w", err)
+	}
+	interview.Status = entity.InterviewStatusScheduled
+	interview.StartsAt = &start
+	interview.EndsAt = &end
+	return nil
+}
+
+// Update saves the interview details and, for a scheduled interview, its
+// time, increasing the invite sequence. Double-booking is checked the same
+// way as in Schedule.
+func (r *InterviewRepository) Update(ctx context.Context, interview *entity.Interview) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var end *time.Time
+	if interview.StartsAt != nil {
+		value := interview.StartsAt.Add(interview.Duration())
+		end = &value
+		if err := r.lockAndCheck(ctx, tx, interview, *interview.StartsAt, value); err != nil {
+			return err
+		}
+	}
+
+	query := `
+		UPDATE interviews
+		SET title = $1, duration_minutes = $2, location = $3, video_link = $4,
+			interviewers = $5, notes = $6, starts_at = $7, ends_at = $8,
+			sequence = sequence + 1, updated_at = $9
+		WHERE id = $10 AND status = $11
+		RETURNING sequence, updated_at`
+
+	err = tx.QueryRowContext(ctx, query,
+		interview.Title,
+		interview.DurationMinutes,
+		interview.Location,
+		interview.VideoLink,
+		pq.Array(interview.Interviewers),
+		interview.Notes,
+		interview.StartsAt,
+		end,
+		time.Now(),
+		interview.ID,
+		interview.Status,
+	).Scan(&interview.Sequence, &interview.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return entity.ErrInterviewStatusConflict
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update interview: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit interview: %w", err)
+	}
+	interview.EndsAt = end
+	return nil
+}
+
+func (r *InterviewRepository) Cancel(ctx context.Context, interview *entity.Interview) error {
+	query := `
+		UPDATE interviews
+		SET status = 'cancelled', sequence = sequence + 1, updated_at = $1
+		WHERE id = $2 AND status <> 'cancelled'
+		RETURNING sequence, updated_at`
+
+	err := r.db.QueryRowContext(ctx, query, time.Now(), interview.ID).
+		Scan(&interview.Sequence, &interview.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return entity.ErrInterviewStatusConflict
+	}
+	if err != nil {
+		return fmt.Errorf("failed to cancel interview: %w", err)
+	}
+	interview.Status = entity.InterviewStatusCancelled
+	return nil
+}
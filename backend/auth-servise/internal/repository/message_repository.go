@@ -0,0 +1,257 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type MessageRepositoryInterface interface {
+	Create(ctx context.Context, message *entity.ApplicationMessage) error
+	List(ctx context.Context, applicationID, beforeID int64, limit int) ([]*entity.ApplicationMessage, error)
+	GetAttachment(ctx context.Context, id int64) (*entity.MessageAttachment, error)
+	MarkRead(ctx context.Context, applicationID, userID, messageID int64) error
+	GetLastSeen(ctx context.Context, applicationID, userID int64) (*time.Time, error)
+	ListThreads(ctx context.Context, userID int64, limit, offset int) ([]*entity.MessageThread, error)
+	CountUnread(ctx context.Context, userID int64) (int, error)
+}
+
+type MessageRepository struct {
+	db *sqlx.DB
+}
+
+func NewMessageRepository(db *sqlx.DB) *MessageRepository {
+	return &MessageRepository{db: db}
+}
+
+// Create stores the message with its attachments. The sender has read the
+// thread up to their own message.
+func (r *MessageRepository) Create(ctx context.Context, message *entity.ApplicationMessage) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	message.CreatedAt = time.Now()
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO application_messages (application_id, sender_id, kind, body, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id`,
+		message.ApplicationID, message.SenderID, message.Kind, message.Body, message.CreatedAt,
+	).Scan(&message.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create message: %w", err)
+	}
+
+	for _, item := range message.Attachments {
+		item.MessageID = message.ID
+		item.ApplicationID = message.ApplicationID
+		item.CreatedAt = message.CreatedAt
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO application_message_attachments (message_id, file_name, content_type, size, storage_key, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id`,
+			item.MessageID, item.FileName, item.ContentType, item.Size, item.StorageKey, item.CreatedAt,
+		).Scan(&item.ID)
+		if err != nil {
+			return fmt.Errorf("failed to create message attachment: %w", err)
+		}
+	}
+
+	if message.SenderID != nil {
+		if err := markRead(ctx, tx, message.ApplicationID, *message.SenderID, message.ID); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit message: %w", err)
+	}
+	return nil
+}
+
+// insertSystemMessage posts a message without a sender to the thread, in
+// the transaction that made the change it reports.
+func insertSystemMessage(ctx context.Context, tx *sqlx.Tx, applicationID int64, body string, at time.Time) error {
+	query := `
+		INSERT INTO application_messages (application_id, kind, body, created_at)
+		VALUES ($1, $2, $3, $4)`
+
+	if _, err := tx.ExecContext(ctx, query, applicationID, entity.MessageKindSystem, body, at); err != nil {
+		return fmt.Errorf("failed to create system message: %w", err)
+	}
+	return nil
+}
+
+// List returns up to limit messages older than beforeID, or the latest ones
+// if beforeID is zero, in the order they were sent.
+func (r *MessageRepository) List(ctx context.Context, applicationID, beforeID int64, limit int) ([]*entity.ApplicationMessage, error) {
+	query := `
+		SELECT m.id, m.application_id, m.sender_id, COALESCE(u.name, '') AS sender_name, m.kind, m.body, m.created_at
+		FROM application_messages m
+		LEFT JOIN users u ON u.id = m.sender_id
+		WHERE m.application_id = $1 AND ($2 = 0 OR m.id < $2)
+		ORDER BY m.id DESC
+		LIMIT $3`
+
+	messages := []*entity.ApplicationMessage{}
+	if err := r.db.SelectContext(ctx, &messages, query, applicationID, beforeID, limit); err != nil {
+		return nil, fmt.Errorf("failed to get messages: %w", err)
+	}
+	slices.Reverse(messages)
+	if len(messages) == 0 {
+		return messages, nil
+	}
+
+	ids := make([]int64, len(messages))
+	byID := make(map[int64]*entity.ApplicationMessage, len(messages))
+	for i, message := range messages {
+		ids[i] = message.ID
+		message.Attachments = []*entity.MessageAttachment{}
+		byID[message.ID] = message
+	}
+
+	attachments := []*entity.MessageAttachment{}
+	err := r.db.SelectContext(ctx, &attachments, `
+		SELECT id, message_id, $2::bigint AS application_id, file_name, content_type, size, storage_key, created_at
+		FROM application_message_attachments
+		WHERE message_id = ANY($1)
+		ORDER BY id`, pq.Array(ids), applicationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message attachments: %w", err)
+	}
+	for _, item := range attachments {
+		byID[item.MessageID].Attachments = append(byID[item.MessageID].Attachments, item)
+	}
+	return messages, nil
+}
+
+func (r *MessageRepository) GetAttachment(ctx context.Context, id int64) (*entity.MessageAttachment, error) {
+	query := `
+		SELECT f.id, f.message_id, m.application_id, f.file_name, f.content_type, f.size, f.storage_key, f.created_at
+		FROM application_message_attachments f
+		JOIN application_messages m ON m.id = f.message_id
+		WHERE f.id = $1`
+
+	var item entity.MessageAttachment
+	err := r.db.GetContext(ctx, &item, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message attachment: %w", err)
+	}
+	return &item, nil
+}
+
+// MarkRead moves the user's read mark in the thread forward to messageID
+// and records the visit.
+func (r *MessageRepository) MarkRead(ctx context.Context, applicationID, userID, messageID int64) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := markRead(ctx, tx, applicationID, userID, messageID); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit read mark: %w", err)
+	}
+	return nil
+}
+
+func markRead(ctx context.Context, tx *sqlx.Tx, applicationID, userID, messageID int64) error {
+	query := `
+		INSERT INTO application_thread_reads (application_id, user_id, last_read_message_id, last_seen_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (application_id, user_id) DO UPDATE
+		SET last_read_message_id = GREATEST(application_thread_reads.last_read_message_id, EXCLUDED.last_read_message_id),
+			last_seen_at = EXCLUDED.last_seen_at`
+
+	if _, err := tx.ExecContext(ctx, query, applicationID, userID, messageID, time.Now()); err != nil {
+		return fmt.Errorf("failed to mark thread as read: %w", err)
+	}
+	return nil
+}
+
+// GetLastSeen returns when the user last opened the thread, or nil if they
+// never did.
+func (r *MessageRepository) GetLastSeen(ctx context.Context, applicationID, userID int64) (*time.Time, error) {
+	var lastSeen time.Time
+	err := r.db.GetContext(ctx, &lastSeen,
+		`SELECT last_seen_at FROM application_thread_reads WHERE application_id = $1 AND user_id = $2`,
+		applicationID, userID,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last seen time: %w", err)
+	}
+	return &lastSeen, nil
+}
+
+// ListThreads returns the conversations the user takes part in, as the
+// applicant or the vacancy owner, most recent first. Applications nobody
+// wrote about yet are left out.
+func (r *MessageRepository) ListThreads(ctx context.Context, userID int64, limit, offset int) ([]*entity.MessageThread, error) {
+	query := `
+		SELECT a.id AS application_id, a.vacancy_id, v.title AS vacancy_title, u.name AS applicant_name,
+			a.status AS application_status, last.body AS last_message, last.created_at AS last_message_at,
+			(
+				SELECT COUNT(*)
+				FROM application_messages m
+				WHERE m.application_id = a.id
+					AND m.id > COALESCE(r.last_read_message_id, 0)
+					AND m.sender_id IS DISTINCT FROM $1
+			) AS unread_count
+		FROM applications a
+		JOIN vacancies v ON v.id = a.vacancy_id
+		JOIN users u ON u.id = a.user_id
+		JOIN LATERAL (
+			SELECT m.body, m.created_at
+			FROM application_messages m
+			WHERE m.application_id = a.id
+			ORDER BY m.id DESC
+			LIMIT 1
+		) last ON TRUE
+		LEFT JOIN application_thread_reads r ON r.application_id = a.id AND r.user_id = $1
+		WHERE a.user_id = $1 OR ` + vacancyAccessCondition("v", "$1") + `
+		ORDER BY last.created_at DESC, a.id DESC
+		LIMIT $2 OFFSET $3`
+
+	threads := []*entity.MessageThread{}
+	if err := r.db.SelectContext(ctx, &threads, query, userID, limit, offset); err != nil {
+		return nil, fmt.Errorf("failed to get message threads: %w", err)
+	}
+	return threads, nil
+}
+
+// CountUnread counts the messages in all of the user's threads they have
+// not read. Their own messages are never unread.
+func (r *MessageRepository) CountUnread(ctx context.Context, userID int64) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM application_messages m
+		JOIN applications a ON a.id = m.application_id
+		JOIN vacancies v ON v.id = a.vacancy_id
+		LEFT JOIN application_thread_reads r ON r.application_id = a.id AND r.user_id = $1
+		WHERE (a.user_id = $1 OR ` + vacancyAccessCondition("v", "$1") + `)
+			AND m.id > COALESCE(r.last_read_message_id, 0)
+			AND m.sender_id IS DISTINCT FROM $1`
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, query, userID); err != nil {
+		return 0, fmt.Errorf("failed to count unread messages: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type NotificationRepositoryInterface interface {
+	Create(ctx context.Context, notification *entity.Notification, inApp, digest bool) error
+	List(ctx context.Context, userID int64, unreadOnly bool, limit, offset int) ([]*entity.Notification, error)
+	CountUnread(ctx context.Context, userID int64) (int, error)
+	MarkRead(ctx context.Context, userID, id int64) (bool, error)
+	MarkAllRead(ctx context.Context, userID int64) (int64, error)
+	GetPreferences(ctx context.Context, userID int64) ([]*entity.NotificationPreference, error)
+	SetPreferences(ctx context.Context, userID int64, preferences []*entity.NotificationPreference) error
+	PendingDigests(ctx context.Context, limit int) ([]*entity.NotificationDigest, error)
+	MarkDigested(ctx context.Context, ids []int64) error
+}
+
+type NotificationRepository struct {
+	db *sqlx.DB
+}
+
+func NewNotificationRepository(db *sqlx.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// Create stores the notification for the notification center, the digest
+// or both.
+func (r *NotificationRepository) Create(ctx context.Context, notification *entity.Notification, inApp, digest bool) error {
+	notification.CreatedAt = time.Now()
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO notifications (user_id, type, title, body, link, in_app, digest, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id`,
+		notification.UserID, notification.Type, notification.Title, notification.Body, notification.Link,
+		inApp, digest, notification.CreatedAt,
+	).Scan(&notification.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+	return nil
+}
+
+// List returns the user's notification center, newest first.
+func (r *NotificationRepository) List(ctx context.Context, userID int64, unreadOnly bool, limit, offset int) ([]*entity.Notification, error) {
+	query := `
+		SELECT id, user_id, type, title, body, link, read_at, created_at
+		FROM notifications
+		WHERE user_id = $1 AND in_app AND (NOT $2 OR read_at IS NULL)
+		ORDER BY id DESC
+		LIMIT $3 OFFSET $4`
+
+	notifications := []*entity.Notification{}
+	if err := r.db.SelectContext(ctx, &notifications, query, userID, unreadOnly, limit, offset); err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+	return notifications, nil
+}
+
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID int64) (int, error) {
+	var count int
+	err := r.db.GetContext(ctx, &count,
+		`SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND in_app AND read_at IS NULL`, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+	return count, nil
+}
+
+// MarkRead marks the user's notification read and reports whether it
+// exists. Marking it again keeps the first read time.
+func (r *NotificationRepository) MarkRead(ctx context.Context, userID, id int64) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE notifications SET read_at = COALESCE(read_at, $1)
+		WHERE id = $2 AND user_id = $3 AND in_app`,
+		time.Now(), id, userID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark notification read: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// MarkAllRead marks every unread notification of the user read and returns
+// how many there were.
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userID int64) (int64, error) {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE notifications SET read_at = $1 WHERE user_id = $2 AND in_app AND read_at IS NULL`,
+		time.Now(), userID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications read: %w", err)
+	}
+	marked, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return marked, nil
+}
+
+// GetPreferences returns the preferences the user has saved. Types without
+// a row use the defaults.
+func (r *NotificationRepository) GetPreferences(ctx context.Context, userID int64) ([]*entity.NotificationPreference, error) {
+	preferences := []*entity.NotificationPreference{}
+	err := r.db.SelectContext(ctx, &preferences,
+		`SELECT type, in_app, email, digest FROM notification_preferences WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification preferences: %w", err)
+	}
+	return preferences, nil
+}
+
+func (r *NotificationRepository) SetPreferences(ctx context.Context, userID int64, preferences []*entity.NotificationPreference) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO notification_preferences (user_id, type, in_app, email, digest)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, type) DO UPDATE
+		SET in_app = EXCLUDED.in_app, email = EXCLUDED.email, digest = EXCLUDED.digest`
+
+	for _, preference := range preferences {
+		_, err := tx.ExecContext(ctx, query, userID, preference.Type, preference.InApp, preference.Email, preference.Digest)
+		if err != nil {
+			return fmt.Errorf("failed to save notification preference: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit notification preferences: %w", err)
+	}
+	return nil
+}
+
+// PendingDigests returns the notifications waiting for the digest of up to
+// limit users, oldest first.
+func (r *NotificationRepository) PendingDigests(ctx context.Context, limit int) ([]*entity.NotificationDigest, error) {
+	query := `
+		SELECT id, user_id, type, title, body, link, read_at, created_at
+		FROM notifications
+		WHERE digest AND digested_at IS NULL AND user_id IN (
+			SELECT DISTINCT user_id FROM notifications
+			WHERE digest AND digested_at IS NULL
+			ORDER BY user_id
+			LIMIT $1
+		)
+		ORDER BY user_id, id`
+
+	notifications := []*entity.Notification{}
+	if err := r.db.SelectContext(ctx, &notifications, query, limit); err != nil {
+		return nil, fmt.Errorf("failed to get notification digests: %w", err)
+	}
+
+	digests := []*entity.NotificationDigest{}
+	for _, notification := range notifications {
+		if len(digests) == 0 || digests[len(digests)-1].UserID != notification.UserID {
+			digests = append(digests, &entity.NotificationDigest{UserID: notification.UserID})
+		}
+		digest := digests[len(digests)-1]
+		digest.Notifications = append(digest.Notifications, notification)
+	}
+	return digests, nil
+}
+
+func (r *NotificationRepository) MarkDigested(ctx context.Context, ids []int64) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE notifications SET digested_at = $1 WHERE id = ANY($2)`,
+		time.Now(), pq.Array(ids),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to mark notifications digested: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,352 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type OrganizationRepositoryInterface interface {
+	Create(ctx context.Context, organization *entity.Organization, ownerID int64) error
+	GetByID(ctx context.Context, id int64) (*entity.Organization, error)
+	Update(ctx context.Context, organization *entity.Organization) error
+	GetMembership(ctx context.Context, userID int64) (*entity.OrganizationMember, error)
+	ListMembers(ctx context.Context, organizationID int64) ([]*entity.OrganizationMember, error)
+	SetMemberRole(ctx context.Context, organizationID, userID int64, role string) error
+	RemoveMember(ctx context.Context, organizationID, userID int64) error
+	CreateInvite(ctx context.Context, invite *entity.OrganizationInvite) error
+	ListInvites(ctx context.Context, organizationID int64) ([]*entity.OrganizationInvite, error)
+	GetInviteByToken(ctx context.Context, token string) (*entity.OrganizationInvite, error)
+	DeleteInvite(ctx context.Context, organizationID, id int64) (bool, error)
+	AcceptInvite(ctx context.Context, invite *entity.OrganizationInvite, userID int64) error
+}
+
+type OrganizationRepository struct {
+	db *sqlx.DB
+}
+
+func NewOrganizationRepository(db *sqlx.DB) *OrganizationRepository {
+	return &OrganizationRepository{db: db}
+}
+
+// vacancyAccessCondition is the SQL condition under which the user in the
+// placeholder param may see vacancy alias: they belong to its organization,
+// or posted it if it has none. Posting a vacancy of an organization gives no
+// access after leaving it.
+func vacancyAccessCondition(alias, param string) string {
+	return fmt.Sprintf(
+		"(%[1]s.organization_id IS NULL AND %[1]s.employer_id = %[2]s OR %[1]s.organization_id = (SELECT om.organization_id FROM organization_members om WHERE om.user_id = %[2]s))",
+		alias, param,
+	)
+}
+
+// employerBlockedCondition is the SQL condition under which the block in
+// alias applies to the employer in the placeholder param: the candidate
+// blocked them or a colleague from their organization, so that blocking one
+// recruiter hides the resume from the whole company.
+func employerBlockedCondition(alias, param string) string {
+	return fmt.Sprintf(
+		"(%[1]s.employer_id = %[2]s OR %[1]s.employer_id IN (SELECT colleague.user_id FROM organization_members colleague WHERE colleague.organization_id = (SELECT om.organization_id FROM organization_members om WHERE om.user_id = %[2]s)))",
+		alias, param,
+	)
+}
+
+// addMember makes the user a member and shares their vacancies posted
+// before joining with the organization.
+func addMember(ctx context.Context, tx *sqlx.Tx, organizationID, userID int64, role string, at time.Time) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO organization_members (organization_id, user_id, role, created_at)
+		VALUES ($1, $2, $3, $4)`,
+		organizationID, userID, role, at,
+	)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return entity.ErrAlreadyInOrganization
+		}
+		return fmt.Errorf("failed to add organization member: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE vacancies SET organization_id = $1 WHERE employer_id = $2 AND organization_id IS NULL`,
+		organizationID, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to share vacancies with organization: %w", err)
+	}
+	return nil
+}
+
+// Create creates the organization with ownerID as its first owner.
+func (r *OrganizationRepository) Create(ctx context.Context, organization *entity.Organization, ownerID int64) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	organization.CreatedAt = now
+	organization.UpdatedAt = now
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO organizations (name, logo_url, description, website, size, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		RETURNING id`,
+		organization.Name, organization.LogoURL, organization.Description, organization.Website, organization.Size, now,
+	).Scan(&organization.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create organization: %w", err)
+	}
+
+	if err := addMember(ctx, tx, organization.ID, ownerID, entity.OrganizationRoleOwner, now); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit organization: %w", err)
+	}
+	return nil
+}
+
+func (r *OrganizationRepository) GetByID(ctx context.Context, id int64) (*entity.Organization, error) {
+	var organization entity.Organization
+	err := r.db.GetContext(ctx, &organization, `
+		SELECT id, name, logo_url, description, website, size, created_at, updated_at
+		FROM organizations
+		WHERE id = $1`, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization: %w", err)
+	}
+	return &organization, nil
+}
+
+func (r *OrganizationRepository) Update(ctx context.Context, organization *entity.Organization) error {
+	organization.UpdatedAt = time.Now()
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE organizations
+		SET name = $1, logo_url = $2, description = $3, website = $4, size = $5, updated_at = $6
+		WHERE id = $7`,
+		organization.Name, organization.LogoURL, organization.Description, organization.Website, organization.Size,
+		organization.UpdatedAt, organization.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update organization: %w", err)
+	}
+	return nil
+}
+
+// GetMembership returns the user's membership, or nil if they are not in an
+// organization.
+func (r *OrganizationRepository) GetMembership(ctx context.Context, userID int64) (*entity.OrganizationMember, error) {
+	var member entity.OrganizationMember
+	err := r.db.GetContext(ctx, &member, `
+		SELECT m.organization_id, m.user_id, u.name, u.email, m.role, m.created_at
+		FROM organization_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.user_id = $1`, userID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization membership: %w", err)
+	}
+	return &member, nil
+}
+
+func (r *OrganizationRepository) ListMembers(ctx context.Context, organizationID int64) ([]*entity.OrganizationMember, error) {
+	members := []*entity.OrganizationMember{}
+	err := r.db.SelectContext(ctx, &members, `
+		SELECT m.organization_id, m.user_id, u.name, u.email, m.role, m.created_at
+		FROM organization_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.organization_id = $1
+		ORDER BY m.created_at, m.user_id`, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organization members: %w", err)
+	}
+	return members, nil
+}
+
+// keepOwner locks the organization's owners and fails if the user is the
+// last of them. Locking makes two owners demoting each other at once safe.
+func keepOwner(ctx context.Context, tx *sqlx.Tx, organizationID, userID int64) error {
+	var owners []int64
+	err := tx.SelectContext(ctx, &owners, `
+		SELECT user_id FROM organization_members
+		WHERE organization_id = $1 AND role = $2
+		FOR UPDATE`,
+		organizationID, entity.OrganizationRoleOwner,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to get organization owners: %w", err)
+	}
+	if len(owners) == 1 && owners[0] == userID {
+		return entity.ErrLastOwner
+	}
+	return nil
+}
+
+func (r *OrganizationRepository) SetMemberRole(ctx context.Context, organizationID, userID int64, role string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if role != entity.OrganizationRoleOwner {
+		if err := keepOwner(ctx, tx, organizationID, userID); err != nil {
+			return err
+		}
+	}
+	result, err := tx.ExecContext(ctx,
+		`UPDATE organization_members SET role = $1 WHERE organization_id = $2 AND user_id = $3`,
+		role, organizationID, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to change member role: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return entity.ErrOrganizationNotFound
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit member role: %w", err)
+	}
+	return nil
+}
+
+// RemoveMember takes the user out of the organization. The vacancies they
+// posted stay with the organization.
+func (r *OrganizationRepository) RemoveMember(ctx context.Context, organizationID, userID int64) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := keepOwner(ctx, tx, organizationID, userID); err != nil {
+		return err
+	}
+	result, err := tx.ExecContext(ctx,
+		`DELETE FROM organization_members WHERE organization_id = $1 AND user_id = $2`,
+		organizationID, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to remove organization member: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return entity.ErrOrganizationNotFound
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit member removal: %w", err)
+	}
+	return nil
+}
+
+func (r *OrganizationRepository) CreateInvite(ctx context.Context, invite *entity.OrganizationInvite) error {
+	invite.CreatedAt = time.Now()
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO organization_invites (organization_id, email, role, token, invited_by, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`,
+		invite.OrganizationID, invite.Email, invite.Role, invite.Token, invite.InvitedBy, invite.ExpiresAt, invite.CreatedAt,
+	).Scan(&invite.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create invite: %w", err)
+	}
+	return nil
+}
+
+const inviteColumns = `id, organization_id, email, role, token, invited_by, expires_at, accepted_at, created_at`
+
+func (r *OrganizationRepository) ListInvites(ctx context.Context, organizationID int64) ([]*entity.OrganizationInvite, error) {
+	invites := []*entity.OrganizationInvite{}
+	err := r.db.SelectContext(ctx, &invites,
+		`SELECT `+inviteColumns+` FROM organization_invites WHERE organization_id = $1 ORDER BY created_at DESC`,
+		organizationID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invites: %w", err)
+	}
+	return invites, nil
+}
+
+func (r *OrganizationRepository) GetInviteByToken(ctx context.Context, token string) (*entity.OrganizationInvite, error) {
+	var invite entity.OrganizationInvite
+	err := r.db.GetContext(ctx, &invite, `SELECT `+inviteColumns+` FROM organization_invites WHERE token = $1`, token)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invite: %w", err)
+	}
+	return &invite, nil
+}
+
+// DeleteInvite revokes an invite and reports whether it existed.
+func (r *OrganizationRepository) DeleteInvite(ctx context.Context, organizationID, id int64) (bool, error) {
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM organization_invites WHERE id = $1 AND organization_id = $2`, id, organizationID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete invite: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// AcceptInvite adds the user to the organization with the invited role. An
+// invite is accepted once.
+func (r *OrganizationRepository) AcceptInvite(ctx context.Context, invite *entity.OrganizationInvite, userID int64) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	result, err := tx.ExecContext(ctx,
+		`UPDATE organization_invites SET accepted_at = $1 WHERE id = $2 AND accepted_at IS NULL AND expires_at > $1`,
+		now, invite.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to accept invite: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return entity.ErrInviteExpired
+	}
+
+	if err := addMember(ctx, tx, invite.OrganizationID, userID, invite.Role, now); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit invite: %w", err)
+	}
+	invite.AcceptedAt = &now
+	return nil
+}
@@ -0,0 +1,306 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type PipelineRepositoryInterface interface {
+	Create(ctx context.Context, pipeline *entity.Pipeline) error
+	GetByID(ctx context.Context, id int64) (*entity.Pipeline, error)
+	GetByEmployerID(ctx context.Context, employerID int64) ([]*entity.Pipeline, error)
+	Update(ctx context.Context, pipeline *entity.Pipeline) error
+	Delete(ctx context.Context, id int64) error
+	GetVacancyPipelineID(ctx context.Context, vacancyID int64) (*int64, error)
+	AssignToVacancy(ctx context.Context, vacancyID int64, pipelineID *int64) error
+	MoveApplications(ctx context.Context, vacancyID int64, applicationIDs []int64, stageID int64) (int64, error)
+}
+
+type PipelineRepository struct {
+	db *sqlx.DB
+}
+
+func NewPipelineRepository(db *sqlx.DB) *PipelineRepository {
+	return &PipelineRepository{db: db}
+}
+
+func (r *PipelineRepository) Create(ctx context.Context, pipeline *entity.Pipeline) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	pipeline.CreatedAt = now
+	pipeline.UpdatedAt = now
+
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO hiring_pipelines (employer_id, name, created_at, updated_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id`,
+		pipeline.EmployerID, pipeline.Name, now, now,
+	).Scan(&pipeline.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create pipeline: %w", err)
+	}
+
+	for _, stage := range pipeline.Stages {
+		if err := insertStage(ctx, tx, pipeline.ID, stage); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit pipeline: %w", err)
+	}
+	return nil
+}
+
+func insertStage(ctx context.Context, tx *sqlx.Tx, pipelineID int64, stage *entity.PipelineStage) error {
+	stage.PipelineID = pipelineID
+	err := tx.QueryRowContext(ctx,
+		`INSERT INTO pipeline_stages (pipeline_id, name, position) VALUES ($1, $2, $3) RETURNING id`,
+		pipelineID, stage.Name, stage.Position,
+	).Scan(&stage.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create pipeline stage: %w", err)
+	}
+	return nil
+}
+
+func (r *PipelineRepository) GetByID(ctx context.Context, id int64) (*entity.Pipeline, error) {
+	query := `
+		SELECT id, employer_id, name, created_at, updated_at
+		FROM hiring_pipelines
+		WHERE id = $1`
+
+	pipeline := &entity.Pipeline{}
+	err := r.db.GetContext(ctx, pipeline, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pipeline: %w", err)
+	}
+
+	if err := r.loadStages(ctx, []*entity.Pipeline{pipeline}); err != nil {
+		return nil, err
+	}
+	return pipeline, nil
+}
+
+func (r *PipelineRepository) GetByEmployerID(ctx context.Context, employerID int64) ([]*entity.Pipeline, error) {
+	query := `
+		SELECT id, employer_id, name, created_at, updated_at
+		FROM hiring_pipelines
+		WHERE employer_id = $1
+		ORDER BY created_at, id`
+
+	pipelines := []*entity.Pipeline{}
+	if err := r.db.SelectContext(ctx, &pipelines, query, employerID); err != nil {
+		return nil, fmt.Errorf("failed to get pipelines: %w", err)
+	}
+
+	if err := r.loadStages(ctx, pipelines); err != nil {
+		return nil, err
+	}
+	return pipelines, nil
+}
+
+// loadStages fills the stages of the given pipelines with a single query.
+func (r *PipelineRepository) loadStages(ctx context.Context, pipelines []*entity.Pipeline) error {
+	if len(pipelines) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(pipelines))
+	byID := make(map[int64]*entity.Pipeline, len(pipelines))
+	for i, pipeline := range pipelines {
+		ids[i] = pipeline.ID
+		pipeline.Stages = []*entity.PipelineStage{}
+		byID[pipeline.ID] = pipeline
+	}
+
+	query := `
+		SELECT id, pipeline_id, name, position
+		FROM pipeline_stages
+		WHERE pipeline_id = ANY($1)
+		ORDER BY pipeline_id, position`
+
+	var stages []*entity.PipelineStage
+	if err := r.db.SelectContext(ctx, &stages, query, pq.Array(ids)); err != nil {
+		return fmt.Errorf("failed to get pipeline stages: %w", err)
+	}
+	for _, stage := range stages {
+		pipeline := byID[stage.PipelineID]
+		pipeline.Stages = append(pipeline.Stages, stage)
+	}
+	return nil
+}
+
+// Update renames the pipeline and replaces its stages: stages with an id are
+// kept and reordered, stages without one are added and the missing ones are
+// removed. Applications left without a stage are put on the first stage.
+func (r *PipelineRepository) Update(ctx context.Context, pipeline *entity.Pipeline) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	pipeline.UpdatedAt = time.Now()
+	result, err := tx.ExecContext(ctx,
+		`UPDATE hiring_pipelines SET name = $1, updated_at = $2 WHERE id = $3`,
+		pipeline.Name, pipeline.UpdatedAt, pipeline.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update pipeline: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return entity.ErrPipelineNotFound
+	}
+
+	keep := []int64{}
+	for _, stage := range pipeline.Stages {
+		if stage.ID != 0 {
+			keep = append(keep, stage.ID)
+		}
+	}
+	_, err = tx.ExecContext(ctx,
+		`DELETE FROM pipeline_stages WHERE pipeline_id = $1 AND NOT (id = ANY($2))`,
+		pipeline.ID, pq.Array(keep),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete pipeline stages: %w", err)
+	}
+
+	for _, stage := range pipeline.Stages {
+		if stage.ID == 0 {
+			if err := insertStage(ctx, tx, pipeline.ID, stage); err != nil {
+				return err
+			}
+			continue
+		}
+
+		stage.PipelineID = pipeline.ID
+		result, err := tx.ExecContext(ctx,
+			`UPDATE pipeline_stages SET name = $1, position = $2 WHERE id = $3 AND pipeline_id = $4`,
+			stage.Name, stage.Position, stage.ID, pipeline.ID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update pipeline stage: %w", err)
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			return entity.ErrPipelineStageNotFound
+		}
+	}
+
+	// Отклики с удаленных этапов переносим на первый этап
+	_, err = tx.ExecContext(ctx, `
+		UPDATE applications a
+		SET stage_id = $2
+		FROM vacancies v
+		WHERE v.id = a.vacancy_id AND v.pipeline_id = $1 AND a.stage_id IS NULL`,
+		pipeline.ID, pipeline.Stages[0].ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to reassign applications: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit pipeline: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the pipeline. Vacancies that used it are left without a
+// pipeline.
+func (r *PipelineRepository) Delete(ctx context.Context, id int64) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM hiring_pipelines WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to delete pipeline: %w", err)
+	}
+	return nil
+}
+
+func (r *PipelineRepository) GetVacancyPipelineID(ctx context.Context, vacancyID int64) (*int64, error) {
+	var pipelineID *int64
+	err := r.db.GetContext(ctx, &pipelineID, `SELECT pipeline_id FROM vacancies WHERE id = $1`, vacancyID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vacancy pipeline: %w", err)
+	}
+	return pipelineID, nil
+}
+
+// AssignToVacancy sets the vacancy pipeline and puts all of its applications
+// on the first stage. A nil pipelineID detaches the pipeline.
+func (r *PipelineRepository) AssignToVacancy(ctx context.Context, vacancyID int64, pipelineID *int64) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE vacancies SET pipeline_id = $1, updated_at = $2 WHERE id = $3`,
+		pipelineID, now, vacancyID,
+	); err != nil {
+		return fmt.Errorf("failed to assign pipeline: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE applications
+		SET stage_id = (
+			SELECT id FROM pipeline_stages WHERE pipeline_id = $1 ORDER BY position LIMIT 1
+		), updated_at = $2
+		WHERE vacancy_id = $3`,
+		pipelineID, now, vacancyID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to reset application stages: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit pipeline assignment: %w", err)
+	}
+	return nil
+}
+
+// MoveApplications puts the open applications of the vacancy with the given
+// ids on the stage and returns how many were moved. Closed applications
+// (hired, rejected, withdrawn) keep their stage.
+func (r *PipelineRepository) MoveApplications(ctx context.Context, vacancyID int64, applicationIDs []int64, stageID int64) (int64, error) {
+	query := `
+		UPDATE applications
+		SET stage_id = $1, updated_at = $2
+		WHERE vacancy_id = $3 AND id = ANY($4)
+			AND status NOT IN ('hired', 'rejected', 'withdrawn')`
+
+	result, err := r.db.ExecContext(ctx, query, stageID, time.Now(), vacancyID, pq.Array(applicationIDs))
+	if err != nil {
+		return 0, fmt.Errorf("failed to move applications: %w", err)
+	}
+	moved, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return moved, nil
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+type ResumeAttachmentRepositoryInterface interface {
+	Create(ctx context.Context, attachment *entity.ResumeAttachment) error
+	GetByID(ctx context.Context, id int64) (*entity.ResumeAttachment, error)
+	GetByResumeID(ctx context.Context, resumeID int64) ([]*entity.ResumeAttachment, error)
+	Delete(ctx context.Context, resumeID, id int64) error
+}
+
+type ResumeAttachmentRepository struct {
+	db *sqlx.DB
+}
+
+func NewResumeAttachmentRepository(db *sqlx.DB) *ResumeAttachmentRepository {
+	return &ResumeAttachmentRepository{db: db}
+}
+
+func (r *ResumeAttachmentRepository) Create(ctx context.Context, attachment *entity.ResumeAttachment) error {
+	query := `
+		INSERT INTO resume_attachments (resume_id, file_name, content_type, size, storage_key, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`
+
+	attachment.CreatedAt = time.Now()
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		attachment.ResumeID,
+		attachment.FileName,
+		attachment.ContentType,
+		attachment.Size,
+		attachment.StorageKey,
+		attachment.CreatedAt,
+	).Scan(&attachment.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create attachment: %w", err)
+	}
+	return nil
+}
+
+func (r *ResumeAttachmentRepository) GetByID(ctx context.Context, id int64) (*entity.ResumeAttachment, error) {
+	query := `
+		SELECT id, resume_id, file_name, content_type, size, storage_key, created_at
+		FROM resume_attachments
+		WHERE id = $1`
+
+	var attachment entity.ResumeAttachment
+	err := r.db.GetContext(ctx, &attachment, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+	return &attachment, nil
+}
+
+func (r *ResumeAttachmentRepository) GetByResumeID(ctx context.Context, resumeID int64) ([]*entity.ResumeAttachment, error) {
+	query := `
+		SELECT id, resume_id, file_name, content_type, size, storage_key, created_at
+		FROM resume_attachments
+		WHERE resume_id = $1
+		ORDER BY created_at`
+
+	attachments := []*entity.ResumeAttachment{}
+	if err := r.db.SelectContext(ctx, &attachments, query, resumeID); err != nil {
+		return nil, fmt.Errorf("failed to get attachments: %w", err)
+	}
+	return attachments, nil
+}
+
+func (r *ResumeAttachmentRepository) Delete(ctx context.Context, resumeID, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM resume_attachments WHERE id = $1 AND resume_id = $2`, id, resumeID)
+	if err != nil {
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return entity.ErrAttachmentNotFound
+	}
+	return nil
+}
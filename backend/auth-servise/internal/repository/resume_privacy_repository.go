@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+// ResumePrivacyRepositoryInterface stores the employers a candidate hides
+// their resumes from.
+type ResumePrivacyRepositoryInterface interface {
+	BlockEmployer(ctx context.Context, userID, employerID int64) error
+	UnblockEmployer(ctx context.Context, userID, employerID int64) error
+	GetBlockedEmployers(ctx context.Context, userID int64) ([]*entity.BlockedEmployer, error)
+	IsEmployerBlocked(ctx context.Context, userID, employerID int64) (bool, error)
+}
+
+type ResumePrivacyRepository struct {
+	db *sqlx.DB
+}
+
+func NewResumePrivacyRepository(db *sqlx.DB) *ResumePrivacyRepository {
+	return &ResumePrivacyRepository{db: db}
+}
+
+func (r *ResumePrivacyRepository) BlockEmployer(ctx context.Context, userID, employerID int64) error {
+	query := `
+		INSERT INTO resume_employer_blocks (user_id, employer_id)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id, employer_id) DO NOTHING`
+
+	if _, err := r.db.ExecContext(ctx, query, userID, employerID); err != nil {
+		return fmt.Errorf("failed to block employer: %w", err)
+	}
+	return nil
+}
+
+func (r *ResumePrivacyRepository) UnblockEmployer(ctx context.Context, userID, employerID int64) error {
+	query := `DELETE FROM resume_employer_blocks WHERE user_id = $1 AND employer_id = $2`
+
+	if _, err := r.db.ExecContext(ctx, query, userID, employerID); err != nil {
+		return fmt.Errorf("failed to unblock employer: %w", err)
+	}
+	return nil
+}
+
+func (r *ResumePrivacyRepository) GetBlockedEmployers(ctx context.Context, userID int64) ([]*entity.BlockedEmployer, error) {
+	query := `
+		SELECT b.user_id, b.employer_id, u.name AS employer_name, b.created_at
+		FROM resume_employer_blocks b
+		JOIN users u ON u.id = b.employer_id
+		WHERE b.user_id = $1
+		ORDER BY b.created_at DESC`
+
+	blocked := []*entity.BlockedEmployer{}
+	if err := r.db.SelectContext(ctx, &blocked, query, userID); err != nil {
+		return nil, fmt.Errorf("failed to get blocked employers: %w", err)
+	}
+	return blocked, nil
+}
+
+// IsEmployerBlocked reports whether the user blocked the employer or a
+// member of the employer's organization.
+func (r *ResumePrivacyRepository) IsEmployerBlocked(ctx context.Context, userID, employerID int64) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM resume_employer_blocks b
+			WHERE b.user_id = $1 AND ` + employerBlockedCondition("b", "$2") + `
+		)`
+
+	var blocked bool
+	if err := r.db.GetContext(ctx, &blocked, query, userID, employerID); err != nil {
+		return false, fmt.Errorf("failed to check employer block: %w", err)
+	}
+	return blocked, nil
+}
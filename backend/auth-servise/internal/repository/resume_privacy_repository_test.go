@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmployerBlockCoversTheOrganization(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	suffix := time.Now().UnixNano()
+
+	user := func(name, role string) int64 {
+		var id int64
+		require.NoError(t, db.GetContext(ctx, &id,
+			`INSERT INTO users (email, password, name, role) VALUES ($1, 'x', $2, $3) RETURNING id`,
+			fmt.Sprintf("%s-%d@example.com", name, suffix), name, role))
+		t.Cleanup(func() { db.Exec(`DELETE FROM users WHERE id = $1`, id) })
+		return id
+	}
+	candidateID := user("candidate", "jobseeker")
+	blockedID := user("blocked", "employer")
+	colleagueID := user("colleague", "employer")
+	outsiderID := user("outsider", "employer")
+
+	var organizationID int64
+	require.NoError(t, db.GetContext(ctx, &organizationID, `INSERT INTO organizations (name) VALUES ('Acme') RETURNING id`))
+	t.Cleanup(func() { db.Exec(`DELETE FROM organizations WHERE id = $1`, organizationID) })
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO organization_members (organization_id, user_id, role)
+		VALUES ($1, $2, 'owner'), ($1, $3, 'recruiter')`, organizationID, blockedID, colleagueID)
+	require.NoError(t, err)
+
+	location := fmt.Sprintf("Город-%d", suffix)
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO resumes (user_id, title, location, status, visibility)
+		VALUES ($1, 'Go developer', $2, 'active', 'employers')`, candidateID, location)
+	require.NoError(t, err)
+
+	privacy := NewResumePrivacyRepository(db)
+	require.NoError(t, privacy.BlockEmployer(ctx, candidateID, blockedID))
+
+	resumes := NewResumeRepository(db)
+	search := func(viewerID int64) int {
+		viewer := entity.ResumeViewer{UserID: viewerID, Role: string(entity.RoleEmployer), Verified: true}
+		_, total, err := resumes.Search(ctx, viewer, entity.ResumeSearchFilter{Location: location}, 10, 0)
+		require.NoError(t, err)
+		return total
+	}
+	assert.Zero(t, search(blockedID))
+	assert.Zero(t, search(colleagueID), "a colleague of the blocked employer does not find the resume")
+	assert.Equal(t, 1, search(outsiderID))
+
+	for viewerID, want := range map[int64]bool{blockedID: true, colleagueID: true, outsiderID: false} {
+		blocked, err := privacy.IsEmployerBlocked(ctx, candidateID, viewerID)
+		require.NoError(t, err)
+		assert.Equal(t, want, blocked, "viewer %d", viewerID)
+	}
+}
@@ -2,7 +2,7 @@ package repository
 
 import (
 	"context"
-	"database/sql/driver"
+	"database/sql"
 	"fmt"
 	"strings"
 	"time"
@@ -12,47 +12,6 @@ import (
 	"github.com/lib/pq"
 )
 
-// StringArray is a custom type that implements sql.Scanner and driver.Valuer
-type StringArray []string
-
-// Scan implements the sql.Scanner interface
-func (a *StringArray) Scan(value interface{}) error {
-	if value == nil {
-		*a = StringArray{}
-		return nil
-	}
-
-	switch v := value.(type) {
-	case []byte:
-		// Remove curly braces and split by comma
-		str := strings.Trim(string(v), "{}")
-		if str == "" {
-			*a = StringArray{}
-			return nil
-		}
-		*a = StringArray(strings.Split(str, ","))
-		return nil
-	case string:
-		str := strings.Trim(v, "{}")
-		if str == "" {
-			*a = StringArray{}
-			return nil
-		}
-		*a = StringArray(strings.Split(str, ","))
-		return nil
-	default:
-		return fmt.Errorf("unsupported type: %T", value)
-	}
-}
-
-// Value implements the driver.Valuer interface
-func (a StringArray) Value() (driver.Value, error) {
-	if len(a) == 0 {
-		return "{}", nil
-	}
-	return "{" + strings.Join(a, ",") + "}", nil
-}
-
 type ResumeRepositoryInterface interface {
 	Create(ctx context.Context, resume *entity.Resume) error
 	GetResumeByID(ctx context.Context, id int64) (*entity.Resume, error)
@@ -60,6 +19,10 @@ type ResumeRepositoryInterface interface {
 	Update(ctx context.Context, resume *entity.Resume) error
 	Delete(ctx context.Context, id int64) error
 	GetAll() ([]*entity.Resume, error)
+	Search(ctx context.Context, viewer entity.ResumeViewer, filter entity.ResumeSearchFilter, limit, offset int) ([]*entity.Resume, int, error)
+	Touch(ctx context.Context, id int64) error
+	GetByPublicToken(ctx context.Context, token string) (*entity.Resume, error)
+	UpdateVisibility(ctx context.Context, id int64, visibility, publicToken string) error
 }
 
 type ResumeRepository struct {
@@ -70,15 +33,92 @@ func NewResumeRepository(db *sqlx.DB) *ResumeRepository {
 	return &ResumeRepository{db: db}
 }
 
+const resumeColumns = `
+			id,
+			user_id,
+			title,
+			COALESCE(description, '') as description,
+			skills,
+			location,
+			resume_experience_years(id) AS experience_years,
+			COALESCE(legacy_experience, '') as legacy_experience,
+			COALESCE(legacy_education, '') as legacy_education,
+			status,
+			visibility,
+			COALESCE(public_token, '') as public_token,
+			created_at,
+			updated_at`
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanResume(row rowScanner) (*entity.Resume, error) {
+	var resume entity.Resume
+	var skills []string
+	err := row.Scan(
+		&resume.ID,
+		&resume.UserID,
+		&resume.Title,
+		&resume.Description,
+		pq.Array(&skills),
+		&resume.Location,
+		&resume.TotalExperienceYears,
+		&resume.LegacyExperience,
+		&resume.LegacyEducation,
+		&resume.Status,
+		&resume.Visibility,
+		&resume.PublicToken,
+		&resume.CreatedAt,
+		&resume.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if skills == nil {
+		skills = []string{}
+	}
+	resume.Skills = skills
+	return &resume, nil
+}
+
+func (r *ResumeRepository) queryResumes(ctx context.Context, query string, args ...interface{}) ([]*entity.Resume, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resumes: %w", err)
+	}
+	defer rows.Close()
+
+	var resumes []*entity.Resume
+	for rows.Next() {
+		resume, err := scanResume(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan resume row: %w", err)
+		}
+		resumes = append(resumes, resume)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating resume rows: %w", err)
+	}
+
+	return resumes, nil
+}
+
 func (r *ResumeRepository) Create(ctx context.Context, resume *entity.Resume) error {
 	query := `
-		INSERT INTO resumes (user_id, title, description, skills, experience, education, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO resumes (user_id, title, description, skills, skill_keys, location, legacy_experience, legacy_education, status, visibility, public_token, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11, ''), $12, $13)
 		RETURNING id`
 
+	if resume.Visibility == "" {
+		resume.Visibility = entity.VisibilityEmployers
+	}
 	now := time.Now()
 	resume.CreatedAt = now
 	resume.UpdatedAt = now
+	resume.Skills = entity.NormalizeSkills(resume.Skills)
 
 	err := r.db.QueryRowContext(
 		ctx,
@@ -87,9 +127,13 @@ func (r *ResumeRepository) Create(ctx context.Context, resume *entity.Resume) er
 		resume.Title,
 		resume.Description,
 		pq.Array(resume.Skills),
-		resume.Experience,
-		resume.Education,
+		pq.Array(entity.SkillKeys(resume.Skills)),
+		resume.Location,
+		resume.LegacyExperience,
+		resume.LegacyEducation,
 		resume.Status,
+		resume.Visibility,
+		resume.PublicToken,
 		resume.CreatedAt,
 		resume.UpdatedAt,
 	).Scan(&resume.ID)
@@ -102,138 +146,38 @@ func (r *ResumeRepository) Create(ctx context.Context, resume *entity.Resume) er
 }
 
 func (r *ResumeRepository) GetResumeByID(ctx context.Context, id int64) (*entity.Resume, error) {
-	query := `
-		SELECT 
-			id, 
-			user_id, 
-			title, 
-			description, 
-			COALESCE(skills::text, '[]') as skills, 
-			experience, 
-			education, 
-			status, 
-			created_at, 
-			updated_at
+	query := `SELECT ` + resumeColumns + `
 		FROM resumes
 		WHERE id = $1`
 
-	var resume entity.Resume
-	var skillsStr string
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&resume.ID,
-		&resume.UserID,
-		&resume.Title,
-		&resume.Description,
-		&skillsStr,
-		&resume.Experience,
-		&resume.Education,
-		&resume.Status,
-		&resume.CreatedAt,
-		&resume.UpdatedAt,
-	)
+	resume, err := scanResume(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get resume: %w", err)
 	}
 
-	// Parse the JSONB string into a string array
-	if skillsStr == "[]" {
-		resume.Skills = []string{}
-	} else {
-		// Remove the square brackets and split by comma
-		skillsStr = strings.Trim(skillsStr, "[]{}")
-		if skillsStr != "" {
-			// Split by comma and trim quotes and spaces
-			skills := strings.Split(skillsStr, ",")
-			for i, skill := range skills {
-				skills[i] = strings.Trim(skill, `" `)
-			}
-			resume.Skills = skills
-		} else {
-			resume.Skills = []string{}
-		}
-	}
-
-	return &resume, nil
+	return resume, nil
 }
 
 func (r *ResumeRepository) GetResumesByUserID(ctx context.Context, userID int64) ([]*entity.Resume, error) {
-	query := `
-		SELECT 
-			id, 
-			user_id, 
-			title, 
-			description, 
-			COALESCE(skills::text, '[]') as skills, 
-			experience, 
-			education, 
-			status, 
-			created_at, 
-			updated_at
+	query := `SELECT ` + resumeColumns + `
 		FROM resumes
 		WHERE user_id = $1
 		ORDER BY created_at DESC`
 
-	rows, err := r.db.QueryContext(ctx, query, userID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get resumes: %w", err)
-	}
-	defer rows.Close()
-
-	var resumes []*entity.Resume
-	for rows.Next() {
-		var resume entity.Resume
-		var skillsStr string
-		err := rows.Scan(
-			&resume.ID,
-			&resume.UserID,
-			&resume.Title,
-			&resume.Description,
-			&skillsStr,
-			&resume.Experience,
-			&resume.Education,
-			&resume.Status,
-			&resume.CreatedAt,
-			&resume.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan resume row: %w", err)
-		}
-
-		// Parse the skills string into a string array
-		if skillsStr == "[]" {
-			resume.Skills = []string{}
-		} else {
-			// Remove the square brackets and split by comma
-			skillsStr = strings.Trim(skillsStr, "[]{}")
-			if skillsStr != "" {
-				// Split by comma and trim quotes and spaces
-				skills := strings.Split(skillsStr, ",")
-				for i, skill := range skills {
-					skills[i] = strings.Trim(skill, `" `)
-				}
-				resume.Skills = skills
-			} else {
-				resume.Skills = []string{}
-			}
-		}
-
-		resumes = append(resumes, &resume)
-	}
-
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating resume rows: %w", err)
-	}
-
-	return resumes, nil
+	return r.queryResumes(ctx, query, userID)
 }
 
 func (r *ResumeRepository) Update(ctx context.Context, resume *entity.Resume) error {
 	query := `
 		UPDATE resumes
-		SET title = $1, description = $2, skills = $3, experience = $4, education = $5, status = $6, updated_at = $7
-		WHERE id = $8`
+		SET title = $1, description = $2, skills = $3, skill_keys = $4, location = $5, legacy_experience = $6, legacy_education = $7, status = $8, updated_at = $9
+		WHERE id = $10`
 
 	resume.UpdatedAt = time.Now()
+	resume.Skills = entity.NormalizeSkills(resume.Skills)
 
 	result, err := r.db.ExecContext(
 		ctx,
@@ -241,8 +185,10 @@ func (r *ResumeRepository) Update(ctx context.Context, resume *entity.Resume) er
 		resume.Title,
 		resume.Description,
 		pq.Array(resume.Skills),
-		resume.Experience,
-		resume.Education,
+		pq.Array(entity.SkillKeys(resume.Skills)),
+		resume.Location,
+		resume.LegacyExperience,
+		resume.LegacyEducation,
 		resume.Status,
 		resume.UpdatedAt,
 		resume.ID,
@@ -282,71 +228,142 @@ func (r *ResumeRepository) Delete(ctx context.Context, id int64) error {
 }
 
 func (r *ResumeRepository) GetAll() ([]*entity.Resume, error) {
-	query := `
-		SELECT 
-			id, 
-			user_id, 
-			title, 
-			description, 
-			COALESCE(skills::text, '[]') as skills, 
-			experience, 
-			education, 
-			status, 
-			created_at, 
-			updated_at
+	query := `SELECT ` + resumeColumns + `
 		FROM resumes
 		ORDER BY created_at DESC`
 
-	rows, err := r.db.QueryContext(context.Background(), query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get resumes: %w", err)
+	return r.queryResumes(context.Background(), query)
+}
+
+// Search returns active resumes the viewer is allowed to see that match the
+// filter, newest first, together with the total number of matches.
+func (r *ResumeRepository) Search(ctx context.Context, viewer entity.ResumeViewer, filter entity.ResumeSearchFilter, limit, offset int) ([]*entity.Resume, int, error) {
+	args := []interface{}{viewer.UserID, viewer.Verified}
+	conditions := []string{"status = 'active'", resumeVisibleTo, ownerListed("resumes.user_id")}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
 	}
-	defer rows.Close()
 
-	var resumes []*entity.Resume
-	for rows.Next() {
-		var resume entity.Resume
-		var skillsStr string
-		err := rows.Scan(
-			&resume.ID,
-			&resume.UserID,
-			&resume.Title,
-			&resume.Description,
-			&skillsStr,
-			&resume.Experience,
-			&resume.Education,
-			&resume.Status,
-			&resume.CreatedAt,
-			&resume.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan resume: %w", err)
+	if keys := entity.SkillKeys(filter.Skills); len(keys) > 0 {
+		// Both operators are served by the GIN index on resumes.skill_keys,
+		// which holds the skills lower-cased.
+		operator := "&&"
+		if filter.MatchAll {
+			operator = "@>"
 		}
+		conditions = append(conditions, "skill_keys "+operator+" "+arg(pq.Array(keys))+"::text[]")
+	}
+	if filter.MinExperience != nil {
+		conditions = append(conditions, "resume_experience_years(id) >= "+arg(*filter.MinExperience))
+	}
+	if filter.MaxExperience != nil {
+		conditions = append(conditions, "resume_experience_years(id) <= "+arg(*filter.MaxExperience))
+	}
+	if filter.Education != "" {
+		pattern := arg("%" + escapeLike(filter.Education) + "%")
+		conditions = append(conditions, `EXISTS (
+				SELECT 1 FROM resume_educations e
+				WHERE e.resume_id = resumes.id
+					AND (e.degree ILIKE `+pattern+` OR e.field_of_study ILIKE `+pattern+`)
+			)`)
+	}
+	if filter.Location != "" {
+		conditions = append(conditions, "location ILIKE "+arg("%"+escapeLike(filter.Location)+"%"))
+	}
+	if filter.UpdatedSince != nil {
+		conditions = append(conditions, "updated_at >= "+arg(*filter.UpdatedSince))
+	}
 
-		// Parse the JSONB string into a string array
-		if skillsStr == "[]" {
-			resume.Skills = []string{}
-		} else {
-			// Remove the square brackets and split by comma
-			skillsStr = strings.Trim(skillsStr, "[]{}")
-			if skillsStr != "" {
-				// Split by comma and trim quotes and spaces
-				skills := strings.Split(skillsStr, ",")
-				for i, skill := range skills {
-					skills[i] = strings.Trim(skill, `" `)
-				}
-				resume.Skills = skills
-			} else {
-				resume.Skills = []string{}
-			}
-		}
+	where := strings.Join(conditions, "\n\t\t\tAND ")
 
-		resumes = append(resumes, &resume)
+	var total int
+	countQuery := `SELECT COUNT(*) FROM resumes WHERE ` + where
+	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to count resumes: %w", err)
 	}
 
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating resume rows: %w", err)
+	query := `SELECT ` + resumeColumns + `
+		FROM resumes
+		WHERE ` + where + `
+		ORDER BY updated_at DESC
+		LIMIT ` + arg(limit) + ` OFFSET ` + arg(offset)
+
+	resumes, err := r.queryResumes(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
 	}
+	return resumes, total, nil
+}
 
-	return resumes, nil
+// resumeVisibleTo is the SQL counterpart of entity.Resume.Access for
+// listings. It expects the viewer ID as $1 and the verified flag as $2.
+var resumeVisibleTo = `(
+				visibility = 'public'
+				OR (visibility = 'employers' AND $2::boolean)
+				OR (visibility = 'applied' AND EXISTS (
+					SELECT 1
+					FROM applications a
+					JOIN vacancies v ON v.id = a.vacancy_id
+					WHERE a.user_id = resumes.user_id AND ` + vacancyAccessCondition("v", "$1") + `
+				))
+			)
+			AND NOT EXISTS (
+				SELECT 1 FROM resume_employer_blocks b
+				WHERE b.user_id = resumes.user_id AND ` + employerBlockedCondition("b", "$1") + `
+			)`
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// Touch marks the resume as updated, for changes made to its sections.
+func (r *ResumeRepository) Touch(ctx context.Context, id int64) error {
+	query := `UPDATE resumes SET updated_at = $1 WHERE id = $2`
+	if _, err := r.db.ExecContext(ctx, query, time.Now(), id); err != nil {
+		return fmt.Errorf("failed to touch resume: %w", err)
+	}
+	return nil
+}
+
+// GetByPublicToken returns the resume behind a public link, or nil if the
+// token is unknown.
+func (r *ResumeRepository) GetByPublicToken(ctx context.Context, token string) (*entity.Resume, error) {
+	query := `SELECT ` + resumeColumns + `
+		FROM resumes
+		WHERE public_token = $1 AND visibility = 'public'`
+
+	resume, err := scanResume(r.db.QueryRowContext(ctx, query, token))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resume by public token: %w", err)
+	}
+
+	return resume, nil
+}
+
+// UpdateVisibility changes who can see the resume. An empty publicToken
+// revokes the public link.
+func (r *ResumeRepository) UpdateVisibility(ctx context.Context, id int64, visibility, publicToken string) error {
+	query := `
+		UPDATE resumes
+		SET visibility = $1, public_token = NULLIF($2, ''), updated_at = $3
+		WHERE id = $4`
+
+	result, err := r.db.ExecContext(ctx, query, visibility, publicToken, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update resume visibility: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("resume not found")
+	}
+
+	return nil
 }
@@ -0,0 +1,301 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+type ResumeSectionRepositoryInterface interface {
+	GetWorkExperiences(ctx context.Context, resumeID int64) ([]*entity.WorkExperience, error)
+	CreateWorkExperience(ctx context.Context, item *entity.WorkExperience) error
+	UpdateWorkExperience(ctx context.Context, item *entity.WorkExperience) error
+	DeleteWorkExperience(ctx context.Context, resumeID, id int64) error
+
+	GetEducation(ctx context.Context, resumeID int64) ([]*entity.EducationEntry, error)
+	CreateEducation(ctx context.Context, item *entity.EducationEntry) error
+	UpdateEducation(ctx context.Context, item *entity.EducationEntry) error
+	DeleteEducation(ctx context.Context, resumeID, id int64) error
+
+	GetLanguages(ctx context.Context, resumeID int64) ([]*entity.LanguageSkill, error)
+	CreateLanguage(ctx context.Context, item *entity.LanguageSkill) error
+	UpdateLanguage(ctx context.Context, item *entity.LanguageSkill) error
+	DeleteLanguage(ctx context.Context, resumeID, id int64) error
+
+	GetCertifications(ctx context.Context, resumeID int64) ([]*entity.Certification, error)
+	CreateCertification(ctx context.Context, item *entity.Certification) error
+	UpdateCertification(ctx context.Context, item *entity.Certification) error
+	DeleteCertification(ctx context.Context, resumeID, id int64) error
+}
+
+type ResumeSectionRepository struct {
+	db *sqlx.DB
+}
+
+func NewResumeSectionRepository(db *sqlx.DB) *ResumeSectionRepository {
+	return &ResumeSectionRepository{db: db}
+}
+
+func (r *ResumeSectionRepository) GetWorkExperiences(ctx context.Context, resumeID int64) ([]*entity.WorkExperience, error) {
+	query := `
+		SELECT id, resume_id, company, title, start_date, end_date, description, created_at, updated_at
+		FROM resume_work_experiences
+		WHERE resume_id = $1
+		ORDER BY start_date DESC`
+
+	items := []*entity.WorkExperience{}
+	if err := r.db.SelectContext(ctx, &items, query, resumeID); err != nil {
+		return nil, fmt.Errorf("failed to get work experience: %w", err)
+	}
+	return items, nil
+}
+
+func (r *ResumeSectionRepository) CreateWorkExperience(ctx context.Context, item *entity.WorkExperience) error {
+	query := `
+		INSERT INTO resume_work_experiences (resume_id, company, title, start_date, end_date, description, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id`
+
+	now := time.Now()
+	item.CreatedAt = now
+	item.UpdatedAt = now
+
+	err := r.db.QueryRowContext(ctx, query,
+		item.ResumeID, item.Company, item.Title, item.StartDate, item.EndDate, item.Description,
+		item.CreatedAt, item.UpdatedAt,
+	).Scan(&item.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create work experience: %w", err)
+	}
+	return nil
+}
+
+func (r *ResumeSectionRepository) UpdateWorkExperience(ctx context.Context, item *entity.WorkExperience) error {
+	query := `
+		UPDATE resume_work_experiences
+		SET company = $1, title = $2, start_date = $3, end_date = $4, description = $5, updated_at = $6
+		WHERE id = $7 AND resume_id = $8
+		RETURNING created_at`
+
+	item.UpdatedAt = time.Now()
+
+	err := r.db.QueryRowContext(ctx, query,
+		item.Company, item.Title, item.StartDate, item.EndDate, item.Description, item.UpdatedAt,
+		item.ID, item.ResumeID,
+	).Scan(&item.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.ErrResumeSectionNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update work experience: %w", err)
+	}
+	return nil
+}
+
+func (r *ResumeSectionRepository) DeleteWorkExperience(ctx context.Context, resumeID, id int64) error {
+	return r.deleteEntry(ctx, "resume_work_experiences", resumeID, id)
+}
+
+func (r *ResumeSectionRepository) GetEducation(ctx context.Context, resumeID int64) ([]*entity.EducationEntry, error) {
+	query := `
+		SELECT id, resume_id, institution, degree, field_of_study, start_year, end_year, created_at, updated_at
+		FROM resume_educations
+		WHERE resume_id = $1
+		ORDER BY start_year DESC`
+
+	items := []*entity.EducationEntry{}
+	if err := r.db.SelectContext(ctx, &items, query, resumeID); err != nil {
+		return nil, fmt.Errorf("failed to get education: %w", err)
+	}
+	return items, nil
+}
+
+func (r *ResumeSectionRepository) CreateEducation(ctx context.Context, item *entity.EducationEntry) error {
+	query := `
+		INSERT INTO resume_educations (resume_id, institution, degree, field_of_study, start_year, end_year, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id`
+
+	now := time.Now()
+	item.CreatedAt = now
+	item.UpdatedAt = now
+
+	err := r.db.QueryRowContext(ctx, query,
+		item.ResumeID, item.Institution, item.Degree, item.FieldOfStudy, item.StartYear, item.EndYear,
+		item.CreatedAt, item.UpdatedAt,
+	).Scan(&item.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create education: %w", err)
+	}
+	return nil
+}
+
+func (r *ResumeSectionRepository) UpdateEducation(ctx context.Context, item *entity.EducationEntry) error {
+	query := `
+		UPDATE resume_educations
+		SET institution = $1, degree = $2, field_of_study = $3, start_year = $4, end_year = $5, updated_at = $6
+		WHERE id = $7 AND resume_id = $8
+		RETURNING created_at`
+
+	item.UpdatedAt = time.Now()
+
+	err := r.db.QueryRowContext(ctx, query,
+		item.Institution, item.Degree, item.FieldOfStudy, item.StartYear, item.EndYear, item.UpdatedAt,
+		item.ID, item.ResumeID,
+	).Scan(&item.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.ErrResumeSectionNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update education: %w", err)
+	}
+	return nil
+}
+
+func (r *ResumeSectionRepository) DeleteEducation(ctx context.Context, resumeID, id int64) error {
+	return r.deleteEntry(ctx, "resume_educations", resumeID, id)
+}
+
+func (r *ResumeSectionRepository) GetLanguages(ctx context.Context, resumeID int64) ([]*entity.LanguageSkill, error) {
+	query := `
+		SELECT id, resume_id, language, level, created_at, updated_at
+		FROM resume_languages
+		WHERE resume_id = $1
+		ORDER BY id`
+
+	items := []*entity.LanguageSkill{}
+	if err := r.db.SelectContext(ctx, &items, query, resumeID); err != nil {
+		return nil, fmt.Errorf("failed to get languages: %w", err)
+	}
+	return items, nil
+}
+
+func (r *ResumeSectionRepository) CreateLanguage(ctx context.Context, item *entity.LanguageSkill) error {
+	query := `
+		INSERT INTO resume_languages (resume_id, language, level, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id`
+
+	now := time.Now()
+	item.CreatedAt = now
+	item.UpdatedAt = now
+
+	err := r.db.QueryRowContext(ctx, query,
+		item.ResumeID, item.Language, item.Level, item.CreatedAt, item.UpdatedAt,
+	).Scan(&item.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create language: %w", err)
+	}
+	return nil
+}
+
+func (r *ResumeSectionRepository) UpdateLanguage(ctx context.Context, item *entity.LanguageSkill) error {
+	query := `
+		UPDATE resume_languages
+		SET language = $1, level = $2, updated_at = $3
+		WHERE id = $4 AND resume_id = $5
+		RETURNING created_at`
+
+	item.UpdatedAt = time.Now()
+
+	err := r.db.QueryRowContext(ctx, query,
+		item.Language, item.Level, item.UpdatedAt, item.ID, item.ResumeID,
+	).Scan(&item.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.ErrResumeSectionNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update language: %w", err)
+	}
+	return nil
+}
+
+func (r *ResumeSectionRepository) DeleteLanguage(ctx context.Context, resumeID, id int64) error {
+	return r.deleteEntry(ctx, "resume_languages", resumeID, id)
+}
+
+func (r *ResumeSectionRepository) GetCertifications(ctx context.Context, resumeID int64) ([]*entity.Certification, error) {
+	query := `
+		SELECT id, resume_id, name, issuer, issued_at, expires_at, credential_url, created_at, updated_at
+		FROM resume_certifications
+		WHERE resume_id = $1
+		ORDER BY issued_at DESC NULLS LAST`
+
+	items := []*entity.Certification{}
+	if err := r.db.SelectContext(ctx, &items, query, resumeID); err != nil {
+		return nil, fmt.Errorf("failed to get certifications: %w", err)
+	}
+	return items, nil
+}
+
+func (r *ResumeSectionRepository) CreateCertification(ctx context.Context, item *entity.Certification) error {
+	query := `
+		INSERT INTO resume_certifications (resume_id, name, issuer, issued_at, expires_at, credential_url, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id`
+
+	now := time.Now()
+	item.CreatedAt = now
+	item.UpdatedAt = now
+
+	err := r.db.QueryRowContext(ctx, query,
+		item.ResumeID, item.Name, item.Issuer, item.IssuedAt, item.ExpiresAt, item.CredentialURL,
+		item.CreatedAt, item.UpdatedAt,
+	).Scan(&item.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create certification: %w", err)
+	}
+	return nil
+}
+
+func (r *ResumeSectionRepository) UpdateCertification(ctx context.Context, item *entity.Certification) error {
+	query := `
+		UPDATE resume_certifications
+		SET name = $1, issuer = $2, issued_at = $3, expires_at = $4, credential_url = $5, updated_at = $6
+		WHERE id = $7 AND resume_id = $8
+		RETURNING created_at`
+
+	item.UpdatedAt = time.Now()
+
+	err := r.db.QueryRowContext(ctx, query,
+		item.Name, item.Issuer, item.IssuedAt, item.ExpiresAt, item.CredentialURL, item.UpdatedAt,
+		item.ID, item.ResumeID,
+	).Scan(&item.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.ErrResumeSectionNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update certification: %w", err)
+	}
+	return nil
+}
+
+func (r *ResumeSectionRepository) DeleteCertification(ctx context.Context, resumeID, id int64) error {
+	return r.deleteEntry(ctx, "resume_certifications", resumeID, id)
+}
+
+// deleteEntry removes a section row scoped to its resume. table is always one
+// of the constant table names above, never user input.
+func (r *ResumeSectionRepository) deleteEntry(ctx context.Context, table string, resumeID, id int64) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE id = $1 AND resume_id = $2`, table)
+
+	result, err := r.db.ExecContext(ctx, query, id, resumeID)
+	if err != nil {
+		return fmt.Errorf("failed to delete from %s: %w", table, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return entity.ErrResumeSectionNotFound
+	}
+
+	return nil
+}
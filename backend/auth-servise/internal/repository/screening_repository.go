@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type ScreeningRepositoryInterface interface {
+	GetQuestions(ctx context.Context, vacancyID int64) ([]*entity.ScreeningQuestion, error)
+	ReplaceQuestions(ctx context.Context, vacancyID int64, questions []*entity.ScreeningQuestion) error
+	GetAnswers(ctx context.Context, applicationIDs []int64) (map[int64][]*entity.ScreeningAnswer, error)
+}
+
+type ScreeningRepository struct {
+	db *sqlx.DB
+}
+
+func NewScreeningRepository(db *sqlx.DB) *ScreeningRepository {
+	return &ScreeningRepository{db: db}
+}
+
+func (r *ScreeningRepository) GetQuestions(ctx context.Context, vacancyID int64) ([]*entity.ScreeningQuestion, error) {
+	query := `
+		SELECT id, vacancy_id, position, type, text, options, required,
+			knockout_action, expected_bool, accepted_options, min_value, max_value
+		FROM vacancy_screening_questions
+		WHERE vacancy_id = $1
+		ORDER BY position`
+
+	rows, err := r.db.QueryContext(ctx, query, vacancyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get screening questions: %w", err)
+	}
+	defer rows.Close()
+
+	questions := []*entity.ScreeningQuestion{}
+	for rows.Next() {
+		q := &entity.ScreeningQuestion{}
+		err := rows.Scan(
+			&q.ID, &q.VacancyID, &q.Position, &q.Type, &q.Text, pq.Array(&q.Options), &q.Required,
+			&q.KnockoutAction, &q.ExpectedBool, pq.Array(&q.AcceptedOptions), &q.MinValue, &q.MaxValue,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan screening question: %w", err)
+		}
+		questions = append(questions, q)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get screening questions: %w", err)
+	}
+	return questions, nil
+}
+
+// ReplaceQuestions sets the questions of the vacancy. Questions keeping
+// their id are updated in place, so answers already given stay linked to
+// them; questions missing from the list are deleted.
+func (r *ScreeningRepository) ReplaceQuestions(ctx context.Context, vacancyID int64, questions []*entity.ScreeningQuestion) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	keep := []int64{}
+	for _, q := range questions {
+		if q.ID != 0 {
+			keep = append(keep, q.ID)
+		}
+	}
+	_, err = tx.ExecContext(ctx,
+		`DELETE FROM vacancy_screening_questions WHERE vacancy_id = $1 AND NOT (id = ANY($2))`,
+		vacancyID, pq.Array(keep),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete screening questions: %w", err)
+	}
+
+	for _, q := range questions {
+		q.VacancyID = vacancyID
+		args := []interface{}{
+			vacancyID, q.Position, q.Type, q.Text, pq.Array(q.Options), q.Required,
+			q.KnockoutAction, q.ExpectedBool, pq.Array(q.AcceptedOptions), q.MinValue, q.MaxValue,
+		}
+		if q.ID == 0 {
+			err = tx.QueryRowContext(ctx, `
+				INSERT INTO vacancy_screening_questions (vacancy_id, position, type, text, options, required,
+					knockout_action, expected_bool, accepted_options, min_value, max_value)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+				RETURNING id`, args...,
+			).Scan(&q.ID)
+			if err != nil {
+				return fmt.Errorf("failed to create screening question: %w", err)
+			}
+			continue
+		}
+
+		result, err := tx.ExecContext(ctx, `
+			UPDATE vacancy_screening_questions
+			SET position = $2, type = $3, text = $4, options = $5, required = $6,
+				knockout_action = $7, expected_bool = $8, accepted_options = $9, min_value = $10, max_value = $11
+			WHERE vacancy_id = $1 AND id = $12`, append(args, q.ID)...,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update screening question: %w", err)
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			return fmt.Errorf("%w: question %d does not belong to the vacancy", entity.ErrInvalidScreening, q.ID)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit screening questions: %w", err)
+	}
+	return nil
+}
+
+func insertScreeningAnswer(ctx context.Context, tx *sqlx.Tx, a *entity.ScreeningAnswer) error {
+	query := `
+		INSERT INTO application_screening_answers (application_id, question_id, question_text, question_type,
+			bool_value, number_value, text_value, options, passed)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, COALESCE($9, TRUE))`
+
+	_, err := tx.ExecContext(ctx, query,
+		a.ApplicationID, a.QuestionID, a.QuestionText, a.QuestionType,
+		a.Bool, a.Number, a.Text, pq.Array(a.Options), a.Passed,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save screening answer: %w", err)
+	}
+	return nil
+}
+
+// GetAnswers returns the screening answers of the applications, keyed by
+// application id.
+func (r *ScreeningRepository) GetAnswers(ctx context.Context, applicationIDs []int64) (map[int64][]*entity.ScreeningAnswer, error) {
+	answers := make(map[int64][]*entity.ScreeningAnswer)
+	if len(applicationIDs) == 0 {
+		return answers, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT application_id, question_id, question_text, question_type,
+			bool_value, number_value, text_value, options, passed
+		FROM application_screening_answers
+		WHERE application_id = ANY($1)
+		ORDER BY application_id, id`,
+		pq.Array(applicationIDs),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get screening answers: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		a := &entity.ScreeningAnswer{}
+		var passed bool
+		err := rows.Scan(
+			&a.ApplicationID, &a.QuestionID, &a.QuestionText, &a.QuestionType,
+			&a.Bool, &a.Number, &a.Text, pq.Array(&a.Options), &passed,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan screening answer: %w", err)
+		}
+		a.Passed = &passed
+		answers[a.ApplicationID] = append(answers[a.ApplicationID], a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get screening answers: %w", err)
+	}
+	return answers, nil
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+type StatsRepositoryInterface interface {
+	Totals(ctx context.Context) (*entity.UserStats, error)
+	Series(ctx context.Context, r entity.StatsRange) ([]*entity.StatsPoint, error)
+}
+
+type StatsRepository struct {
+	db *sqlx.DB
+}
+
+func NewStatsRepository(db *sqlx.DB) *StatsRepository {
+	return &StatsRepository{db: db}
+}
+
+// Totals counts users by role and vacancies, resumes and applications by
+// status in a single round trip.
+func (r *StatsRepository) Totals(ctx context.Context) (*entity.UserStats, error) {
+	query := `
+		SELECT 'users' AS kind, role AS key, COUNT(*) AS total FROM users GROUP BY role
+		UNION ALL
+		SELECT 'vacancies', status, COUNT(*) FROM vacancies GROUP BY status
+		UNION ALL
+		SELECT 'resumes', status, COUNT(*) FROM resumes GROUP BY status
+		UNION ALL
+		SELECT 'applications', status, COUNT(*) FROM applications GROUP BY status`
+
+	var rows []struct {
+		Kind  string `db:"kind"`
+		Key   string `db:"key"`
+		Total int    `db:"total"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, query); err != nil {
+		return nil, fmt.Errorf("failed to count platform totals: %w", err)
+	}
+
+	stats := &entity.UserStats{
+		UsersByRole:          map[string]int{},
+		VacanciesByStatus:    map[string]int{},
+		ResumesByStatus:      map[string]int{},
+		ApplicationsByStatus: map[string]int{},
+	}
+	for _, row := range rows {
+		switch row.Kind {
+		case "users":
+			stats.UsersByRole[row.Key] = row.Total
+			stats.TotalUsers += row.Total
+		case "vacancies":
+			stats.VacanciesByStatus[row.Key] = row.Total
+			stats.TotalVacancies += row.Total
+		case "resumes":
+			stats.ResumesByStatus[row.Key] = row.Total
+			stats.TotalResumes += row.Total
+		case "applications":
+			stats.ApplicationsByStatus[row.Key] = row.Total
+			stats.TotalApplications += row.Total
+		}
+	}
+	stats.TotalEmployers = stats.UsersByRole[string(entity.RoleEmployer)]
+	stats.TotalJobseekers = stats.UsersByRole[string(entity.RoleJobseeker)]
+	return stats, nil
+}
+
+// Series counts registrations, new vacancies and applications per day or
+// week of the range, in UTC. Intervals without events are included with
+// zeros.
+func (r *StatsRepository) Series(ctx context.Context, rng entity.StatsRange) ([]*entity.StatsPoint, error) {
+	// users и vacancies хранят время с часовым поясом, applications — без него
+	query := `
+		WITH registrations AS (
+			SELECT date_trunc($1, created_at AT TIME ZONE 'UTC') AS bucket,
+				COUNT(*) AS total,
+				COUNT(*) FILTER (WHERE role = 'employer') AS employers,
+				COUNT(*) FILTER (WHERE role = 'jobseeker') AS jobseekers
+			FROM users
+			WHERE created_at >= $2::timestamp AT TIME ZONE 'UTC' AND created_at < $4::timestamp AT TIME ZONE 'UTC'
+			GROUP BY 1
+		), new_vacancies AS (
+			SELECT date_trunc($1, created_at AT TIME ZONE 'UTC') AS bucket, COUNT(*) AS total
+			FROM vacancies
+			WHERE created_at >= $2::timestamp AT TIME ZONE 'UTC' AND created_at < $4::timestamp AT TIME ZONE 'UTC'
+			GROUP BY 1
+		), new_applications AS (
+			SELECT date_trunc($1, created_at) AS bucket, COUNT(*) AS total
+			FROM applications
+			WHERE created_at >= $2::timestamp AND created_at < $4::timestamp
+			GROUP BY 1
+		)
+		SELECT b.bucket,
+			COALESCE(r.total, 0) AS registrations,
+			COALESCE(r.employers, 0) AS employers,
+			COALESCE(r.jobseekers, 0) AS jobseekers,
+			COALESCE(v.total, 0) AS vacancies,
+			COALESCE(a.total, 0) AS applications
+		FROM generate_series($2::timestamp, $3::timestamp, ('1 ' || $1)::interval) AS b(bucket)
+		LEFT JOIN registrations r ON r.bucket = b.bucket
+		LEFT JOIN new_vacancies v ON v.bucket = b.bucket
+		LEFT JOIN new_applications a ON a.bucket = b.bucket
+		ORDER BY b.bucket`
+
+	points := []*entity.StatsPoint{}
+	err := r.db.SelectContext(ctx, &points, query,
+		rng.Interval, rng.From.UTC(), rng.To.UTC(), rng.End().UTC(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statistics series: %w", err)
+	}
+	for _, point := range points {
+		point.Start = point.Start.UTC()
+	}
+	return points, nil
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
 	"github.com/jmoiron/sqlx"
@@ -16,6 +18,9 @@ type UserRepositoryInterface interface {
 	Update(ctx context.Context, user *entity.User) error
 	Delete(ctx context.Context, id int64) error
 	GetAll(ctx context.Context) ([]*entity.User, error)
+	SetVerified(ctx context.Context, id int64, verified bool) error
+	UpdateAccountState(ctx context.Context, user *entity.User) error
+	ListRestricted(ctx context.Context, state string, limit, offset int) ([]*entity.User, int, error)
 }
 
 type UserRepository struct {
@@ -26,6 +31,36 @@ func NewUserRepository(db *sqlx.DB) *UserRepository {
 	return &UserRepository{db: db}
 }
 
+const userColumns = `id, email, password, name, role, is_verified, created_at, updated_at,
+	suspended_until, suspension_reason, banned_at, ban_reason, deleted_at`
+
+type userScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanUser(row userScanner) (*entity.User, error) {
+	user := &entity.User{}
+	err := row.Scan(
+		&user.ID,
+		&user.Email,
+		&user.Password,
+		&user.Name,
+		&user.Role,
+		&user.IsVerified,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&user.SuspendedUntil,
+		&user.SuspensionReason,
+		&user.BannedAt,
+		&user.BanReason,
+		&user.DeletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
 func (r *UserRepository) Create(ctx context.Context, user *entity.User) error {
 	query := `
 		INSERT INTO users (email, password, name, role, created_at, updated_at)
@@ -47,22 +82,9 @@ func (r *UserRepository) Create(ctx context.Context, user *entity.User) error {
 }
 
 func (r *UserRepository) GetByID(ctx context.Context, id int64) (*entity.User, error) {
-	query := `
-		SELECT id, email, password, name, role, created_at, updated_at
-		FROM users
-		WHERE id = $1`
-
-	user := &entity.User{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&user.ID,
-		&user.Email,
-		&user.Password,
-		&user.Name,
-		&user.Role,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
 
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -74,22 +96,9 @@ func (r *UserRepository) GetByID(ctx context.Context, id int64) (*entity.User, e
 }
 
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
-	query := `
-		SELECT id, email, password, name, role, created_at, updated_at
-		FROM users
-		WHERE email = $1`
-
-	user := &entity.User{}
-	err := r.db.QueryRowContext(ctx, query, email).Scan(
-		&user.ID,
-		&user.Email,
-		&user.Password,
-		&user.Name,
-		&user.Role,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	query := `SELECT ` + userColumns + ` FROM users WHERE email = $1`
 
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, email))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -127,7 +136,7 @@ func (r *UserRepository) Delete(ctx context.Context, id int64) error {
 }
 
 func (r *UserRepository) GetAll(ctx context.Context) ([]*entity.User, error) {
-	query := `SELECT id, name, email, role, created_at, updated_at FROM users`
+	query := `SELECT ` + userColumns + ` FROM users`
 	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
@@ -136,20 +145,89 @@ func (r *UserRepository) GetAll(ctx context.Context) ([]*entity.User, error) {
 
 	var users []*entity.User
 	for rows.Next() {
-		user := &entity.User{}
-		err := rows.Scan(
-			&user.ID,
-			&user.Name,
-			&user.Email,
-			&user.Role,
-			&user.CreatedAt,
-			&user.UpdatedAt,
-		)
+		user, err := scanUser(rows)
 		if err != nil {
 			return nil, err
 		}
 		users = append(users, user)
 	}
 
-	return users, nil
+	return users, rows.Err()
+}
+
+func (r *UserRepository) SetVerified(ctx context.Context, id int64, verified bool) error {
+	query := `UPDATE users SET is_verified = $1, updated_at = $2 WHERE id = $3`
+	_, err := r.db.ExecContext(ctx, query, verified, time.Now(), id)
+	return err
+}
+
+// UpdateAccountState saves the suspension, ban and deletion of the user.
+func (r *UserRepository) UpdateAccountState(ctx context.Context, user *entity.User) error {
+	query := `
+		UPDATE users
+		SET suspended_until = $1, suspension_reason = $2, banned_at = $3, ban_reason = $4,
+			deleted_at = $5, updated_at = $6
+		WHERE id = $7`
+
+	_, err := r.db.ExecContext(ctx, query,
+		user.SuspendedUntil, user.SuspensionReason, user.BannedAt, user.BanReason,
+		user.DeletedAt, user.UpdatedAt, user.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update account state: %w", err)
+	}
+	return nil
+}
+
+// ownerListed is the SQL condition under which the account in the user ID
+// column is neither banned nor deleted. Listings and search hide what such
+// accounts posted; it stays in the database in case the account is restored.
+func ownerListed(column string) string {
+	return fmt.Sprintf(
+		"NOT EXISTS (SELECT 1 FROM users owner WHERE owner.id = %s AND (owner.banned_at IS NOT NULL OR owner.deleted_at IS NOT NULL))",
+		column,
+	)
+}
+
+// restrictedConditions selects the accounts in each restricted state, the
+// same way entity.User.AccountState decides it.
+var restrictedConditions = map[string]string{
+	entity.AccountDeleted:   `deleted_at IS NOT NULL`,
+	entity.AccountBanned:    `deleted_at IS NULL AND banned_at IS NOT NULL`,
+	entity.AccountSuspended: `deleted_at IS NULL AND banned_at IS NULL AND suspended_until > NOW()`,
+	"":                      `(deleted_at IS NOT NULL OR banned_at IS NOT NULL OR suspended_until > NOW())`,
+}
+
+// ListRestricted returns a page of accounts in the state, or in any
+// restricted state if state is empty, most recently changed first, and their
+// total number.
+func (r *UserRepository) ListRestricted(ctx context.Context, state string, limit, offset int) ([]*entity.User, int, error) {
+	condition, ok := restrictedConditions[state]
+	if !ok {
+		return nil, 0, fmt.Errorf("%w: unknown state %q", entity.ErrInvalidAccountAction, state)
+	}
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM users WHERE `+condition); err != nil {
+		return nil, 0, fmt.Errorf("failed to count restricted users: %w", err)
+	}
+
+	query := `SELECT ` + userColumns + ` FROM users WHERE ` + condition + `
+		ORDER BY updated_at DESC, id DESC
+		LIMIT $1 OFFSET $2`
+	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list restricted users: %w", err)
+	}
+	defer rows.Close()
+
+	users := []*entity.User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+	return users, total, rows.Err()
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"regexp"
 	"testing"
 	"time"
 
@@ -13,6 +14,16 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+var userRowColumns = []string{
+	"id", "email", "password", "name", "role", "is_verified", "created_at", "updated_at",
+	"suspended_until", "suspension_reason", "banned_at", "ban_reason", "deleted_at",
+}
+
+func userRows(id int64, email string, createdAt time.Time) *sqlmock.Rows {
+	return sqlmock.NewRows(userRowColumns).
+		AddRow(id, email, "password", "Test User", "user", false, createdAt, createdAt, nil, "", nil, "", nil)
+}
+
 func TestCreateUser_Success(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -23,20 +34,23 @@ func TestCreateUser_Success(t *testing.T) {
 	sqlxDB := sqlx.NewDb(db, "sqlmock")
 	repo := NewUserRepository(sqlxDB)
 
+	now := time.Now()
 	user := &domain.User{
-		Username:  "testuser",
+		Email:     "test@example.com",
 		Password:  "password",
+		Name:      "Test User",
 		Role:      "user",
-		CreatedAt: time.Now(),
+		CreatedAt: now,
+		UpdatedAt: now,
 	}
 
 	mock.ExpectQuery("INSERT INTO users").
-		WithArgs(user.Username, user.Password, user.Role, user.CreatedAt).
+		WithArgs(user.Email, user.Password, user.Name, user.Role, user.CreatedAt, user.UpdatedAt).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 
-	id, err := repo.CreateUser(context.Background(), user)
+	err = repo.Create(context.Background(), user)
 	assert.NoError(t, err)
-	assert.Equal(t, int64(1), id)
+	assert.Equal(t, int64(1), user.ID)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -50,24 +64,27 @@ func TestCreateUser_Error(t *testing.T) {
 	sqlxDB := sqlx.NewDb(db, "sqlmock")
 	repo := NewUserRepository(sqlxDB)
 
+	now := time.Now()
 	user := &domain.User{
-		Username:  "testuser",
+		Email:     "test@example.com",
 		Password:  "password",
+		Name:      "Test User",
 		Role:      "user",
-		CreatedAt: time.Now(),
+		CreatedAt: now,
+		UpdatedAt: now,
 	}
 
 	mock.ExpectQuery("INSERT INTO users").
-		WithArgs(user.Username, user.Password, user.Role, user.CreatedAt).
+		WithArgs(user.Email, user.Password, user.Name, user.Role, user.CreatedAt, user.UpdatedAt).
 		WillReturnError(errors.New("database error"))
 
-	id, err := repo.CreateUser(context.Background(), user)
+	err = repo.Create(context.Background(), user)
 	assert.Error(t, err)
-	assert.Equal(t, int64(0), id)
+	assert.Equal(t, int64(0), user.ID)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestGetUserByUsername_Success(t *testing.T) {
+func TestGetUserByEmail_Success(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
@@ -77,27 +94,25 @@ func TestGetUserByUsername_Success(t *testing.T) {
 	sqlxDB := sqlx.NewDb(db, "sqlmock")
 	repo := NewUserRepository(sqlxDB)
 
-	username := "testuser"
+	email := "test@example.com"
 	createdAt := time.Now()
 
-	rows := sqlmock.NewRows([]string{"id", "username", "password", "role", "created_at"}).
-		AddRow(1, username, "password", "user", createdAt)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM users WHERE email = $1")).
+		WithArgs(email).
+		WillReturnRows(userRows(1, email, createdAt))
 
-	mock.ExpectQuery("SELECT id, username, password, role, created_at FROM users WHERE username = \\$1").
-		WithArgs(username).
-		WillReturnRows(rows)
-
-	user, err := repo.GetUserByUsername(context.Background(), username)
+	user, err := repo.GetByEmail(context.Background(), email)
 	assert.NoError(t, err)
 	assert.Equal(t, int64(1), user.ID)
-	assert.Equal(t, username, user.Username)
+	assert.Equal(t, email, user.Email)
 	assert.Equal(t, "password", user.Password)
 	assert.Equal(t, "user", user.Role)
 	assert.Equal(t, createdAt.Unix(), user.CreatedAt.Unix())
+	assert.Nil(t, user.BannedAt)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestGetUserByUsername_NotFound(t *testing.T) {
+func TestGetUserByEmail_NotFound(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
@@ -107,19 +122,19 @@ func TestGetUserByUsername_NotFound(t *testing.T) {
 	sqlxDB := sqlx.NewDb(db, "sqlmock")
 	repo := NewUserRepository(sqlxDB)
 
-	username := "nonexistent"
+	email := "nonexistent@example.com"
 
-	mock.ExpectQuery("SELECT id, username, password, role, created_at FROM users WHERE username = \\$1").
-		WithArgs(username).
+	mock.ExpectQuery(regexp.QuoteMeta("FROM users WHERE email = $1")).
+		WithArgs(email).
 		WillReturnError(sql.ErrNoRows)
 
-	user, err := repo.GetUserByUsername(context.Background(), username)
-	assert.ErrorIs(t, err, sql.ErrNoRows)
+	user, err := repo.GetByEmail(context.Background(), email)
+	assert.NoError(t, err)
 	assert.Nil(t, user)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestGetUserByUsername_Error(t *testing.T) {
+func TestGetUserByEmail_Error(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
@@ -129,13 +144,13 @@ func TestGetUserByUsername_Error(t *testing.T) {
 	sqlxDB := sqlx.NewDb(db, "sqlmock")
 	repo := NewUserRepository(sqlxDB)
 
-	username := "testuser"
+	email := "test@example.com"
 
-	mock.ExpectQuery("SELECT id, username, password, role, created_at FROM users WHERE username = \\$1").
-		WithArgs(username).
+	mock.ExpectQuery(regexp.QuoteMeta("FROM users WHERE email = $1")).
+		WithArgs(email).
 		WillReturnError(errors.New("database error"))
 
-	user, err := repo.GetUserByUsername(context.Background(), username)
+	user, err := repo.GetByEmail(context.Background(), email)
 	assert.Error(t, err)
 	assert.Nil(t, user)
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -154,17 +169,14 @@ func TestGetUserByID_Success(t *testing.T) {
 	id := int64(1)
 	createdAt := time.Now()
 
-	rows := sqlmock.NewRows([]string{"id", "username", "password", "role", "created_at"}).
-		AddRow(id, "testuser", "password", "user", createdAt)
-
-	mock.ExpectQuery("SELECT id, username, password, role, created_at FROM users WHERE id = \\$1").
+	mock.ExpectQuery(regexp.QuoteMeta("FROM users WHERE id = $1")).
 		WithArgs(id).
-		WillReturnRows(rows)
+		WillReturnRows(userRows(id, "test@example.com", createdAt))
 
-	user, err := repo.GetUserByID(context.Background(), id)
+	user, err := repo.GetByID(context.Background(), id)
 	assert.NoError(t, err)
 	assert.Equal(t, id, user.ID)
-	assert.Equal(t, "testuser", user.Username)
+	assert.Equal(t, "test@example.com", user.Email)
 	assert.Equal(t, "password", user.Password)
 	assert.Equal(t, "user", user.Role)
 	assert.Equal(t, createdAt.Unix(), user.CreatedAt.Unix())
@@ -183,12 +195,12 @@ func TestGetUserByID_NotFound(t *testing.T) {
 
 	id := int64(999)
 
-	mock.ExpectQuery("SELECT id, username, password, role, created_at FROM users WHERE id = \\$1").
+	mock.ExpectQuery(regexp.QuoteMeta("FROM users WHERE id = $1")).
 		WithArgs(id).
 		WillReturnError(sql.ErrNoRows)
 
-	user, err := repo.GetUserByID(context.Background(), id)
-	assert.NoError(t, err) 
+	user, err := repo.GetByID(context.Background(), id)
+	assert.NoError(t, err)
 	assert.Nil(t, user)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -205,11 +217,11 @@ func TestGetUserByID_Error(t *testing.T) {
 
 	id := int64(1)
 
-	mock.ExpectQuery("SELECT id, username, password, role, created_at FROM users WHERE id = \\$1").
+	mock.ExpectQuery(regexp.QuoteMeta("FROM users WHERE id = $1")).
 		WithArgs(id).
 		WillReturnError(errors.New("database error"))
 
-	user, err := repo.GetUserByID(context.Background(), id)
+	user, err := repo.GetByID(context.Background(), id)
 	assert.Error(t, err)
 	assert.Nil(t, user)
 	assert.NoError(t, mock.ExpectationsWereMet())
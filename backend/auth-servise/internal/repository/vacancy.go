@@ -25,17 +25,23 @@ func (r *VacancyRepository) Create(ctx context.Context, vacancy *entity.Vacancy)
 		INSERT INTO vacancies (
 			employer_id, title, description, requirements, responsibilities,
 			salary, location, employment_type, company, status, skills, education,
-			created_at, updated_at
+			allow_reapply, reapply_after_days, created_at, updated_at, organization_id
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17
 		) RETURNING id, created_at, updated_at`
 
 	now := time.Now()
 	vacancy.CreatedAt = now
 	vacancy.UpdatedAt = now
 
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
 	fmt.Printf("Executing query with values: %+v\n", vacancy)
-	err := r.db.QueryRowContext(
+	err = tx.QueryRowContext(
 		ctx,
 		query,
 		vacancy.EmployerID,
@@ -50,8 +56,11 @@ func (r *VacancyRepository) Create(ctx context.Context, vacancy *entity.Vacancy)
 		vacancy.Status,
 		pq.Array(vacancy.Skills),
 		vacancy.Education,
+		vacancy.AllowReapply,
+		vacancy.ReapplyAfterDays,
 		now,
 		now,
+		vacancy.OrganizationID,
 	).Scan(&vacancy.ID, &vacancy.CreatedAt, &vacancy.UpdatedAt)
 
 	if err != nil {
@@ -59,17 +68,28 @@ func (r *VacancyRepository) Create(ctx context.Context, vacancy *entity.Vacancy)
 		return err
 	}
 
+	if err := enqueueWebhook(ctx, tx, vacancy.EmployerID, entity.EventVacancyCreated, vacancyEventData(vacancy)); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit vacancy: %w", err)
+	}
+
 	fmt.Printf("Vacancy created successfully with ID: %d\n", vacancy.ID)
 	return nil
 }
 
+// employerVerifiedColumn selects the verified badge of the employer that
+// posted vacancy v.
+const employerVerifiedColumn = `COALESCE((SELECT u.is_verified FROM users u WHERE u.id = v.employer_id), FALSE) AS employer_verified`
+
 func (r *VacancyRepository) GetByID(ctx context.Context, id int64) (*entity.Vacancy, error) {
 	fmt.Printf("Fetching vacancy with ID: %d\n", id)
 	query := `
-		SELECT id, employer_id, title, description, requirements, responsibilities,
+		SELECT id, employer_id, organization_id, title, description, requirements, responsibilities,
 			salary, location, employment_type, company, status, skills, education,
-			created_at, updated_at
-		FROM vacancies
+			allow_reapply, reapply_after_days, created_at, updated_at, ` + employerVerifiedColumn + `
+		FROM vacancies v
 		WHERE id = $1`
 
 	var vacancy entity.Vacancy
@@ -77,6 +97,7 @@ func (r *VacancyRepository) GetByID(ctx context.Context, id int64) (*entity.Vaca
 	err := r.db.QueryRowxContext(ctx, query, id).Scan(
 		&vacancy.ID,
 		&vacancy.EmployerID,
+		&vacancy.OrganizationID,
 		&vacancy.Title,
 		&vacancy.Description,
 		&vacancy.Requirements,
@@ -88,8 +109,11 @@ func (r *VacancyRepository) GetByID(ctx context.Context, id int64) (*entity.Vaca
 		&vacancy.Status,
 		pq.Array(&skills),
 		&vacancy.Education,
+		&vacancy.AllowReapply,
+		&vacancy.ReapplyAfterDays,
 		&vacancy.CreatedAt,
 		&vacancy.UpdatedAt,
+		&vacancy.EmployerVerified,
 	)
 	if err == sql.ErrNoRows {
 		fmt.Printf("No vacancy found with ID: %d\n", id)
@@ -105,14 +129,17 @@ func (r *VacancyRepository) GetByID(ctx context.Context, id int64) (*entity.Vaca
 	return &vacancy, nil
 }
 
+// GetAll lists the vacancies, newest first, leaving out those of banned and
+// deleted employers.
 func (r *VacancyRepository) GetAll(ctx context.Context) ([]*entity.Vacancy, error) {
 	fmt.Printf("Starting to fetch all vacancies in repository\n")
 	var vacancies []*entity.Vacancy
 	query := `
-		SELECT id, employer_id, title, description, requirements, responsibilities,
+		SELECT id, employer_id, organization_id, title, description, requirements, responsibilities,
 			salary, location, employment_type, company, status, skills, education,
-			created_at, updated_at
-		FROM vacancies
+			allow_reapply, reapply_after_days, created_at, updated_at, ` + employerVerifiedColumn + `
+		FROM vacancies v
+		WHERE ` + ownerListed("v.employer_id") + `
 		ORDER BY created_at DESC`
 
 	rows, err := r.db.QueryxContext(ctx, query)
@@ -128,6 +155,7 @@ func (r *VacancyRepository) GetAll(ctx context.Context) ([]*entity.Vacancy, erro
 		err := rows.Scan(
 			&vacancy.ID,
 			&vacancy.EmployerID,
+			&vacancy.OrganizationID,
 			&vacancy.Title,
 			&vacancy.Description,
 			&vacancy.Requirements,
@@ -139,8 +167,11 @@ func (r *VacancyRepository) GetAll(ctx context.Context) ([]*entity.Vacancy, erro
 			&vacancy.Status,
 			pq.Array(&skills),
 			&vacancy.Education,
+			&vacancy.AllowReapply,
+			&vacancy.ReapplyAfterDays,
 			&vacancy.CreatedAt,
 			&vacancy.UpdatedAt,
+			&vacancy.EmployerVerified,
 		)
 		if err != nil {
 			fmt.Printf("Error scanning row: %v\n", err)
@@ -162,17 +193,27 @@ func (r *VacancyRepository) GetAll(ctx context.Context) ([]*entity.Vacancy, erro
 func (r *VacancyRepository) Update(ctx context.Context, vacancy *entity.Vacancy) error {
 	fmt.Printf("Starting vacancy update in repository for ID: %d\n", vacancy.ID)
 	query := `
-		UPDATE vacancies 
+		UPDATE vacancies v
 		SET title = $1, description = $2, requirements = $3, responsibilities = $4,
 			salary = $5, location = $6, employment_type = $7, company = $8,
-			status = $9, skills = $10, education = $11, updated_at = $12
-		WHERE id = $13 AND employer_id = $14
-		RETURNING updated_at`
+			status = $9, skills = $10, education = $11, updated_at = $12,
+			allow_reapply = $15, reapply_after_days = $16
+		FROM (SELECT id, status FROM vacancies WHERE id = $13 FOR UPDATE) old
+		WHERE v.id = old.id AND v.employer_id = $14
+		RETURNING v.updated_at, old.status`
 
 	vacancy.UpdatedAt = time.Now()
 
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Прежний статус нужен, чтобы отличить закрытие вакансии от правки
+	var previousStatus string
 	fmt.Printf("Executing update query with values: %+v\n", vacancy)
-	err := r.db.QueryRowContext(
+	err = tx.QueryRowContext(
 		ctx,
 		query,
 		vacancy.Title,
@@ -189,50 +230,72 @@ func (r *VacancyRepository) Update(ctx context.Context, vacancy *entity.Vacancy)
 		vacancy.UpdatedAt,
 		vacancy.ID,
 		vacancy.EmployerID,
-	).Scan(&vacancy.UpdatedAt)
+		vacancy.AllowReapply,
+		vacancy.ReapplyAfterDays,
+	).Scan(&vacancy.UpdatedAt, &previousStatus)
 
 	if err != nil {
 		fmt.Printf("Error updating vacancy: %v\n", err)
 		return fmt.Errorf("failed to update vacancy: %w", err)
 	}
 
+	event := entity.EventVacancyUpdated
+	if previousStatus == "active" && vacancy.Status != "active" {
+		event = entity.EventVacancyClosed
+	}
+	if err := enqueueWebhook(ctx, tx, vacancy.EmployerID, event, vacancyEventData(vacancy)); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit vacancy: %w", err)
+	}
+
 	fmt.Printf("Successfully updated vacancy with ID: %d\n", vacancy.ID)
 	return nil
 }
 
 func (r *VacancyRepository) Delete(ctx context.Context, id int64) error {
 	fmt.Printf("Starting vacancy deletion in repository for ID: %d\n", id)
-	query := `DELETE FROM vacancies WHERE id = $1`
+	query := `DELETE FROM vacancies WHERE id = $1 RETURNING employer_id, title, status`
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
-		fmt.Printf("Error deleting vacancy: %v\n", err)
-		return fmt.Errorf("failed to delete vacancy: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	rowsAffected, err := result.RowsAffected()
+	vacancy := &entity.Vacancy{ID: id}
+	err = tx.QueryRowContext(ctx, query, id).Scan(&vacancy.EmployerID, &vacancy.Title, &vacancy.Status)
+	if err == sql.ErrNoRows {
+		fmt.Printf("No vacancy found with ID: %d\n", id)
+		return fmt.Errorf("vacancy not found")
+	}
 	if err != nil {
-		fmt.Printf("Error getting rows affected: %v\n", err)
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		fmt.Printf("Error deleting vacancy: %v\n", err)
+		return fmt.Errorf("failed to delete vacancy: %w", err)
 	}
 
-	if rowsAffected == 0 {
-		fmt.Printf("No vacancy found with ID: %d\n", id)
-		return fmt.Errorf("vacancy not found")
+	if err := enqueueWebhook(ctx, tx, vacancy.EmployerID, entity.EventVacancyDeleted, vacancyEventData(vacancy)); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit vacancy deletion: %w", err)
 	}
 
 	fmt.Printf("Successfully deleted vacancy with ID: %d\n", id)
 	return nil
 }
 
+// GetByEmployerID returns the vacancies the employer posted and those of
+// their organization.
 func (r *VacancyRepository) GetByEmployerID(ctx context.Context, employerID int64) ([]*entity.Vacancy, error) {
 	fmt.Printf("Fetching vacancies for employer ID: %d\n", employerID)
 	query := `
-		SELECT id, employer_id, title, description, requirements, responsibilities,
+		SELECT id, employer_id, organization_id, title, description, requirements, responsibilities,
 			salary, location, employment_type, company, status, skills, education,
-			created_at, updated_at
-		FROM vacancies
-		WHERE employer_id = $1
+			allow_reapply, reapply_after_days, created_at, updated_at, ` + employerVerifiedColumn + `
+		FROM vacancies v
+		WHERE ` + vacancyAccessCondition("v", "$1") + `
 		ORDER BY created_at DESC`
 
 	var vacancies []*entity.Vacancy
@@ -249,6 +312,7 @@ func (r *VacancyRepository) GetByEmployerID(ctx context.Context, employerID int6
 		err := rows.Scan(
 			&vacancy.ID,
 			&vacancy.EmployerID,
+			&vacancy.OrganizationID,
 			&vacancy.Title,
 			&vacancy.Description,
 			&vacancy.Requirements,
@@ -260,8 +324,11 @@ func (r *VacancyRepository) GetByEmployerID(ctx context.Context, employerID int6
 			&vacancy.Status,
 			pq.Array(&skills),
 			&vacancy.Education,
+			&vacancy.AllowReapply,
+			&vacancy.ReapplyAfterDays,
 			&vacancy.CreatedAt,
 			&vacancy.UpdatedAt,
+			&vacancy.EmployerVerified,
 		)
 		if err != nil {
 			fmt.Printf("Error scanning row: %v\n", err)
@@ -279,3 +346,7 @@ func (r *VacancyRepository) GetByEmployerID(ctx context.Context, employerID int6
 	fmt.Printf("Successfully fetched %d vacancies for employer ID: %d\n", len(vacancies), employerID)
 	return vacancies, nil
 }
+
+func vacancyEventData(vacancy *entity.Vacancy) entity.VacancyEventData {
+	return entity.VacancyEventData{VacancyID: vacancy.ID, Title: vacancy.Title, Status: vacancy.Status}
+}
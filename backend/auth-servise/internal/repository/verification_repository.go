@@ -0,0 +1,290 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type VerificationRepositoryInterface interface {
+	Create(ctx context.Context, verification *entity.EmployerVerification) error
+	Resubmit(ctx context.Context, verification *entity.EmployerVerification, documents []*entity.VerificationDocument) error
+	GetByID(ctx context.Context, id int64) (*entity.EmployerVerification, error)
+	GetLatest(ctx context.Context, employerID int64) (*entity.EmployerVerification, error)
+	ConfirmEmail(ctx context.Context, token string) (bool, error)
+	ListQueue(ctx context.Context, status string, limit, offset int) ([]*entity.EmployerVerification, int, error)
+	Review(ctx context.Context, id, adminID int64, status, comment string) (*entity.EmployerVerification, error)
+	GetDocument(ctx context.Context, id int64) (*entity.VerificationDocument, error)
+}
+
+type VerificationRepository struct {
+	db *sqlx.DB
+}
+
+func NewVerificationRepository(db *sqlx.DB) *VerificationRepository {
+	return &VerificationRepository{db: db}
+}
+
+const verificationColumns = `
+	v.id, v.employer_id, u.name AS employer_name, u.email AS employer_email,
+	v.registration_number, v.company_email, v.email_token, v.email_confirmed_at,
+	v.status, v.admin_comment, v.reviewed_by, v.reviewed_at, v.created_at, v.updated_at`
+
+func insertDocuments(ctx context.Context, tx *sqlx.Tx, verificationID int64, documents []*entity.VerificationDocument, at time.Time) error {
+	for _, document := range documents {
+		document.VerificationID = verificationID
+		document.CreatedAt = at
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO verification_documents (verification_id, file_name, content_type, size, storage_key, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id`,
+			document.VerificationID, document.FileName, document.ContentType, document.Size, document.StorageKey, document.CreatedAt,
+		).Scan(&document.ID)
+		if err != nil {
+			return fmt.Errorf("failed to create verification document: %w", err)
+		}
+	}
+	return nil
+}
+
+// Create stores a new request with its documents. An employer has at most
+// one open request.
+func (r *VerificationRepository) Create(ctx context.Context, verification *entity.EmployerVerification) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	verification.Status = entity.VerificationPending
+	verification.CreatedAt = now
+	verification.UpdatedAt = now
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO employer_verifications (employer_id, registration_number, company_email, email_token, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		RETURNING id`,
+		verification.EmployerID, verification.RegistrationNumber, verification.CompanyEmail,
+		verification.EmailToken, verification.Status, now,
+	).Scan(&verification.ID)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return entity.ErrVerificationPending
+		}
+		return fmt.Errorf("failed to create verification request: %w", err)
+	}
+
+	if err := insertDocuments(ctx, tx, verification.ID, verification.Documents, now); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit verification request: %w", err)
+	}
+	return nil
+}
+
+// Resubmit puts a request the admin sent back into the queue again with the
+// corrected details and the added documents. A changed company email has to
+// be confirmed again.
+func (r *VerificationRepository) Resubmit(ctx context.Context, verification *entity.EmployerVerification, documents []*entity.VerificationDocument) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	err = tx.QueryRowContext(ctx, `
+		UPDATE employer_verifications
+		SET registration_number = $1, company_email = $2, email_token = $3,
+			email_confirmed_at = CASE WHEN company_email = $2 THEN email_confirmed_at END,
+			status = $4, updated_at = $5
+		WHERE id = $6 AND status = $7
+		RETURNING email_confirmed_at`,
+		verification.RegistrationNumber, verification.CompanyEmail, verification.EmailToken,
+		entity.VerificationPending, now, verification.ID, entity.VerificationInfoRequested,
+	).Scan(&verification.EmailConfirmedAt)
+	if err == sql.ErrNoRows {
+		return entity.ErrVerificationClosed
+	}
+	if err != nil {
+		return fmt.Errorf("failed to resubmit verification request: %w", err)
+	}
+
+	if err := insertDocuments(ctx, tx, verification.ID, documents, now); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit verification request: %w", err)
+	}
+	verification.Status = entity.VerificationPending
+	verification.UpdatedAt = now
+	verification.Documents = append(verification.Documents, documents...)
+	return nil
+}
+
+func (r *VerificationRepository) get(ctx context.Context, condition string, arg interface{}) (*entity.EmployerVerification, error) {
+	var verification entity.EmployerVerification
+	err := r.db.GetContext(ctx, &verification, `
+		SELECT `+verificationColumns+`
+		FROM employer_verifications v
+		JOIN users u ON u.id = v.employer_id
+		WHERE `+condition+`
+		ORDER BY v.created_at DESC
+		LIMIT 1`, arg)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get verification request: %w", err)
+	}
+
+	if err := r.loadDocuments(ctx, []*entity.EmployerVerification{&verification}); err != nil {
+		return nil, err
+	}
+	return &verification, nil
+}
+
+func (r *VerificationRepository) GetByID(ctx context.Context, id int64) (*entity.EmployerVerification, error) {
+	return r.get(ctx, "v.id = $1", id)
+}
+
+// GetLatest returns the employer's most recent request.
+func (r *VerificationRepository) GetLatest(ctx context.Context, employerID int64) (*entity.EmployerVerification, error) {
+	return r.get(ctx, "v.employer_id = $1", employerID)
+}
+
+func (r *VerificationRepository) loadDocuments(ctx context.Context, verifications []*entity.EmployerVerification) error {
+	if len(verifications) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(verifications))
+	byID := make(map[int64]*entity.EmployerVerification, len(verifications))
+	for i, verification := range verifications {
+		ids[i] = verification.ID
+		verification.Documents = []*entity.VerificationDocument{}
+		byID[verification.ID] = verification
+	}
+
+	documents := []*entity.VerificationDocument{}
+	err := r.db.SelectContext(ctx, &documents, `
+		SELECT id, verification_id, file_name, content_type, size, storage_key, created_at
+		FROM verification_documents
+		WHERE verification_id = ANY($1)
+		ORDER BY id`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to get verification documents: %w", err)
+	}
+	for _, document := range documents {
+		byID[document.VerificationID].Documents = append(byID[document.VerificationID].Documents, document)
+	}
+	return nil
+}
+
+// ConfirmEmail marks the company email of an open request as confirmed and
+// reports whether the token matched one.
+func (r *VerificationRepository) ConfirmEmail(ctx context.Context, token string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE employer_verifications
+		SET email_confirmed_at = COALESCE(email_confirmed_at, $1)
+		WHERE email_token = $2 AND status IN ($3, $4)`,
+		time.Now(), token, entity.VerificationPending, entity.VerificationInfoRequested,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to confirm company email: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// ListQueue returns a page of requests with the status, the longest waiting
+// first, and the number of such requests.
+func (r *VerificationRepository) ListQueue(ctx context.Context, status string, limit, offset int) ([]*entity.EmployerVerification, int, error) {
+	var total int
+	err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM employer_verifications WHERE status = $1`, status)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count verification requests: %w", err)
+	}
+
+	verifications := []*entity.EmployerVerification{}
+	err = r.db.SelectContext(ctx, &verifications, `
+		SELECT `+verificationColumns+`
+		FROM employer_verifications v
+		JOIN users u ON u.id = v.employer_id
+		WHERE v.status = $1
+		ORDER BY v.updated_at, v.id
+		LIMIT $2 OFFSET $3`, status, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list verification requests: %w", err)
+	}
+	if err := r.loadDocuments(ctx, verifications); err != nil {
+		return nil, 0, err
+	}
+	return verifications, total, nil
+}
+
+// Review records the admin's decision on a pending request. Approving it
+// verifies the employer in the same transaction.
+func (r *VerificationRepository) Review(ctx context.Context, id, adminID int64, status, comment string) (*entity.EmployerVerification, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	var employerID int64
+	err = tx.QueryRowContext(ctx, `
+		UPDATE employer_verifications
+		SET status = $1, admin_comment = $2, reviewed_by = $3, reviewed_at = $4, updated_at = $4
+		WHERE id = $5 AND status = $6
+		RETURNING employer_id`,
+		status, comment, adminID, now, id, entity.VerificationPending,
+	).Scan(&employerID)
+	if err == sql.ErrNoRows {
+		return nil, entity.ErrVerificationClosed
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to review verification request: %w", err)
+	}
+
+	if status == entity.VerificationApproved {
+		_, err := tx.ExecContext(ctx,
+			`UPDATE users SET is_verified = TRUE, updated_at = $1 WHERE id = $2`, now, employerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify employer: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit verification review: %w", err)
+	}
+	return r.GetByID(ctx, id)
+}
+
+func (r *VerificationRepository) GetDocument(ctx context.Context, id int64) (*entity.VerificationDocument, error) {
+	var document entity.VerificationDocument
+	err := r.db.GetContext(ctx, &document, `
+		SELECT id, verification_id, file_name, content_type, size, storage_key, created_at
+		FROM verification_documents
+		WHERE id = $1`, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get verification document: %w", err)
+	}
+	return &document, nil
+}
@@ -0,0 +1,291 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type WebhookRepositoryInterface interface {
+	CreateEndpoint(ctx context.Context, endpoint *entity.WebhookEndpoint) error
+	GetEndpoint(ctx context.Context, id int64) (*entity.WebhookEndpoint, error)
+	ListEndpoints(ctx context.Context, employerID int64) ([]*entity.WebhookEndpoint, error)
+	UpdateEndpoint(ctx context.Context, endpoint *entity.WebhookEndpoint) error
+	DeleteEndpoint(ctx context.Context, id int64) error
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*entity.WebhookDelivery, error)
+	RecordAttempt(ctx context.Context, delivery *entity.WebhookDelivery, attempt *entity.WebhookAttempt) error
+	ListDeliveries(ctx context.Context, endpointID int64, status string, limit, offset int) ([]*entity.WebhookDelivery, error)
+	GetDelivery(ctx context.Context, id int64) (*entity.WebhookDelivery, error)
+	Redeliver(ctx context.Context, id int64) (*entity.WebhookDelivery, error)
+}
+
+type WebhookRepository struct {
+	db *sqlx.DB
+}
+
+func NewWebhookRepository(db *sqlx.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+const deliveryColumns = `d.id, d.endpoint_id, d.event, d.payload, d.status, d.attempts, d.next_attempt_at,
+			d.last_status_code, d.last_error, d.redelivery_of, d.created_at, d.delivered_at`
+
+// enqueueWebhook queues the event for every active endpoint of the employer
+// subscribed to it. It runs in the transaction that changes the data, so an
+// event is queued if and only if the change is committed.
+func enqueueWebhook(ctx context.Context, tx *sqlx.Tx, employerID int64, event string, data interface{}) error {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Errorf("failed to generate event id: %w", err)
+	}
+	now := time.Now()
+	payload, err := json.Marshal(entity.WebhookEvent{
+		ID:        "evt_" + hex.EncodeToString(buf),
+		Type:      event,
+		CreatedAt: now,
+		Data:      data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook event: %w", err)
+	}
+
+	query := `
+		INSERT INTO webhook_deliveries (endpoint_id, event, payload, status, next_attempt_at, created_at)
+		SELECT e.id, $2, $3, $4, $5, $5
+		FROM webhook_endpoints e
+		WHERE e.employer_id = $1 AND e.active AND $2 = ANY(e.events)`
+
+	if _, err := tx.ExecContext(ctx, query, employerID, event, payload, entity.DeliveryStatusPending, now); err != nil {
+		return fmt.Errorf("failed to queue webhook: %w", err)
+	}
+	return nil
+}
+
+func scanWebhookEndpoint(row rowScanner) (*entity.WebhookEndpoint, error) {
+	var endpoint entity.WebhookEndpoint
+	err := row.Scan(
+		&endpoint.ID, &endpoint.EmployerID, &endpoint.URL, &endpoint.Secret, pq.Array(&endpoint.Events),
+		&endpoint.Active, &endpoint.CreatedAt, &endpoint.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &endpoint, nil
+}
+
+func (r *WebhookRepository) CreateEndpoint(ctx context.Context, endpoint *entity.WebhookEndpoint) error {
+	now := time.Now()
+	endpoint.CreatedAt = now
+	endpoint.UpdatedAt = now
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO webhook_endpoints (employer_id, url, secret, events, active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		RETURNING id`,
+		endpoint.EmployerID, endpoint.URL, endpoint.Secret, pq.Array(endpoint.Events), endpoint.Active, now,
+	).Scan(&endpoint.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook endpoint: %w", err)
+	}
+	return nil
+}
+
+func (r *WebhookRepository) GetEndpoint(ctx context.Context, id int64) (*entity.WebhookEndpoint, error) {
+	query := `
+		SELECT id, employer_id, url, secret, events, active, created_at, updated_at
+		FROM webhook_endpoints
+		WHERE id = $1`
+
+	endpoint, err := scanWebhookEndpoint(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook endpoint: %w", err)
+	}
+	return endpoint, nil
+}
+
+func (r *WebhookRepository) ListEndpoints(ctx context.Context, employerID int64) ([]*entity.WebhookEndpoint, error) {
+	query := `
+		SELECT id, employer_id, url, secret, events, active, created_at, updated_at
+		FROM webhook_endpoints
+		WHERE employer_id = $1
+		ORDER BY id`
+
+	rows, err := r.db.QueryContext(ctx, query, employerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook endpoints: %w", err)
+	}
+	defer rows.Close()
+
+	endpoints := []*entity.WebhookEndpoint{}
+	for rows.Next() {
+		endpoint, err := scanWebhookEndpoint(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook endpoint: %w", err)
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list webhook endpoints: %w", err)
+	}
+	return endpoints, nil
+}
+
+// UpdateEndpoint saves the URL, the events and the active flag. The secret
+// never changes.
+func (r *WebhookRepository) UpdateEndpoint(ctx context.Context, endpoint *entity.WebhookEndpoint) error {
+	endpoint.UpdatedAt = time.Now()
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE webhook_endpoints SET url = $1, events = $2, active = $3, updated_at = $4
+		WHERE id = $5`,
+		endpoint.URL, pq.Array(endpoint.Events), endpoint.Active, endpoint.UpdatedAt, endpoint.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook endpoint: %w", err)
+	}
+	return nil
+}
+
+func (r *WebhookRepository) DeleteEndpoint(ctx context.Context, id int64) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM webhook_endpoints WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to delete webhook endpoint: %w", err)
+	}
+	return nil
+}
+
+// ClaimDue takes up to limit pending deliveries whose time has come and
+// locks them for lease, so several instances of the service never send the
+// same delivery at once. A delivery whose sender died is picked up again
+// when the lease runs out.
+func (r *WebhookRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*entity.WebhookDelivery, error) {
+	now := time.Now()
+	query := `
+		UPDATE webhook_deliveries d
+		SET locked_until = $2
+		FROM webhook_endpoints e
+		WHERE e.id = d.endpoint_id AND d.id IN (
+			SELECT q.id
+			FROM webhook_deliveries q
+			JOIN webhook_endpoints qe ON qe.id = q.endpoint_id AND qe.active
+			WHERE q.status = $3 AND q.next_attempt_at <= $1
+				AND (q.locked_until IS NULL OR q.locked_until < $1)
+			ORDER BY q.next_attempt_at, q.id
+			LIMIT $4
+			FOR UPDATE OF q SKIP LOCKED
+		)
+		RETURNING ` + deliveryColumns + `, e.url, e.secret`
+
+	deliveries := []*entity.WebhookDelivery{}
+	err := r.db.SelectContext(ctx, &deliveries, query, now, now.Add(lease), entity.DeliveryStatusPending, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// RecordAttempt logs the attempt and saves the delivery's new state, which
+// the caller has already applied with Record.
+func (r *WebhookRepository) RecordAttempt(ctx context.Context, delivery *entity.WebhookDelivery, attempt *entity.WebhookAttempt) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO webhook_delivery_attempts (delivery_id, number, status_code, error, response, duration_ms, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`,
+		delivery.ID, attempt.Number, attempt.StatusCode, attempt.Error, attempt.Response, attempt.DurationMS, attempt.CreatedAt,
+	).Scan(&attempt.ID)
+	if err != nil {
+		return fmt.Errorf("failed to log webhook attempt: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE webhook_deliveries
+		SET status = $1, attempts = $2, next_attempt_at = $3, last_status_code = $4, last_error = $5,
+			delivered_at = $6, locked_until = NULL
+		WHERE id = $7`,
+		delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.LastStatusCode, delivery.LastError,
+		delivery.DeliveredAt, delivery.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit webhook attempt: %w", err)
+	}
+	return nil
+}
+
+// ListDeliveries returns the endpoint's deliveries, newest first, optionally
+// only those with the status.
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, endpointID int64, status string, limit, offset int) ([]*entity.WebhookDelivery, error) {
+	query := `
+		SELECT ` + deliveryColumns + `
+		FROM webhook_deliveries d
+		WHERE d.endpoint_id = $1 AND ($2 = '' OR d.status = $2)
+		ORDER BY d.id DESC
+		LIMIT $3 OFFSET $4`
+
+	deliveries := []*entity.WebhookDelivery{}
+	if err := r.db.SelectContext(ctx, &deliveries, query, endpointID, status, limit, offset); err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// GetDelivery returns the delivery with its attempt log.
+func (r *WebhookRepository) GetDelivery(ctx context.Context, id int64) (*entity.WebhookDelivery, error) {
+	var delivery entity.WebhookDelivery
+	err := r.db.GetContext(ctx, &delivery, `SELECT `+deliveryColumns+` FROM webhook_deliveries d WHERE d.id = $1`, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+
+	delivery.AttemptLog = []*entity.WebhookAttempt{}
+	err = r.db.SelectContext(ctx, &delivery.AttemptLog, `
+		SELECT id, delivery_id, number, status_code, error, response, duration_ms, created_at
+		FROM webhook_delivery_attempts
+		WHERE delivery_id = $1
+		ORDER BY number, id`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook attempts: %w", err)
+	}
+	return &delivery, nil
+}
+
+// Redeliver queues a copy of the delivery with the same payload, so the
+// receiver can tell it by the event id, and returns it.
+func (r *WebhookRepository) Redeliver(ctx context.Context, id int64) (*entity.WebhookDelivery, error) {
+	query := `
+		INSERT INTO webhook_deliveries AS d (endpoint_id, event, payload, status, next_attempt_at, redelivery_of, created_at)
+		SELECT endpoint_id, event, payload, $2, $3, id, $3
+		FROM webhook_deliveries
+		WHERE id = $1
+		RETURNING ` + deliveryColumns
+
+	var delivery entity.WebhookDelivery
+	err := r.db.GetContext(ctx, &delivery, query, id, entity.DeliveryStatusPending, time.Now())
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to redeliver webhook: %w", err)
+	}
+	return &delivery, nil
+}
@@ -0,0 +1,200 @@
+package resumeimport
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+)
+
+// jsonResume covers the parts of the jsonresume.org schema that map onto
+// our resume. Everything else is reported as unmapped.
+type jsonResume struct {
+	Basics struct {
+		Name     string          `json:"name"`
+		Label    string          `json:"label"`
+		Email    string          `json:"email"`
+		Phone    string          `json:"phone"`
+		URL      string          `json:"url"`
+		Summary  string          `json:"summary"`
+		Image    string          `json:"image"`
+		Location json.RawMessage `json:"location"`
+		Profiles json.RawMessage `json:"profiles"`
+	} `json:"basics"`
+	Work []struct {
+		Name       string   `json:"name"`
+		Position   string   `json:"position"`
+		StartDate  string   `json:"startDate"`
+		EndDate    string   `json:"endDate"`
+		Summary    string   `json:"summary"`
+		Highlights []string `json:"highlights"`
+	} `json:"work"`
+	Education []struct {
+		Institution string   `json:"institution"`
+		Area        string   `json:"area"`
+		StudyType   string   `json:"studyType"`
+		StartDate   string   `json:"startDate"`
+		EndDate     string   `json:"endDate"`
+		Score       string   `json:"score"`
+		Courses     []string `json:"courses"`
+	} `json:"education"`
+	Certificates []struct {
+		Name   string `json:"name"`
+		Date   string `json:"date"`
+		Issuer string `json:"issuer"`
+		URL    string `json:"url"`
+	} `json:"certificates"`
+	Skills []struct {
+		Name     string   `json:"name"`
+		Keywords []string `json:"keywords"`
+	} `json:"skills"`
+	Languages []struct {
+		Language string `json:"language"`
+		Fluency  string `json:"fluency"`
+	} `json:"languages"`
+}
+
+var jsonResumeMappedSections = map[string]bool{
+	"$schema":      true,
+	"meta":         true,
+	"basics":       true,
+	"work":         true,
+	"education":    true,
+	"certificates": true,
+	"skills":       true,
+	"languages":    true,
+}
+
+// ParseJSONResume maps a jsonresume.org document.
+func ParseJSONResume(r io.Reader) (*Result, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read document: %w", err)
+	}
+
+	var sections map[string]json.RawMessage
+	if err := json.Unmarshal(data, &sections); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDocument, err)
+	}
+	var doc jsonResume
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDocument, err)
+	}
+
+	result := newResult(SourceJSONResume)
+	resume := result.Resume
+
+	resume.Title = strings.TrimSpace(doc.Basics.Label)
+	resume.Description = strings.TrimSpace(doc.Basics.Summary)
+	if doc.Basics.Name != "" {
+		result.unmapped("basics.name: the account name is used instead")
+	}
+	if doc.Basics.Email != "" {
+		result.unmapped("basics.email: the account email is used instead")
+	}
+	for field, value := range map[string]string{
+		"basics.phone": doc.Basics.Phone,
+		"basics.url":   doc.Basics.URL,
+		"basics.image": doc.Basics.Image,
+	} {
+		if value != "" {
+			result.unmapped(field)
+		}
+	}
+	if !isEmptyJSON(doc.Basics.Location) {
+		result.unmapped("basics.location")
+	}
+	if !isEmptyJSON(doc.Basics.Profiles) {
+		result.unmapped("basics.profiles")
+	}
+
+	for i, w := range doc.Work {
+		start, ok := parseDate(w.StartDate)
+		if !ok {
+			result.unmapped(fmt.Sprintf("work[%d]: missing or invalid startDate", i))
+			continue
+		}
+		var highlights []string
+		for _, h := range w.Highlights {
+			highlights = append(highlights, "• "+h)
+		}
+		resume.WorkExperience = append(resume.WorkExperience, &entity.WorkExperience{
+			Company:     w.Name,
+			Title:       w.Position,
+			StartDate:   start,
+			EndDate:     parseOptionalDate(w.EndDate),
+			Description: joinLines(w.Summary, strings.Join(highlights, "\n")),
+		})
+	}
+
+	for i, e := range doc.Education {
+		startYear, ok := parseYear(e.StartDate)
+		if !ok {
+			result.unmapped(fmt.Sprintf("education[%d]: missing or invalid startDate", i))
+			continue
+		}
+		entry := &entity.EducationEntry{
+			Institution:  e.Institution,
+			Degree:       e.StudyType,
+			FieldOfStudy: e.Area,
+			StartYear:    startYear,
+		}
+		if endYear, ok := parseYear(e.EndDate); ok {
+			entry.EndYear = &endYear
+		}
+		resume.Education = append(resume.Education, entry)
+		if e.Score != "" {
+			result.unmapped(fmt.Sprintf("education[%d].score", i))
+		}
+		if len(e.Courses) > 0 {
+			result.unmapped(fmt.Sprintf("education[%d].courses", i))
+		}
+	}
+
+	for _, c := range doc.Certificates {
+		resume.Certifications = append(resume.Certifications, &entity.Certification{
+			Name:          c.Name,
+			Issuer:        c.Issuer,
+			IssuedAt:      parseOptionalDate(c.Date),
+			CredentialURL: c.URL,
+		})
+	}
+
+	for _, s := range doc.Skills {
+		resume.Skills = append(resume.Skills, s.Name)
+		resume.Skills = append(resume.Skills, s.Keywords...)
+	}
+
+	for i, l := range doc.Languages {
+		level, ok := languageLevel(l.Fluency)
+		if !ok {
+			result.unmapped(fmt.Sprintf("languages[%d]: unknown fluency %q", i, l.Fluency))
+			continue
+		}
+		resume.Languages = append(resume.Languages, &entity.LanguageSkill{Language: l.Language, Level: level})
+	}
+
+	var extra []string
+	for name, raw := range sections {
+		if !jsonResumeMappedSections[name] && !isEmptyJSON(raw) {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+	result.Unmapped = append(result.Unmapped, extra...)
+	sort.Strings(result.Unmapped)
+
+	result.finish()
+	return result, nil
+}
+
+func isEmptyJSON(raw json.RawMessage) bool {
+	switch strings.TrimSpace(string(raw)) {
+	case "", "null", "{}", "[]", `""`:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,228 @@
+package resumeimport
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+)
+
+// linkedInProfileSections are the CSV files of a LinkedIn data export that
+// describe the profile but have no counterpart in our resume.
+var linkedInProfileSections = map[string]bool{
+	"Honors.csv":                   true,
+	"Projects.csv":                 true,
+	"Courses.csv":                  true,
+	"Publications.csv":             true,
+	"Patents.csv":                  true,
+	"Volunteering.csv":             true,
+	"Recommendations_Received.csv": true,
+	"Organizations.csv":            true,
+	"Test Scores.csv":              true,
+}
+
+// linkedInMappedSections are the CSV files of a LinkedIn data export that
+// the import reads. The rest of the archive, such as messages and
+// connections, is never opened.
+var linkedInMappedSections = map[string]bool{
+	"Profile.csv":        true,
+	"Positions.csv":      true,
+	"Education.csv":      true,
+	"Skills.csv":         true,
+	"Languages.csv":      true,
+	"Certifications.csv": true,
+}
+
+// maxLinkedInFileSize limits a single decompressed CSV file, so that a small
+// upload cannot expand into an arbitrary amount of memory.
+const maxLinkedInFileSize = 1 << 20
+
+// csvTable is a CSV file keyed by its header row.
+type csvTable struct {
+	rows []map[string]string
+}
+
+func readCSV(r io.Reader) (*csvTable, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return &csvTable{}, nil
+	}
+
+	header := records[0]
+	for i := range header {
+		header[i] = strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff"))
+	}
+
+	table := &csvTable{}
+	for _, record := range records[1:] {
+		row := make(map[string]string, len(header))
+		for i, name := range header {
+			if i < len(record) {
+				row[name] = strings.TrimSpace(record[i])
+			}
+		}
+		table.rows = append(table.rows, row)
+	}
+	return table, nil
+}
+
+// readArchiveCSV reads a CSV file of the archive. The size recorded in the
+// archive is checked up front and the actual output is limited as well,
+// since the recorded size may lie.
+func readArchiveCSV(f *zip.File) (*csvTable, error) {
+	if f.UncompressedSize64 > maxLinkedInFileSize {
+		return nil, fmt.Errorf("file is larger than %d bytes", maxLinkedInFileSize)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, maxLinkedInFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxLinkedInFileSize {
+		return nil, fmt.Errorf("file is larger than %d bytes", maxLinkedInFileSize)
+	}
+	return readCSV(bytes.NewReader(data))
+}
+
+// ParseLinkedInArchive maps the "Get a copy of your data" zip archive from LinkedIn.
+func ParseLinkedInArchive(r io.ReaderAt, size int64) (*Result, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDocument, err)
+	}
+
+	tables := make(map[string]*csvTable)
+	for _, f := range archive.File {
+		name := path.Base(f.Name)
+		if f.FileInfo().IsDir() || tables[name] != nil {
+			continue
+		}
+		if !linkedInMappedSections[name] && !linkedInProfileSections[name] {
+			continue
+		}
+		table, err := readArchiveCSV(f)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidDocument, name, err)
+		}
+		tables[name] = table
+	}
+
+	if tables["Profile.csv"] == nil && tables["Positions.csv"] == nil {
+		return nil, fmt.Errorf("%w: Profile.csv or Positions.csv not found in archive", ErrInvalidDocument)
+	}
+
+	result := newResult(SourceLinkedIn)
+	resume := result.Resume
+
+	if profile := tables["Profile.csv"]; profile != nil && len(profile.rows) > 0 {
+		row := profile.rows[0]
+		resume.Title = row["Headline"]
+		resume.Description = row["Summary"]
+		for _, field := range []string{"Address", "Birth Date", "Industry", "Geo Location", "Websites", "Twitter Handles", "Instant Messengers"} {
+			if row[field] != "" {
+				result.unmapped("Profile.csv: " + field)
+			}
+		}
+	}
+
+	if positions := tables["Positions.csv"]; positions != nil {
+		for i, row := range positions.rows {
+			start, ok := parseDate(row["Started On"])
+			if !ok {
+				result.unmapped(fmt.Sprintf("Positions.csv row %d: missing or invalid Started On", i+1))
+				continue
+			}
+			resume.WorkExperience = append(resume.WorkExperience, &entity.WorkExperience{
+				Company:     row["Company Name"],
+				Title:       row["Title"],
+				StartDate:   start,
+				EndDate:     parseOptionalDate(row["Finished On"]),
+				Description: row["Description"],
+			})
+			if row["Location"] != "" {
+				result.unmapped(fmt.Sprintf("Positions.csv row %d: Location", i+1))
+			}
+		}
+	}
+
+	if education := tables["Education.csv"]; education != nil {
+		for i, row := range education.rows {
+			startYear, ok := parseYear(row["Start Date"])
+			if !ok {
+				result.unmapped(fmt.Sprintf("Education.csv row %d: missing or invalid Start Date", i+1))
+				continue
+			}
+			entry := &entity.EducationEntry{
+				Institution: row["School Name"],
+				Degree:      row["Degree Name"],
+				StartYear:   startYear,
+			}
+			if endYear, ok := parseYear(row["End Date"]); ok {
+				entry.EndYear = &endYear
+			}
+			resume.Education = append(resume.Education, entry)
+			if row["Notes"] != "" || row["Activities"] != "" {
+				result.unmapped(fmt.Sprintf("Education.csv row %d: Notes/Activities", i+1))
+			}
+		}
+	}
+
+	if skills := tables["Skills.csv"]; skills != nil {
+		for _, row := range skills.rows {
+			resume.Skills = append(resume.Skills, row["Name"])
+		}
+	}
+
+	if languages := tables["Languages.csv"]; languages != nil {
+		for i, row := range languages.rows {
+			level, ok := languageLevel(row["Proficiency"])
+			if !ok {
+				result.unmapped(fmt.Sprintf("Languages.csv row %d: unknown proficiency %q", i+1, row["Proficiency"]))
+				continue
+			}
+			resume.Languages = append(resume.Languages, &entity.LanguageSkill{Language: row["Name"], Level: level})
+		}
+	}
+
+	if certifications := tables["Certifications.csv"]; certifications != nil {
+		for _, row := range certifications.rows {
+			resume.Certifications = append(resume.Certifications, &entity.Certification{
+				Name:          row["Name"],
+				Issuer:        row["Authority"],
+				IssuedAt:      parseOptionalDate(row["Started On"]),
+				ExpiresAt:     parseOptionalDate(row["Finished On"]),
+				CredentialURL: row["Url"],
+			})
+		}
+	}
+
+	var extra []string
+	for name, table := range tables {
+		if linkedInProfileSections[name] && len(table.rows) > 0 {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+	result.Unmapped = append(result.Unmapped, extra...)
+
+	result.finish()
+	return result, nil
+}
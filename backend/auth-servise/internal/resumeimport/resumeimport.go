@@ -0,0 +1,149 @@
+// Package resumeimport maps third-party resume formats onto entity.Resume.
+package resumeimport
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+)
+
+const (
+	SourceJSONResume = "jsonresume"
+	SourceLinkedIn   = "linkedin"
+)
+
+var ErrInvalidDocument = errors.New("invalid import document")
+
+// Result is the outcome of an import. Resume holds the mapped data with its
+// sections attached but nothing persisted; Unmapped lists the pieces of the
+// source document that have no place in a resume here.
+type Result struct {
+	Source   string         `json:"source"`
+	Resume   *entity.Resume `json:"resume"`
+	Unmapped []string       `json:"unmapped"`
+}
+
+func newResult(source string) *Result {
+	return &Result{
+		Source: source,
+		Resume: &entity.Resume{
+			Status:         "active",
+			Skills:         []string{},
+			WorkExperience: []*entity.WorkExperience{},
+			Education:      []*entity.EducationEntry{},
+			Languages:      []*entity.LanguageSkill{},
+			Certifications: []*entity.Certification{},
+		},
+		Unmapped: []string{},
+	}
+}
+
+func (r *Result) unmapped(item string) {
+	r.Unmapped = append(r.Unmapped, item)
+}
+
+func (r *Result) finish() {
+	r.Resume.Skills = entity.NormalizeSkills(r.Resume.Skills)
+
+	// A resume lists each language once.
+	seen := make(map[string]bool, len(r.Resume.Languages))
+	languages := r.Resume.Languages[:0]
+	for _, l := range r.Resume.Languages {
+		key := strings.ToLower(strings.TrimSpace(l.Language))
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		languages = append(languages, l)
+	}
+	r.Resume.Languages = languages
+
+	r.Resume.TotalExperienceYears = entity.TotalExperienceYears(r.Resume.WorkExperience, time.Now())
+	if r.Resume.Title == "" && len(r.Resume.WorkExperience) > 0 {
+		r.Resume.Title = r.Resume.WorkExperience[0].Title
+	}
+}
+
+var dateLayouts = []string{
+	"2006-01-02",
+	"2006-01",
+	"2006",
+	"Jan 2006",
+	"January 2006",
+	"01/2006",
+	"1/2/2006",
+}
+
+// parseDate accepts the partial dates used by JSON Resume (YYYY, YYYY-MM,
+// YYYY-MM-DD) and by LinkedIn exports (Jan 2020).
+func parseDate(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func parseOptionalDate(value string) *time.Time {
+	t, ok := parseDate(value)
+	if !ok {
+		return nil
+	}
+	return &t
+}
+
+func parseYear(value string) (int, bool) {
+	if t, ok := parseDate(value); ok {
+		return t.Year(), true
+	}
+	if year, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && year > 0 {
+		return year, true
+	}
+	return 0, false
+}
+
+// languageLevel maps the free-text fluency used by JSON Resume and LinkedIn
+// onto the levels accepted by resumes.
+func languageLevel(fluency string) (string, bool) {
+	value := strings.ToLower(strings.TrimSpace(fluency))
+	for _, level := range []string{
+		entity.LanguageLevelA1, entity.LanguageLevelA2, entity.LanguageLevelB1,
+		entity.LanguageLevelB2, entity.LanguageLevelC1, entity.LanguageLevelC2,
+	} {
+		if strings.HasPrefix(value, strings.ToLower(level)) {
+			return level, true
+		}
+	}
+
+	switch {
+	case strings.Contains(value, "native"), strings.Contains(value, "bilingual"), strings.Contains(value, "родной"):
+		return entity.LanguageLevelNative, true
+	case strings.Contains(value, "full professional"), strings.Contains(value, "fluent"), strings.Contains(value, "свободн"):
+		return entity.LanguageLevelC1, true
+	case strings.Contains(value, "professional working"), strings.Contains(value, "advanced"), strings.Contains(value, "upper"):
+		return entity.LanguageLevelB2, true
+	case strings.Contains(value, "limited working"), strings.Contains(value, "intermediate"), strings.Contains(value, "conversational"):
+		return entity.LanguageLevelB1, true
+	case strings.Contains(value, "elementary"), strings.Contains(value, "basic"), strings.Contains(value, "beginner"):
+		return entity.LanguageLevelA2, true
+	}
+	return "", false
+}
+
+func joinLines(parts ...string) string {
+	var lines []string
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			lines = append(lines, p)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
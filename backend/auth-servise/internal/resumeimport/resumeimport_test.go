@@ -0,0 +1,142 @@
+package resumeimport
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleJSONResume = `{
+  "basics": {
+    "name": "John Doe",
+    "label": "Backend Developer",
+    "email": "john@example.com",
+    "summary": "Builds APIs.",
+    "location": {"city": "Berlin"}
+  },
+  "work": [
+    {"name": "Acme", "position": "Developer", "startDate": "2018-03", "endDate": "2021-03-01", "summary": "Payments", "highlights": ["Cut latency"]},
+    {"name": "Broken", "position": "Intern"}
+  ],
+  "education": [
+    {"institution": "MIT", "area": "CS", "studyType": "Bachelor", "startDate": "2014", "endDate": "2018-06-01", "score": "4.0"}
+  ],
+  "skills": [{"name": "Go", "keywords": ["gRPC", "go"]}],
+  "languages": [{"language": "English", "fluency": "Native speaker"}, {"language": "Klingon", "fluency": "Sort of"}],
+  "certificates": [{"name": "CKA", "issuer": "CNCF", "date": "2020-05-01"}],
+  "projects": [{"name": "Side project"}],
+  "interests": []
+}`
+
+func TestParseJSONResume(t *testing.T) {
+	result, err := ParseJSONResume(strings.NewReader(sampleJSONResume))
+	require.NoError(t, err)
+
+	resume := result.Resume
+	assert.Equal(t, SourceJSONResume, result.Source)
+	assert.Equal(t, "Backend Developer", resume.Title)
+	assert.Equal(t, "Builds APIs.", resume.Description)
+	assert.Equal(t, []string{"Go", "gRPC"}, resume.Skills)
+
+	require.Len(t, resume.WorkExperience, 1)
+	assert.Equal(t, "Acme", resume.WorkExperience[0].Company)
+	assert.Equal(t, "Payments\n• Cut latency", resume.WorkExperience[0].Description)
+	assert.Equal(t, 3.0, resume.TotalExperienceYears)
+
+	require.Len(t, resume.Education, 1)
+	assert.Equal(t, 2014, resume.Education[0].StartYear)
+	require.NotNil(t, resume.Education[0].EndYear)
+	assert.Equal(t, 2018, *resume.Education[0].EndYear)
+
+	require.Len(t, resume.Languages, 1)
+	assert.Equal(t, "native", resume.Languages[0].Level)
+	require.Len(t, resume.Certifications, 1)
+	assert.Equal(t, "CNCF", resume.Certifications[0].Issuer)
+
+	assert.Contains(t, result.Unmapped, "projects")
+	assert.NotContains(t, result.Unmapped, "interests")
+	assert.Contains(t, result.Unmapped, "basics.location")
+	assert.Contains(t, result.Unmapped, "education[0].score")
+	assert.Contains(t, result.Unmapped, "work[1]: missing or invalid startDate")
+	assert.Contains(t, result.Unmapped, `languages[1]: unknown fluency "Sort of"`)
+}
+
+func TestParseJSONResumeInvalid(t *testing.T) {
+	_, err := ParseJSONResume(strings.NewReader("[1, 2"))
+	assert.True(t, errors.Is(err, ErrInvalidDocument))
+}
+
+func buildZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestParseLinkedInArchive(t *testing.T) {
+	data := buildZip(t, map[string]string{
+		"Profile.csv": "\ufeffFirst Name,Last Name,Headline,Summary,Websites\n" +
+			"John,Doe,Go Engineer,\"Likes Go, SQL\",https://example.com\n",
+		"Positions.csv": "Company Name,Title,Description,Location,Started On,Finished On\n" +
+			"Acme,Engineer,Did things,,Jan 2019,Jan 2021\n" +
+			"Now Inc,Lead,,Berlin,Feb 2021,\n",
+		"Education.csv":   "School Name,Start Date,End Date,Notes,Degree Name,Activities\nMIT,2012,2016,,BSc,\n",
+		"Skills.csv":      "Name\nGo\nPostgreSQL\n",
+		"Languages.csv":   "Name,Proficiency\nGerman,Limited working proficiency\n",
+		"Honors.csv":      "Title,Description,Issued On\nAward,,2019\n",
+		"Connections.csv": "First Name,Last Name\nJane,Roe\n",
+	})
+
+	result, err := ParseLinkedInArchive(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	resume := result.Resume
+	assert.Equal(t, "Go Engineer", resume.Title)
+	assert.Equal(t, "Likes Go, SQL", resume.Description)
+	assert.Equal(t, []string{"Go", "PostgreSQL"}, resume.Skills)
+	require.Len(t, resume.WorkExperience, 2)
+	assert.Nil(t, resume.WorkExperience[1].EndDate)
+	require.Len(t, resume.Education, 1)
+	assert.Equal(t, "BSc", resume.Education[0].Degree)
+	require.Len(t, resume.Languages, 1)
+	assert.Equal(t, "B1", resume.Languages[0].Level)
+
+	assert.Contains(t, result.Unmapped, "Profile.csv: Websites")
+	assert.Contains(t, result.Unmapped, "Positions.csv row 2: Location")
+	assert.Contains(t, result.Unmapped, "Honors.csv")
+	assert.NotContains(t, result.Unmapped, "Connections.csv")
+}
+
+func TestParseLinkedInArchiveWithoutProfile(t *testing.T) {
+	data := buildZip(t, map[string]string{"Connections.csv": "First Name\nJane\n"})
+	_, err := ParseLinkedInArchive(bytes.NewReader(data), int64(len(data)))
+	assert.True(t, errors.Is(err, ErrInvalidDocument))
+}
+
+func TestParseLinkedInArchiveLimitsFileSize(t *testing.T) {
+	huge := strings.Repeat("x", maxLinkedInFileSize+1)
+
+	// Файлы, которые импорт не читает, могут быть любого размера
+	data := buildZip(t, map[string]string{
+		"Positions.csv": "Company Name,Started On\nAcme,Jan 2019\n",
+		"messages.csv":  "Content\n" + huge,
+	})
+	_, err := ParseLinkedInArchive(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	data = buildZip(t, map[string]string{"Positions.csv": "Company Name\n" + huge})
+	_, err = ParseLinkedInArchive(bytes.NewReader(data), int64(len(data)))
+	assert.True(t, errors.Is(err, ErrInvalidDocument))
+}
@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testBlobStore(t *testing.T, store BlobStore) {
+	ctx := context.Background()
+	content := "%PDF-1.4 test"
+
+	require.NoError(t, store.Put(ctx, "resumes/1/cv file.pdf", strings.NewReader(content), int64(len(content)), "application/pdf"))
+
+	rc, err := store.Get(ctx, "resumes/1/cv file.pdf")
+	require.NoError(t, err)
+	data, err := io.ReadAll(rc)
+	rc.Close()
+	require.NoError(t, err)
+	assert.Equal(t, content, string(data))
+
+	require.NoError(t, store.Delete(ctx, "resumes/1/cv file.pdf"))
+	_, err = store.Get(ctx, "resumes/1/cv file.pdf")
+	assert.ErrorIs(t, err, ErrBlobNotFound)
+
+	// Deleting a missing blob is not an error.
+	assert.NoError(t, store.Delete(ctx, "resumes/1/cv file.pdf"))
+}
+
+func TestLocalStore(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+	testBlobStore(t, store)
+}
+
+func TestLocalStoreRejectsTraversal(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	for _, key := range []string{"../secret", "a/../../b", "/abs", ""} {
+		err := store.Put(context.Background(), key, strings.NewReader("x"), 1, "")
+		assert.Error(t, err, key)
+	}
+}
+
+// fakeS3 is a minimal in-memory S3 that only checks requests are signed.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string]string
+	paths   []string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/20240102/eu-central-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=") ||
+		r.Header.Get("X-Amz-Date") != "20240102T030405Z" {
+		http.Error(w, "bad signature", http.StatusForbidden)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.paths = append(f.paths, r.URL.EscapedPath())
+
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		if sha256Hex(body) != r.Header.Get("X-Amz-Content-Sha256") {
+			http.Error(w, "payload hash mismatch", http.StatusBadRequest)
+			return
+		}
+		f.objects[r.URL.Path] = string(body)
+	case http.MethodGet:
+		body, ok := f.objects[r.URL.Path]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		io.WriteString(w, body)
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3Store(t *testing.T) {
+	fake := &fakeS3{objects: map[string]string{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	store := NewS3Store(S3Config{
+		Endpoint:  server.URL + "/",
+		Region:    "eu-central-1",
+		Bucket:    "cv",
+		AccessKey: "AKID",
+		SecretKey: "secret",
+	}, server.Client())
+	store.now = func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	testBlobStore(t, store)
+	assert.Equal(t, "/cv/resumes/1/cv%20file.pdf", fake.paths[0])
+}
+
+func TestEscapeKey(t *testing.T) {
+	assert.Equal(t, "a/b~c/d%2Be%3D%D1%84", escapeKey("a/b~c/d+e=ф"))
+}
@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/export"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/repository"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/storage"
+)
+
+// exportTimeout bounds the generation of a background export.
+const exportTimeout = 30 * time.Minute
+
+type ApplicationExportUsecaseInterface interface {
+	Export(ctx context.Context, employerID int64, format string, filter entity.ApplicationFilter, open func(fileName string) io.Writer) (*entity.ApplicationExport, error)
+	Get(ctx context.Context, employerID, id int64) (*entity.ApplicationExport, error)
+	Download(ctx context.Context, employerID, id int64) (*entity.ApplicationExport, io.ReadCloser, error)
+}
+
+type ApplicationExportUsecase struct {
+	exportRepo repository.ApplicationExportRepositoryInterface
+	store      storage.BlobStore
+	// threshold is the number of applications above which the export is
+	// generated in the background instead of streamed in the request.
+	threshold int
+	// background runs the generation of large exports.
+	background func(func())
+}
+
+func NewApplicationExportUsecase(
+	exportRepo repository.ApplicationExportRepositoryInterface,
+	store storage.BlobStore,
+	threshold int,
+) *ApplicationExportUsecase {
+	return &ApplicationExportUsecase{
+		exportRepo: exportRepo,
+		store:      store,
+		threshold:  threshold,
+		background: func(f func()) { go f() },
+	}
+}
+
+func exportFileName(format string, now time.Time) string {
+	return fmt.Sprintf("applications-%s.%s", now.Format("20060102-150405"), format)
+}
+
+// Export writes the employer's applications matching the filter as a CSV
+// or XLSX file. Up to the threshold the file is streamed right away to the
+// writer returned by open, and Export returns nil. Larger exports are
+// queued: Export returns the job, and the file is downloaded once it is done.
+// An admin impersonating the employer can only stream small exports.
+func (uc *ApplicationExportUsecase) Export(ctx context.Context, employerID int64, format string, filter entity.ApplicationFilter, open func(fileName string) io.Writer) (*entity.ApplicationExport, error) {
+	if format != export.FormatCSV && format != export.FormatXLSX {
+		return nil, export.ErrUnknownFormat
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	count, err := uc.exportRepo.Count(ctx, employerID, filter)
+	if err != nil {
+		return nil, err
+	}
+	if count <= uc.threshold {
+		_, err := uc.write(ctx, employerID, format, filter, open(exportFileName(format, time.Now())))
+		return nil, err
+	}
+
+	// Фоновая выгрузка остается в истории работодателя
+	if impersonating(ctx) {
+		return nil, ErrReadOnlySession
+	}
+	job := &entity.ApplicationExport{EmployerID: employerID, Format: format, Filter: filter}
+	if err := uc.exportRepo.Create(ctx, job); err != nil {
+		return nil, err
+	}
+	uc.background(func() { uc.generate(job) })
+	return job, nil
+}
+
+// write streams the spreadsheet to w and returns the number of applications.
+func (uc *ApplicationExportUsecase) write(ctx context.Context, employerID int64, format string, filter entity.ApplicationFilter, w io.Writer) (int, error) {
+	table, err := export.NewTableWriter(w, format)
+	if err != nil {
+		return 0, err
+	}
+	if err := table.WriteRow(export.ApplicationHeader); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	err = uc.exportRepo.Stream(ctx, employerID, filter, func(row *entity.ApplicationExportRow) error {
+		count++
+		return table.WriteRow(export.ApplicationRecord(row))
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, table.Close()
+}
+
+// generate writes a queued export to a temporary file and moves it to the
+// blob store. A failure is recorded on the job.
+func (uc *ApplicationExportUsecase) generate(job *entity.ApplicationExport) {
+	ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+	defer cancel()
+
+	if err := uc.exportRepo.MarkRunning(ctx, job.ID); err != nil {
+		fmt.Printf("Failed to start export %d: %v\n", job.ID, err)
+		return
+	}
+	if err := uc.generateFile(ctx, job); err != nil {
+		fmt.Printf("Failed to generate export %d: %v\n", job.ID, err)
+		if err := uc.exportRepo.Fail(ctx, job.ID, err.Error()); err != nil {
+			fmt.Printf("Failed to mark export %d as failed: %v\n", job.ID, err)
+		}
+	}
+}
+
+func (uc *ApplicationExportUsecase) generateFile(ctx context.Context, job *entity.ApplicationExport) error {
+	tmp, err := os.CreateTemp("", "applications-export-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	count, err := uc.write(ctx, job.EmployerID, job.Format, job.Filter, tmp)
+	if err != nil {
+		return err
+	}
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("failed to get export size: %w", err)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind export: %w", err)
+	}
+
+	token, err := newPublicToken()
+	if err != nil {
+		return err
+	}
+	job.RowCount = count
+	job.Size = size
+	job.FileName = exportFileName(job.Format, job.CreatedAt)
+	job.StorageKey = fmt.Sprintf("exports/%d/%s.%s", job.EmployerID, token, job.Format)
+	if err := uc.store.Put(ctx, job.StorageKey, tmp, size, export.TableContentType(job.Format)); err != nil {
+		return err
+	}
+	if err := uc.exportRepo.Finish(ctx, job); err != nil {
+		_ = uc.store.Delete(ctx, job.StorageKey)
+		return err
+	}
+	return nil
+}
+
+// Get returns the employer's export with a download link once it is done.
+func (uc *ApplicationExportUsecase) Get(ctx context.Context, employerID, id int64) (*entity.ApplicationExport, error) {
+	job, err := uc.exportRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil || job.EmployerID != employerID {
+		return nil, entity.ErrExportNotFound
+	}
+	if job.Status == entity.ExportStatusDone {
+		job.DownloadURL = fmt.Sprintf("/api/v1/applications/exports/%d/download", job.ID)
+	}
+	return job, nil
+}
+
+func (uc *ApplicationExportUsecase) Download(ctx context.Context, employerID, id int64) (*entity.ApplicationExport, io.ReadCloser, error) {
+	job, err := uc.Get(ctx, employerID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if job.Status != entity.ExportStatusDone {
+		return nil, nil, entity.ErrExportNotReady
+	}
+
+	body, err := uc.store.Get(ctx, job.StorageKey)
+	if err == storage.ErrBlobNotFound {
+		return nil, nil, entity.ErrExportNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return job, body, nil
+}
@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/export"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockExportRepo struct {
+	mock.Mock
+}
+
+func (m *MockExportRepo) Count(ctx context.Context, employerID int64, filter entity.ApplicationFilter) (int, error) {
+	args := m.Called(ctx, employerID, filter)
+	return args.Int(0), args.Error(1)
+}
+
+// Stream passes the rows the call returns to fn.
+func (m *MockExportRepo) Stream(ctx context.Context, employerID int64, filter entity.ApplicationFilter, fn func(row *entity.ApplicationExportRow) error) error {
+	args := m.Called(ctx, employerID, filter)
+	rows, _ := args.Get(0).([]*entity.ApplicationExportRow)
+	for _, row := range rows {
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockExportRepo) Create(ctx context.Context, job *entity.ApplicationExport) error {
+	args := m.Called(ctx, job)
+	return args.Error(0)
+}
+
+func (m *MockExportRepo) GetByID(ctx context.Context, id int64) (*entity.ApplicationExport, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ApplicationExport), args.Error(1)
+}
+
+func (m *MockExportRepo) MarkRunning(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockExportRepo) Finish(ctx context.Context, job *entity.ApplicationExport) error {
+	args := m.Called(ctx, job)
+	return args.Error(0)
+}
+
+func (m *MockExportRepo) Fail(ctx context.Context, id int64, message string) error {
+	args := m.Called(ctx, id, message)
+	return args.Error(0)
+}
+
+// expectCreate makes the next queued export get the id.
+func (m *MockExportRepo) expectCreate(id int64) {
+	m.On("Create", mock.Anything, mock.AnythingOfType("*entity.ApplicationExport")).Return(nil).Run(func(args mock.Arguments) {
+		job := args.Get(1).(*entity.ApplicationExport)
+		job.ID = id
+		job.Status = entity.ExportStatusPending
+		job.CreatedAt = time.Now()
+	}).Once()
+}
+
+func setupExportTest(t *testing.T, rows int) (*ApplicationExportUsecase, *MockExportRepo) {
+	var exportRows []*entity.ApplicationExportRow
+	for i := 0; i < rows; i++ {
+		exportRows = append(exportRows, &entity.ApplicationExportRow{
+			ApplicationID: int64(i + 1),
+			ApplicantName: "Иван Петров",
+			Status:        entity.ApplicationStatusPending,
+		})
+	}
+	repo := new(MockExportRepo)
+	repo.On("Count", mock.Anything, mock.Anything, mock.Anything).Return(rows, nil).Maybe()
+	repo.On("Stream", mock.Anything, mock.Anything, mock.Anything).Return(exportRows, nil).Maybe()
+	repo.On("MarkRunning", mock.Anything, mock.Anything).Return(nil).Maybe()
+	repo.On("Finish", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(1).(*entity.ApplicationExport).Status = entity.ExportStatusDone
+	}).Maybe()
+	store, err := storage.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	uc := NewApplicationExportUsecase(repo, store, 3)
+	// Фоновая выгрузка выполняется сразу, чтобы проверить ее результат
+	uc.background = func(f func()) { f() }
+	return uc, repo
+}
+
+func TestExportStreamsSmallResult(t *testing.T) {
+	uc, _ := setupExportTest(t, 3)
+
+	var buf bytes.Buffer
+	var fileName string
+	job, err := uc.Export(context.Background(), 1, export.FormatCSV, entity.ApplicationFilter{}, func(name string) io.Writer {
+		fileName = name
+		return &buf
+	})
+	require.NoError(t, err)
+	assert.Nil(t, job)
+	assert.True(t, strings.HasSuffix(fileName, ".csv"))
+	assert.Equal(t, 4, strings.Count(buf.String(), "\n"), "header and three applications")
+}
+
+func TestExportQueuesLargeResult(t *testing.T) {
+	uc, repo := setupExportTest(t, 4)
+	ctx := context.Background()
+	repo.expectCreate(1)
+
+	job, err := uc.Export(ctx, 1, export.FormatCSV, entity.ApplicationFilter{}, func(string) io.Writer {
+		t.Fatal("a large export must not be streamed")
+		return nil
+	})
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	assert.Equal(t, entity.ExportStatusDone, job.Status)
+	repo.On("GetByID", mock.Anything, job.ID).Return(job, nil)
+
+	got, err := uc.Get(ctx, 1, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 4, got.RowCount)
+	assert.NotEmpty(t, got.DownloadURL)
+
+	_, err = uc.Get(ctx, 2, job.ID)
+	assert.ErrorIs(t, err, entity.ErrExportNotFound, "another employer's export")
+
+	_, body, err := uc.Download(ctx, 1, job.ID)
+	require.NoError(t, err)
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	assert.Equal(t, 5, strings.Count(string(data), "\n"))
+}
+
+func TestExportValidation(t *testing.T) {
+	uc, _ := setupExportTest(t, 1)
+	open := func(string) io.Writer { return io.Discard }
+
+	_, err := uc.Export(context.Background(), 1, "pdf", entity.ApplicationFilter{}, open)
+	assert.ErrorIs(t, err, export.ErrUnknownFormat)
+
+	_, err = uc.Export(context.Background(), 1, export.FormatXLSX, entity.ApplicationFilter{Statuses: []string{"lost"}}, open)
+	assert.ErrorIs(t, err, entity.ErrInvalidApplicationFilter)
+}
+
+func TestExportDownloadNotReady(t *testing.T) {
+	uc, repo := setupExportTest(t, 4)
+	uc.background = func(func()) {}
+	repo.expectCreate(1)
+
+	job, err := uc.Export(context.Background(), 1, export.FormatXLSX, entity.ApplicationFilter{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, entity.ExportStatusPending, job.Status)
+	repo.On("GetByID", mock.Anything, job.ID).Return(job, nil)
+
+	_, _, err = uc.Download(context.Background(), 1, job.ID)
+	assert.ErrorIs(t, err, entity.ErrExportNotReady)
+}
@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/repository"
+)
+
+// MaxNoteLength limits the size of a note, in bytes.
+const MaxNoteLength = 10000
+
+type ApplicationReviewUsecaseInterface interface {
+	GetReview(ctx context.Context, userID, applicationID int64) (*entity.ApplicationReview, error)
+	AddNote(ctx context.Context, userID, applicationID int64, body string) (*entity.ApplicationNote, error)
+	UpdateNote(ctx context.Context, userID, applicationID, noteID int64, body string) (*entity.ApplicationNote, error)
+	DeleteNote(ctx context.Context, userID, applicationID, noteID int64) error
+	Rate(ctx context.Context, userID, applicationID int64, rating int) error
+	DeleteRating(ctx context.Context, userID, applicationID int64) error
+	SetTags(ctx context.Context, userID, applicationID int64, tags []string) ([]string, error)
+	ListTags(ctx context.Context, userID int64) ([]string, error)
+}
+
+// ApplicationReviewUsecase manages notes, ratings and tags the employer's
+// staff keep on applications. None of it is visible to the applicant.
+type ApplicationReviewUsecase struct {
+	reviewRepo      repository.ApplicationReviewRepositoryInterface
+	applicationRepo repository.ApplicationRepositoryInterface
+	vacancyRepo     repository.VacancyRepositoryInterface
+	access          *vacancyAccess
+}
+
+func NewApplicationReviewUsecase(
+	reviewRepo repository.ApplicationReviewRepositoryInterface,
+	applicationRepo repository.ApplicationRepositoryInterface,
+	vacancyRepo repository.VacancyRepositoryInterface,
+	orgRepo repository.OrganizationRepositoryInterface,
+) *ApplicationReviewUsecase {
+	return &ApplicationReviewUsecase{
+		reviewRepo:      reviewRepo,
+		applicationRepo: applicationRepo,
+		vacancyRepo:     vacancyRepo,
+		access:          &vacancyAccess{orgRepo: orgRepo},
+	}
+}
+
+// authorize checks that the user sees the vacancy the application was sent
+// to or, with manage, may change its reviews.
+func (uc *ApplicationReviewUsecase) authorize(ctx context.Context, userID, applicationID int64, manage bool) error {
+	application, err := uc.applicationRepo.GetByID(ctx, applicationID)
+	if err != nil {
+		return fmt.Errorf("failed to get application: %w", err)
+	}
+	if application == nil {
+		return entity.ErrApplicationNotFound
+	}
+
+	vacancy, err := uc.vacancyRepo.GetByID(ctx, application.VacancyID)
+	if err != nil {
+		return fmt.Errorf("failed to get vacancy: %w", err)
+	}
+	if vacancy == nil {
+		return entity.ErrVacancyNotFound
+	}
+	role, err := uc.access.role(ctx, userID, vacancy)
+	if err != nil {
+		return err
+	}
+	if role == "" || (manage && !entity.CanManageVacancies(role)) {
+		return ErrPermissionDenied
+	}
+	return nil
+}
+
+func normalizeNote(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" || len(body) > MaxNoteLength {
+		return "", fmt.Errorf("%w: note must be 1 to %d bytes long", entity.ErrInvalidNote, MaxNoteLength)
+	}
+	return body, nil
+}
+
+func (uc *ApplicationReviewUsecase) GetReview(ctx context.Context, userID, applicationID int64) (*entity.ApplicationReview, error) {
+	if err := uc.authorize(ctx, userID, applicationID, false); err != nil {
+		return nil, err
+	}
+
+	review := &entity.ApplicationReview{ApplicationID: applicationID}
+	var err error
+	if review.Notes, err = uc.reviewRepo.GetNotes(ctx, applicationID); err != nil {
+		return nil, err
+	}
+	if review.Ratings, err = uc.reviewRepo.GetRatings(ctx, applicationID); err != nil {
+		return nil, err
+	}
+
+	summaries, err := uc.reviewRepo.GetSummaries(ctx, []int64{applicationID})
+	if err != nil {
+		return nil, err
+	}
+	review.Tags = []string{}
+	if summary, ok := summaries[applicationID]; ok {
+		review.ApplicationReviewSummary = *summary
+	}
+	return review, nil
+}
+
+func (uc *ApplicationReviewUsecase) AddNote(ctx context.Context, userID, applicationID int64, body string) (*entity.ApplicationNote, error) {
+	if err := uc.authorize(ctx, userID, applicationID, true); err != nil {
+		return nil, err
+	}
+	body, err := normalizeNote(body)
+	if err != nil {
+		return nil, err
+	}
+
+	note := &entity.ApplicationNote{ApplicationID: applicationID, AuthorID: userID, Body: body}
+	if err := uc.reviewRepo.AddNote(ctx, note); err != nil {
+		return nil, err
+	}
+	return note, nil
+}
+
+// UpdateNote edits a note; only its author may do so.
+func (uc *ApplicationReviewUsecase) UpdateNote(ctx context.Context, userID, applicationID, noteID int64, body string) (*entity.ApplicationNote, error) {
+	if err := uc.authorize(ctx, userID, applicationID, true); err != nil {
+		return nil, err
+	}
+	body, err := normalizeNote(body)
+	if err != nil {
+		return nil, err
+	}
+
+	note := &entity.ApplicationNote{ID: noteID, ApplicationID: applicationID, AuthorID: userID, Body: body}
+	if err := uc.reviewRepo.UpdateNote(ctx, note); err != nil {
+		return nil, err
+	}
+	return note, nil
+}
+
+func (uc *ApplicationReviewUsecase) DeleteNote(ctx context.Context, userID, applicationID, noteID int64) error {
+	if err := uc.authorize(ctx, userID, applicationID, true); err != nil {
+		return err
+	}
+	return uc.reviewRepo.DeleteNote(ctx, applicationID, noteID, userID)
+}
+
+// Rate sets the user's own rating of the applicant.
+func (uc *ApplicationReviewUsecase) Rate(ctx context.Context, userID, applicationID int64, rating int) error {
+	if err := entity.ValidateRating(rating); err != nil {
+		return err
+	}
+	if err := uc.authorize(ctx, userID, applicationID, true); err != nil {
+		return err
+	}
+	return uc.reviewRepo.SetRating(ctx, applicationID, userID, rating)
+}
+
+func (uc *ApplicationReviewUsecase) DeleteRating(ctx context.Context, userID, applicationID int64) error {
+	if err := uc.authorize(ctx, userID, applicationID, true); err != nil {
+		return err
+	}
+	return uc.reviewRepo.DeleteRating(ctx, applicationID, userID)
+}
+
+// SetTags replaces the application tags and returns them normalized.
+func (uc *ApplicationReviewUsecase) SetTags(ctx context.Context, userID, applicationID int64, tags []string) ([]string, error) {
+	tags, err := entity.NormalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.authorize(ctx, userID, applicationID, true); err != nil {
+		return nil, err
+	}
+	if err := uc.reviewRepo.SetTags(ctx, applicationID, tags, userID); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// ListTags returns the tags the employer has used, for autocompletion.
+func (uc *ApplicationReviewUsecase) ListTags(ctx context.Context, userID int64) ([]string, error) {
+	return uc.reviewRepo.GetEmployerTags(ctx, userID)
+}
@@ -2,18 +2,32 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
 	"github.com/Mandarinka0707/newRepoGOODarhit/internal/repository"
 )
 
+var (
+	ErrInvalidStatusTransition = errors.New("status transition is not allowed")
+	// ErrApplicationClosed is returned when moving a hired, rejected or
+	// withdrawn application between pipeline stages.
+	ErrApplicationClosed = errors.New("application is closed")
+)
+
 type ApplicationUsecaseInterface interface {
 	Create(ctx context.Context, application *entity.Application) error
-	GetByID(ctx context.Context, id int64) (*entity.Application, error)
+	GetByID(ctx context.Context, id int64, viewerID int64) (*entity.Application, error)
+	MarkViewed(ctx context.Context, id int64, userID int64) (*entity.Application, error)
 	GetAll(ctx context.Context, userID int64) ([]*entity.Application, error)
-	GetByEmployerID(ctx context.Context, employerID int64, vacancyIDs []int64) ([]*entity.Application, error)
-	UpdateStatus(ctx context.Context, id int64, userID int64, status string) error
+	GetByEmployerID(ctx context.Context, employerID int64, filter entity.ApplicationFilter, limit, offset int) ([]*entity.Application, int, error)
+	UpdateStatus(ctx context.Context, id int64, userID int64, status, comment string) error
+	Withdraw(ctx context.Context, id int64, userID int64, reason string) error
+	GetPipelineBoard(ctx context.Context, employerID, vacancyID int64) (*entity.PipelineBoard, error)
+	MoveToStage(ctx context.Context, id int64, employerID, stageID int64) error
+	BulkMoveToStage(ctx context.Context, employerID, vacancyID int64, applicationIDs []int64, stageID int64) (int64, error)
 }
 
 type ApplicationUsecase struct {
@@ -21,6 +35,11 @@ type ApplicationUsecase struct {
 	userRepo        repository.UserRepositoryInterface
 	vacancyRepo     repository.VacancyRepositoryInterface
 	resumeRepo      repository.ResumeRepositoryInterface
+	pipelineRepo    repository.PipelineRepositoryInterface
+	reviewRepo      repository.ApplicationReviewRepositoryInterface
+	screeningRepo   repository.ScreeningRepositoryInterface
+	notifications   Notifier
+	access          *vacancyAccess
 }
 
 func NewApplicationUsecase(
@@ -28,12 +47,22 @@ func NewApplicationUsecase(
 	userRepo repository.UserRepositoryInterface,
 	vacancyRepo repository.VacancyRepositoryInterface,
 	resumeRepo repository.ResumeRepositoryInterface,
+	pipelineRepo repository.PipelineRepositoryInterface,
+	reviewRepo repository.ApplicationReviewRepositoryInterface,
+	screeningRepo repository.ScreeningRepositoryInterface,
+	orgRepo repository.OrganizationRepositoryInterface,
+	notifications Notifier,
 ) *ApplicationUsecase {
 	return &ApplicationUsecase{
 		applicationRepo: applicationRepo,
 		userRepo:        userRepo,
 		vacancyRepo:     vacancyRepo,
 		resumeRepo:      resumeRepo,
+		pipelineRepo:    pipelineRepo,
+		reviewRepo:      reviewRepo,
+		screeningRepo:   screeningRepo,
+		notifications:   notifications,
+		access:          &vacancyAccess{orgRepo: orgRepo},
 	}
 }
 
@@ -81,21 +110,39 @@ func (uc *ApplicationUsecase) Create(ctx context.Context, application *entity.Ap
 		return fmt.Errorf("resume does not belong to user")
 	}
 
-	// Проверяем, что пользователь еще не откликался на эту вакансию
-	existingApplications, err := uc.applicationRepo.GetAll(ctx, application.UserID)
+	// Проверяем, что пользователь еще не откликался на эту вакансию. Гонку
+	// параллельных откликов закрывает уникальный индекс в базе.
+	existingApplications, err := uc.applicationRepo.GetByUserAndVacancy(ctx, application.UserID, application.VacancyID)
 	if err != nil {
 		fmt.Printf("Error getting existing applications: %v\n", err)
 		return fmt.Errorf("failed to get existing applications: %w", err)
 	}
 
-	for _, existingApp := range existingApplications {
-		if existingApp.VacancyID == application.VacancyID {
-			fmt.Printf("User already has an application for this vacancy: %+v\n", existingApp)
-			return fmt.Errorf("user already applied for this vacancy")
-		}
+	// Повторный отклик возможен только после отзыва и если вакансия это разрешает
+	if err := entity.CheckReapply(vacancy, existingApplications, time.Now()); err != nil {
+		fmt.Printf("User cannot apply for vacancy %d: %v\n", application.VacancyID, err)
+		return err
 	}
 
-	fmt.Printf("No existing application found, creating new one\n")
+	coverLetter, err := entity.NormalizeCoverLetter(application.CoverLetter)
+	if err != nil {
+		return err
+	}
+	application.CoverLetter = coverLetter
+
+	// Проверяем ответы на отсеивающие вопросы вакансии
+	questions, err := uc.screeningRepo.GetQuestions(ctx, application.VacancyID)
+	if err != nil {
+		return err
+	}
+	screening, err := entity.EvaluateScreening(questions, application.Answers)
+	if err != nil {
+		return err
+	}
+
+	application.Status = entity.ApplicationStatusPending
+	application.Answers = screening.Answers
+	application.Flagged = screening.Flagged
 
 	// Создаем отклик
 	if err := uc.applicationRepo.Create(ctx, application); err != nil {
@@ -103,114 +150,352 @@ func (uc *ApplicationUsecase) Create(ctx context.Context, application *entity.Ap
 		return fmt.Errorf("failed to create application: %w", err)
 	}
 
+	if screening.Rejected {
+		err := uc.applicationRepo.ChangeStatus(ctx, application.ID, entity.ApplicationStatusPending,
+			entity.ApplicationStatusRejected, 0, "Не пройдены отсеивающие вопросы")
+		if err != nil {
+			return fmt.Errorf("failed to reject application: %w", err)
+		}
+		application.Status = entity.ApplicationStatusRejected
+	}
+	hideScreeningResult(application)
+
 	fmt.Printf("Application created successfully: %+v\n", application)
 	return nil
 }
 
-func (uc *ApplicationUsecase) GetByID(ctx context.Context, id int64) (*entity.Application, error) {
-	return uc.applicationRepo.GetByID(ctx, id)
+// GetByID returns the application with its status timeline to the applicant,
+// the vacancy owner, members of the vacancy's organization or an admin.
+func (uc *ApplicationUsecase) GetByID(ctx context.Context, id int64, viewerID int64) (*entity.Application, error) {
+	application, vacancy, viewer, err := uc.loadForActor(ctx, id, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	role, err := uc.access.role(ctx, viewerID, vacancy)
+	if err != nil {
+		return nil, err
+	}
+	if viewer.Role != string(entity.RoleAdmin) && application.UserID != viewerID && role == "" {
+		return nil, ErrPermissionDenied
+	}
+
+	if application.Timeline, err = uc.applicationRepo.GetStatusHistory(ctx, id); err != nil {
+		return nil, err
+	}
+
+	answers, err := uc.screeningRepo.GetAnswers(ctx, []int64{id})
+	if err != nil {
+		return nil, err
+	}
+	application.Answers = answers[id]
+	if viewer.Role != string(entity.RoleAdmin) && role == "" {
+		hideScreeningResult(application)
+	}
+	return application, nil
+}
+
+// MarkViewed marks a pending application as viewed, which the applicant sees
+// in the timeline and the employer's webhooks receive. Only someone who
+// manages the vacancy may do it; applications past pending are left as they
+// are, so the client can call it every time the application is opened. An
+// admin impersonating the employer may not.
+func (uc *ApplicationUsecase) MarkViewed(ctx context.Context, id int64, userID int64) (*entity.Application, error) {
+	if impersonating(ctx) {
+		return nil, ErrReadOnlySession
+	}
+	application, vacancy, _, err := uc.loadForActor(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	allowed, err := uc.access.canManage(ctx, userID, vacancy)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, ErrPermissionDenied
+	}
+	if application.Status != entity.ApplicationStatusPending {
+		return application, nil
+	}
+
+	err = uc.applicationRepo.ChangeStatus(ctx, id, entity.ApplicationStatusPending, entity.ApplicationStatusViewed, userID, "")
+	switch {
+	case err == nil:
+		application.Status = entity.ApplicationStatusViewed
+		return application, nil
+	case errors.Is(err, entity.ErrApplicationStatusConflict):
+		// Статус уже изменили параллельно — возвращаем актуальный
+		return uc.applicationRepo.GetByID(ctx, id)
+	default:
+		return nil, err
+	}
+}
+
+// hideScreeningResult removes what the applicant should not learn: which
+// answers failed the knock-out rules and whether the application was flagged.
+func hideScreeningResult(application *entity.Application) {
+	application.Flagged = false
+	for _, answer := range application.Answers {
+		answer.Passed = nil
+	}
+}
+
+// loadForActor loads the application, its vacancy and the acting user.
+func (uc *ApplicationUsecase) loadForActor(ctx context.Context, id, userID int64) (*entity.Application, *entity.Vacancy, *entity.User, error) {
+	application, err := uc.applicationRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get application: %w", err)
+	}
+	if application == nil {
+		return nil, nil, nil, entity.ErrApplicationNotFound
+	}
+
+	vacancy, err := uc.vacancyRepo.GetByID(ctx, application.VacancyID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get vacancy: %w", err)
+	}
+	if vacancy == nil {
+		return nil, nil, nil, entity.ErrVacancyNotFound
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, nil, nil, ErrUserNotFound
+	}
+
+	return application, vacancy, user, nil
 }
 
 func (uc *ApplicationUsecase) GetAll(ctx context.Context, userID int64) ([]*entity.Application, error) {
-	return uc.applicationRepo.GetAll(ctx, userID)
+	applications, err := uc.applicationRepo.GetAll(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, application := range applications {
+		hideScreeningResult(application)
+	}
+	return applications, nil
 }
 
-func (uc *ApplicationUsecase) GetByEmployerID(ctx context.Context, employerID int64, vacancyIDs []int64) ([]*entity.Application, error) {
-	fmt.Printf("GetByEmployerID: Starting with employerID=%d, vacancyIDs=%v\n", employerID, vacancyIDs)
+// GetByEmployerID lists a page of applications to the employer's vacancies
+// with the applicant, the resume, the review summary and the screening
+// answers. The number of queries does not depend on the page size.
+func (uc *ApplicationUsecase) GetByEmployerID(ctx context.Context, employerID int64, filter entity.ApplicationFilter, limit, offset int) ([]*entity.Application, int, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, 0, err
+	}
 
-	// Получаем все вакансии работодателя
-	vacancies, err := uc.vacancyRepo.GetByEmployerID(ctx, employerID)
+	applications, total, err := uc.applicationRepo.ListByEmployer(ctx, employerID, filter, limit, offset)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get employer vacancies: %w", err)
+		return nil, 0, err
 	}
-	fmt.Printf("GetByEmployerID: Found %d vacancies for employer\n", len(vacancies))
 
-	// Если указаны конкретные vacancy_ids, фильтруем вакансии
-	if len(vacancyIDs) > 0 {
-		filteredVacancies := make([]*entity.Vacancy, 0)
-		for _, v := range vacancies {
-			for _, id := range vacancyIDs {
-				if v.ID == id {
-					filteredVacancies = append(filteredVacancies, v)
-					break
-				}
-			}
-		}
-		vacancies = filteredVacancies
-		fmt.Printf("GetByEmployerID: Filtered to %d vacancies\n", len(vacancies))
+	ids := make([]int64, len(applications))
+	for i, application := range applications {
+		ids[i] = application.ID
 	}
 
-	// Получаем все отклики для этих вакансий
-	var allApplications []*entity.Application
-	for _, vacancy := range vacancies {
-		fmt.Printf("GetByEmployerID: Getting applications for vacancy ID=%d\n", vacancy.ID)
-		applications, err := uc.applicationRepo.GetByVacancyID(ctx, vacancy.ID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get applications for vacancy %d: %w", vacancy.ID, err)
-		}
-		fmt.Printf("GetByEmployerID: Found %d applications for vacancy ID=%d\n", len(applications), vacancy.ID)
-		allApplications = append(allApplications, applications...)
+	// Заметки, оценки и теги видит только работодатель
+	summaries, err := uc.reviewRepo.GetSummaries(ctx, ids)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get application reviews: %w", err)
+	}
+	// Ответы на отсеивающие вопросы показываем рядом с резюме
+	answers, err := uc.screeningRepo.GetAnswers(ctx, ids)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get screening answers: %w", err)
 	}
-	fmt.Printf("GetByEmployerID: Total applications found: %d\n", len(allApplications))
 
-	// Получаем информацию о пользователях для каждого отклика
-	for _, application := range allApplications {
-		user, err := uc.userRepo.GetByID(ctx, application.UserID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get user info for application %d: %w", application.ID, err)
-		}
-		if user != nil {
-			application.ApplicantName = user.Name
-			application.ApplicantEmail = user.Email
+	for _, application := range applications {
+		application.Review = summaries[application.ID]
+		if application.Review == nil {
+			application.Review = &entity.ApplicationReviewSummary{Tags: []string{}}
 		}
+		application.Answers = answers[application.ID]
+	}
+	return applications, total, nil
+}
+
+// UpdateStatus moves the application through the hiring flow. The vacancy
+// owner drives it forward, the applicant may only withdraw; see
+// entity.CanTransitionApplication.
+func (uc *ApplicationUsecase) UpdateStatus(ctx context.Context, id int64, userID int64, status, comment string) error {
+	application, vacancy, user, err := uc.loadForActor(ctx, id, userID)
+	if err != nil {
+		return err
+	}
 
-		// Получаем информацию о резюме
-		resume, err := uc.resumeRepo.GetResumeByID(ctx, application.ResumeID)
+	role := entity.UserRole(user.Role)
+	switch role {
+	case entity.RoleEmployer:
+		allowed, err := uc.access.canManage(ctx, userID, vacancy)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get resume info for application %d: %w", application.ID, err)
+			return err
 		}
-		if resume != nil {
-			application.Resume = resume
+		if !allowed {
+			return ErrPermissionDenied
 		}
+	case entity.RoleJobseeker:
+		if application.UserID != userID {
+			return ErrPermissionDenied
+		}
+	case entity.RoleAdmin:
+	default:
+		return ErrPermissionDenied
+	}
+
+	if !entity.CanTransitionApplication(role, application.Status, status) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidStatusTransition, application.Status, status)
 	}
 
-	return allApplications, nil
+	if err := uc.applicationRepo.ChangeStatus(ctx, id, application.Status, status, userID, comment); err != nil {
+		return err
+	}
+	if status == entity.ApplicationStatusWithdrawn {
+		uc.notifyWithdrawal(ctx, application, vacancy, comment)
+	} else {
+		uc.notifications.Publish(ctx, &entity.Notification{
+			UserID: application.UserID,
+			Type:   entity.NotificationApplicationStatus,
+			Title:  "Отклик на вакансию «" + vacancy.Title + "»",
+			Body:   entity.StatusChangeMessage(status, comment),
+			Link:   fmt.Sprintf("/applications/%d", application.ID),
+		})
+	}
+	return nil
 }
 
-func (uc *ApplicationUsecase) UpdateStatus(ctx context.Context, id int64, userID int64, status string) error {
+// Withdraw lets the applicant take back an application that is still
+// pending. The employer is notified.
+func (uc *ApplicationUsecase) Withdraw(ctx context.Context, id int64, userID int64, reason string) error {
 	application, err := uc.applicationRepo.GetByID(ctx, id)
 	if err != nil {
 		return fmt.Errorf("failed to get application: %w", err)
 	}
 	if application == nil {
-		return fmt.Errorf("application not found")
+		return entity.ErrApplicationNotFound
+	}
+	if application.UserID != userID {
+		return ErrPermissionDenied
+	}
+	if application.Status != entity.ApplicationStatusPending {
+		return fmt.Errorf("%w: only pending applications can be withdrawn", ErrInvalidStatusTransition)
 	}
 
-	// Проверяем, что пользователь имеет право обновлять статус
-	// (либо это работодатель, создавший вакансию, либо соискатель, создавший отклик)
-	vacancy, err := uc.vacancyRepo.GetByID(ctx, application.VacancyID)
+	return uc.UpdateStatus(ctx, id, userID, entity.ApplicationStatusWithdrawn, reason)
+}
+
+// notifyWithdrawal tells the vacancy owner that the applicant withdrew.
+func (uc *ApplicationUsecase) notifyWithdrawal(ctx context.Context, application *entity.Application, vacancy *entity.Vacancy, reason string) {
+	applicant, err := uc.userRepo.GetByID(ctx, application.UserID)
+	if err != nil || applicant == nil {
+		fmt.Printf("Failed to get applicant %d to notify about withdrawal: %v\n", application.UserID, err)
+		return
+	}
+
+	body := fmt.Sprintf("%s отозвал(а) отклик на вакансию «%s».", applicant.Name, vacancy.Title)
+	if reason != "" {
+		body += "\n\nПричина: " + reason
+	}
+	uc.notifications.Publish(ctx, &entity.Notification{
+		UserID: vacancy.EmployerID,
+		Type:   entity.NotificationApplicationWithdrawn,
+		Title:  "Отклик отозван: " + vacancy.Title,
+		Body:   body,
+		Link:   fmt.Sprintf("/applications/%d", application.ID),
+	})
+}
+
+// vacancyPipeline returns the vacancy and the pipeline assigned to it,
+// checking that the employer may see the vacancy or, with manage, change its
+// applications.
+func (uc *ApplicationUsecase) vacancyPipeline(ctx context.Context, employerID, vacancyID int64, manage bool) (*entity.Vacancy, *entity.Pipeline, error) {
+	vacancy, err := uc.vacancyRepo.GetByID(ctx, vacancyID)
 	if err != nil {
-		return fmt.Errorf("failed to get vacancy: %w", err)
+		return nil, nil, fmt.Errorf("failed to get vacancy: %w", err)
 	}
 	if vacancy == nil {
-		return fmt.Errorf("vacancy not found")
+		return nil, nil, entity.ErrVacancyNotFound
+	}
+	role, err := uc.access.role(ctx, employerID, vacancy)
+	if err != nil {
+		return nil, nil, err
+	}
+	if role == "" || (manage && !entity.CanManageVacancies(role)) {
+		return nil, nil, ErrPermissionDenied
 	}
 
-	user, err := uc.userRepo.GetByID(ctx, userID)
+	pipelineID, err := uc.pipelineRepo.GetVacancyPipelineID(ctx, vacancyID)
 	if err != nil {
-		return fmt.Errorf("failed to get user: %w", err)
+		return nil, nil, err
 	}
-	if user == nil {
-		return fmt.Errorf("user not found")
+	if pipelineID == nil {
+		return nil, nil, entity.ErrPipelineNotFound
+	}
+	pipeline, err := uc.pipelineRepo.GetByID(ctx, *pipelineID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if pipeline == nil {
+		return nil, nil, entity.ErrPipelineNotFound
+	}
+	return vacancy, pipeline, nil
+}
+
+// GetPipelineBoard returns the applications of the vacancy grouped by the
+// stages of its pipeline.
+func (uc *ApplicationUsecase) GetPipelineBoard(ctx context.Context, employerID, vacancyID int64) (*entity.PipelineBoard, error) {
+	_, pipeline, err := uc.vacancyPipeline(ctx, employerID, vacancyID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	applications, err := uc.applicationRepo.GetByVacancyID(ctx, vacancyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return entity.NewPipelineBoard(vacancyID, pipeline, applications), nil
+}
+
+// MoveToStage puts an open application on another stage of its vacancy's
+// pipeline.
+func (uc *ApplicationUsecase) MoveToStage(ctx context.Context, id int64, employerID, stageID int64) error {
+	application, err := uc.applicationRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get application: %w", err)
+	}
+	if application == nil {
+		return entity.ErrApplicationNotFound
 	}
 
-	if user.Role == "employer" && vacancy.EmployerID != userID {
-		return fmt.Errorf("permission denied: not the vacancy owner")
+	moved, err := uc.BulkMoveToStage(ctx, employerID, application.VacancyID, []int64{id}, stageID)
+	if err != nil {
+		return err
+	}
+	if moved == 0 {
+		return ErrApplicationClosed
+	}
+	return nil
+}
+
+// BulkMoveToStage puts the given applications of the vacancy on the stage and
+// returns how many were moved. Closed applications and applications to other
+// vacancies are skipped.
+func (uc *ApplicationUsecase) BulkMoveToStage(ctx context.Context, employerID, vacancyID int64, applicationIDs []int64, stageID int64) (int64, error) {
+	_, pipeline, err := uc.vacancyPipeline(ctx, employerID, vacancyID, true)
+	if err != nil {
+		return 0, err
+	}
+	if pipeline.Stage(stageID) == nil {
+		return 0, entity.ErrPipelineStageNotFound
 	}
-	if user.Role == "jobseeker" && application.UserID != userID {
-		return fmt.Errorf("permission denied: not the application owner")
+	if len(applicationIDs) == 0 {
+		return 0, nil
 	}
 
-	application.Status = status
-	return uc.applicationRepo.Update(ctx, application)
+	return uc.pipelineRepo.MoveApplications(ctx, vacancyID, applicationIDs, stageID)
 }
@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/repository"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The listing runs on the real repositories over go-sqlmock. Every statement
+// they send has to match the next expectation, so the counter below is the
+// number of statements GetByEmployerID actually executes: an extra query
+// fails the call instead of going unnoticed.
+
+type statementCounter struct {
+	statements int
+}
+
+func employerListingFixture(tb testing.TB) (*ApplicationUsecase, sqlmock.Sqlmock, *statementCounter) {
+	counter := &statementCounter{}
+	matcher := sqlmock.QueryMatcherFunc(func(expected, actual string) error {
+		if err := sqlmock.QueryMatcherRegexp.Match(expected, actual); err != nil {
+			return err
+		}
+		counter.statements++
+		return nil
+	})
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(matcher))
+	require.NoError(tb, err)
+	tb.Cleanup(func() { db.Close() })
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	uc := NewApplicationUsecase(
+		repository.NewApplicationRepository(sqlxDB),
+		nil,
+		nil,
+		nil,
+		nil,
+		repository.NewApplicationReviewRepository(sqlxDB),
+		repository.NewScreeningRepository(sqlxDB),
+		nil,
+		nil,
+	)
+	return uc, mock, counter
+}
+
+var employerListingColumns = []string{
+	"id", "user_id", "vacancy_id", "resume_id", "status", "stage_id", "screening_flagged",
+	"cover_letter", "created_at", "updated_at",
+	"name", "email",
+	"id", "user_id", "title", "description", "skills", "location",
+	"experience_years", "legacy_experience", "legacy_education",
+	"status", "visibility", "created_at", "updated_at",
+	"total",
+}
+
+// expectEmployerListing queues the statements of one listing that returns
+// a page of the given size out of total applications.
+func expectEmployerListing(mock sqlmock.Sqlmock, page, total int) {
+	now := time.Now()
+	rows := sqlmock.NewRows(employerListingColumns)
+	for i := 1; i <= page; i++ {
+		rows.AddRow(
+			i, 100+i, i%10+1, 1000+i, entity.ApplicationStatusPending, nil, false,
+			"", now, now,
+			"Applicant", "applicant@example.com",
+			1000+i, 100+i, "Go developer", "", "{Go}", "Remote",
+			3.5, "", "",
+			"active", entity.VisibilityEmployers, now, now,
+			total,
+		)
+	}
+	mock.ExpectQuery(`FROM applications a`).WillReturnRows(rows)
+	if page == 0 {
+		return
+	}
+
+	mock.ExpectQuery(`FROM application_tags`).
+		WillReturnRows(sqlmock.NewRows([]string{"application_id", "tags"}))
+	mock.ExpectQuery(`FROM application_ratings`).
+		WillReturnRows(sqlmock.NewRows([]string{"application_id", "avg", "count"}))
+	mock.ExpectQuery(`FROM application_screening_answers`).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"application_id", "question_id", "question_text", "question_type",
+			"bool_value", "number_value", "text_value", "options", "passed",
+		}))
+}
+
+func BenchmarkGetByEmployerID(b *testing.B) {
+	const pageSize = 100
+	for _, size := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("applications=%d", size), func(b *testing.B) {
+			uc, mock, counter := employerListingFixture(b)
+			ctx := context.Background()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				expectEmployerListing(mock, min(size, pageSize), size)
+				b.StartTimer()
+				if _, _, err := uc.GetByEmployerID(ctx, 1, entity.ApplicationFilter{}, pageSize, 0); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(counter.statements)/float64(b.N), "queries/op")
+		})
+	}
+}
+
+func TestGetByEmployerIDQueryCount(t *testing.T) {
+	for _, size := range []int{0, 10, 1000} {
+		uc, mock, counter := employerListingFixture(t)
+		expectEmployerListing(mock, min(size, 100), size)
+
+		applications, total, err := uc.GetByEmployerID(context.Background(), 1, entity.ApplicationFilter{}, 100, 0)
+		require.NoError(t, err)
+		assert.Equal(t, size, total)
+		assert.Len(t, applications, min(size, 100))
+		assert.NoError(t, mock.ExpectationsWereMet())
+
+		// Список, теги, оценки и ответы — независимо от числа откликов
+		want := 4
+		if size == 0 {
+			want = 1
+		}
+		assert.Equal(t, want, counter.statements, "applications=%d", size)
+	}
+}
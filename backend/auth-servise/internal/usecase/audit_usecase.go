@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/repository"
+)
+
+// Auditor records admin and security-relevant actions. No such action may
+// take place unrecorded: callers record the entry before they apply the
+// change or hand out a token, and give up if Record fails. An entry whose
+// change fails afterwards stays in the log, which errs on the side of
+// showing too much.
+type Auditor interface {
+	Record(ctx context.Context, entry *entity.AuditEntry) error
+}
+
+// NopAuditor drops every entry.
+type NopAuditor struct{}
+
+func (NopAuditor) Record(context.Context, *entity.AuditEntry) error { return nil }
+
+type clientKey struct{}
+
+type client struct {
+	ip        string
+	userAgent string
+}
+
+// WithClient returns a copy of ctx carrying the IP and user agent of the
+// request, which Auditor adds to the entries recorded with it.
+func WithClient(ctx context.Context, ip, userAgent string) context.Context {
+	return context.WithValue(ctx, clientKey{}, client{ip: ip, userAgent: userAgent})
+}
+
+// auditEntry builds an entry about a target, with snapshots of it before and
+// after the action. A zero actorID or targetID is left out.
+func auditEntry(actorID int64, action, targetType string, targetID int64, before, after any) *entity.AuditEntry {
+	entry := &entity.AuditEntry{
+		Action:     action,
+		TargetType: targetType,
+		Before:     entity.AuditSnapshot(before),
+		After:      entity.AuditSnapshot(after),
+	}
+	if actorID != 0 {
+		entry.ActorID = &actorID
+	}
+	if targetID != 0 {
+		entry.TargetID = &targetID
+	}
+	return entry
+}
+
+type AuditUsecaseInterface interface {
+	List(ctx context.Context, adminID int64, filter entity.AuditFilter, limit, offset int) ([]*entity.AuditEntry, int, error)
+	Verify(ctx context.Context, adminID int64) (*entity.AuditVerification, error)
+}
+
+// AuditUsecase keeps the audit log as a chain of entries, each hashed
+// together with the one before it under a key held outside the database.
+type AuditUsecase struct {
+	auditRepo repository.AuditRepositoryInterface
+	userRepo  repository.UserRepositoryInterface
+	key       []byte
+	now       func() time.Time
+
+	// Записи, пришедшие, пока пишется предыдущая пачка, ждут следующей:
+	// так каждый вход не берет блокировку журнала отдельно
+	mu       sync.Mutex
+	pending  []*auditRequest
+	flushing bool
+}
+
+// auditRequest is an entry waiting for its batch to be appended.
+type auditRequest struct {
+	entry *entity.AuditEntry
+	lead  chan struct{}
+	done  chan error
+}
+
+func NewAuditUsecase(auditRepo repository.AuditRepositoryInterface, userRepo repository.UserRepositoryInterface, key []byte) *AuditUsecase {
+	return &AuditUsecase{
+		auditRepo: auditRepo,
+		userRepo:  userRepo,
+		key:       key,
+		now:       time.Now,
+	}
+}
+
+// auditVerifyBatch is how many entries Verify reads at a time.
+const auditVerifyBatch = 500
+
+// Record appends the entry to the log and returns once it is stored.
+// Entries recorded while another batch is being written, such as a burst of
+// logins, are appended together in the next one.
+func (uc *AuditUsecase) Record(ctx context.Context, entry *entity.AuditEntry) error {
+	if c, ok := ctx.Value(clientKey{}).(client); ok {
+		entry.IP = c.ip
+		entry.UserAgent = c.userAgent
+	}
+	// Postgres хранит микросекунды, а время входит в хеш
+	entry.CreatedAt = uc.now().UTC().Truncate(time.Microsecond)
+
+	req := &auditRequest{entry: entry, lead: make(chan struct{}, 1), done: make(chan error, 1)}
+	uc.mu.Lock()
+	uc.pending = append(uc.pending, req)
+	if !uc.flushing {
+		uc.flushing = true
+		req.lead <- struct{}{}
+	}
+	uc.mu.Unlock()
+
+	select {
+	case err := <-req.done:
+		return err
+	case <-req.lead:
+		// Пачку пишут и за тех, кто в ней ждет, поэтому отмена запроса ее не прерывает
+		uc.appendPending(context.WithoutCancel(ctx))
+		return <-req.done
+	}
+}
+
+// appendPending appends the waiting entries as one batch, then hands the
+// writing of the next batch to the first entry that arrived meanwhile.
+func (uc *AuditUsecase) appendPending(ctx context.Context) {
+	uc.mu.Lock()
+	batch := uc.pending
+	uc.pending = nil
+	uc.mu.Unlock()
+
+	entries := make([]*entity.AuditEntry, len(batch))
+	for i, req := range batch {
+		entries[i] = req.entry
+	}
+	err := uc.auditRepo.Append(ctx, uc.key, entries)
+	if err != nil {
+		err = fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	for _, req := range batch {
+		req.done <- err
+	}
+
+	uc.mu.Lock()
+	if len(uc.pending) > 0 {
+		uc.pending[0].lead <- struct{}{}
+	} else {
+		uc.flushing = false
+	}
+	uc.mu.Unlock()
+}
+
+func (uc *AuditUsecase) requireAdmin(ctx context.Context, adminID int64) error {
+	admin, err := uc.userRepo.GetByID(ctx, adminID)
+	if err != nil {
+		return err
+	}
+	if admin == nil || admin.Role != string(entity.RoleAdmin) {
+		return ErrPermissionDenied
+	}
+	return nil
+}
+
+// List returns a page of the audit log, newest first.
+func (uc *AuditUsecase) List(ctx context.Context, adminID int64, filter entity.AuditFilter, limit, offset int) ([]*entity.AuditEntry, int, error) {
+	if err := uc.requireAdmin(ctx, adminID); err != nil {
+		return nil, 0, err
+	}
+	return uc.auditRepo.List(ctx, filter, limit, offset)
+}
+
+// Verify walks the whole log in order, recomputing every hash and checking
+// that each entry points at the one before it.
+func (uc *AuditUsecase) Verify(ctx context.Context, adminID int64) (*entity.AuditVerification, error) {
+	if err := uc.requireAdmin(ctx, adminID); err != nil {
+		return nil, err
+	}
+
+	result := &entity.AuditVerification{Valid: true}
+	var lastID int64
+	prevHash := ""
+	for {
+		entries, err := uc.auditRepo.ListAfter(ctx, lastID, auditVerifyBatch)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			result.Checked++
+			if entry.PrevHash != prevHash || entry.ComputeHash(uc.key) != entry.Hash {
+				id := entry.ID
+				result.Valid = false
+				result.BrokenAt = &id
+				return result, nil
+			}
+			prevHash = entry.Hash
+			lastID = entry.ID
+		}
+		if len(entries) < auditVerifyBatch {
+			return result, nil
+		}
+	}
+}
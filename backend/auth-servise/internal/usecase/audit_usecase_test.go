@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// memoryAuditRepo chains entries the way AuditRepository.Append does.
+type memoryAuditRepo struct {
+	repository.AuditRepositoryInterface
+	entries []*entity.AuditEntry
+	batches []int
+	err     error
+}
+
+func (r *memoryAuditRepo) Append(ctx context.Context, key []byte, entries []*entity.AuditEntry) error {
+	if r.err != nil {
+		return r.err
+	}
+	for _, entry := range entries {
+		if len(r.entries) > 0 {
+			entry.PrevHash = r.entries[len(r.entries)-1].Hash
+		}
+		entry.Hash = entry.ComputeHash(key)
+		entry.ID = int64(len(r.entries) + 1)
+		r.entries = append(r.entries, entry)
+	}
+	r.batches = append(r.batches, len(entries))
+	return nil
+}
+
+func (r *memoryAuditRepo) ListAfter(ctx context.Context, afterID int64, limit int) ([]*entity.AuditEntry, error) {
+	var entries []*entity.AuditEntry
+	for _, entry := range r.entries {
+		if entry.ID > afterID && len(entries) < limit {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func TestAuditRecordsAdminActions(t *testing.T) {
+	users, repo, _ := setupAccountTest(t)
+	audit := &memoryAuditRepo{}
+	auditor := NewAuditUsecase(audit, repo, []byte("audit key"))
+	users.auditor = auditor
+	ctx := WithClient(context.Background(), "203.0.113.5", "curl/8.0")
+
+	_, err := users.Ban(ctx, accountAdmin, accountSeeker, "спам")
+	require.NoError(t, err)
+	_, err = users.Unban(ctx, accountAdmin, accountSeeker)
+	require.NoError(t, err)
+
+	require.Len(t, audit.entries, 2)
+	entry := audit.entries[0]
+	assert.Equal(t, entity.AuditUserBan, entry.Action)
+	assert.Equal(t, int64(accountAdmin), *entry.ActorID)
+	assert.Equal(t, int64(accountSeeker), *entry.TargetID)
+	assert.NotContains(t, string(entry.Before), "спам")
+	assert.Contains(t, string(entry.After), "спам")
+	assert.Equal(t, "203.0.113.5", entry.IP)
+	assert.Equal(t, "curl/8.0", entry.UserAgent)
+	assert.Equal(t, entry.Hash, audit.entries[1].PrevHash)
+
+	_, err = auditor.Verify(ctx, accountSeeker)
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+	result, err := auditor.Verify(ctx, accountAdmin)
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, 2, result.Checked)
+}
+
+func TestAuditVerifyFindsTampering(t *testing.T) {
+	_, repo, _ := setupAccountTest(t)
+	audit := &memoryAuditRepo{}
+	auditor := NewAuditUsecase(audit, repo, []byte("audit key"))
+	ctx := context.Background()
+	for i := 0; i < auditVerifyBatch+2; i++ {
+		require.NoError(t, auditor.Record(ctx, auditEntry(accountAdmin, entity.AuditLogin, entity.AuditTargetUser, accountAdmin, nil, nil)))
+	}
+
+	result, err := auditor.Verify(ctx, accountAdmin)
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, auditVerifyBatch+2, result.Checked, "the log is read in batches")
+
+	audit.entries[auditVerifyBatch].CreatedAt = audit.entries[auditVerifyBatch].CreatedAt.Add(-time.Hour)
+	result, err = auditor.Verify(ctx, accountAdmin)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, int64(auditVerifyBatch+1), *result.BrokenAt)
+
+	// Удаление записи рвет ссылку следующей на предыдущую
+	audit.entries[auditVerifyBatch].CreatedAt = audit.entries[auditVerifyBatch].CreatedAt.Add(time.Hour)
+	audit.entries = append(audit.entries[:3], audit.entries[4:]...)
+	result, err = auditor.Verify(ctx, accountAdmin)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, int64(5), *result.BrokenAt)
+}
+
+func TestAuditVerifyNeedsTheKey(t *testing.T) {
+	_, repo, _ := setupAccountTest(t)
+	audit := &memoryAuditRepo{}
+	ctx := context.Background()
+	require.NoError(t, NewAuditUsecase(audit, repo, []byte("audit key")).Record(ctx, auditEntry(accountAdmin, entity.AuditLogin, entity.AuditTargetUser, accountAdmin, nil, nil)))
+
+	result, err := NewAuditUsecase(audit, repo, []byte("other key")).Verify(ctx, accountAdmin)
+	require.NoError(t, err)
+	assert.False(t, result.Valid, "an entry rehashed without the key does not verify")
+}
+
+func TestAuditFailureStopsTheAction(t *testing.T) {
+	users, repo, _ := setupAccountTest(t)
+	audit := &memoryAuditRepo{err: errors.New("database is down")}
+	users.auditor = NewAuditUsecase(audit, repo, []byte("audit key"))
+	auth := NewAuthUsecase(repo, &Config{TokenSecret: "secret", TokenExpiration: time.Hour}, zap.NewNop(), users.auditor)
+	ctx := context.Background()
+
+	_, err := users.Ban(ctx, accountAdmin, accountSeeker, "спам")
+	assert.ErrorContains(t, err, "database is down")
+	// Без записи в журнале аккаунт не меняется
+	repo.AssertNotCalled(t, "UpdateAccountState", mock.Anything, mock.Anything)
+
+	_, err = auth.Login(ctx, &LoginRequest{Email: "seeker@example.com", Password: "secret"})
+	assert.ErrorContains(t, err, "database is down", "no token is issued unrecorded")
+}
+
+// blockingAuditRepo holds the first append until released, so that the
+// entries recorded meanwhile queue up behind it.
+type blockingAuditRepo struct {
+	memoryAuditRepo
+	entered chan struct{}
+	release chan struct{}
+	once    sync.Once
+}
+
+func (r *blockingAuditRepo) Append(ctx context.Context, key []byte, entries []*entity.AuditEntry) error {
+	r.once.Do(func() {
+		close(r.entered)
+		<-r.release
+	})
+	return r.memoryAuditRepo.Append(ctx, key, entries)
+}
+
+func TestAuditBatchesConcurrentEntries(t *testing.T) {
+	_, repo, _ := setupAccountTest(t)
+	audit := &blockingAuditRepo{entered: make(chan struct{}), release: make(chan struct{})}
+	auditor := NewAuditUsecase(audit, repo, []byte("audit key"))
+	ctx := context.Background()
+	login := func() error {
+		return auditor.Record(ctx, auditEntry(accountSeeker, entity.AuditLogin, entity.AuditTargetUser, accountSeeker, nil, nil))
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 11)
+	wg.Add(1)
+	go func() { defer wg.Done(); errs <- login() }()
+	<-audit.entered
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() { defer wg.Done(); errs <- login() }()
+	}
+	require.Eventually(t, func() bool {
+		auditor.mu.Lock()
+		defer auditor.mu.Unlock()
+		return len(auditor.pending) == 10
+	}, time.Second, time.Millisecond)
+	close(audit.release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []int{1, 10}, audit.batches, "logins that arrive during a write share the next one")
+	result, err := auditor.Verify(ctx, accountAdmin)
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, 11, result.Checked)
+}
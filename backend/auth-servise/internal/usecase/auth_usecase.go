@@ -18,6 +18,9 @@ type AuthUsecaseInterface interface {
 	Register(ctx context.Context, req *RegisterRequest) (*RegisterResponse, error)
 	Login(ctx context.Context, req *LoginRequest) (*LoginResponse, error)
 	ValidateToken(ctx context.Context, req *ValidateTokenRequest) (int64, error)
+	CheckAccount(ctx context.Context, userID int64) error
+	Impersonate(ctx context.Context, adminID, userID int64, reason string) (*ImpersonationResponse, error)
+	StreamToken(ctx context.Context, userID int64) (*StreamTokenResponse, error)
 	GetUser(ctx context.Context, req *GetUserRequest) (*entity.User, error)
 	GetUserByID(ctx context.Context, id int64) (*entity.User, error)
 	GetTokenSecret() string
@@ -28,13 +31,15 @@ type authUsecase struct {
 	userRepo repository.UserRepositoryInterface
 	config   *Config
 	logger   *zap.Logger
+	auditor  Auditor
 }
 
-func NewAuthUsecase(userRepo repository.UserRepositoryInterface, config *Config, logger *zap.Logger) AuthUsecaseInterface {
+func NewAuthUsecase(userRepo repository.UserRepositoryInterface, config *Config, logger *zap.Logger, auditor Auditor) AuthUsecaseInterface {
 	return &authUsecase{
 		userRepo: userRepo,
 		config:   config,
 		logger:   logger,
+		auditor:  auditor,
 	}
 }
 
@@ -74,6 +79,9 @@ type GetUserRequest struct {
 type Config struct {
 	TokenSecret     string
 	TokenExpiration time.Duration
+	// ImpersonateExpiration is the lifetime of tokens admins get to act as
+	// a user.
+	ImpersonateExpiration time.Duration
 }
 
 func (uc *authUsecase) Register(ctx context.Context, req *RegisterRequest) (*RegisterResponse, error) {
@@ -126,14 +134,38 @@ func (uc *authUsecase) Login(ctx context.Context, req *LoginRequest) (*LoginResp
 	uc.logger.Info("Login attempt", zap.String("email", req.Email))
 
 	user, err := uc.userRepo.GetByEmail(ctx, req.Email)
-	if err != nil {
+	if err != nil || user == nil {
+		if err := uc.auditor.Record(ctx, auditEntry(0, entity.AuditLoginFailed, entity.AuditTargetUser, 0, nil, loginAttempt{Email: req.Email})); err != nil {
+			return nil, err
+		}
 		return nil, fmt.Errorf("invalid credentials")
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+		if err := uc.auditor.Record(ctx, auditEntry(0, entity.AuditLoginFailed, entity.AuditTargetUser, user.ID, nil, loginAttempt{Email: req.Email})); err != nil {
+			return nil, err
+		}
 		return nil, fmt.Errorf("invalid credentials")
 	}
 
+	// Состояние аккаунта раскрываем только тому, кто знает пароль;
+	// удаленный аккаунт выглядит как несуществующий
+	if err := user.CheckAccess(time.Now()); err != nil {
+		uc.logger.Info("Login of restricted account", zap.Int64("user_id", user.ID), zap.Error(err))
+		if err := uc.auditor.Record(ctx, auditEntry(user.ID, entity.AuditLoginBlocked, entity.AuditTargetUser, user.ID, nil, loginAttempt{Email: req.Email, Reason: err.Error()})); err != nil {
+			return nil, err
+		}
+		if errors.Is(err, entity.ErrAccountDeleted) {
+			return nil, fmt.Errorf("invalid credentials")
+		}
+		return nil, err
+	}
+
+	// Без записи в журнале токен не выдаем
+	if err := uc.auditor.Record(ctx, auditEntry(user.ID, entity.AuditLogin, entity.AuditTargetUser, user.ID, nil, nil)); err != nil {
+		return nil, err
+	}
+
 	token, err := uc.generateToken(user.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate token: %w", err)
@@ -145,6 +177,12 @@ func (uc *authUsecase) Login(ctx context.Context, req *LoginRequest) (*LoginResp
 	}, nil
 }
 
+// loginAttempt is the audit snapshot of a sign-in that did not succeed.
+type loginAttempt struct {
+	Email  string `json:"email"`
+	Reason string `json:"reason,omitempty"`
+}
+
 func (uc *authUsecase) ValidateToken(ctx context.Context, req *ValidateTokenRequest) (int64, error) {
 	token, err := jwt.Parse(req.Token, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
@@ -162,12 +200,36 @@ func (uc *authUsecase) ValidateToken(ctx context.Context, req *ValidateTokenRequ
 		if !ok {
 			return 0, errors.New("invalid token claims")
 		}
+		// Другие сервисы не умеют ограничивать действия под чужим именем
+		if _, ok := claims["act"]; ok {
+			return 0, ErrImpersonationToken
+		}
+		if _, ok := claims["scope"]; ok {
+			return 0, errors.New("invalid token")
+		}
+		if err := uc.CheckAccount(ctx, int64(userID)); err != nil {
+			return 0, err
+		}
 		return int64(userID), nil
 	}
 
 	return 0, errors.New("invalid token")
 }
 
+// CheckAccount returns why the user may not use a token issued earlier: the
+// account was suspended, banned or deleted since. Tokens of removed accounts
+// count as deleted.
+func (uc *authUsecase) CheckAccount(ctx context.Context, userID int64) error {
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return entity.ErrAccountDeleted
+	}
+	return user.CheckAccess(time.Now())
+}
+
 func (uc *authUsecase) GetUser(ctx context.Context, req *GetUserRequest) (*entity.User, error) {
 	return uc.userRepo.GetByID(ctx, req.ID)
 }
@@ -2,18 +2,14 @@ package usecase
 
 import (
 	"context"
-	"database/sql"
 	"errors"
 	"testing"
 	"time"
 
 	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
-	"github.com/Mandarinka0707/newRepoGOODarhit/pkg/auth"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
-	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest"
-	"go.uber.org/zap/zaptest/observer"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -21,427 +17,279 @@ type MockUserRepo struct {
 	mock.Mock
 }
 
-func (m *MockUserRepo) CreateUser(ctx context.Context, user *entity.User) (int64, error) {
+func (m *MockUserRepo) Create(ctx context.Context, user *entity.User) error {
 	args := m.Called(ctx, user)
-	return args.Get(0).(int64), args.Error(1)
+	return args.Error(0)
 }
 
-func (m *MockUserRepo) GetUserByUsername(ctx context.Context, username string) (*entity.User, error) {
-	args := m.Called(ctx, username)
+func (m *MockUserRepo) GetByID(ctx context.Context, id int64) (*entity.User, error) {
+	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*entity.User), args.Error(1)
 }
 
-func (m *MockUserRepo) GetUserByID(ctx context.Context, id int64) (*entity.User, error) {
-	args := m.Called(ctx, id)
+func (m *MockUserRepo) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
+	args := m.Called(ctx, email)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*entity.User), args.Error(1)
 }
 
-type MockSessionRepo struct {
-	mock.Mock
+func (m *MockUserRepo) Update(ctx context.Context, user *entity.User) error {
+	args := m.Called(ctx, user)
+	return args.Error(0)
 }
 
-func (m *MockSessionRepo) CreateSession(ctx context.Context, session *entity.Session) error {
-	args := m.Called(ctx, session)
+func (m *MockUserRepo) Delete(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
-func (m *MockSessionRepo) GetSessionByToken(ctx context.Context, token string) (*entity.Session, error) {
-	args := m.Called(ctx, token)
+func (m *MockUserRepo) GetAll(ctx context.Context) ([]*entity.User, error) {
+	args := m.Called(ctx)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*entity.Session), args.Error(1)
+	return args.Get(0).([]*entity.User), args.Error(1)
+}
+
+func (m *MockUserRepo) SetVerified(ctx context.Context, id int64, verified bool) error {
+	args := m.Called(ctx, id, verified)
+	return args.Error(0)
 }
 
-func setupTest(t *testing.T) (*AuthUsecase, *MockUserRepo, *MockSessionRepo) {
+func (m *MockUserRepo) UpdateAccountState(ctx context.Context, user *entity.User) error {
+	args := m.Called(ctx, user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepo) ListRestricted(ctx context.Context, state string, limit, offset int) ([]*entity.User, int, error) {
+	args := m.Called(ctx, state, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*entity.User), args.Int(1), args.Error(2)
+}
+
+// withUsers lets tests read the users by id and email as often as they
+// need to.
+func (m *MockUserRepo) withUsers(users ...*entity.User) *MockUserRepo {
+	for _, user := range users {
+		m.On("GetByID", mock.Anything, user.ID).Return(user, nil).Maybe()
+		m.On("GetByEmail", mock.Anything, user.Email).Return(user, nil).Maybe()
+	}
+	return m
+}
+
+func setupTest(t *testing.T) (AuthUsecaseInterface, *MockUserRepo) {
 	userRepo := new(MockUserRepo)
-	sessionRepo := new(MockSessionRepo)
-	cfg := &auth.Config{
+	cfg := &Config{
 		TokenSecret:     "test-secret",
 		TokenExpiration: time.Hour,
 	}
 
 	logger := zaptest.NewLogger(t)
 
-	return NewAuthUsecase(userRepo, sessionRepo, cfg, logger), userRepo, sessionRepo
+	return NewAuthUsecase(userRepo, cfg, logger, NopAuditor{}), userRepo
+}
+
+func hashPassword(t *testing.T, password string) string {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("failed to hash password: %v", err)
+	}
+	return string(hashed)
 }
 
 func TestGetUserByID_Success(t *testing.T) {
-	uc, userRepo, _ := setupTest(t)
+	uc, userRepo := setupTest(t)
 	ctx := context.Background()
 
 	expectedUser := &entity.User{
-		ID:       1,
-		Username: "testuser",
-		Role:     "user",
+		ID:    1,
+		Email: "test@example.com",
+		Role:  "user",
 	}
 
-	userRepo.On("GetUserByID", ctx, int64(1)).Return(expectedUser, nil)
+	userRepo.On("GetByID", ctx, int64(1)).Return(expectedUser, nil)
 
-	user, err := uc.GetUserByID(ctx, 1) // Изменено: передаем int64 вместо строки
+	user, err := uc.GetUserByID(ctx, 1)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedUser, user)
 	userRepo.AssertExpectations(t)
 }
 
-func TestRegister_Success(t *testing.T) {
-	uc, userRepo, _ := setupTest(t)
+func TestGetUserByID_NotFound(t *testing.T) {
+	uc, userRepo := setupTest(t)
 	ctx := context.Background()
 
-	req := &RegisterRequest{
-		Username: "testuser",
-		Password: "password123",
-	}
+	userRepo.On("GetByID", ctx, int64(1)).Return(nil, nil)
 
-	userRepo.On("CreateUser", ctx, mock.AnythingOfType("*entity.User")).Return(int64(1), nil)
-
-	resp, err := uc.Register(ctx, req)
+	user, err := uc.GetUserByID(ctx, 1)
 
-	assert.NoError(t, err)
-	assert.Equal(t, int64(1), resp.UserID)
+	assert.EqualError(t, err, "user not found")
+	assert.Nil(t, user)
 	userRepo.AssertExpectations(t)
 }
 
-func TestRegister_HashError(t *testing.T) {
-	uc, _, _ := setupTest(t)
+func TestGetUserByID_DBError(t *testing.T) {
+	uc, userRepo := setupTest(t)
 	ctx := context.Background()
 
-	req := &RegisterRequest{
-		Username: "testuser",
-		Password: string(make([]byte, 100)),
-	}
+	dbError := errors.New("database error")
+	userRepo.On("GetByID", ctx, int64(1)).Return(nil, dbError)
 
-	resp, err := uc.Register(ctx, req)
+	user, err := uc.GetUserByID(ctx, 1)
 
-	assert.Error(t, err)
-	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, dbError)
+	assert.Nil(t, user)
+	userRepo.AssertExpectations(t)
 }
 
-func TestRegister_DBError(t *testing.T) {
-	uc, userRepo, _ := setupTest(t)
+func TestRegister_Success(t *testing.T) {
+	uc, userRepo := setupTest(t)
 	ctx := context.Background()
 
 	req := &RegisterRequest{
-		Username: "testuser",
+		Email:    "test@example.com",
 		Password: "password123",
+		Name:     "Test User",
+		Role:     "jobseeker",
 	}
 
-	userRepo.On("CreateUser", ctx, mock.AnythingOfType("*entity.User")).Return(int64(0), errors.New("db error"))
+	userRepo.On("GetByEmail", ctx, req.Email).Return(nil, nil)
+	userRepo.On("Create", ctx, mock.AnythingOfType("*entity.User")).
+		Run(func(args mock.Arguments) { args.Get(1).(*entity.User).ID = 1 }).
+		Return(nil)
 
 	resp, err := uc.Register(ctx, req)
 
-	assert.Error(t, err)
-	assert.Nil(t, resp)
-	userRepo.AssertExpectations(t)
-}
-
-func TestLogin_Success(t *testing.T) {
-	uc, userRepo, sessionRepo := setupTest(t)
-	ctx := context.Background()
-
-	password := "password123"
-	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	user := &entity.User{
-		ID:       1,
-		Username: "testuser",
-		Password: string(hashedPassword),
-		Role:     "user",
-	}
-
-	userRepo.On("GetUserByUsername", ctx, "testuser").Return(user, nil)
-	sessionRepo.On("CreateSession", ctx, mock.AnythingOfType("*entity.Session")).Return(nil)
-
-	req := &LoginRequest{
-		Username: "testuser",
-		Password: password,
-	}
-
-	resp, err := uc.Login(ctx, req)
-
 	assert.NoError(t, err)
-	assert.NotEmpty(t, resp.Token)
-	assert.Equal(t, "testuser", resp.Username)
+	assert.Equal(t, int64(1), resp.ID)
+	assert.Equal(t, req.Email, resp.Email)
+	created := userRepo.Calls[1].Arguments.Get(1).(*entity.User)
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(created.Password), []byte(req.Password)))
 	userRepo.AssertExpectations(t)
-	sessionRepo.AssertExpectations(t)
 }
 
-func TestLogin_UserNotFound(t *testing.T) {
-	uc, userRepo, _ := setupTest(t)
+func TestRegister_EmailTaken(t *testing.T) {
+	uc, userRepo := setupTest(t)
 	ctx := context.Background()
 
-	userRepo.On("GetUserByUsername", ctx, "testuser").Return(nil, sql.ErrNoRows)
-
-	req := &LoginRequest{
-		Username: "testuser",
+	req := &RegisterRequest{
+		Email:    "test@example.com",
 		Password: "password123",
 	}
 
-	resp, err := uc.Login(ctx, req)
+	userRepo.On("GetByEmail", ctx, req.Email).Return(&entity.User{ID: 1, Email: req.Email}, nil)
 
-	assert.Error(t, err)
-	assert.Equal(t, "invalid username or password", err.Error())
-	assert.Nil(t, resp)
-	userRepo.AssertExpectations(t)
-}
-
-func TestLogin_InvalidPassword(t *testing.T) {
-	uc, userRepo, _ := setupTest(t)
-	ctx := context.Background()
-
-	user := &entity.User{
-		ID:       1,
-		Username: "testuser",
-		Password: "$2a$10$invalidhash",
-		Role:     "user",
-	}
-
-	userRepo.On("GetUserByUsername", ctx, "testuser").Return(user, nil)
-
-	req := &LoginRequest{
-		Username: "testuser",
-		Password: "wrongpassword",
-	}
-
-	resp, err := uc.Login(ctx, req)
+	resp, err := uc.Register(ctx, req)
 
 	assert.Error(t, err)
-	assert.Equal(t, "invalid username or password", err.Error())
 	assert.Nil(t, resp)
-	userRepo.AssertExpectations(t)
+	userRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
 }
 
-func TestLogin_SessionCreationError(t *testing.T) {
-	uc, userRepo, sessionRepo := setupTest(t)
+func TestRegister_DBError(t *testing.T) {
+	uc, userRepo := setupTest(t)
 	ctx := context.Background()
 
-	password := "password123"
-	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	user := &entity.User{
-		ID:       1,
-		Username: "testuser",
-		Password: string(hashedPassword),
-		Role:     "user",
+	req := &RegisterRequest{
+		Email:    "test@example.com",
+		Password: "password123",
 	}
 
-	userRepo.On("GetUserByUsername", ctx, "testuser").Return(user, nil)
-	sessionRepo.On("CreateSession", ctx, mock.AnythingOfType("*entity.Session")).Return(errors.New("db error"))
+	userRepo.On("GetByEmail", ctx, req.Email).Return(nil, nil)
+	userRepo.On("Create", ctx, mock.AnythingOfType("*entity.User")).Return(errors.New("db error"))
 
-	req := &LoginRequest{
-		Username: "testuser",
-		Password: password,
-	}
-
-	resp, err := uc.Login(ctx, req)
+	resp, err := uc.Register(ctx, req)
 
 	assert.Error(t, err)
-	assert.Equal(t, "internal server error", err.Error())
 	assert.Nil(t, resp)
 	userRepo.AssertExpectations(t)
-	sessionRepo.AssertExpectations(t)
 }
 
-func TestGetUser_Success(t *testing.T) {
-	uc, userRepo, _ := setupTest(t)
+func TestLogin_Success(t *testing.T) {
+	uc, userRepo := setupTest(t)
 	ctx := context.Background()
 
 	user := &entity.User{
 		ID:       1,
-		Username: "testuser",
+		Email:    "test@example.com",
+		Password: hashPassword(t, "password123"),
 		Role:     "user",
 	}
+	userRepo.withUsers(user)
 
-	userRepo.On("GetUserByID", ctx, int64(1)).Return(user, nil)
-
-	req := &GetUserRequest{UserID: 1}
-	resp, err := uc.GetUser(ctx, req)
+	resp, err := uc.Login(ctx, &LoginRequest{Email: user.Email, Password: "password123"})
 
 	assert.NoError(t, err)
+	assert.NotEmpty(t, resp.Token)
 	assert.Equal(t, user, resp.User)
-	userRepo.AssertExpectations(t)
+
+	userID, err := uc.ValidateToken(ctx, &ValidateTokenRequest{Token: resp.Token})
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, userID)
 }
 
-func TestGetUser_NotFound(t *testing.T) {
-	uc, userRepo, _ := setupTest(t)
+func TestLogin_UserNotFound(t *testing.T) {
+	uc, userRepo := setupTest(t)
 	ctx := context.Background()
 
-	userRepo.On("GetUserByID", ctx, int64(1)).Return(nil, nil)
+	userRepo.On("GetByEmail", ctx, "test@example.com").Return(nil, nil)
 
-	req := &GetUserRequest{UserID: 1}
-	resp, err := uc.GetUser(ctx, req)
+	resp, err := uc.Login(ctx, &LoginRequest{Email: "test@example.com", Password: "password123"})
 
-	assert.Error(t, err)
-	assert.Equal(t, "user not found", err.Error())
+	assert.EqualError(t, err, "invalid credentials")
 	assert.Nil(t, resp)
 	userRepo.AssertExpectations(t)
 }
 
-func TestGetUser_DBError(t *testing.T) {
-	uc, userRepo, _ := setupTest(t)
+func TestLogin_InvalidPassword(t *testing.T) {
+	uc, userRepo := setupTest(t)
 	ctx := context.Background()
 
-	userRepo.On("GetUserByID", ctx, int64(1)).Return(nil, errors.New("db error"))
+	user := &entity.User{
+		ID:       1,
+		Email:    "test@example.com",
+		Password: hashPassword(t, "password123"),
+		Role:     "user",
+	}
+	userRepo.On("GetByEmail", ctx, user.Email).Return(user, nil)
 
-	req := &GetUserRequest{UserID: 1}
-	resp, err := uc.GetUser(ctx, req)
+	resp, err := uc.Login(ctx, &LoginRequest{Email: user.Email, Password: "wrongpassword"})
 
-	assert.Error(t, err)
-	assert.Equal(t, "user not found", err.Error())
+	assert.EqualError(t, err, "invalid credentials")
 	assert.Nil(t, resp)
 	userRepo.AssertExpectations(t)
 }
 
-func TestGetUser_Logging(t *testing.T) {
-	t.Run("Success logs info message", func(t *testing.T) {
-		uc, userRepo, _ := setupTest(t)
-		ctx := context.Background()
-
-		user := &entity.User{
-			ID:       1,
-			Username: "testuser",
-			Role:     "user",
-		}
-
-		userRepo.On("GetUserByID", ctx, int64(1)).Return(user, nil)
-
-		req := &GetUserRequest{UserID: 1}
-		resp, err := uc.GetUser(ctx, req)
-
-		assert.NoError(t, err)
-		assert.Equal(t, user, resp.User)
-		userRepo.AssertExpectations(t)
-
-	})
-
-	t.Run("User not found logs error", func(t *testing.T) {
-		uc, userRepo, _ := setupTest(t)
-		ctx := context.Background()
-
-		userRepo.On("GetUserByID", ctx, int64(1)).Return(nil, nil)
-
-		req := &GetUserRequest{UserID: 1}
-		resp, err := uc.GetUser(ctx, req)
-
-		assert.Error(t, err)
-		assert.Equal(t, "user not found", err.Error())
-		assert.Nil(t, resp)
-		userRepo.AssertExpectations(t)
-
-	})
-
-	t.Run("DB error logs error", func(t *testing.T) {
-		uc, userRepo, _ := setupTest(t)
-		ctx := context.Background()
-
-		dbError := errors.New("database error")
-		userRepo.On("GetUserByID", ctx, int64(1)).Return(nil, dbError)
-
-		req := &GetUserRequest{UserID: 1}
-		resp, err := uc.GetUser(ctx, req)
-
-		assert.Error(t, err)
-		assert.Equal(t, "user not found", err.Error())
-		assert.Nil(t, resp)
-		userRepo.AssertExpectations(t)
-
-	})
-}
-func setupTestWithLogObserver(t *testing.T) (*AuthUsecase, *MockUserRepo, *MockSessionRepo, *observer.ObservedLogs) {
-	userRepo := new(MockUserRepo)
-	sessionRepo := new(MockSessionRepo)
-	cfg := &auth.Config{
-		TokenSecret:     "test-secret",
-		TokenExpiration: time.Hour,
-	}
-
-	core, recorded := observer.New(zap.InfoLevel)
-	logger := zap.New(core)
-
-	return NewAuthUsecase(userRepo, sessionRepo, cfg, logger), userRepo, sessionRepo, recorded
-}
-
-func TestGetUser_LoggingWithObserver(t *testing.T) {
-	t.Run("Success logs info message", func(t *testing.T) {
-		uc, userRepo, _, logs := setupTestWithLogObserver(t)
-		ctx := context.Background()
-
-		user := &entity.User{
-			ID:       1,
-			Username: "testuser",
-			Role:     "user",
-		}
-
-		userRepo.On("GetUserByID", ctx, int64(1)).Return(user, nil)
-
-		req := &GetUserRequest{UserID: 1}
-		resp, err := uc.GetUser(ctx, req)
-
-		assert.NoError(t, err)
-		assert.Equal(t, user, resp.User)
-		userRepo.AssertExpectations(t)
-
-		assert.Equal(t, 1, logs.Len())
-		assert.Contains(t, logs.All()[0].Message, "Get user request")
-		assert.Equal(t, zap.InfoLevel, logs.All()[0].Level)
-	})
-
-	t.Run("User not found logs error", func(t *testing.T) {
-		uc, userRepo, _, logs := setupTestWithLogObserver(t)
-		ctx := context.Background()
-
-		userRepo.On("GetUserByID", ctx, int64(1)).Return(nil, nil)
-
-		req := &GetUserRequest{UserID: 1}
-		resp, err := uc.GetUser(ctx, req)
-
-		assert.Error(t, err)
-		assert.Equal(t, "user not found", err.Error())
-		assert.Nil(t, resp)
-		userRepo.AssertExpectations(t)
-
-		assert.Equal(t, 2, logs.Len())
-		assert.Contains(t, logs.All()[1].Message, "User not found")
-		assert.Equal(t, zap.ErrorLevel, logs.All()[1].Level)
-	})
-
-	t.Run("DB error logs error", func(t *testing.T) {
-		uc, userRepo, _, logs := setupTestWithLogObserver(t)
-		ctx := context.Background()
-
-		dbError := errors.New("database error")
-		userRepo.On("GetUserByID", ctx, int64(1)).Return(nil, dbError)
+func TestLogin_RestrictedAccount(t *testing.T) {
+	uc, userRepo := setupTest(t)
+	ctx := context.Background()
 
-		req := &GetUserRequest{UserID: 1}
-		resp, err := uc.GetUser(ctx, req)
+	now := time.Now()
+	banned := &entity.User{ID: 1, Email: "banned@example.com", Password: hashPassword(t, "password123"), BannedAt: &now}
+	deleted := &entity.User{ID: 2, Email: "deleted@example.com", Password: hashPassword(t, "password123"), DeletedAt: &now}
+	userRepo.withUsers(banned, deleted)
 
-		assert.Error(t, err)
-		assert.Equal(t, "user not found", err.Error())
-		assert.Nil(t, resp)
-		userRepo.AssertExpectations(t)
+	_, err := uc.Login(ctx, &LoginRequest{Email: banned.Email, Password: "password123"})
+	assert.ErrorIs(t, err, entity.ErrAccountBanned)
 
-		assert.Equal(t, 2, logs.Len())
-		assert.Contains(t, logs.All()[1].Message, "User not found")
-		assert.Equal(t, zap.ErrorLevel, logs.All()[1].Level)
-		assert.Equal(t, dbError, logs.All()[1].Context[0].Interface.(error))
-	})
+	_, err = uc.Login(ctx, &LoginRequest{Email: deleted.Email, Password: "password123"})
+	assert.EqualError(t, err, "invalid credentials", "deleted accounts look like missing ones")
 }
 
-func TestGetUserByID_NotFound(t *testing.T) {
-	uc, userRepo, _ := setupTest(t)
+func TestCheckAccount_RemovedUser(t *testing.T) {
+	uc, userRepo := setupTest(t)
 	ctx := context.Background()
 
-	userRepo.On("GetUserByID", ctx, int64(1)).Return(nil, sql.ErrNoRows)
-
-	user, err := uc.GetUserByID(ctx, 1) // Изменено: передаем int64 вместо строки
+	userRepo.On("GetByID", ctx, int64(1)).Return(nil, nil)
 
-	assert.Error(t, err)
-	assert.True(t, errors.Is(err, sql.ErrNoRows))
-	assert.Nil(t, user)
+	assert.ErrorIs(t, uc.CheckAccount(ctx, 1), entity.ErrAccountDeleted)
 	userRepo.AssertExpectations(t)
 }
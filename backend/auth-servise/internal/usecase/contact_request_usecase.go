@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/repository"
+)
+
+var ErrContactRequestAnswered = errors.New("contact request already answered")
+
+type ContactRequestUsecaseInterface interface {
+	Create(ctx context.Context, employerID, resumeID int64, message string) (*entity.ContactRequest, error)
+	List(ctx context.Context, userID int64) ([]*entity.ContactRequest, error)
+	Respond(ctx context.Context, userID, id int64, accept bool) (*entity.ContactRequest, error)
+}
+
+type ContactRequestUsecase struct {
+	contactRepo repository.ContactRequestRepositoryInterface
+	resumeRepo  repository.ResumeRepositoryInterface
+	userRepo    repository.UserRepositoryInterface
+	access      *resumeAccess
+}
+
+func NewContactRequestUsecase(
+	contactRepo repository.ContactRequestRepositoryInterface,
+	resumeRepo repository.ResumeRepositoryInterface,
+	userRepo repository.UserRepositoryInterface,
+	applicationRepo repository.ApplicationRepositoryInterface,
+	privacyRepo repository.ResumePrivacyRepositoryInterface,
+) *ContactRequestUsecase {
+	return &ContactRequestUsecase{
+		contactRepo: contactRepo,
+		resumeRepo:  resumeRepo,
+		userRepo:    userRepo,
+		access: &resumeAccess{
+			userRepo:        userRepo,
+			applicationRepo: applicationRepo,
+			privacyRepo:     privacyRepo,
+			contactRepo:     contactRepo,
+		},
+	}
+}
+
+// Create asks the owner of a resume the employer can see to share contacts.
+// Like the candidate search it leads from, it is open to verified employers
+// only.
+func (uc *ContactRequestUsecase) Create(ctx context.Context, employerID, resumeID int64, message string) (*entity.ContactRequest, error) {
+	employer, err := uc.userRepo.GetByID(ctx, employerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if employer == nil || employer.Role != string(entity.RoleEmployer) {
+		return nil, ErrPermissionDenied
+	}
+	if !employer.IsVerified {
+		return nil, ErrEmployerNotVerified
+	}
+
+	resume, err := uc.resumeRepo.GetResumeByID(ctx, resumeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resume: %w", err)
+	}
+	if resume == nil {
+		return nil, ErrResumeNotFound
+	}
+	visible, _, err := uc.access.check(ctx, employerID, resume)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, ErrResumeNotFound
+	}
+
+	request := &entity.ContactRequest{
+		EmployerID:   employerID,
+		JobseekerID:  resume.UserID,
+		ResumeID:     &resume.ID,
+		Message:      message,
+		EmployerName: employer.Name,
+	}
+	if err := uc.contactRepo.Create(ctx, request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// List returns the requests an employer sent or a candidate received.
+func (uc *ContactRequestUsecase) List(ctx context.Context, userID int64) ([]*entity.ContactRequest, error) {
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	if user.Role == string(entity.RoleEmployer) {
+		return uc.contactRepo.GetByEmployerID(ctx, userID)
+	}
+	return uc.contactRepo.GetByJobseekerID(ctx, userID)
+}
+
+// Respond accepts or declines a pending request addressed to the user.
+func (uc *ContactRequestUsecase) Respond(ctx context.Context, userID, id int64, accept bool) (*entity.ContactRequest, error) {
+	request, err := uc.contactRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if request == nil || request.JobseekerID != userID {
+		return nil, entity.ErrContactRequestNotFound
+	}
+	if request.Status != entity.ContactRequestPending {
+		return nil, ErrContactRequestAnswered
+	}
+
+	status := entity.ContactRequestDeclined
+	if accept {
+		status = entity.ContactRequestAccepted
+	}
+	if err := uc.contactRepo.UpdateStatus(ctx, id, status); err != nil {
+		return nil, err
+	}
+
+	return uc.contactRepo.GetByID(ctx, id)
+}
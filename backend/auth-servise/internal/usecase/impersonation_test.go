@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/export"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/repository"
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestImpersonation(t *testing.T) {
+	users, repo, _ := setupAccountTest(t)
+	audit := &memoryAuditRepo{}
+	auditor := NewAuditUsecase(audit, repo, []byte("audit key"))
+	auth := NewAuthUsecase(repo, &Config{
+		TokenSecret:           "secret",
+		TokenExpiration:       time.Hour,
+		ImpersonateExpiration: 15 * time.Minute,
+	}, zap.NewNop(), auditor)
+	ctx := context.Background()
+
+	_, err := auth.Impersonate(ctx, accountSeeker, accountSeeker, "")
+	assert.ErrorIs(t, err, ErrPermissionDenied, "only admins impersonate")
+	_, err = auth.Impersonate(ctx, accountAdmin, accountAdmin, "")
+	assert.ErrorIs(t, err, ErrPermissionDenied, "admins cannot be impersonated")
+
+	resp, err := auth.Impersonate(ctx, accountAdmin, accountSeeker, "тикет 1234")
+	require.NoError(t, err)
+	assert.Equal(t, int64(accountSeeker), resp.User.ID)
+	assert.WithinDuration(t, time.Now().Add(15*time.Minute), resp.ExpiresAt, time.Minute)
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(resp.Token, claims, func(*jwt.Token) (interface{}, error) { return []byte("secret"), nil })
+	require.NoError(t, err)
+	assert.Equal(t, float64(accountSeeker), claims["user_id"])
+	assert.Equal(t, map[string]interface{}{"sub": "1"}, claims["act"])
+	assert.Equal(t, resp.SessionID, claims["jti"])
+
+	_, err = auth.ValidateToken(ctx, &ValidateTokenRequest{Token: resp.Token})
+	assert.ErrorIs(t, err, ErrImpersonationToken, "other services cannot keep the session read-only")
+
+	require.Len(t, audit.entries, 1)
+	entry := audit.entries[0]
+	assert.Equal(t, entity.AuditUserImpersonate, entry.Action)
+	assert.Equal(t, int64(accountAdmin), *entry.ActorID)
+	assert.Equal(t, int64(accountSeeker), *entry.TargetID)
+	assert.Contains(t, string(entry.After), resp.SessionID)
+	assert.Contains(t, string(entry.After), "тикет 1234")
+
+	users.auditor = auditor
+	_, err = users.Ban(ctx, accountAdmin, accountSeeker, "спам")
+	require.NoError(t, err)
+	_, err = auth.Impersonate(ctx, accountAdmin, accountSeeker, "")
+	assert.ErrorIs(t, err, entity.ErrAccountBanned)
+}
+
+// traceApplicationRepo counts the changes made to a single application.
+type traceApplicationRepo struct {
+	repository.ApplicationRepositoryInterface
+	application   *entity.Application
+	statusChanges int
+}
+
+func (r *traceApplicationRepo) GetByID(ctx context.Context, id int64) (*entity.Application, error) {
+	copied := *r.application
+	return &copied, nil
+}
+
+func (r *traceApplicationRepo) GetStatusHistory(ctx context.Context, id int64) ([]*entity.ApplicationStatusChange, error) {
+	return nil, nil
+}
+
+func (r *traceApplicationRepo) ChangeStatus(ctx context.Context, id int64, from, to string, actorID int64, comment string) error {
+	r.statusChanges++
+	r.application.Status = to
+	return nil
+}
+
+type traceVacancyRepo struct {
+	repository.VacancyRepositoryInterface
+	vacancy *entity.Vacancy
+}
+
+func (r *traceVacancyRepo) GetByID(ctx context.Context, id int64) (*entity.Vacancy, error) {
+	return r.vacancy, nil
+}
+
+type traceScreeningRepo struct {
+	repository.ScreeningRepositoryInterface
+}
+
+func (traceScreeningRepo) GetAnswers(ctx context.Context, applicationIDs []int64) (map[int64][]*entity.ScreeningAnswer, error) {
+	return map[int64][]*entity.ScreeningAnswer{}, nil
+}
+
+type traceMessageRepo struct {
+	repository.MessageRepositoryInterface
+	reads int
+}
+
+func (r *traceMessageRepo) List(ctx context.Context, applicationID, beforeID int64, limit int) ([]*entity.ApplicationMessage, error) {
+	return []*entity.ApplicationMessage{{ID: 1, ApplicationID: applicationID, Body: "Здравствуйте"}}, nil
+}
+
+func (r *traceMessageRepo) MarkRead(ctx context.Context, applicationID, userID, messageID int64) error {
+	r.reads++
+	return nil
+}
+
+func TestImpersonationLeavesNoTrace(t *testing.T) {
+	const employerID, applicantID = 3, 4
+	applications := &traceApplicationRepo{application: &entity.Application{
+		ID: 10, UserID: applicantID, VacancyID: 20, Status: entity.ApplicationStatusPending,
+	}}
+	vacancies := &traceVacancyRepo{vacancy: &entity.Vacancy{ID: 20, EmployerID: employerID}}
+	users := new(MockUserRepo).withUsers(
+		&entity.User{ID: employerID, Role: string(entity.RoleEmployer)},
+		&entity.User{ID: applicantID, Role: string(entity.RoleJobseeker)},
+	)
+	messageRepo := &traceMessageRepo{}
+	applicationsUC := NewApplicationUsecase(applications, users, vacancies, nil, nil, nil, traceScreeningRepo{}, nil, nil)
+	messages := NewMessageUsecase(messageRepo, applications, vacancies, users, nil, nil, nil, nil, nil)
+	ctx := WithImpersonator(context.Background(), accountAdmin)
+
+	application, err := applicationsUC.GetByID(ctx, 10, employerID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.ApplicationStatusPending, application.Status)
+	_, err = applicationsUC.MarkViewed(ctx, 10, employerID)
+	assert.ErrorIs(t, err, ErrReadOnlySession)
+	assert.Zero(t, applications.statusChanges)
+	assert.Equal(t, entity.ApplicationStatusPending, applications.application.Status)
+
+	thread, err := messages.List(ctx, employerID, 10, 0, 50)
+	require.NoError(t, err)
+	assert.Len(t, thread, 1)
+	assert.Zero(t, messageRepo.reads, "the thread stays unread for the employer")
+
+	exports, exportRepo := setupExportTest(t, 5)
+	_, err = exports.Export(ctx, employerID, export.FormatCSV, entity.ApplicationFilter{}, nil)
+	assert.ErrorIs(t, err, ErrReadOnlySession)
+	// Выгрузка не ставится в очередь от имени работодателя
+	exportRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+
+	// Сам работодатель читает как обычно
+	_, err = messages.List(context.Background(), employerID, 10, 0, 50)
+	require.NoError(t, err)
+	assert.Equal(t, 1, messageRepo.reads)
+	_, err = applicationsUC.MarkViewed(context.Background(), 10, employerID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, applications.statusChanges)
+}
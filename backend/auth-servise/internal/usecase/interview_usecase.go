@@ -0,0 +1,442 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/ical"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/notify"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/repository"
+)
+
+var (
+	// ErrApplicationNotShortlisted is returned when proposing an interview
+	// before the applicant was shortlisted.
+	ErrApplicationNotShortlisted = errors.New("interviews can only be proposed for shortlisted applications")
+	ErrInterviewNotScheduled     = errors.New("interview is not scheduled")
+)
+
+type InterviewUsecaseInterface interface {
+	Propose(ctx context.Context, employerID, applicationID int64, interview *entity.Interview, slots []time.Time) error
+	List(ctx context.Context, userID, applicationID int64) ([]*entity.Interview, error)
+	Get(ctx context.Context, userID, id int64) (*entity.Interview, error)
+	SelectSlot(ctx context.Context, candidateID, id, slotID int64) (*entity.Interview, error)
+	Update(ctx context.Context, employerID int64, interview *entity.Interview) (*entity.Interview, error)
+	Cancel(ctx context.Context, userID, id int64, reason string) (*entity.Interview, error)
+	Invite(ctx context.Context, userID, id int64) ([]byte, error)
+}
+
+// InterviewUsecase schedules interviews for applications. The employer
+// proposes slots, the candidate picks one, and every change is sent to the
+// participants as an iCalendar invite through the notifier.
+type InterviewUsecase struct {
+	interviewRepo   repository.InterviewRepositoryInterface
+	applicationRepo repository.ApplicationRepositoryInterface
+	vacancyRepo     repository.VacancyRepositoryInterface
+	userRepo        repository.UserRepositoryInterface
+	notifier        notify.Notifier
+	access          *vacancyAccess
+	now             func() time.Time
+}
+
+func NewInterviewUsecase(
+	interviewRepo repository.InterviewRepositoryInterface,
+	applicationRepo repository.ApplicationRepositoryInterface,
+	vacancyRepo repository.VacancyRepositoryInterface,
+	userRepo repository.UserRepositoryInterface,
+	orgRepo repository.OrganizationRepositoryInterface,
+	notifier notify.Notifier,
+) *InterviewUsecase {
+	return &InterviewUsecase{
+		interviewRepo:   interviewRepo,
+		applicationRepo: applicationRepo,
+		vacancyRepo:     vacancyRepo,
+		userRepo:        userRepo,
+		notifier:        notifier,
+		access:          &vacancyAccess{orgRepo: orgRepo},
+		now:             time.Now,
+	}
+}
+
+// interviewContext is what is needed to address the participants.
+type interviewContext struct {
+	application *entity.Application
+	vacancy     *entity.Vacancy
+	employer    *entity.User
+	candidate   *entity.User
+}
+
+func (uc *InterviewUsecase) loadContext(ctx context.Context, applicationID int64) (*interviewContext, error) {
+	application, err := uc.applicationRepo.GetByID(ctx, applicationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+	if application == nil {
+		return nil, entity.ErrApplicationNotFound
+	}
+
+	vacancy, err := uc.vacancyRepo.GetByID(ctx, application.VacancyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vacancy: %w", err)
+	}
+	if vacancy == nil {
+		return nil, entity.ErrVacancyNotFound
+	}
+
+	employer, err := uc.userRepo.GetByID(ctx, vacancy.EmployerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get employer: %w", err)
+	}
+	candidate, err := uc.userRepo.GetByID(ctx, application.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get candidate: %w", err)
+	}
+	if employer == nil || candidate == nil {
+		return nil, ErrUserNotFound
+	}
+
+	return &interviewContext{application: application, vacancy: vacancy, employer: employer, candidate: candidate}, nil
+}
+
+// loadInterview returns the interview and its context if the user is its
+// candidate or sees the applications of its vacancy.
+func (uc *InterviewUsecase) loadInterview(ctx context.Context, userID, id int64) (*entity.Interview, *interviewContext, error) {
+	interview, err := uc.interviewRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if interview == nil {
+		return nil, nil, entity.ErrInterviewNotFound
+	}
+
+	ic, err := uc.loadContext(ctx, interview.ApplicationID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if interview.CandidateID != userID {
+		allowed, err := uc.access.canView(ctx, userID, ic.vacancy)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !allowed {
+			return nil, nil, entity.ErrInterviewNotFound
+		}
+	}
+	return interview, ic, nil
+}
+
+// requireManage returns ErrPermissionDenied unless the user may act on the
+// applications of the interview's vacancy.
+func (uc *InterviewUsecase) requireManage(ctx context.Context, userID int64, ic *interviewContext) error {
+	allowed, err := uc.access.canManage(ctx, userID, ic.vacancy)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return ErrPermissionDenied
+	}
+	return nil
+}
+
+// Propose creates an interview with the slots the candidate can choose
+// from. A shortlisted application moves to the interview stage.
+func (uc *InterviewUsecase) Propose(ctx context.Context, employerID, applicationID int64, interview *entity.Interview, slots []time.Time) error {
+	ic, err := uc.loadContext(ctx, applicationID)
+	if err != nil {
+		return err
+	}
+	allowed, err := uc.access.canManage(ctx, employerID, ic.vacancy)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return ErrPermissionDenied
+	}
+	status := ic.application.Status
+	if status != entity.ApplicationStatusShortlisted && status != entity.ApplicationStatusInterview && status != entity.ApplicationStatusOffer {
+		return ErrApplicationNotShortlisted
+	}
+
+	interview.ApplicationID = applicationID
+	interview.EmployerID = employerID
+	interview.CandidateID = ic.application.UserID
+	interview.Status = entity.InterviewStatusProposed
+	if len(interview.Interviewers) == 0 {
+		interview.Interviewers = []string{ic.employer.Email}
+	}
+	if err := interview.Validate(); err != nil {
+		return err
+	}
+	slots, err = entity.ValidateSlots(slots, uc.now())
+	if err != nil {
+		return err
+	}
+
+	// Слоты, которые уже заняты у кандидата или интервьюеров, не предлагаем
+	interview.Slots = make([]*entity.InterviewSlot, len(slots))
+	for i, start := range slots {
+		conflicts, err := uc.interviewRepo.FindConflicts(ctx, interview.CandidateID, interview.Interviewers, start, start.Add(interview.Duration()), 0)
+		if err != nil {
+			return err
+		}
+		if len(conflicts) > 0 {
+			return fmt.Errorf("%w: slot %s overlaps interview %d", entity.ErrInterviewConflict, start.Format(time.RFC3339), conflicts[0].ID)
+		}
+		interview.Slots[i] = &entity.InterviewSlot{StartsAt: start}
+	}
+
+	if err := uc.interviewRepo.Create(ctx, interview); err != nil {
+		return err
+	}
+
+	if status == entity.ApplicationStatusShortlisted {
+		err := uc.applicationRepo.ChangeStatus(ctx, applicationID, status, entity.ApplicationStatusInterview, employerID, "")
+		if err != nil && !errors.Is(err, entity.ErrApplicationStatusConflict) {
+			return err
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s предлагает интервью по вакансии «%s».\n\nВыберите удобное время:\n", ic.employer.Name, ic.vacancy.Title)
+	for _, slot := range interview.Slots {
+		fmt.Fprintf(&b, "  • %s UTC\n", slot.StartsAt.Format("02.01.2006 15:04"))
+	}
+	fmt.Fprintf(&b, "\nДлительность: %d мин.\n", interview.DurationMinutes)
+	uc.send(ctx, notify.Message{
+		To:      []string{ic.candidate.Email},
+		Subject: "Приглашение на интервью: " + ic.vacancy.Title,
+		Body:    b.String(),
+	})
+	return nil
+}
+
+func (uc *InterviewUsecase) List(ctx context.Context, userID, applicationID int64) ([]*entity.Interview, error) {
+	ic, err := uc.loadContext(ctx, applicationID)
+	if err != nil {
+		return nil, err
+	}
+	if ic.application.UserID != userID {
+		allowed, err := uc.access.canView(ctx, userID, ic.vacancy)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, ErrPermissionDenied
+		}
+	}
+	return uc.interviewRepo.GetByApplicationID(ctx, applicationID)
+}
+
+func (uc *InterviewUsecase) Get(ctx context.Context, userID, id int64) (*entity.Interview, error) {
+	interview, _, err := uc.loadInterview(ctx, userID, id)
+	return interview, err
+}
+
+// SelectSlot books the slot chosen by the candidate and sends the invite to
+// everyone.
+func (uc *InterviewUsecase) SelectSlot(ctx context.Context, candidateID, id, slotID int64) (*entity.Interview, error) {
+	interview, ic, err := uc.loadInterview(ctx, candidateID, id)
+	if err != nil {
+		return nil, err
+	}
+	if interview.CandidateID != candidateID {
+		return nil, ErrPermissionDenied
+	}
+	if interview.Status != entity.InterviewStatusProposed {
+		return nil, entity.ErrInterviewStatusConflict
+	}
+	slot := interview.Slot(slotID)
+	if slot == nil {
+		return nil, entity.ErrInterviewSlotNotFound
+	}
+	if !slot.StartsAt.After(uc.now()) {
+		return nil, fmt.Errorf("%w: slot is in the past", entity.ErrInvalidInterview)
+	}
+
+	if err := uc.interviewRepo.Schedule(ctx, interview, slot.StartsAt); err != nil {
+		return nil, err
+	}
+
+	uc.sendInvite(ctx, ic, interview, ical.MethodRequest, "Интервью назначено")
+	return interview, nil
+}
+
+// Update changes the interview details on behalf of anyone who manages the
+// vacancy. For a scheduled interview the time may be moved as well;
+// participants get an updated invite.
+func (uc *InterviewUsecase) Update(ctx context.Context, employerID int64, changes *entity.Interview) (*entity.Interview, error) {
+	interview, ic, err := uc.loadInterview(ctx, employerID, changes.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.requireManage(ctx, employerID, ic); err != nil {
+		return nil, err
+	}
+	if interview.Status == entity.InterviewStatusCancelled {
+		return nil, entity.ErrInterviewStatusConflict
+	}
+
+	interview.Title = changes.Title
+	interview.DurationMinutes = changes.DurationMinutes
+	interview.Location = changes.Location
+	interview.VideoLink = changes.VideoLink
+	interview.Interviewers = changes.Interviewers
+	interview.Notes = changes.Notes
+	if len(interview.Interviewers) == 0 {
+		interview.Interviewers = []string{ic.employer.Email}
+	}
+	if err := interview.Validate(); err != nil {
+		return nil, err
+	}
+
+	if changes.StartsAt != nil {
+		if interview.Status != entity.InterviewStatusScheduled {
+			return nil, ErrInterviewNotScheduled
+		}
+		if !changes.StartsAt.After(uc.now()) {
+			return nil, fmt.Errorf("%w: start time is in the past", entity.ErrInvalidInterview)
+		}
+		start := changes.StartsAt.UTC()
+		interview.StartsAt = &start
+	}
+
+	if err := uc.interviewRepo.Update(ctx, interview); err != nil {
+		return nil, err
+	}
+
+	if interview.Status == entity.InterviewStatusScheduled {
+		uc.sendInvite(ctx, ic, interview, ical.MethodRequest, "Интервью изменено")
+	}
+	return interview, nil
+}
+
+// Cancel cancels the interview on behalf of the candidate or anyone who
+// manages the vacancy.
+func (uc *InterviewUsecase) Cancel(ctx context.Context, userID, id int64, reason string) (*entity.Interview, error) {
+	interview, ic, err := uc.loadInterview(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if interview.CandidateID != userID {
+		if err := uc.requireManage(ctx, userID, ic); err != nil {
+			return nil, err
+		}
+	}
+	wasScheduled := interview.Status == entity.InterviewStatusScheduled
+
+	if err := uc.interviewRepo.Cancel(ctx, interview); err != nil {
+		return nil, err
+	}
+
+	if wasScheduled {
+		uc.sendInvite(ctx, ic, interview, ical.MethodCancel, "Интервью отменено", reason)
+		return interview, nil
+	}
+
+	// Время еще не выбрано — календарь обновлять нечего
+	to := ic.candidate.Email
+	if userID == interview.CandidateID {
+		to = ic.employer.Email
+	}
+	body := fmt.Sprintf("Интервью по вакансии «%s» отменено.", ic.vacancy.Title)
+	if reason != "" {
+		body += "\n\n" + reason
+	}
+	uc.send(ctx, notify.Message{To: []string{to}, Subject: "Интервью отменено: " + ic.vacancy.Title, Body: body})
+	return interview, nil
+}
+
+// Invite returns the current .ics invite of a scheduled interview.
+func (uc *InterviewUsecase) Invite(ctx context.Context, userID, id int64) ([]byte, error) {
+	interview, ic, err := uc.loadInterview(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch interview.Status {
+	case entity.InterviewStatusScheduled:
+		return ical.Invite(ical.MethodRequest, uc.event(ic, interview)), nil
+	case entity.InterviewStatusCancelled:
+		if interview.StartsAt != nil {
+			return ical.Invite(ical.MethodCancel, uc.event(ic, interview)), nil
+		}
+	}
+	return nil, ErrInterviewNotScheduled
+}
+
+func (uc *InterviewUsecase) event(ic *interviewContext, interview *entity.Interview) ical.Event {
+	description := fmt.Sprintf("Интервью по вакансии «%s» (%s).", ic.vacancy.Title, ic.vacancy.Company)
+	if interview.VideoLink != "" {
+		description += "\nСсылка на видеозвонок: " + interview.VideoLink
+	}
+	if interview.Notes != "" {
+		description += "\n\n" + interview.Notes
+	}
+
+	location := interview.Location
+	if location == "" {
+		location = interview.VideoLink
+	}
+
+	attendees := []ical.Attendee{{Name: ic.candidate.Name, Email: ic.candidate.Email}}
+	for _, email := range interview.Interviewers {
+		attendees = append(attendees, ical.Attendee{Email: email})
+	}
+
+	return ical.Event{
+		UID:         interview.UID(),
+		Sequence:    interview.Sequence,
+		Start:       *interview.StartsAt,
+		End:         *interview.EndsAt,
+		Summary:     interview.Title,
+		Description: description,
+		Location:    location,
+		URL:         interview.VideoLink,
+		Organizer:   ical.Attendee{Name: ic.employer.Name, Email: ic.employer.Email},
+		Attendees:   attendees,
+		Stamp:       uc.now(),
+	}
+}
+
+// sendInvite mails the invite to the candidate, the employer and the
+// interviewers.
+func (uc *InterviewUsecase) sendInvite(ctx context.Context, ic *interviewContext, interview *entity.Interview, method, subject string, details ...string) {
+	recipients := []string{ic.candidate.Email, ic.employer.Email}
+	for _, email := range interview.Interviewers {
+		if !strings.EqualFold(email, ic.employer.Email) {
+			recipients = append(recipients, email)
+		}
+	}
+
+	body := fmt.Sprintf("%s: %s\nВакансия: %s\nНачало: %s UTC\nДлительность: %d мин.",
+		subject, interview.Title, ic.vacancy.Title,
+		interview.StartsAt.UTC().Format("02.01.2006 15:04"), interview.DurationMinutes)
+	for _, detail := range details {
+		if detail != "" {
+			body += "\n\n" + detail
+		}
+	}
+
+	uc.send(ctx, notify.Message{
+		To:      recipients,
+		Subject: subject + ": " + ic.vacancy.Title,
+		Body:    body,
+		Attachments: []notify.Attachment{{
+			Name:        "invite.ics",
+			ContentType: ical.ContentType(method),
+			Data:        ical.Invite(method, uc.event(ic, interview)),
+		}},
+	})
+}
+
+// send delivers a notification. The change is already saved, so a delivery
+// failure is only logged.
+func (uc *InterviewUsecase) send(ctx context.Context, msg notify.Message) {
+	if err := uc.notifier.Send(ctx, msg); err != nil {
+		fmt.Printf("Failed to send interview notification %q: %v\n", msg.Subject, err)
+	}
+}
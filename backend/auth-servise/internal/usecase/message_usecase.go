@@ -0,0 +1,283 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/attachment"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/repository"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/storage"
+)
+
+// MessageFile is a file uploaded with a message.
+type MessageFile struct {
+	Name string
+	Data []byte
+}
+
+type MessageUsecaseInterface interface {
+	ListThreads(ctx context.Context, userID int64, limit, offset int) ([]*entity.MessageThread, error)
+	CountUnread(ctx context.Context, userID int64) (int, error)
+	List(ctx context.Context, userID, applicationID, beforeID int64, limit int) ([]*entity.ApplicationMessage, error)
+	Send(ctx context.Context, userID, applicationID int64, body string, files []MessageFile) (*entity.ApplicationMessage, error)
+	Download(ctx context.Context, id int64, link attachment.Link) (*entity.MessageAttachment, io.ReadCloser, error)
+}
+
+type MessageUsecase struct {
+	messageRepo     repository.MessageRepositoryInterface
+	applicationRepo repository.ApplicationRepositoryInterface
+	vacancyRepo     repository.VacancyRepositoryInterface
+	userRepo        repository.UserRepositoryInterface
+	store           storage.BlobStore
+	scanner         attachment.Scanner
+	signer          *attachment.Signer
+	notifications   Notifier
+	access          *vacancyAccess
+}
+
+func NewMessageUsecase(
+	messageRepo repository.MessageRepositoryInterface,
+	applicationRepo repository.ApplicationRepositoryInterface,
+	vacancyRepo repository.VacancyRepositoryInterface,
+	userRepo repository.UserRepositoryInterface,
+	store storage.BlobStore,
+	scanner attachment.Scanner,
+	signer *attachment.Signer,
+	orgRepo repository.OrganizationRepositoryInterface,
+	notifications Notifier,
+) *MessageUsecase {
+	if scanner == nil {
+		scanner = attachment.NopScanner{}
+	}
+	return &MessageUsecase{
+		messageRepo:     messageRepo,
+		applicationRepo: applicationRepo,
+		vacancyRepo:     vacancyRepo,
+		userRepo:        userRepo,
+		store:           store,
+		scanner:         scanner,
+		signer:          signer,
+		notifications:   notifications,
+		access:          &vacancyAccess{orgRepo: orgRepo},
+	}
+}
+
+func (uc *MessageUsecase) ListThreads(ctx context.Context, userID int64, limit, offset int) ([]*entity.MessageThread, error) {
+	return uc.messageRepo.ListThreads(ctx, userID, limit, offset)
+}
+
+func (uc *MessageUsecase) CountUnread(ctx context.Context, userID int64) (int, error) {
+	return uc.messageRepo.CountUnread(ctx, userID)
+}
+
+// thread loads the application and its vacancy and checks the user takes
+// part in the conversation: the applicant, the vacancy owner and the members
+// of its organization. Organization viewers may only read, so write denies
+// them. To anybody else the thread does not exist.
+func (uc *MessageUsecase) thread(ctx context.Context, userID, applicationID int64, write bool) (*entity.Application, *entity.Vacancy, error) {
+	application, err := uc.applicationRepo.GetByID(ctx, applicationID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get application: %w", err)
+	}
+	if application == nil {
+		return nil, nil, entity.ErrApplicationNotFound
+	}
+
+	vacancy, err := uc.vacancyRepo.GetByID(ctx, application.VacancyID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get vacancy: %w", err)
+	}
+	if vacancy == nil {
+		return nil, nil, entity.ErrVacancyNotFound
+	}
+
+	if application.UserID == userID {
+		return application, vacancy, nil
+	}
+	role, err := uc.access.role(ctx, userID, vacancy)
+	if err != nil {
+		return nil, nil, err
+	}
+	if role == "" {
+		return nil, nil, entity.ErrApplicationNotFound
+	}
+	if write && !entity.CanManageVacancies(role) {
+		return nil, nil, ErrPermissionDenied
+	}
+	return application, vacancy, nil
+}
+
+// List returns a page of the thread with download links for the user.
+// Opening the latest page marks the thread as read, unless an admin is
+// impersonating the user.
+func (uc *MessageUsecase) List(ctx context.Context, userID, applicationID, beforeID int64, limit int) ([]*entity.ApplicationMessage, error) {
+	if _, _, err := uc.thread(ctx, userID, applicationID, false); err != nil {
+		return nil, err
+	}
+
+	messages, err := uc.messageRepo.List(ctx, applicationID, beforeID, limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, message := range messages {
+		for _, item := range message.Attachments {
+			item.DownloadURL = uc.signer.MessageURL(item.ID, userID)
+		}
+	}
+
+	if beforeID == 0 && !impersonating(ctx) {
+		var lastID int64
+		if len(messages) > 0 {
+			lastID = messages[len(messages)-1].ID
+		}
+		if err := uc.messageRepo.MarkRead(ctx, applicationID, userID, lastID); err != nil {
+			return nil, err
+		}
+	}
+	return messages, nil
+}
+
+// Send posts a message with optional files to the thread. If the other
+// participant has not been in the thread recently, they also get it by email.
+func (uc *MessageUsecase) Send(ctx context.Context, userID, applicationID int64, body string, files []MessageFile) (*entity.ApplicationMessage, error) {
+	application, vacancy, err := uc.thread(ctx, userID, applicationID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err = entity.NormalizeMessage(body, len(files))
+	if err != nil {
+		return nil, err
+	}
+
+	message := &entity.ApplicationMessage{
+		ApplicationID: applicationID,
+		SenderID:      &userID,
+		Kind:          entity.MessageKindUser,
+		Body:          body,
+		Attachments:   []*entity.MessageAttachment{},
+	}
+	for _, file := range files {
+		item, err := uc.prepareFile(ctx, applicationID, file)
+		if err != nil {
+			return nil, err
+		}
+		message.Attachments = append(message.Attachments, item)
+	}
+
+	// Файлы загружаются до записи сообщения и удаляются, если она не удалась
+	stored := make([]string, 0, len(message.Attachments))
+	cleanup := func() {
+		for _, key := range stored {
+			_ = uc.store.Delete(ctx, key)
+		}
+	}
+	for i, item := range message.Attachments {
+		if err := uc.store.Put(ctx, item.StorageKey, bytes.NewReader(files[i].Data), item.Size, item.ContentType); err != nil {
+			cleanup()
+			return nil, err
+		}
+		stored = append(stored, item.StorageKey)
+	}
+	if err := uc.messageRepo.Create(ctx, message); err != nil {
+		cleanup()
+		return nil, err
+	}
+
+	for _, item := range message.Attachments {
+		item.DownloadURL = uc.signer.MessageURL(item.ID, userID)
+	}
+
+	recipientID := application.UserID
+	if userID == application.UserID {
+		recipientID = vacancy.EmployerID
+	}
+	uc.notifyRecipient(ctx, recipientID, userID, vacancy, message)
+	return message, nil
+}
+
+// prepareFile validates and scans a file and picks the key it is stored under.
+func (uc *MessageUsecase) prepareFile(ctx context.Context, applicationID int64, file MessageFile) (*entity.MessageAttachment, error) {
+	fileName := filepath.Base(strings.ReplaceAll(file.Name, `\`, "/"))
+	contentType, err := attachment.DetectMessageType(fileName, file.Data)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.scanner.Scan(ctx, fileName, file.Data); err != nil {
+		return nil, err
+	}
+
+	token, err := newPublicToken()
+	if err != nil {
+		return nil, err
+	}
+	return &entity.MessageAttachment{
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        int64(len(file.Data)),
+		StorageKey:  fmt.Sprintf("messages/%d/%s%s", applicationID, token, strings.ToLower(filepath.Ext(fileName))),
+	}, nil
+}
+
+// notifyRecipient publishes the message to the other participant. It is
+// emailed only if the recipient is away from the thread. The message is
+// already stored, so a failure is only logged.
+func (uc *MessageUsecase) notifyRecipient(ctx context.Context, recipientID, senderID int64, vacancy *entity.Vacancy, message *entity.ApplicationMessage) {
+	lastSeen, err := uc.messageRepo.GetLastSeen(ctx, message.ApplicationID, recipientID)
+	if err != nil {
+		fmt.Printf("Failed to get last seen time of user %d: %v\n", recipientID, err)
+		return
+	}
+	sender, err := uc.userRepo.GetByID(ctx, senderID)
+	if err != nil || sender == nil {
+		fmt.Printf("Failed to get user %d to notify about message: %v\n", senderID, err)
+		return
+	}
+
+	body := fmt.Sprintf("%s написал(а) вам по отклику на вакансию «%s»:\n\n%s", sender.Name, vacancy.Title, message.Body)
+	if n := len(message.Attachments); n > 0 {
+		body += fmt.Sprintf("\n\nПрикреплено файлов: %d. Откройте переписку, чтобы их скачать.", n)
+	}
+	uc.notifications.Publish(ctx, &entity.Notification{
+		UserID:    recipientID,
+		Type:      entity.NotificationMessage,
+		Title:     "Новое сообщение: " + vacancy.Title,
+		Body:      body,
+		Link:      fmt.Sprintf("/applications/%d/messages", message.ApplicationID),
+		SkipEmail: !entity.IsOffline(lastSeen, time.Now()),
+	})
+}
+
+// Download checks the signed link and that the user it was issued to still
+// takes part in the thread.
+func (uc *MessageUsecase) Download(ctx context.Context, id int64, link attachment.Link) (*entity.MessageAttachment, io.ReadCloser, error) {
+	if err := uc.signer.VerifyMessage(id, link); err != nil {
+		return nil, nil, err
+	}
+
+	item, err := uc.messageRepo.GetAttachment(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if item == nil {
+		return nil, nil, entity.ErrMessageAttachmentNotFound
+	}
+	if _, _, err := uc.thread(ctx, link.ViewerID, item.ApplicationID, false); err != nil {
+		return nil, nil, err
+	}
+
+	body, err := uc.store.Get(ctx, item.StorageKey)
+	if err == storage.ErrBlobNotFound {
+		return nil, nil, entity.ErrMessageAttachmentNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return item, body, nil
+}
@@ -0,0 +1,238 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/notify"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/push"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/repository"
+)
+
+// digestBatchSize is how many users' digests are sent per query.
+const digestBatchSize = 100
+
+// Notifier is what usecases publish notifications to. Delivery failures are
+// logged, never returned: the change the notification is about has already
+// happened.
+type Notifier interface {
+	Publish(ctx context.Context, notification *entity.Notification)
+}
+
+// NopNotifier drops every notification.
+type NopNotifier struct{}
+
+func (NopNotifier) Publish(context.Context, *entity.Notification) {}
+
+type NotificationUsecaseInterface interface {
+	List(ctx context.Context, userID int64, unreadOnly bool, limit, offset int) ([]*entity.Notification, int, error)
+	MarkRead(ctx context.Context, userID, id int64) error
+	MarkAllRead(ctx context.Context, userID int64) (int64, error)
+	GetPreferences(ctx context.Context, userID int64) ([]*entity.NotificationPreference, error)
+	SetPreferences(ctx context.Context, userID int64, preferences []*entity.NotificationPreference) ([]*entity.NotificationPreference, error)
+	Subscribe(ctx context.Context, userID int64) (<-chan *entity.Notification, func(), error)
+}
+
+// NotificationUsecase runs the notification center and delivers published
+// notifications through the channels each user chose.
+type NotificationUsecase struct {
+	notificationRepo repository.NotificationRepositoryInterface
+	userRepo         repository.UserRepositoryInterface
+	hub              *push.Hub
+	mailer           notify.Notifier
+}
+
+func NewNotificationUsecase(
+	notificationRepo repository.NotificationRepositoryInterface,
+	userRepo repository.UserRepositoryInterface,
+	hub *push.Hub,
+	mailer notify.Notifier,
+) *NotificationUsecase {
+	return &NotificationUsecase{
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		hub:              hub,
+		mailer:           mailer,
+	}
+}
+
+// Publish stores the notification for the notification center and the
+// digest, pushes it to the user's open streams and emails it. The email is
+// skipped while the user has the site open and sees the notification live.
+func (uc *NotificationUsecase) Publish(ctx context.Context, notification *entity.Notification) {
+	preference, err := uc.preference(ctx, notification.UserID, notification.Type)
+	if err != nil {
+		fmt.Printf("Failed to get notification preferences of user %d: %v\n", notification.UserID, err)
+		preference = entity.DefaultNotificationPreference(notification.Type)
+	}
+
+	if preference.InApp || preference.Digest {
+		if err := uc.notificationRepo.Create(ctx, notification, preference.InApp, preference.Digest); err != nil {
+			fmt.Printf("Failed to store notification for user %d: %v\n", notification.UserID, err)
+			return
+		}
+	}
+	online := uc.hub.Online(notification.UserID)
+	if preference.InApp && online {
+		uc.hub.Publish(notification)
+	}
+	if preference.Email && !notification.SkipEmail && !(preference.InApp && online) {
+		uc.email(ctx, notification.UserID, notification.Title, notificationText(notification))
+	}
+}
+
+func (uc *NotificationUsecase) preference(ctx context.Context, userID int64, notificationType string) (*entity.NotificationPreference, error) {
+	preferences, err := uc.notificationRepo.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, preference := range preferences {
+		if preference.Type == notificationType {
+			return preference, nil
+		}
+	}
+	return entity.DefaultNotificationPreference(notificationType), nil
+}
+
+func (uc *NotificationUsecase) email(ctx context.Context, userID int64, subject, body string) {
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil || user == nil {
+		fmt.Printf("Failed to get user %d to email a notification: %v\n", userID, err)
+		return
+	}
+	msg := notify.Message{To: []string{user.Email}, Subject: subject, Body: body}
+	if err := uc.mailer.Send(ctx, msg); err != nil {
+		fmt.Printf("Failed to email notification to user %d: %v\n", userID, err)
+	}
+}
+
+func notificationText(notification *entity.Notification) string {
+	text := notification.Body
+	if notification.Link != "" {
+		text += "\n\n" + notification.Link
+	}
+	return text
+}
+
+// List returns a page of the user's notifications and the number of unread
+// ones.
+func (uc *NotificationUsecase) List(ctx context.Context, userID int64, unreadOnly bool, limit, offset int) ([]*entity.Notification, int, error) {
+	notifications, err := uc.notificationRepo.List(ctx, userID, unreadOnly, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	unread, err := uc.notificationRepo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return notifications, unread, nil
+}
+
+func (uc *NotificationUsecase) MarkRead(ctx context.Context, userID, id int64) error {
+	found, err := uc.notificationRepo.MarkRead(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return entity.ErrNotificationNotFound
+	}
+	return nil
+}
+
+func (uc *NotificationUsecase) MarkAllRead(ctx context.Context, userID int64) (int64, error) {
+	return uc.notificationRepo.MarkAllRead(ctx, userID)
+}
+
+// GetPreferences returns the settings of every notification type, with the
+// defaults for types the user has not changed.
+func (uc *NotificationUsecase) GetPreferences(ctx context.Context, userID int64) ([]*entity.NotificationPreference, error) {
+	saved, err := uc.notificationRepo.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	preferences := make([]*entity.NotificationPreference, len(entity.NotificationTypes))
+	for i, notificationType := range entity.NotificationTypes {
+		preferences[i] = entity.DefaultNotificationPreference(notificationType)
+		for _, preference := range saved {
+			if preference.Type == notificationType {
+				preferences[i] = preference
+			}
+		}
+	}
+	return preferences, nil
+}
+
+// SetPreferences saves the settings of the given types and returns the
+// settings of all of them.
+func (uc *NotificationUsecase) SetPreferences(ctx context.Context, userID int64, preferences []*entity.NotificationPreference) ([]*entity.NotificationPreference, error) {
+	for _, preference := range preferences {
+		if err := preference.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if err := uc.notificationRepo.SetPreferences(ctx, userID, preferences); err != nil {
+		return nil, err
+	}
+	return uc.GetPreferences(ctx, userID)
+}
+
+// Subscribe opens a real-time stream of the user's notifications. An admin
+// impersonating the user may not open one: while it is open the user counts
+// as online and would not get notifications by email.
+func (uc *NotificationUsecase) Subscribe(ctx context.Context, userID int64) (<-chan *entity.Notification, func(), error) {
+	if impersonating(ctx) {
+		return nil, nil, ErrReadOnlySession
+	}
+	notifications, unsubscribe := uc.hub.Subscribe(userID)
+	return notifications, unsubscribe, nil
+}
+
+// SendDigests emails every user their notifications waiting for the digest,
+// one email per user, and returns how many emails were sent.
+func (uc *NotificationUsecase) SendDigests(ctx context.Context) (int, error) {
+	sent := 0
+	for {
+		digests, err := uc.notificationRepo.PendingDigests(ctx, digestBatchSize)
+		if err != nil {
+			return sent, err
+		}
+		for _, digest := range digests {
+			var body strings.Builder
+			ids := make([]int64, len(digest.Notifications))
+			for i, notification := range digest.Notifications {
+				ids[i] = notification.ID
+				fmt.Fprintf(&body, "%s — %s\n%s\n\n",
+					notification.CreatedAt.Format("02.01.2006 15:04"), notification.Title, notificationText(notification))
+			}
+			// Сводку отмечаем до отправки, чтобы сбой почты не повторял ее бесконечно
+			if err := uc.notificationRepo.MarkDigested(ctx, ids); err != nil {
+				return sent, err
+			}
+			subject := fmt.Sprintf("Сводка уведомлений: %d", len(digest.Notifications))
+			uc.email(ctx, digest.UserID, subject, strings.TrimSpace(body.String()))
+			sent++
+		}
+		if len(digests) < digestBatchSize {
+			return sent, nil
+		}
+	}
+}
+
+// RunDigests sends the digests every interval until ctx is cancelled.
+func (uc *NotificationUsecase) RunDigests(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := uc.SendDigests(ctx); err != nil {
+				fmt.Printf("Failed to send notification digests: %v\n", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/notify"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/push"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockNotificationRepo struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepo) Create(ctx context.Context, notification *entity.Notification, inApp, digest bool) error {
+	args := m.Called(ctx, notification, inApp, digest)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepo) List(ctx context.Context, userID int64, unreadOnly bool, limit, offset int) ([]*entity.Notification, error) {
+	args := m.Called(ctx, userID, unreadOnly, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepo) CountUnread(ctx context.Context, userID int64) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockNotificationRepo) MarkRead(ctx context.Context, userID, id int64) (bool, error) {
+	args := m.Called(ctx, userID, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockNotificationRepo) MarkAllRead(ctx context.Context, userID int64) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockNotificationRepo) GetPreferences(ctx context.Context, userID int64) ([]*entity.NotificationPreference, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.NotificationPreference), args.Error(1)
+}
+
+func (m *MockNotificationRepo) SetPreferences(ctx context.Context, userID int64, preferences []*entity.NotificationPreference) error {
+	args := m.Called(ctx, userID, preferences)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepo) PendingDigests(ctx context.Context, limit int) ([]*entity.NotificationDigest, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.NotificationDigest), args.Error(1)
+}
+
+func (m *MockNotificationRepo) MarkDigested(ctx context.Context, ids []int64) error {
+	args := m.Called(ctx, ids)
+	return args.Error(0)
+}
+
+// MockMailer accepts every message; tests read them back with sent.
+type MockMailer struct {
+	mock.Mock
+}
+
+func newMockMailer() *MockMailer {
+	m := new(MockMailer)
+	m.On("Send", mock.Anything, mock.Anything).Return(nil).Maybe()
+	return m
+}
+
+func (m *MockMailer) Send(ctx context.Context, msg notify.Message) error {
+	args := m.Called(ctx, msg)
+	return args.Error(0)
+}
+
+// sent returns the messages sent so far, oldest first.
+func (m *MockMailer) sent() []notify.Message {
+	var messages []notify.Message
+	for _, call := range m.Calls {
+		if call.Method == "Send" {
+			messages = append(messages, call.Arguments.Get(1).(notify.Message))
+		}
+	}
+	return messages
+}
+
+func setupNotificationTest() (*NotificationUsecase, *MockNotificationRepo, *MockMailer, *push.Hub) {
+	notificationRepo := new(MockNotificationRepo)
+	userRepo := new(MockUserRepo).withUsers(&entity.User{ID: 1, Email: "user@example.com"})
+	mailer := newMockMailer()
+	hub := push.NewHub()
+	return NewNotificationUsecase(notificationRepo, userRepo, hub, mailer), notificationRepo, mailer, hub
+}
+
+func statusNotification() *entity.Notification {
+	return &entity.Notification{
+		UserID: 1,
+		Type:   entity.NotificationApplicationStatus,
+		Title:  "Отклик на вакансию «Go developer»",
+		Body:   "Статус отклика изменен: «Приглашение на интервью»",
+		Link:   "/applications/7",
+	}
+}
+
+func TestPublishUsesDefaultChannels(t *testing.T) {
+	uc, notificationRepo, mailer, _ := setupNotificationTest()
+	ctx := context.Background()
+
+	notificationRepo.On("GetPreferences", ctx, int64(1)).Return(nil, nil)
+	notificationRepo.On("Create", ctx, mock.AnythingOfType("*entity.Notification"), true, false).Return(nil)
+
+	uc.Publish(ctx, statusNotification())
+
+	notificationRepo.AssertExpectations(t)
+	sent := mailer.sent()
+	require.Len(t, sent, 1)
+	assert.Equal(t, []string{"user@example.com"}, sent[0].To)
+	assert.Contains(t, sent[0].Body, "/applications/7")
+}
+
+func TestPublishPushesToOnlineUserInsteadOfEmail(t *testing.T) {
+	uc, notificationRepo, mailer, hub := setupNotificationTest()
+	ctx := context.Background()
+	stream, unsubscribe := hub.Subscribe(1)
+	defer unsubscribe()
+
+	notificationRepo.On("GetPreferences", ctx, int64(1)).Return(nil, nil)
+	notificationRepo.On("Create", ctx, mock.AnythingOfType("*entity.Notification"), true, false).Return(nil)
+
+	uc.Publish(ctx, statusNotification())
+
+	require.Len(t, stream, 1)
+	assert.Equal(t, entity.NotificationApplicationStatus, (<-stream).Type)
+	assert.Empty(t, mailer.sent(), "the user sees the notification live")
+}
+
+func TestPublishRespectsSkipEmail(t *testing.T) {
+	uc, notificationRepo, mailer, _ := setupNotificationTest()
+	ctx := context.Background()
+	notification := statusNotification()
+	notification.SkipEmail = true
+
+	notificationRepo.On("GetPreferences", ctx, int64(1)).Return(nil, nil)
+	notificationRepo.On("Create", ctx, notification, true, false).Return(nil)
+
+	uc.Publish(ctx, notification)
+
+	notificationRepo.AssertExpectations(t)
+	assert.Empty(t, mailer.sent())
+}
+
+func TestNotificationDigest(t *testing.T) {
+	uc, notificationRepo, mailer, _ := setupNotificationTest()
+	ctx := context.Background()
+
+	digest := []*entity.NotificationPreference{{Type: entity.NotificationApplicationStatus, Digest: true}}
+	notificationRepo.On("SetPreferences", ctx, int64(1), digest).Return(nil)
+	notificationRepo.On("GetPreferences", ctx, int64(1)).Return(digest, nil)
+	notificationRepo.On("Create", ctx, mock.AnythingOfType("*entity.Notification"), false, true).Return(nil).Twice()
+
+	_, err := uc.SetPreferences(ctx, 1, digest)
+	require.NoError(t, err)
+
+	first, second := statusNotification(), statusNotification()
+	uc.Publish(ctx, first)
+	uc.Publish(ctx, second)
+	assert.Empty(t, mailer.sent(), "digest notifications are not emailed right away")
+
+	first.ID, second.ID = 1, 2
+	notificationRepo.On("PendingDigests", ctx, digestBatchSize).Return([]*entity.NotificationDigest{
+		{UserID: 1, Notifications: []*entity.Notification{first, second}},
+	}, nil)
+	notificationRepo.On("MarkDigested", ctx, []int64{1, 2}).Return(nil)
+
+	sent, err := uc.SendDigests(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	require.Len(t, mailer.sent(), 1)
+	assert.Equal(t, "Сводка уведомлений: 2", mailer.sent()[0].Subject)
+	notificationRepo.AssertExpectations(t)
+}
+
+func TestNotificationDigestMarkedBeforeSending(t *testing.T) {
+	uc, notificationRepo, mailer, _ := setupNotificationTest()
+	ctx := context.Background()
+
+	notification := statusNotification()
+	notification.ID = 1
+	notificationRepo.On("PendingDigests", ctx, digestBatchSize).Return([]*entity.NotificationDigest{
+		{UserID: 1, Notifications: []*entity.Notification{notification}},
+	}, nil)
+	notificationRepo.On("MarkDigested", ctx, []int64{1}).Return(errors.New("database is down"))
+
+	sent, err := uc.SendDigests(ctx)
+	assert.Error(t, err)
+	assert.Zero(t, sent)
+	assert.Empty(t, mailer.sent(), "a digest that was not marked could be sent again")
+}
+
+func TestNotificationPreferences(t *testing.T) {
+	uc, notificationRepo, _, _ := setupNotificationTest()
+	ctx := context.Background()
+
+	_, err := uc.SetPreferences(ctx, 1, []*entity.NotificationPreference{{Type: "unknown", InApp: true}})
+	assert.ErrorIs(t, err, entity.ErrInvalidNotificationPreference)
+	_, err = uc.SetPreferences(ctx, 1, []*entity.NotificationPreference{
+		{Type: entity.NotificationMessage, Email: true, Digest: true},
+	})
+	assert.ErrorIs(t, err, entity.ErrInvalidNotificationPreference)
+	notificationRepo.AssertNotCalled(t, "SetPreferences", mock.Anything, mock.Anything, mock.Anything)
+
+	changed := []*entity.NotificationPreference{{Type: entity.NotificationMessage, InApp: true}}
+	notificationRepo.On("SetPreferences", ctx, int64(1), changed).Return(nil)
+	notificationRepo.On("GetPreferences", ctx, int64(1)).Return(changed, nil)
+
+	preferences, err := uc.SetPreferences(ctx, 1, changed)
+	require.NoError(t, err)
+	require.Len(t, preferences, len(entity.NotificationTypes))
+	for _, preference := range preferences {
+		if preference.Type == entity.NotificationMessage {
+			assert.False(t, preference.Email)
+		} else {
+			assert.Equal(t, entity.DefaultNotificationPreference(preference.Type), preference)
+		}
+	}
+}
+
+func TestImpersonatorCannotKeepUserOnline(t *testing.T) {
+	uc, notificationRepo, mailer, hub := setupNotificationTest()
+	ctx := context.Background()
+
+	_, _, err := uc.Subscribe(WithImpersonator(ctx, 99), 1)
+	assert.ErrorIs(t, err, ErrReadOnlySession)
+	assert.False(t, hub.Online(1))
+
+	notificationRepo.On("GetPreferences", ctx, int64(1)).Return(nil, nil)
+	notificationRepo.On("Create", ctx, mock.AnythingOfType("*entity.Notification"), true, false).Return(nil)
+
+	uc.Publish(ctx, statusNotification())
+	assert.Len(t, mailer.sent(), 1, "the user still gets the email")
+}
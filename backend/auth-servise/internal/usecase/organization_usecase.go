@@ -0,0 +1,253 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/notify"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/repository"
+)
+
+type OrganizationUsecaseInterface interface {
+	Create(ctx context.Context, userID int64, organization *entity.Organization) error
+	Get(ctx context.Context, userID int64) (*entity.Organization, error)
+	Update(ctx context.Context, userID int64, organization *entity.Organization) (*entity.Organization, error)
+	ListMembers(ctx context.Context, userID int64) ([]*entity.OrganizationMember, error)
+	ChangeRole(ctx context.Context, userID, memberID int64, role string) error
+	RemoveMember(ctx context.Context, userID, memberID int64) error
+	Invite(ctx context.Context, userID int64, invite *entity.OrganizationInvite) error
+	ListInvites(ctx context.Context, userID int64) ([]*entity.OrganizationInvite, error)
+	RevokeInvite(ctx context.Context, userID, id int64) error
+	AcceptInvite(ctx context.Context, userID int64, token string) (*entity.OrganizationMember, error)
+}
+
+// OrganizationUsecase manages employer organizations: the company profile,
+// the members and their roles, and email invites.
+type OrganizationUsecase struct {
+	orgRepo  repository.OrganizationRepositoryInterface
+	userRepo repository.UserRepositoryInterface
+	notifier notify.Notifier
+	now      func() time.Time
+}
+
+func NewOrganizationUsecase(
+	orgRepo repository.OrganizationRepositoryInterface,
+	userRepo repository.UserRepositoryInterface,
+	notifier notify.Notifier,
+) *OrganizationUsecase {
+	return &OrganizationUsecase{
+		orgRepo:  orgRepo,
+		userRepo: userRepo,
+		notifier: notifier,
+		now:      time.Now,
+	}
+}
+
+// employer returns the user if it is an employer account.
+func (uc *OrganizationUsecase) employer(ctx context.Context, userID int64) (*entity.User, error) {
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	if user.Role != string(entity.RoleEmployer) {
+		return nil, ErrPermissionDenied
+	}
+	return user, nil
+}
+
+// membership returns the user's membership, checking that the user has one
+// and, with owner set, that the user owns the organization.
+func (uc *OrganizationUsecase) membership(ctx context.Context, userID int64, owner bool) (*entity.OrganizationMember, error) {
+	member, err := uc.orgRepo.GetMembership(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, entity.ErrOrganizationNotFound
+	}
+	if owner && member.Role != entity.OrganizationRoleOwner {
+		return nil, ErrPermissionDenied
+	}
+	return member, nil
+}
+
+// Create sets up an organization with the employer as its owner. The
+// employer's vacancies become the organization's.
+func (uc *OrganizationUsecase) Create(ctx context.Context, userID int64, organization *entity.Organization) error {
+	if _, err := uc.employer(ctx, userID); err != nil {
+		return err
+	}
+	if err := organization.Validate(); err != nil {
+		return err
+	}
+	member, err := uc.orgRepo.GetMembership(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if member != nil {
+		return entity.ErrAlreadyInOrganization
+	}
+	return uc.orgRepo.Create(ctx, organization, userID)
+}
+
+// Get returns the organization the user belongs to.
+func (uc *OrganizationUsecase) Get(ctx context.Context, userID int64) (*entity.Organization, error) {
+	member, err := uc.membership(ctx, userID, false)
+	if err != nil {
+		return nil, err
+	}
+	organization, err := uc.orgRepo.GetByID(ctx, member.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	if organization == nil {
+		return nil, entity.ErrOrganizationNotFound
+	}
+	return organization, nil
+}
+
+// Update replaces the profile of the user's organization. Only owners may.
+func (uc *OrganizationUsecase) Update(ctx context.Context, userID int64, organization *entity.Organization) (*entity.Organization, error) {
+	member, err := uc.membership(ctx, userID, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := organization.Validate(); err != nil {
+		return nil, err
+	}
+	organization.ID = member.OrganizationID
+	if err := uc.orgRepo.Update(ctx, organization); err != nil {
+		return nil, err
+	}
+	return uc.orgRepo.GetByID(ctx, organization.ID)
+}
+
+func (uc *OrganizationUsecase) ListMembers(ctx context.Context, userID int64) ([]*entity.OrganizationMember, error) {
+	member, err := uc.membership(ctx, userID, false)
+	if err != nil {
+		return nil, err
+	}
+	return uc.orgRepo.ListMembers(ctx, member.OrganizationID)
+}
+
+// ChangeRole gives a member another role. Only owners may, and the last
+// owner cannot step down.
+func (uc *OrganizationUsecase) ChangeRole(ctx context.Context, userID, memberID int64, role string) error {
+	if !entity.IsValidOrganizationRole(role) {
+		return fmt.Errorf("%w: unknown role %q", entity.ErrInvalidOrganization, role)
+	}
+	member, err := uc.membership(ctx, userID, true)
+	if err != nil {
+		return err
+	}
+	return uc.orgRepo.SetMemberRole(ctx, member.OrganizationID, memberID, role)
+}
+
+// RemoveMember takes a member out of the organization. Owners remove
+// anybody; any member may leave.
+func (uc *OrganizationUsecase) RemoveMember(ctx context.Context, userID, memberID int64) error {
+	member, err := uc.membership(ctx, userID, memberID != userID)
+	if err != nil {
+		return err
+	}
+	return uc.orgRepo.RemoveMember(ctx, member.OrganizationID, memberID)
+}
+
+// Invite emails a link to join the organization with the given role. Only
+// owners may invite.
+func (uc *OrganizationUsecase) Invite(ctx context.Context, userID int64, invite *entity.OrganizationInvite) error {
+	member, err := uc.membership(ctx, userID, true)
+	if err != nil {
+		return err
+	}
+	if err := invite.Validate(); err != nil {
+		return err
+	}
+	organization, err := uc.orgRepo.GetByID(ctx, member.OrganizationID)
+	if err != nil {
+		return err
+	}
+	if organization == nil {
+		return entity.ErrOrganizationNotFound
+	}
+
+	token, err := newPublicToken()
+	if err != nil {
+		return err
+	}
+	invite.OrganizationID = member.OrganizationID
+	invite.Token = token
+	invite.InvitedBy = userID
+	invite.ExpiresAt = uc.now().Add(entity.InviteTTL)
+	if err := uc.orgRepo.CreateInvite(ctx, invite); err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Вас пригласили в компанию «%s» на роль «%s».\n\n", organization.Name, invite.Role)
+	fmt.Fprintf(&b, "Чтобы принять приглашение, войдите в аккаунт работодателя с этим адресом и откройте ссылку:\n")
+	fmt.Fprintf(&b, "/organizations/invites/accept?token=%s\n\n", token)
+	fmt.Fprintf(&b, "Приглашение действует до %s.", invite.ExpiresAt.Format("02.01.2006 15:04"))
+	msg := notify.Message{
+		To:      []string{invite.Email},
+		Subject: "Приглашение в компанию " + organization.Name,
+		Body:    b.String(),
+	}
+	if err := uc.notifier.Send(ctx, msg); err != nil {
+		fmt.Printf("Failed to send organization invite %d: %v\n", invite.ID, err)
+	}
+	return nil
+}
+
+func (uc *OrganizationUsecase) ListInvites(ctx context.Context, userID int64) ([]*entity.OrganizationInvite, error) {
+	member, err := uc.membership(ctx, userID, true)
+	if err != nil {
+		return nil, err
+	}
+	return uc.orgRepo.ListInvites(ctx, member.OrganizationID)
+}
+
+func (uc *OrganizationUsecase) RevokeInvite(ctx context.Context, userID, id int64) error {
+	member, err := uc.membership(ctx, userID, true)
+	if err != nil {
+		return err
+	}
+	found, err := uc.orgRepo.DeleteInvite(ctx, member.OrganizationID, id)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return entity.ErrInviteNotFound
+	}
+	return nil
+}
+
+// AcceptInvite adds the employer to the organization of the invite. The
+// invite only works for the account with the address it was sent to.
+func (uc *OrganizationUsecase) AcceptInvite(ctx context.Context, userID int64, token string) (*entity.OrganizationMember, error) {
+	user, err := uc.employer(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	invite, err := uc.orgRepo.GetInviteByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if invite == nil || !strings.EqualFold(invite.Email, user.Email) {
+		return nil, entity.ErrInviteNotFound
+	}
+	if invite.AcceptedAt != nil || !uc.now().Before(invite.ExpiresAt) {
+		return nil, entity.ErrInviteExpired
+	}
+
+	if err := uc.orgRepo.AcceptInvite(ctx, invite, userID); err != nil {
+		return nil, err
+	}
+	return uc.orgRepo.GetMembership(ctx, userID)
+}
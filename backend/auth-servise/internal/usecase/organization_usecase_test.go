@@ -0,0 +1,269 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockOrganizationRepo struct {
+	mock.Mock
+}
+
+func (m *MockOrganizationRepo) Create(ctx context.Context, organization *entity.Organization, ownerID int64) error {
+	args := m.Called(ctx, organization, ownerID)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationRepo) GetByID(ctx context.Context, id int64) (*entity.Organization, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Organization), args.Error(1)
+}
+
+func (m *MockOrganizationRepo) Update(ctx context.Context, organization *entity.Organization) error {
+	args := m.Called(ctx, organization)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationRepo) GetMembership(ctx context.Context, userID int64) (*entity.OrganizationMember, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.OrganizationMember), args.Error(1)
+}
+
+func (m *MockOrganizationRepo) ListMembers(ctx context.Context, organizationID int64) ([]*entity.OrganizationMember, error) {
+	args := m.Called(ctx, organizationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.OrganizationMember), args.Error(1)
+}
+
+func (m *MockOrganizationRepo) SetMemberRole(ctx context.Context, organizationID, userID int64, role string) error {
+	args := m.Called(ctx, organizationID, userID, role)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationRepo) RemoveMember(ctx context.Context, organizationID, userID int64) error {
+	args := m.Called(ctx, organizationID, userID)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationRepo) CreateInvite(ctx context.Context, invite *entity.OrganizationInvite) error {
+	args := m.Called(ctx, invite)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationRepo) ListInvites(ctx context.Context, organizationID int64) ([]*entity.OrganizationInvite, error) {
+	args := m.Called(ctx, organizationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.OrganizationInvite), args.Error(1)
+}
+
+func (m *MockOrganizationRepo) GetInviteByToken(ctx context.Context, token string) (*entity.OrganizationInvite, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.OrganizationInvite), args.Error(1)
+}
+
+func (m *MockOrganizationRepo) DeleteInvite(ctx context.Context, organizationID, id int64) (bool, error) {
+	args := m.Called(ctx, organizationID, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockOrganizationRepo) AcceptInvite(ctx context.Context, invite *entity.OrganizationInvite, userID int64) error {
+	args := m.Called(ctx, invite, userID)
+	return args.Error(0)
+}
+
+// withMembers makes GetMembership return the given memberships.
+func (m *MockOrganizationRepo) withMembers(members ...*entity.OrganizationMember) *MockOrganizationRepo {
+	for _, member := range members {
+		m.On("GetMembership", mock.Anything, member.UserID).Return(member, nil).Maybe()
+	}
+	return m
+}
+
+func setupOrganizationTest() (*OrganizationUsecase, *MockOrganizationRepo, *MockMailer) {
+	repo := new(MockOrganizationRepo)
+	mailer := newMockMailer()
+	users := new(MockUserRepo).withUsers(
+		&entity.User{ID: 1, Email: "owner@acme.test", Role: string(entity.RoleEmployer)},
+		&entity.User{ID: 2, Email: "Recruiter@acme.test", Role: string(entity.RoleEmployer)},
+		&entity.User{ID: 3, Email: "other@acme.test", Role: string(entity.RoleEmployer)},
+		&entity.User{ID: 4, Email: "seeker@example.com", Role: string(entity.RoleJobseeker)},
+	)
+	return NewOrganizationUsecase(repo, users, mailer), repo, mailer
+}
+
+// setupOwnedOrganization registers organization 1 owned by user 1.
+func setupOwnedOrganization(repo *MockOrganizationRepo) *entity.Organization {
+	organization := &entity.Organization{ID: 1, Name: "Acme"}
+	repo.On("GetByID", mock.Anything, organization.ID).Return(organization, nil).Maybe()
+	repo.withMembers(&entity.OrganizationMember{OrganizationID: organization.ID, UserID: 1, Role: entity.OrganizationRoleOwner})
+	return organization
+}
+
+// inviteToken pulls the token out of the accept link in the invite email.
+func inviteToken(t *testing.T, body string) string {
+	_, link, found := strings.Cut(body, "token=")
+	require.True(t, found, "the email has the accept link")
+	return strings.Fields(link)[0]
+}
+
+func TestOrganizationInviteFlow(t *testing.T) {
+	uc, repo, mailer := setupOrganizationTest()
+	ctx := context.Background()
+
+	repo.On("GetMembership", mock.Anything, int64(1)).Return(nil, nil).Once()
+	repo.On("Create", mock.Anything, mock.AnythingOfType("*entity.Organization"), int64(1)).Return(nil).Once()
+	require.NoError(t, uc.Create(ctx, 1, &entity.Organization{Name: " Acme ", Size: "11-50"}))
+	setupOwnedOrganization(repo)
+	assert.ErrorIs(t, uc.Create(ctx, 1, &entity.Organization{Name: "Acme 2"}), entity.ErrAlreadyInOrganization)
+	assert.ErrorIs(t, uc.Create(ctx, 4, &entity.Organization{Name: "Acme"}), ErrPermissionDenied)
+	repo.AssertNumberOfCalls(t, "Create", 1)
+
+	invite := &entity.OrganizationInvite{Email: "recruiter@acme.test", Role: entity.OrganizationRoleRecruiter}
+	repo.On("CreateInvite", mock.Anything, invite).Return(nil).Once()
+	require.NoError(t, uc.Invite(ctx, 1, invite))
+	assert.Equal(t, int64(1), invite.OrganizationID)
+	assert.WithinDuration(t, time.Now().Add(entity.InviteTTL), invite.ExpiresAt, time.Minute)
+	require.Len(t, mailer.sent(), 1)
+	assert.Equal(t, []string{"recruiter@acme.test"}, mailer.sent()[0].To)
+	token := inviteToken(t, mailer.sent()[0].Body)
+	repo.On("GetInviteByToken", mock.Anything, token).Return(invite, nil)
+
+	_, err := uc.AcceptInvite(ctx, 3, token)
+	assert.ErrorIs(t, err, entity.ErrInviteNotFound, "the invite is bound to its address")
+
+	recruiter := &entity.OrganizationMember{OrganizationID: 1, UserID: 2, Role: entity.OrganizationRoleRecruiter}
+	repo.On("AcceptInvite", mock.Anything, invite, int64(2)).Return(nil).Run(func(args mock.Arguments) {
+		acceptedAt := time.Now()
+		args.Get(1).(*entity.OrganizationInvite).AcceptedAt = &acceptedAt
+	}).Once()
+	repo.withMembers(recruiter)
+	member, err := uc.AcceptInvite(ctx, 2, token)
+	require.NoError(t, err)
+	assert.Equal(t, entity.OrganizationRoleRecruiter, member.Role)
+	assert.Equal(t, int64(1), member.OrganizationID)
+
+	_, err = uc.AcceptInvite(ctx, 2, token)
+	assert.ErrorIs(t, err, entity.ErrInviteExpired, "an invite is accepted once")
+	repo.AssertNumberOfCalls(t, "AcceptInvite", 1)
+
+	err = uc.Invite(ctx, 2, &entity.OrganizationInvite{Email: "x@acme.test", Role: entity.OrganizationRoleViewer})
+	assert.ErrorIs(t, err, ErrPermissionDenied, "only owners invite")
+	repo.AssertExpectations(t)
+}
+
+func TestOrganizationInviteExpires(t *testing.T) {
+	uc, repo, mailer := setupOrganizationTest()
+	ctx := context.Background()
+	setupOwnedOrganization(repo)
+	invite := &entity.OrganizationInvite{Email: "other@acme.test", Role: entity.OrganizationRoleViewer}
+	repo.On("CreateInvite", mock.Anything, invite).Return(nil).Once()
+	require.NoError(t, uc.Invite(ctx, 1, invite))
+	token := inviteToken(t, mailer.sent()[0].Body)
+	repo.On("GetInviteByToken", mock.Anything, token).Return(invite, nil)
+
+	uc.now = func() time.Time { return time.Now().Add(entity.InviteTTL + time.Hour) }
+	_, err := uc.AcceptInvite(ctx, 3, token)
+	assert.ErrorIs(t, err, entity.ErrInviteExpired)
+	repo.AssertNotCalled(t, "AcceptInvite", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestOrganizationInviteRejectsOwnerRole(t *testing.T) {
+	uc, repo, mailer := setupOrganizationTest()
+	ctx := context.Background()
+	setupOwnedOrganization(repo)
+
+	err := uc.Invite(ctx, 1, &entity.OrganizationInvite{Email: "other@acme.test", Role: entity.OrganizationRoleOwner})
+	assert.ErrorIs(t, err, entity.ErrInvalidOrganization)
+	assert.Empty(t, mailer.sent())
+	repo.AssertNotCalled(t, "CreateInvite", mock.Anything, mock.Anything)
+}
+
+func TestVacancyAccessSharesOrganizationVacancies(t *testing.T) {
+	orgID := int64(1)
+	repo := new(MockOrganizationRepo).withMembers(
+		&entity.OrganizationMember{OrganizationID: orgID, UserID: 1, Role: entity.OrganizationRoleOwner},
+		&entity.OrganizationMember{OrganizationID: orgID, UserID: 2, Role: entity.OrganizationRoleRecruiter},
+		&entity.OrganizationMember{OrganizationID: orgID, UserID: 3, Role: entity.OrganizationRoleViewer},
+		&entity.OrganizationMember{OrganizationID: 2, UserID: 5, Role: entity.OrganizationRoleOwner},
+	)
+	access := &vacancyAccess{orgRepo: repo}
+	ctx := context.Background()
+
+	shared := &entity.Vacancy{EmployerID: 1, OrganizationID: &orgID}
+	private := &entity.Vacancy{EmployerID: 1}
+
+	for _, tc := range []struct {
+		name            string
+		userID          int64
+		vacancy         *entity.Vacancy
+		view, canManage bool
+	}{
+		{"owner", 1, private, true, true},
+		{"poster", 1, shared, true, true},
+		{"recruiter", 2, shared, true, true},
+		{"viewer", 3, shared, true, false},
+		{"other organization", 5, shared, false, false},
+		{"vacancy outside the organization", 2, private, false, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			view, err := access.canView(ctx, tc.userID, tc.vacancy)
+			require.NoError(t, err)
+			assert.Equal(t, tc.view, view)
+			manage, err := access.canManage(ctx, tc.userID, tc.vacancy)
+			require.NoError(t, err)
+			assert.Equal(t, tc.canManage, manage)
+		})
+	}
+}
+
+func TestVacancyAccessFollowsCurrentMembership(t *testing.T) {
+	orgID := int64(1)
+	repo := new(MockOrganizationRepo).withMembers(
+		&entity.OrganizationMember{OrganizationID: orgID, UserID: 1, Role: entity.OrganizationRoleOwner},
+	)
+	recruiter := &entity.OrganizationMember{OrganizationID: orgID, UserID: 2, Role: entity.OrganizationRoleRecruiter}
+	// Рекрутер состоит в организации на первых двух проверках, затем его удаляют
+	repo.On("GetMembership", mock.Anything, recruiter.UserID).Return(recruiter, nil).Twice()
+	repo.On("GetMembership", mock.Anything, recruiter.UserID).Return(nil, nil)
+	access := &vacancyAccess{orgRepo: repo}
+	ctx := context.Background()
+
+	// Вакансию разместил рекрутер, после вступления она принадлежит организации
+	vacancy := &entity.Vacancy{EmployerID: 2, OrganizationID: &orgID}
+	manage, err := access.canManage(ctx, 2, vacancy)
+	require.NoError(t, err)
+	assert.True(t, manage)
+
+	recruiter.Role = entity.OrganizationRoleViewer
+	manage, err = access.canManage(ctx, 2, vacancy)
+	require.NoError(t, err)
+	assert.False(t, manage, "downgraded member only views")
+
+	view, err := access.canView(ctx, 2, vacancy)
+	require.NoError(t, err)
+	assert.False(t, view, "removed member loses access to the vacancy they posted")
+
+	view, err = access.canView(ctx, 1, vacancy)
+	require.NoError(t, err)
+	assert.True(t, view)
+}
@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/repository"
+)
+
+type PipelineUsecaseInterface interface {
+	Create(ctx context.Context, employerID int64, pipeline *entity.Pipeline) error
+	List(ctx context.Context, employerID int64) ([]*entity.Pipeline, error)
+	Get(ctx context.Context, employerID, id int64) (*entity.Pipeline, error)
+	Update(ctx context.Context, employerID int64, pipeline *entity.Pipeline) error
+	Delete(ctx context.Context, employerID, id int64) error
+	AssignToVacancy(ctx context.Context, employerID, vacancyID int64, pipelineID *int64) error
+}
+
+// PipelineUsecase manages the employer's hiring pipeline templates and their
+// assignment to vacancies. Moving applications between stages is done by
+// ApplicationUsecase.
+type PipelineUsecase struct {
+	pipelineRepo repository.PipelineRepositoryInterface
+	vacancyRepo  repository.VacancyRepositoryInterface
+	userRepo     repository.UserRepositoryInterface
+	access       *vacancyAccess
+}
+
+func NewPipelineUsecase(
+	pipelineRepo repository.PipelineRepositoryInterface,
+	vacancyRepo repository.VacancyRepositoryInterface,
+	userRepo repository.UserRepositoryInterface,
+	orgRepo repository.OrganizationRepositoryInterface,
+) *PipelineUsecase {
+	return &PipelineUsecase{
+		pipelineRepo: pipelineRepo,
+		vacancyRepo:  vacancyRepo,
+		userRepo:     userRepo,
+		access:       &vacancyAccess{orgRepo: orgRepo},
+	}
+}
+
+func (uc *PipelineUsecase) Create(ctx context.Context, employerID int64, pipeline *entity.Pipeline) error {
+	user, err := uc.userRepo.GetByID(ctx, employerID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil || user.Role != string(entity.RoleEmployer) {
+		return ErrPermissionDenied
+	}
+
+	pipeline.ID = 0
+	pipeline.EmployerID = employerID
+	for _, stage := range pipeline.Stages {
+		stage.ID = 0
+	}
+	if err := pipeline.Validate(); err != nil {
+		return err
+	}
+
+	return uc.pipelineRepo.Create(ctx, pipeline)
+}
+
+func (uc *PipelineUsecase) List(ctx context.Context, employerID int64) ([]*entity.Pipeline, error) {
+	return uc.pipelineRepo.GetByEmployerID(ctx, employerID)
+}
+
+// Get returns the pipeline if it belongs to the employer.
+func (uc *PipelineUsecase) Get(ctx context.Context, employerID, id int64) (*entity.Pipeline, error) {
+	pipeline, err := uc.pipelineRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if pipeline == nil || pipeline.EmployerID != employerID {
+		return nil, entity.ErrPipelineNotFound
+	}
+	return pipeline, nil
+}
+
+func (uc *PipelineUsecase) Update(ctx context.Context, employerID int64, pipeline *entity.Pipeline) error {
+	existing, err := uc.Get(ctx, employerID, pipeline.ID)
+	if err != nil {
+		return err
+	}
+
+	pipeline.EmployerID = employerID
+	pipeline.CreatedAt = existing.CreatedAt
+	if err := pipeline.Validate(); err != nil {
+		return err
+	}
+
+	return uc.pipelineRepo.Update(ctx, pipeline)
+}
+
+func (uc *PipelineUsecase) Delete(ctx context.Context, employerID, id int64) error {
+	if _, err := uc.Get(ctx, employerID, id); err != nil {
+		return err
+	}
+	return uc.pipelineRepo.Delete(ctx, id)
+}
+
+// AssignToVacancy switches the vacancy to one of the employer's pipelines,
+// placing all of its applications on the first stage. Anyone who manages the
+// vacancy may do it. A nil pipelineID detaches the pipeline.
+func (uc *PipelineUsecase) AssignToVacancy(ctx context.Context, employerID, vacancyID int64, pipelineID *int64) error {
+	vacancy, err := uc.vacancyRepo.GetByID(ctx, vacancyID)
+	if err != nil {
+		return fmt.Errorf("failed to get vacancy: %w", err)
+	}
+	if vacancy == nil {
+		return entity.ErrVacancyNotFound
+	}
+	allowed, err := uc.access.canManage(ctx, employerID, vacancy)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return ErrPermissionDenied
+	}
+
+	if pipelineID != nil {
+		if _, err := uc.Get(ctx, employerID, *pipelineID); err != nil {
+			return err
+		}
+	}
+
+	return uc.pipelineRepo.AssignToVacancy(ctx, vacancyID, pipelineID)
+}
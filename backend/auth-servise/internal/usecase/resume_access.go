@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/repository"
+)
+
+// resumeAccess collects what entity.Resume.Access needs to know about the
+// relationship between a viewer and a resume owner.
+type resumeAccess struct {
+	userRepo        repository.UserRepositoryInterface
+	applicationRepo repository.ApplicationRepositoryInterface
+	privacyRepo     repository.ResumePrivacyRepositoryInterface
+	contactRepo     repository.ContactRequestRepositoryInterface
+}
+
+func (a *resumeAccess) viewer(ctx context.Context, viewerID int64, resume *entity.Resume) (entity.ResumeViewer, error) {
+	v := entity.ResumeViewer{UserID: viewerID}
+	if viewerID == resume.UserID {
+		return v, nil
+	}
+
+	user, err := a.userRepo.GetByID(ctx, viewerID)
+	if err != nil {
+		return v, err
+	}
+	if user == nil {
+		return v, ErrUserNotFound
+	}
+	v.Role = user.Role
+	v.Verified = user.IsVerified
+	if user.Role != string(entity.RoleEmployer) {
+		return v, nil
+	}
+
+	if v.ReceivedResume, err = a.applicationRepo.EmployerHasResume(ctx, viewerID, resume.ID); err != nil {
+		return v, err
+	}
+	if v.AppliedTo, err = a.applicationRepo.EmployerHasApplicant(ctx, viewerID, resume.UserID); err != nil {
+		return v, err
+	}
+	if v.Blocked, err = a.privacyRepo.IsEmployerBlocked(ctx, resume.UserID, viewerID); err != nil {
+		return v, err
+	}
+	if v.ContactAccepted, err = a.contactRepo.IsAccepted(ctx, viewerID, resume.UserID); err != nil {
+		return v, err
+	}
+	return v, nil
+}
+
+// check returns whether the viewer may see the resume and the owner's contacts.
+func (a *resumeAccess) check(ctx context.Context, viewerID int64, resume *entity.Resume) (visible, contact bool, err error) {
+	v, err := a.viewer(ctx, viewerID, resume)
+	if err != nil {
+		return false, false, err
+	}
+	visible, contact = resume.Access(v)
+	return visible, contact, nil
+}
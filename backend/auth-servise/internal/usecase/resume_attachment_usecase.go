@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/attachment"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/repository"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/storage"
+)
+
+type ResumeAttachmentUsecaseInterface interface {
+	Upload(ctx context.Context, userID, resumeID int64, fileName string, data []byte) (*entity.ResumeAttachment, error)
+	List(ctx context.Context, viewerID, resumeID int64) ([]*entity.ResumeAttachment, error)
+	Delete(ctx context.Context, userID, resumeID, id int64) error
+	Download(ctx context.Context, id int64, link attachment.Link) (*entity.ResumeAttachment, io.ReadCloser, error)
+}
+
+type ResumeAttachmentUsecase struct {
+	attachmentRepo repository.ResumeAttachmentRepositoryInterface
+	resumeRepo     repository.ResumeRepositoryInterface
+	store          storage.BlobStore
+	scanner        attachment.Scanner
+	signer         *attachment.Signer
+	access         *resumeAccess
+}
+
+func NewResumeAttachmentUsecase(
+	attachmentRepo repository.ResumeAttachmentRepositoryInterface,
+	resumeRepo repository.ResumeRepositoryInterface,
+	userRepo repository.UserRepositoryInterface,
+	applicationRepo repository.ApplicationRepositoryInterface,
+	privacyRepo repository.ResumePrivacyRepositoryInterface,
+	contactRepo repository.ContactRequestRepositoryInterface,
+	store storage.BlobStore,
+	scanner attachment.Scanner,
+	signer *attachment.Signer,
+) *ResumeAttachmentUsecase {
+	if scanner == nil {
+		scanner = attachment.NopScanner{}
+	}
+	return &ResumeAttachmentUsecase{
+		attachmentRepo: attachmentRepo,
+		resumeRepo:     resumeRepo,
+		store:          store,
+		scanner:        scanner,
+		signer:         signer,
+		access: &resumeAccess{
+			userRepo:        userRepo,
+			applicationRepo: applicationRepo,
+			privacyRepo:     privacyRepo,
+			contactRepo:     contactRepo,
+		},
+	}
+}
+
+// Upload validates, scans and stores a CV file for the owner's resume.
+func (uc *ResumeAttachmentUsecase) Upload(ctx context.Context, userID, resumeID int64, fileName string, data []byte) (*entity.ResumeAttachment, error) {
+	resume, err := uc.getResume(ctx, resumeID)
+	if err != nil {
+		return nil, err
+	}
+	if resume.UserID != userID {
+		return nil, ErrPermissionDenied
+	}
+
+	fileName = filepath.Base(strings.ReplaceAll(fileName, `\`, "/"))
+	contentType, err := attachment.DetectType(fileName, data)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.scanner.Scan(ctx, fileName, data); err != nil {
+		return nil, err
+	}
+
+	token, err := newPublicToken()
+	if err != nil {
+		return nil, err
+	}
+	item := &entity.ResumeAttachment{
+		ResumeID:    resumeID,
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		StorageKey:  fmt.Sprintf("resumes/%d/%s%s", resumeID, token, strings.ToLower(filepath.Ext(fileName))),
+	}
+
+	if err := uc.store.Put(ctx, item.StorageKey, bytes.NewReader(data), item.Size, contentType); err != nil {
+		return nil, err
+	}
+	if err := uc.attachmentRepo.Create(ctx, item); err != nil {
+		_ = uc.store.Delete(ctx, item.StorageKey)
+		return nil, err
+	}
+
+	item.DownloadURL = uc.signer.URL(item.ID, userID)
+	return item, nil
+}
+
+// List returns the attachments with download links for the viewer. A CV
+// file carries the candidate's contacts, so the viewer needs contact access.
+func (uc *ResumeAttachmentUsecase) List(ctx context.Context, viewerID, resumeID int64) ([]*entity.ResumeAttachment, error) {
+	resume, err := uc.getResume(ctx, resumeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.checkAccess(ctx, viewerID, resume); err != nil {
+		return nil, err
+	}
+
+	items, err := uc.attachmentRepo.GetByResumeID(ctx, resumeID)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		item.DownloadURL = uc.signer.URL(item.ID, viewerID)
+	}
+	return items, nil
+}
+
+func (uc *ResumeAttachmentUsecase) Delete(ctx context.Context, userID, resumeID, id int64) error {
+	resume, err := uc.getResume(ctx, resumeID)
+	if err != nil {
+		return err
+	}
+	if resume.UserID != userID {
+		return ErrPermissionDenied
+	}
+
+	item, err := uc.attachmentRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if item == nil || item.ResumeID != resumeID {
+		return entity.ErrAttachmentNotFound
+	}
+
+	if err := uc.attachmentRepo.Delete(ctx, resumeID, id); err != nil {
+		return err
+	}
+	return uc.store.Delete(ctx, item.StorageKey)
+}
+
+// Download checks the signed link and re-checks that the user it was issued
+// to still has access, so revoked access also revokes links already issued.
+func (uc *ResumeAttachmentUsecase) Download(ctx context.Context, id int64, link attachment.Link) (*entity.ResumeAttachment, io.ReadCloser, error) {
+	if err := uc.signer.Verify(id, link); err != nil {
+		return nil, nil, err
+	}
+
+	item, err := uc.attachmentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if item == nil {
+		return nil, nil, entity.ErrAttachmentNotFound
+	}
+
+	resume, err := uc.getResume(ctx, item.ResumeID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := uc.checkAccess(ctx, link.ViewerID, resume); err != nil {
+		return nil, nil, err
+	}
+
+	body, err := uc.store.Get(ctx, item.StorageKey)
+	if err == storage.ErrBlobNotFound {
+		return nil, nil, entity.ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return item, body, nil
+}
+
+func (uc *ResumeAttachmentUsecase) getResume(ctx context.Context, resumeID int64) (*entity.Resume, error) {
+	resume, err := uc.resumeRepo.GetResumeByID(ctx, resumeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resume: %w", err)
+	}
+	if resume == nil {
+		return nil, ErrResumeNotFound
+	}
+	return resume, nil
+}
+
+func (uc *ResumeAttachmentUsecase) checkAccess(ctx context.Context, viewerID int64, resume *entity.Resume) error {
+	visible, contact, err := uc.access.check(ctx, viewerID, resume)
+	if err != nil {
+		return err
+	}
+	if !visible {
+		return ErrResumeNotFound
+	}
+	if !contact {
+		return ErrPermissionDenied
+	}
+	return nil
+}
@@ -2,12 +2,23 @@ package usecase
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
 	"github.com/Mandarinka0707/newRepoGOODarhit/internal/repository"
 )
 
+var (
+	ErrResumeNotFound      = errors.New("resume not found")
+	ErrInvalidVisibility   = errors.New("invalid resume visibility")
+	ErrEmployerNotFound    = errors.New("employer not found")
+	ErrEmployerNotVerified = errors.New("employer is not verified")
+)
+
 type ResumeUsecaseInterface interface {
 	CreateResume(ctx context.Context, resume *entity.Resume) error
 	GetResume(ctx context.Context, id int64) (*entity.Resume, error)
@@ -16,20 +27,64 @@ type ResumeUsecaseInterface interface {
 	DeleteResume(ctx context.Context, id int64) error
 	GetAllResumes(ctx context.Context) ([]*entity.Resume, error)
 	Delete(ctx context.Context, id int64) error
+	RemoveByModerator(ctx context.Context, adminID, id int64) error
 	GetAll(ctx context.Context) ([]*entity.Resume, error)
+	SearchCandidates(ctx context.Context, employerID int64, filter entity.ResumeSearchFilter, limit, offset int) ([]*entity.Resume, int, error)
+	GetResumeForExport(ctx context.Context, viewerID, resumeID int64) (*entity.Resume, *entity.User, error)
+	ViewResume(ctx context.Context, viewerID, resumeID int64) (*entity.Resume, error)
+	GetPublicResume(ctx context.Context, token string) (*entity.Resume, error)
+	SetVisibility(ctx context.Context, userID, resumeID int64, visibility string) (*entity.Resume, error)
+	GetBlockedEmployers(ctx context.Context, userID int64) ([]*entity.BlockedEmployer, error)
+	BlockEmployer(ctx context.Context, userID, employerID int64) error
+	UnblockEmployer(ctx context.Context, userID, employerID int64) error
+	ImportResume(ctx context.Context, userID int64, resume *entity.Resume) error
+
+	AddWorkExperience(ctx context.Context, userID int64, item *entity.WorkExperience) error
+	UpdateWorkExperience(ctx context.Context, userID int64, item *entity.WorkExperience) error
+	DeleteWorkExperience(ctx context.Context, userID, resumeID, id int64) error
+	AddEducation(ctx context.Context, userID int64, item *entity.EducationEntry) error
+	UpdateEducation(ctx context.Context, userID int64, item *entity.EducationEntry) error
+	DeleteEducation(ctx context.Context, userID, resumeID, id int64) error
+	AddLanguage(ctx context.Context, userID int64, item *entity.LanguageSkill) error
+	UpdateLanguage(ctx context.Context, userID int64, item *entity.LanguageSkill) error
+	DeleteLanguage(ctx context.Context, userID, resumeID, id int64) error
+	AddCertification(ctx context.Context, userID int64, item *entity.Certification) error
+	UpdateCertification(ctx context.Context, userID int64, item *entity.Certification) error
+	DeleteCertification(ctx context.Context, userID, resumeID, id int64) error
 }
 
 type ResumeUsecase struct {
 	resumeRepo      repository.ResumeRepositoryInterface
+	sectionRepo     repository.ResumeSectionRepositoryInterface
 	userRepo        repository.UserRepositoryInterface
 	applicationRepo repository.ApplicationRepositoryInterface
+	privacyRepo     repository.ResumePrivacyRepositoryInterface
+	auditor         Auditor
+	access          *resumeAccess
 }
 
-func NewResumeUsecase(resumeRepo repository.ResumeRepositoryInterface, userRepo repository.UserRepositoryInterface, applicationRepo repository.ApplicationRepositoryInterface) *ResumeUsecase {
+func NewResumeUsecase(
+	resumeRepo repository.ResumeRepositoryInterface,
+	sectionRepo repository.ResumeSectionRepositoryInterface,
+	userRepo repository.UserRepositoryInterface,
+	applicationRepo repository.ApplicationRepositoryInterface,
+	privacyRepo repository.ResumePrivacyRepositoryInterface,
+	contactRepo repository.ContactRequestRepositoryInterface,
+	auditor Auditor,
+) *ResumeUsecase {
 	return &ResumeUsecase{
 		resumeRepo:      resumeRepo,
+		sectionRepo:     sectionRepo,
 		userRepo:        userRepo,
 		applicationRepo: applicationRepo,
+		privacyRepo:     privacyRepo,
+		auditor:         auditor,
+		access: &resumeAccess{
+			userRepo:        userRepo,
+			applicationRepo: applicationRepo,
+			privacyRepo:     privacyRepo,
+			contactRepo:     contactRepo,
+		},
 	}
 }
 
@@ -43,6 +98,12 @@ func (uc *ResumeUsecase) CreateResume(ctx context.Context, resume *entity.Resume
 		return fmt.Errorf("user not found")
 	}
 
+	if resume.Visibility == entity.VisibilityPublic && resume.PublicToken == "" {
+		if resume.PublicToken, err = newPublicToken(); err != nil {
+			return err
+		}
+	}
+
 	return uc.resumeRepo.Create(ctx, resume)
 }
 
@@ -57,6 +118,9 @@ func (uc *ResumeUsecase) GetResume(ctx context.Context, id int64) (*entity.Resum
 		fmt.Printf("Usecase: Resume not found with ID: %d\n", id)
 		return nil, nil
 	}
+	if err := uc.loadSections(ctx, resume); err != nil {
+		return nil, err
+	}
 	fmt.Printf("Usecase: Successfully got resume: %+v\n", resume)
 	return resume, nil
 }
@@ -87,6 +151,379 @@ func (u *ResumeUsecase) Delete(ctx context.Context, id int64) error {
 	return u.resumeRepo.Delete(ctx, id)
 }
 
+// RemoveByModerator deletes a resume that breaks the rules and records it in
+// the audit log.
+func (u *ResumeUsecase) RemoveByModerator(ctx context.Context, adminID, id int64) error {
+	admin, err := u.userRepo.GetByID(ctx, adminID)
+	if err != nil {
+		return err
+	}
+	if admin == nil || admin.Role != string(entity.RoleAdmin) {
+		return ErrPermissionDenied
+	}
+
+	resume, err := u.resumeRepo.GetResumeByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if resume == nil {
+		return ErrResumeNotFound
+	}
+	if err := u.auditor.Record(ctx, auditEntry(adminID, entity.AuditResumeDelete, entity.AuditTargetResume, id, resume, nil)); err != nil {
+		return err
+	}
+	return u.resumeRepo.Delete(ctx, id)
+}
+
 func (uc *ResumeUsecase) GetAll(ctx context.Context) ([]*entity.Resume, error) {
 	return uc.GetAllResumes(ctx)
 }
+
+// SearchCandidates lets verified employers look through active resumes they
+// are allowed to see. Contacts are never part of the results.
+func (uc *ResumeUsecase) SearchCandidates(ctx context.Context, employerID int64, filter entity.ResumeSearchFilter, limit, offset int) ([]*entity.Resume, int, error) {
+	user, err := uc.userRepo.GetByID(ctx, employerID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil || user.Role != string(entity.RoleEmployer) {
+		return nil, 0, ErrPermissionDenied
+	}
+	if !user.IsVerified {
+		return nil, 0, ErrEmployerNotVerified
+	}
+
+	viewer := entity.ResumeViewer{UserID: user.ID, Role: user.Role, Verified: user.IsVerified}
+	resumes, total, err := uc.resumeRepo.Search(ctx, viewer, filter, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, resume := range resumes {
+		resume.PublicToken = ""
+	}
+	return resumes, total, nil
+}
+
+// GetResumeForExport returns the full resume together with its owner to
+// the owner, admins and employers the resume was sent to. Anybody else gets
+// ErrResumeNotFound, even when the resume is visible to them.
+func (uc *ResumeUsecase) GetResumeForExport(ctx context.Context, viewerID, resumeID int64) (*entity.Resume, *entity.User, error) {
+	return uc.resumeFor(ctx, viewerID, resumeID, true)
+}
+
+// resumeFor returns the resume with its sections as seen by viewerID. The
+// owner is nil when the viewer may see the resume but not the contacts.
+func (uc *ResumeUsecase) resumeFor(ctx context.Context, viewerID, resumeID int64, export bool) (*entity.Resume, *entity.User, error) {
+	resume, err := uc.resumeRepo.GetResumeByID(ctx, resumeID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get resume: %w", err)
+	}
+	if resume == nil {
+		return nil, nil, ErrResumeNotFound
+	}
+
+	v, err := uc.access.viewer(ctx, viewerID, resume)
+	if err != nil {
+		return nil, nil, err
+	}
+	visible, contact := resume.Access(v)
+	if !visible || (export && !resume.CanExport(v)) {
+		return nil, nil, ErrResumeNotFound
+	}
+
+	if err := uc.loadSections(ctx, resume); err != nil {
+		return nil, nil, err
+	}
+	if !contact {
+		return resume, nil, nil
+	}
+
+	owner, err := uc.userRepo.GetByID(ctx, resume.UserID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get resume owner: %w", err)
+	}
+
+	return resume, owner, nil
+}
+
+// ViewResume returns the resume as seen by viewerID. Resumes the viewer may
+// not see are reported as not found, and contacts are attached only when the
+// viewer is allowed to reach the candidate.
+func (uc *ResumeUsecase) ViewResume(ctx context.Context, viewerID, resumeID int64) (*entity.Resume, error) {
+	resume, owner, err := uc.resumeFor(ctx, viewerID, resumeID, false)
+	if err != nil {
+		return nil, err
+	}
+	if owner != nil {
+		resume.Contact = &entity.ResumeContact{Name: owner.Name, Email: owner.Email}
+	}
+	if resume.UserID != viewerID {
+		resume.PublicToken = ""
+	}
+	return resume, nil
+}
+
+// GetPublicResume returns the resume behind a public link without contacts.
+func (uc *ResumeUsecase) GetPublicResume(ctx context.Context, token string) (*entity.Resume, error) {
+	resume, err := uc.resumeRepo.GetByPublicToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if resume == nil {
+		return nil, ErrResumeNotFound
+	}
+	if err := uc.loadSections(ctx, resume); err != nil {
+		return nil, err
+	}
+	resume.PublicToken = ""
+	return resume, nil
+}
+
+// SetVisibility changes who can see the resume. Switching to public issues
+// a link token; switching away from public revokes it.
+func (uc *ResumeUsecase) SetVisibility(ctx context.Context, userID, resumeID int64, visibility string) (*entity.Resume, error) {
+	if !entity.IsValidVisibility(visibility) {
+		return nil, ErrInvalidVisibility
+	}
+
+	resume, err := uc.resumeRepo.GetResumeByID(ctx, resumeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resume: %w", err)
+	}
+	if resume == nil {
+		return nil, ErrResumeNotFound
+	}
+	if resume.UserID != userID {
+		return nil, ErrPermissionDenied
+	}
+
+	token := ""
+	if visibility == entity.VisibilityPublic {
+		token = resume.PublicToken
+		if token == "" {
+			if token, err = newPublicToken(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := uc.resumeRepo.UpdateVisibility(ctx, resumeID, visibility, token); err != nil {
+		return nil, err
+	}
+	resume.Visibility = visibility
+	resume.PublicToken = token
+	return resume, nil
+}
+
+func newPublicToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate public link: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func (uc *ResumeUsecase) GetBlockedEmployers(ctx context.Context, userID int64) ([]*entity.BlockedEmployer, error) {
+	return uc.privacyRepo.GetBlockedEmployers(ctx, userID)
+}
+
+// BlockEmployer hides all of the user's resumes from the employer, e.g. a
+// current employer. Applications already sent stay visible to it.
+func (uc *ResumeUsecase) BlockEmployer(ctx context.Context, userID, employerID int64) error {
+	employer, err := uc.userRepo.GetByID(ctx, employerID)
+	if err != nil {
+		return fmt.Errorf("failed to get employer: %w", err)
+	}
+	if employer == nil || employer.Role != string(entity.RoleEmployer) {
+		return ErrEmployerNotFound
+	}
+	return uc.privacyRepo.BlockEmployer(ctx, userID, employerID)
+}
+
+func (uc *ResumeUsecase) UnblockEmployer(ctx context.Context, userID, employerID int64) error {
+	return uc.privacyRepo.UnblockEmployer(ctx, userID, employerID)
+}
+
+// ImportResume saves a resume produced by the resumeimport package together
+// with all of its sections. If a section cannot be stored the half-imported
+// resume is removed again.
+func (uc *ResumeUsecase) ImportResume(ctx context.Context, userID int64, resume *entity.Resume) error {
+	resume.UserID = userID
+	if resume.Status == "" {
+		resume.Status = "active"
+	}
+	if err := uc.CreateResume(ctx, resume); err != nil {
+		return err
+	}
+
+	if err := uc.createSections(ctx, resume); err != nil {
+		if delErr := uc.resumeRepo.Delete(ctx, resume.ID); delErr != nil {
+			return fmt.Errorf("%w (cleanup failed: %v)", err, delErr)
+		}
+		return err
+	}
+
+	return uc.touchResume(ctx, resume.ID)
+}
+
+func (uc *ResumeUsecase) createSections(ctx context.Context, resume *entity.Resume) error {
+	for _, item := range resume.WorkExperience {
+		item.ResumeID = resume.ID
+		if err := uc.sectionRepo.CreateWorkExperience(ctx, item); err != nil {
+			return err
+		}
+	}
+	for _, item := range resume.Education {
+		item.ResumeID = resume.ID
+		if err := uc.sectionRepo.CreateEducation(ctx, item); err != nil {
+			return err
+		}
+	}
+	for _, item := range resume.Languages {
+		item.ResumeID = resume.ID
+		if err := uc.sectionRepo.CreateLanguage(ctx, item); err != nil {
+			return err
+		}
+	}
+	for _, item := range resume.Certifications {
+		item.ResumeID = resume.ID
+		if err := uc.sectionRepo.CreateCertification(ctx, item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadSections attaches the structured sections to the resume and computes
+// the total length of work history.
+func (uc *ResumeUsecase) loadSections(ctx context.Context, resume *entity.Resume) error {
+	var err error
+	if resume.WorkExperience, err = uc.sectionRepo.GetWorkExperiences(ctx, resume.ID); err != nil {
+		return err
+	}
+	if resume.Education, err = uc.sectionRepo.GetEducation(ctx, resume.ID); err != nil {
+		return err
+	}
+	if resume.Languages, err = uc.sectionRepo.GetLanguages(ctx, resume.ID); err != nil {
+		return err
+	}
+	if resume.Certifications, err = uc.sectionRepo.GetCertifications(ctx, resume.ID); err != nil {
+		return err
+	}
+	resume.TotalExperienceYears = entity.TotalExperienceYears(resume.WorkExperience, time.Now())
+	return nil
+}
+
+// touchResume bumps the resume's update time after a change to its work
+// history, so that candidate search sees it as recently updated.
+func (uc *ResumeUsecase) touchResume(ctx context.Context, resumeID int64) error {
+	return uc.resumeRepo.Touch(ctx, resumeID)
+}
+
+// checkOwner makes sure the resume exists and belongs to the user.
+func (uc *ResumeUsecase) checkOwner(ctx context.Context, resumeID, userID int64) error {
+	resume, err := uc.resumeRepo.GetResumeByID(ctx, resumeID)
+	if err != nil {
+		return fmt.Errorf("failed to get resume: %w", err)
+	}
+	if resume == nil {
+		return ErrResumeNotFound
+	}
+	if resume.UserID != userID {
+		return ErrPermissionDenied
+	}
+	return nil
+}
+
+func (uc *ResumeUsecase) AddWorkExperience(ctx context.Context, userID int64, item *entity.WorkExperience) error {
+	if err := uc.checkOwner(ctx, item.ResumeID, userID); err != nil {
+		return err
+	}
+	if err := uc.sectionRepo.CreateWorkExperience(ctx, item); err != nil {
+		return err
+	}
+	return uc.touchResume(ctx, item.ResumeID)
+}
+
+func (uc *ResumeUsecase) UpdateWorkExperience(ctx context.Context, userID int64, item *entity.WorkExperience) error {
+	if err := uc.checkOwner(ctx, item.ResumeID, userID); err != nil {
+		return err
+	}
+	if err := uc.sectionRepo.UpdateWorkExperience(ctx, item); err != nil {
+		return err
+	}
+	return uc.touchResume(ctx, item.ResumeID)
+}
+
+func (uc *ResumeUsecase) DeleteWorkExperience(ctx context.Context, userID, resumeID, id int64) error {
+	if err := uc.checkOwner(ctx, resumeID, userID); err != nil {
+		return err
+	}
+	if err := uc.sectionRepo.DeleteWorkExperience(ctx, resumeID, id); err != nil {
+		return err
+	}
+	return uc.touchResume(ctx, resumeID)
+}
+
+func (uc *ResumeUsecase) AddEducation(ctx context.Context, userID int64, item *entity.EducationEntry) error {
+	if err := uc.checkOwner(ctx, item.ResumeID, userID); err != nil {
+		return err
+	}
+	return uc.sectionRepo.CreateEducation(ctx, item)
+}
+
+func (uc *ResumeUsecase) UpdateEducation(ctx context.Context, userID int64, item *entity.EducationEntry) error {
+	if err := uc.checkOwner(ctx, item.ResumeID, userID); err != nil {
+		return err
+	}
+	return uc.sectionRepo.UpdateEducation(ctx, item)
+}
+
+func (uc *ResumeUsecase) DeleteEducation(ctx context.Context, userID, resumeID, id int64) error {
+	if err := uc.checkOwner(ctx, resumeID, userID); err != nil {
+		return err
+	}
+	return uc.sectionRepo.DeleteEducation(ctx, resumeID, id)
+}
+
+func (uc *ResumeUsecase) AddLanguage(ctx context.Context, userID int64, item *entity.LanguageSkill) error {
+	if err := uc.checkOwner(ctx, item.ResumeID, userID); err != nil {
+		return err
+	}
+	return uc.sectionRepo.CreateLanguage(ctx, item)
+}
+
+func (uc *ResumeUsecase) UpdateLanguage(ctx context.Context, userID int64, item *entity.LanguageSkill) error {
+	if err := uc.checkOwner(ctx, item.ResumeID, userID); err != nil {
+		return err
+	}
+	return uc.sectionRepo.UpdateLanguage(ctx, item)
+}
+
+func (uc *ResumeUsecase) DeleteLanguage(ctx context.Context, userID, resumeID, id int64) error {
+	if err := uc.checkOwner(ctx, resumeID, userID); err != nil {
+		return err
+	}
+	return uc.sectionRepo.DeleteLanguage(ctx, resumeID, id)
+}
+
+func (uc *ResumeUsecase) AddCertification(ctx context.Context, userID int64, item *entity.Certification) error {
+	if err := uc.checkOwner(ctx, item.ResumeID, userID); err != nil {
+		return err
+	}
+	return uc.sectionRepo.CreateCertification(ctx, item)
+}
+
+func (uc *ResumeUsecase) UpdateCertification(ctx context.Context, userID int64, item *entity.Certification) error {
+	if err := uc.checkOwner(ctx, item.ResumeID, userID); err != nil {
+		return err
+	}
+	return uc.sectionRepo.UpdateCertification(ctx, item)
+}
+
+func (uc *ResumeUsecase) DeleteCertification(ctx context.Context, userID, resumeID, id int64) error {
+	if err := uc.checkOwner(ctx, resumeID, userID); err != nil {
+		return err
+	}
+	return uc.sectionRepo.DeleteCertification(ctx, resumeID, id)
+}
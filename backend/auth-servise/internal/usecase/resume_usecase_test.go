@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockResumeRepo struct {
+	repository.ResumeRepositoryInterface
+	mock.Mock
+}
+
+func (m *MockResumeRepo) GetResumeByID(ctx context.Context, id int64) (*entity.Resume, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Resume), args.Error(1)
+}
+
+type MockResumeSectionRepo struct {
+	repository.ResumeSectionRepositoryInterface
+	mock.Mock
+}
+
+func (m *MockResumeSectionRepo) GetWorkExperiences(ctx context.Context, resumeID int64) ([]*entity.WorkExperience, error) {
+	args := m.Called(ctx, resumeID)
+	items, _ := args.Get(0).([]*entity.WorkExperience)
+	return items, args.Error(1)
+}
+
+func (m *MockResumeSectionRepo) GetEducation(ctx context.Context, resumeID int64) ([]*entity.EducationEntry, error) {
+	args := m.Called(ctx, resumeID)
+	items, _ := args.Get(0).([]*entity.EducationEntry)
+	return items, args.Error(1)
+}
+
+func (m *MockResumeSectionRepo) GetLanguages(ctx context.Context, resumeID int64) ([]*entity.LanguageSkill, error) {
+	args := m.Called(ctx, resumeID)
+	items, _ := args.Get(0).([]*entity.LanguageSkill)
+	return items, args.Error(1)
+}
+
+func (m *MockResumeSectionRepo) GetCertifications(ctx context.Context, resumeID int64) ([]*entity.Certification, error) {
+	args := m.Called(ctx, resumeID)
+	items, _ := args.Get(0).([]*entity.Certification)
+	return items, args.Error(1)
+}
+
+// withEmptySections makes every resume come without sections.
+func (m *MockResumeSectionRepo) withEmptySections() *MockResumeSectionRepo {
+	for _, method := range []string{"GetWorkExperiences", "GetEducation", "GetLanguages", "GetCertifications"} {
+		m.On(method, mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	}
+	return m
+}
+
+type MockApplicationRepo struct {
+	repository.ApplicationRepositoryInterface
+	mock.Mock
+}
+
+func (m *MockApplicationRepo) EmployerHasResume(ctx context.Context, employerID, resumeID int64) (bool, error) {
+	args := m.Called(ctx, employerID, resumeID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockApplicationRepo) EmployerHasApplicant(ctx context.Context, employerID, userID int64) (bool, error) {
+	args := m.Called(ctx, employerID, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+type MockResumePrivacyRepo struct {
+	repository.ResumePrivacyRepositoryInterface
+	mock.Mock
+}
+
+func (m *MockResumePrivacyRepo) IsEmployerBlocked(ctx context.Context, userID, employerID int64) (bool, error) {
+	args := m.Called(ctx, userID, employerID)
+	return args.Bool(0), args.Error(1)
+}
+
+type MockContactRequestRepo struct {
+	repository.ContactRequestRepositoryInterface
+	mock.Mock
+}
+
+func (m *MockContactRequestRepo) IsAccepted(ctx context.Context, employerID, jobseekerID int64) (bool, error) {
+	args := m.Called(ctx, employerID, jobseekerID)
+	return args.Bool(0), args.Error(1)
+}
+
+func TestResumeExportNeedsTheResume(t *testing.T) {
+	ctx := context.Background()
+	owner := &entity.User{ID: 10, Name: "Анна", Email: "anna@example.com", Role: string(entity.RoleJobseeker)}
+	employer := &entity.User{ID: 20, Role: string(entity.RoleEmployer), IsVerified: true}
+	admin := &entity.User{ID: 30, Role: string(entity.RoleAdmin)}
+	resume := &entity.Resume{ID: 5, UserID: owner.ID, Visibility: entity.VisibilityEmployers}
+
+	resumes := new(MockResumeRepo)
+	resumes.On("GetResumeByID", mock.Anything, resume.ID).Return(resume, nil)
+	applications := new(MockApplicationRepo)
+	applications.On("EmployerHasResume", mock.Anything, employer.ID, resume.ID).Return(false, nil).Twice()
+	applications.On("EmployerHasResume", mock.Anything, employer.ID, resume.ID).Return(true, nil)
+	applications.On("EmployerHasApplicant", mock.Anything, employer.ID, owner.ID).Return(false, nil)
+	privacy := new(MockResumePrivacyRepo)
+	privacy.On("IsEmployerBlocked", mock.Anything, owner.ID, employer.ID).Return(false, nil)
+	contacts := new(MockContactRequestRepo)
+	contacts.On("IsAccepted", mock.Anything, employer.ID, owner.ID).Return(false, nil)
+
+	uc := NewResumeUsecase(resumes, new(MockResumeSectionRepo).withEmptySections(), new(MockUserRepo).withUsers(owner, employer, admin),
+		applications, privacy, contacts, NopAuditor{})
+
+	_, _, err := uc.GetResumeForExport(ctx, employer.ID, resume.ID)
+	assert.ErrorIs(t, err, ErrResumeNotFound, "a visible resume is not enough to download it")
+	viewed, err := uc.ViewResume(ctx, employer.ID, resume.ID)
+	require.NoError(t, err)
+	assert.Nil(t, viewed.Contact, "contacts stay hidden")
+
+	exported, exportedOwner, err := uc.GetResumeForExport(ctx, employer.ID, resume.ID)
+	require.NoError(t, err, "the employer received the resume in an application")
+	assert.Equal(t, resume.ID, exported.ID)
+	assert.Equal(t, owner, exportedOwner)
+
+	for _, viewerID := range []int64{owner.ID, admin.ID} {
+		_, _, err := uc.GetResumeForExport(ctx, viewerID, resume.ID)
+		assert.NoError(t, err)
+	}
+	applications.AssertExpectations(t)
+}
@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/repository"
+)
+
+type ScreeningUsecaseInterface interface {
+	GetQuestions(ctx context.Context, userID, vacancyID int64) ([]*entity.ScreeningQuestion, error)
+	SetQuestions(ctx context.Context, userID, vacancyID int64, questions []*entity.ScreeningQuestion) ([]*entity.ScreeningQuestion, error)
+}
+
+// ScreeningUsecase manages the screening questions of vacancies.
+type ScreeningUsecase struct {
+	screeningRepo repository.ScreeningRepositoryInterface
+	vacancyRepo   repository.VacancyRepositoryInterface
+	access        *vacancyAccess
+}
+
+func NewScreeningUsecase(
+	screeningRepo repository.ScreeningRepositoryInterface,
+	vacancyRepo repository.VacancyRepositoryInterface,
+	orgRepo repository.OrganizationRepositoryInterface,
+) *ScreeningUsecase {
+	return &ScreeningUsecase{
+		screeningRepo: screeningRepo,
+		vacancyRepo:   vacancyRepo,
+		access:        &vacancyAccess{orgRepo: orgRepo},
+	}
+}
+
+// GetQuestions returns the questions of the vacancy. Only the vacancy owner
+// and the members of its organization see the knock-out rules.
+func (uc *ScreeningUsecase) GetQuestions(ctx context.Context, userID, vacancyID int64) ([]*entity.ScreeningQuestion, error) {
+	vacancy, err := uc.vacancyRepo.GetByID(ctx, vacancyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vacancy: %w", err)
+	}
+	if vacancy == nil {
+		return nil, entity.ErrVacancyNotFound
+	}
+
+	questions, err := uc.screeningRepo.GetQuestions(ctx, vacancyID)
+	if err != nil {
+		return nil, err
+	}
+	allowed, err := uc.access.canView(ctx, userID, vacancy)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		for i, question := range questions {
+			questions[i] = question.PublicView()
+		}
+	}
+	return questions, nil
+}
+
+// SetQuestions replaces the questions of the employer's vacancy.
+func (uc *ScreeningUsecase) SetQuestions(ctx context.Context, userID, vacancyID int64, questions []*entity.ScreeningQuestion) ([]*entity.ScreeningQuestion, error) {
+	if err := entity.ValidateQuestions(questions); err != nil {
+		return nil, err
+	}
+
+	vacancy, err := uc.vacancyRepo.GetByID(ctx, vacancyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vacancy: %w", err)
+	}
+	if vacancy == nil {
+		return nil, entity.ErrVacancyNotFound
+	}
+	allowed, err := uc.access.canManage(ctx, userID, vacancy)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, ErrPermissionDenied
+	}
+
+	if err := uc.screeningRepo.ReplaceQuestions(ctx, vacancyID, questions); err != nil {
+		return nil, err
+	}
+	return questions, nil
+}
@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/repository"
+)
+
+type StatsUsecaseInterface interface {
+	GetStats(ctx context.Context, adminID int64) (*entity.UserStats, error)
+	GetSeries(ctx context.Context, adminID int64, r entity.StatsRange) (*entity.StatsSeries, error)
+}
+
+// StatsUsecase serves the admin dashboard. The aggregates scan whole tables,
+// so results are cached for a short time and shared between admins.
+type StatsUsecase struct {
+	statsRepo repository.StatsRepositoryInterface
+	userRepo  repository.UserRepositoryInterface
+	cache     *statsCache
+}
+
+func NewStatsUsecase(
+	statsRepo repository.StatsRepositoryInterface,
+	userRepo repository.UserRepositoryInterface,
+	ttl time.Duration,
+) *StatsUsecase {
+	return &StatsUsecase{
+		statsRepo: statsRepo,
+		userRepo:  userRepo,
+		cache:     newStatsCache(ttl),
+	}
+}
+
+func (uc *StatsUsecase) requireAdmin(ctx context.Context, adminID int64) error {
+	admin, err := uc.userRepo.GetByID(ctx, adminID)
+	if err != nil {
+		return err
+	}
+	if admin == nil || admin.Role != string(entity.RoleAdmin) {
+		return ErrPermissionDenied
+	}
+	return nil
+}
+
+// GetStats returns the platform totals with breakdowns by role and status.
+func (uc *StatsUsecase) GetStats(ctx context.Context, adminID int64) (*entity.UserStats, error) {
+	if err := uc.requireAdmin(ctx, adminID); err != nil {
+		return nil, err
+	}
+	if cached, ok := uc.cache.get("totals"); ok {
+		return cached.(*entity.UserStats), nil
+	}
+
+	stats, err := uc.statsRepo.Totals(ctx)
+	if err != nil {
+		return nil, err
+	}
+	uc.cache.set("totals", stats)
+	return stats, nil
+}
+
+// GetSeries returns registrations, vacancies and applications per day or
+// week of the range.
+func (uc *StatsUsecase) GetSeries(ctx context.Context, adminID int64, r entity.StatsRange) (*entity.StatsSeries, error) {
+	if err := uc.requireAdmin(ctx, adminID); err != nil {
+		return nil, err
+	}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("series:%s:%s:%s", r.Interval, r.From.Format(time.DateOnly), r.To.Format(time.DateOnly))
+	if cached, ok := uc.cache.get(key); ok {
+		return cached.(*entity.StatsSeries), nil
+	}
+
+	points, err := uc.statsRepo.Series(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	series := &entity.StatsSeries{StatsRange: r, Points: points}
+	uc.cache.set(key, series)
+	return series, nil
+}
+
+type statsCacheEntry struct {
+	value   any
+	expires time.Time
+}
+
+type statsCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	now     func() time.Time
+	entries map[string]statsCacheEntry
+}
+
+func newStatsCache(ttl time.Duration) *statsCache {
+	return &statsCache{ttl: ttl, now: time.Now, entries: map[string]statsCacheEntry{}}
+}
+
+func (c *statsCache) get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || !c.now().Before(entry.expires) {
+		return nil, false
+	}
+	return entry.value, true
+}
+
+func (c *statsCache) set(key string, value any) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Просроченные записи удаляются при записи, чтобы разные диапазоны
+	// не копились в памяти
+	now := c.now()
+	for k, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = statsCacheEntry{value: value, expires: now.Add(c.ttl)}
+}
@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockStatsRepo struct {
+	mock.Mock
+}
+
+func (m *MockStatsRepo) Totals(ctx context.Context) (*entity.UserStats, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.UserStats), args.Error(1)
+}
+
+func (m *MockStatsRepo) Series(ctx context.Context, rng entity.StatsRange) ([]*entity.StatsPoint, error) {
+	args := m.Called(ctx, rng)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.StatsPoint), args.Error(1)
+}
+
+func setupStatsTest() (*StatsUsecase, *MockStatsRepo, *time.Time) {
+	statsRepo := new(MockStatsRepo)
+	userRepo := new(MockUserRepo).withUsers(
+		&entity.User{ID: 1, Role: string(entity.RoleAdmin)},
+		&entity.User{ID: 2, Role: string(entity.RoleEmployer)},
+	)
+	uc := NewStatsUsecase(statsRepo, userRepo, time.Minute)
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	uc.cache.now = func() time.Time { return now }
+	return uc, statsRepo, &now
+}
+
+// interval matches a stats range by its interval.
+func interval(name string) interface{} {
+	return mock.MatchedBy(func(rng entity.StatsRange) bool { return rng.Interval == name })
+}
+
+func TestStatsTotalsAreCached(t *testing.T) {
+	uc, statsRepo, now := setupStatsTest()
+	ctx := context.Background()
+
+	_, err := uc.GetStats(ctx, 2)
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+
+	statsRepo.On("Totals", ctx).Return(&entity.UserStats{TotalUsers: 1}, nil).Once()
+	stats, err := uc.GetStats(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.TotalUsers)
+	stats, err = uc.GetStats(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.TotalUsers, "served from the cache")
+
+	*now = now.Add(time.Minute)
+	statsRepo.On("Totals", ctx).Return(&entity.UserStats{TotalUsers: 2}, nil).Once()
+	stats, err = uc.GetStats(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.TotalUsers, "the cached totals expired")
+	statsRepo.AssertNumberOfCalls(t, "Totals", 2)
+}
+
+func TestStatsSeriesCachedPerRange(t *testing.T) {
+	uc, statsRepo, _ := setupStatsTest()
+	ctx := context.Background()
+	day := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := uc.GetSeries(ctx, 1, entity.StatsRange{Interval: "year", From: day, To: day})
+	assert.ErrorIs(t, err, entity.ErrInvalidStatsRange)
+
+	statsRepo.On("Series", ctx, interval(entity.StatsIntervalDay)).Return([]*entity.StatsPoint{{Start: day}}, nil).Once()
+	daily := entity.StatsRange{Interval: entity.StatsIntervalDay, From: day, To: day.AddDate(0, 0, 6)}
+	series, err := uc.GetSeries(ctx, 1, daily)
+	require.NoError(t, err)
+	assert.Equal(t, entity.StatsIntervalDay, series.Interval)
+	_, err = uc.GetSeries(ctx, 1, daily)
+	require.NoError(t, err)
+
+	statsRepo.On("Series", ctx, interval(entity.StatsIntervalWeek)).Return([]*entity.StatsPoint{}, nil).Once()
+	weekly := daily
+	weekly.Interval = entity.StatsIntervalWeek
+	series, err = uc.GetSeries(ctx, 1, weekly)
+	require.NoError(t, err, "another interval is another cache entry")
+	assert.Equal(t, time.Date(2024, 1, 29, 0, 0, 0, 0, time.UTC), series.From)
+	statsRepo.AssertExpectations(t)
+}
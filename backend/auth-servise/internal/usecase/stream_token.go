@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// StreamTokenScope is the "scope" claim of tokens that only open the
+// notification stream. No other route accepts them.
+const StreamTokenScope = "notifications:stream"
+
+// streamTokenTTL is short because the token travels in the URL and may end
+// up in proxy logs; it is only checked when the stream is opened.
+const streamTokenTTL = time.Minute
+
+// StreamTokenResponse is a token for the "token" query parameter of the
+// notification stream.
+type StreamTokenResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// StreamToken issues the user a token for opening the notification stream
+// with the browser EventSource API, which cannot send the Authorization
+// header.
+func (uc *authUsecase) StreamToken(ctx context.Context, userID int64) (*StreamTokenResponse, error) {
+	if err := uc.CheckAccount(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(streamTokenTTL)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": userID,
+		"scope":   StreamTokenScope,
+		"exp":     expiresAt.Unix(),
+	})
+	signed, err := token.SignedString([]byte(uc.config.TokenSecret))
+	if err != nil {
+		return nil, fmt.Errorf("error signing token: %w", err)
+	}
+	return &StreamTokenResponse{Token: signed, ExpiresAt: expiresAt}, nil
+}
@@ -3,6 +3,8 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
@@ -22,14 +24,22 @@ type UserUsecaseInterface interface {
 	Logout(ctx context.Context, token string) error
 	GetByID(ctx context.Context, id int64) (*entity.User, error)
 	Update(ctx context.Context, user *entity.User) error
-	Delete(ctx context.Context, id int64) error
-	GetAll(ctx context.Context) ([]*entity.User, error)
-	GetStats(ctx context.Context) (*entity.UserStats, error)
+	GetAll(ctx context.Context, adminID int64) ([]*entity.User, error)
+	SetVerified(ctx context.Context, adminID, userID int64, verified bool) error
+	ListRestricted(ctx context.Context, adminID int64, state string, limit, offset int) ([]*entity.User, int, error)
+	Suspend(ctx context.Context, adminID, userID int64, until time.Time, reason string) (*entity.User, error)
+	Unsuspend(ctx context.Context, adminID, userID int64) (*entity.User, error)
+	Ban(ctx context.Context, adminID, userID int64, reason string) (*entity.User, error)
+	Unban(ctx context.Context, adminID, userID int64) (*entity.User, error)
+	SoftDelete(ctx context.Context, adminID, userID int64) (*entity.User, error)
+	Restore(ctx context.Context, adminID, userID int64) (*entity.User, error)
 }
 
 type UserUsecase struct {
 	userRepo repository.UserRepositoryInterface
 	config   *UserConfig
+	auditor  Auditor
+	now      func() time.Time
 }
 
 type UserConfig struct {
@@ -37,10 +47,12 @@ type UserConfig struct {
 	TokenExpiration time.Duration
 }
 
-func NewUserUsecase(userRepo repository.UserRepositoryInterface, config *UserConfig) *UserUsecase {
+func NewUserUsecase(userRepo repository.UserRepositoryInterface, config *UserConfig, auditor Auditor) *UserUsecase {
 	return &UserUsecase{
 		userRepo: userRepo,
 		config:   config,
+		auditor:  auditor,
+		now:      time.Now,
 	}
 }
 
@@ -72,6 +84,9 @@ func (uc *UserUsecase) Login(ctx context.Context, email, password string) (strin
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
 		return "", ErrInvalidCredentials
 	}
+	if err := user.CheckAccess(uc.now()); err != nil {
+		return "", err
+	}
 
 	// Generate JWT token
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
@@ -112,34 +127,169 @@ func (uc *UserUsecase) Update(ctx context.Context, user *entity.User) error {
 	return uc.userRepo.Update(ctx, user)
 }
 
-func (u *UserUsecase) Delete(ctx context.Context, id int64) error {
-	return u.userRepo.Delete(ctx, id)
+// GetAll returns every account. Only admins may list them.
+func (u *UserUsecase) GetAll(ctx context.Context, adminID int64) ([]*entity.User, error) {
+	if err := u.requireAdmin(ctx, adminID); err != nil {
+		return nil, err
+	}
+	return u.userRepo.GetAll(ctx)
 }
 
-func (u *UserUsecase) GetAll(ctx context.Context) ([]*entity.User, error) {
-	return u.userRepo.GetAll(ctx)
+// SetVerified marks an employer as verified. Only admins may do this.
+func (u *UserUsecase) SetVerified(ctx context.Context, adminID, userID int64, verified bool) error {
+	if err := u.requireAdmin(ctx, adminID); err != nil {
+		return err
+	}
+
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil || user.Role != string(entity.RoleEmployer) {
+		return ErrEmployerNotFound
+	}
+
+	after := *user
+	after.IsVerified = verified
+	if err := u.auditor.Record(ctx, auditEntry(adminID, entity.AuditUserVerify, entity.AuditTargetUser, userID, user, &after)); err != nil {
+		return err
+	}
+	return u.userRepo.SetVerified(ctx, userID, verified)
 }
 
-func (u *UserUsecase) GetStats(ctx context.Context) (*entity.UserStats, error) {
-	users, err := u.userRepo.GetAll(ctx)
+func (u *UserUsecase) requireAdmin(ctx context.Context, adminID int64) error {
+	admin, err := u.userRepo.GetByID(ctx, adminID)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if admin == nil || admin.Role != string(entity.RoleAdmin) {
+		return ErrPermissionDenied
 	}
+	return nil
+}
 
-	stats := &entity.UserStats{
-		TotalUsers:      len(users),
-		TotalEmployers:  0,
-		TotalJobseekers: 0,
+// ListRestricted returns a page of suspended, banned or deleted accounts.
+// An empty state lists all of them.
+func (u *UserUsecase) ListRestricted(ctx context.Context, adminID int64, state string, limit, offset int) ([]*entity.User, int, error) {
+	if err := u.requireAdmin(ctx, adminID); err != nil {
+		return nil, 0, err
 	}
+	switch state {
+	case "", entity.AccountSuspended, entity.AccountBanned, entity.AccountDeleted:
+	default:
+		return nil, 0, fmt.Errorf("%w: unknown state %q", entity.ErrInvalidAccountAction, state)
+	}
+	return u.userRepo.ListRestricted(ctx, state, limit, offset)
+}
+
+// Suspend blocks the account until the given time.
+func (u *UserUsecase) Suspend(ctx context.Context, adminID, userID int64, until time.Time, reason string) (*entity.User, error) {
+	return u.changeAccountState(ctx, adminID, userID, entity.AuditUserSuspend, func(user *entity.User, now time.Time) error {
+		if user.DeletedAt != nil {
+			return fmt.Errorf("%w: the account is deleted", entity.ErrInvalidAccountAction)
+		}
+		if !until.After(now) {
+			return fmt.Errorf("%w: suspension must end in the future", entity.ErrInvalidAccountAction)
+		}
+		user.SuspendedUntil = &until
+		user.SuspensionReason = strings.TrimSpace(reason)
+		return nil
+	})
+}
+
+// Unsuspend lifts the suspension before it ends.
+func (u *UserUsecase) Unsuspend(ctx context.Context, adminID, userID int64) (*entity.User, error) {
+	return u.changeAccountState(ctx, adminID, userID, entity.AuditUserUnsuspend, func(user *entity.User, now time.Time) error {
+		if user.SuspendedUntil == nil || !now.Before(*user.SuspendedUntil) {
+			return fmt.Errorf("%w: the account is not suspended", entity.ErrInvalidAccountAction)
+		}
+		user.SuspendedUntil = nil
+		user.SuspensionReason = ""
+		return nil
+	})
+}
 
-	for _, user := range users {
-		switch user.Role {
-		case "employer":
-			stats.TotalEmployers++
-		case "jobseeker":
-			stats.TotalJobseekers++
+// Ban blocks the account until an admin unbans it. The reason is required
+// and is shown to the user when they try to sign in.
+func (u *UserUsecase) Ban(ctx context.Context, adminID, userID int64, reason string) (*entity.User, error) {
+	return u.changeAccountState(ctx, adminID, userID, entity.AuditUserBan, func(user *entity.User, now time.Time) error {
+		reason = strings.TrimSpace(reason)
+		if reason == "" {
+			return fmt.Errorf("%w: ban reason is required", entity.ErrInvalidAccountAction)
+		}
+		if user.DeletedAt != nil {
+			return fmt.Errorf("%w: the account is deleted", entity.ErrInvalidAccountAction)
 		}
+		user.BannedAt = &now
+		user.BanReason = reason
+		return nil
+	})
+}
+
+func (u *UserUsecase) Unban(ctx context.Context, adminID, userID int64) (*entity.User, error) {
+	return u.changeAccountState(ctx, adminID, userID, entity.AuditUserUnban, func(user *entity.User, now time.Time) error {
+		if user.BannedAt == nil {
+			return fmt.Errorf("%w: the account is not banned", entity.ErrInvalidAccountAction)
+		}
+		user.BannedAt = nil
+		user.BanReason = ""
+		return nil
+	})
+}
+
+// SoftDelete closes the account. Its resumes, vacancies and applications
+// are kept, so the history of other users stays intact, and Restore brings
+// the account back.
+func (u *UserUsecase) SoftDelete(ctx context.Context, adminID, userID int64) (*entity.User, error) {
+	return u.changeAccountState(ctx, adminID, userID, entity.AuditUserDelete, func(user *entity.User, now time.Time) error {
+		if user.DeletedAt != nil {
+			return fmt.Errorf("%w: the account is already deleted", entity.ErrInvalidAccountAction)
+		}
+		user.DeletedAt = &now
+		return nil
+	})
+}
+
+func (u *UserUsecase) Restore(ctx context.Context, adminID, userID int64) (*entity.User, error) {
+	return u.changeAccountState(ctx, adminID, userID, entity.AuditUserRestore, func(user *entity.User, now time.Time) error {
+		if user.DeletedAt == nil {
+			return fmt.Errorf("%w: the account is not deleted", entity.ErrInvalidAccountAction)
+		}
+		user.DeletedAt = nil
+		return nil
+	})
+}
+
+// changeAccountState applies change to the user on behalf of the admin and
+// records it in the audit log as action.
+// Admin accounts cannot be restricted, including the admin's own.
+func (u *UserUsecase) changeAccountState(ctx context.Context, adminID, userID int64, action string, change func(user *entity.User, now time.Time) error) (*entity.User, error) {
+	if err := u.requireAdmin(ctx, adminID); err != nil {
+		return nil, err
 	}
 
-	return stats, nil
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	if user.Role == string(entity.RoleAdmin) {
+		return nil, ErrPermissionDenied
+	}
+
+	before := *user
+	now := u.now()
+	if err := change(user, now); err != nil {
+		return nil, err
+	}
+	user.UpdatedAt = now
+	if err := u.auditor.Record(ctx, auditEntry(adminID, action, entity.AuditTargetUser, userID, &before, user)); err != nil {
+		return nil, err
+	}
+	if err := u.userRepo.UpdateAccountState(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
 }
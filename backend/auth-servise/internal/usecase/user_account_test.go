@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	accountAdmin  = 1
+	accountSeeker = 2
+)
+
+func setupAccountTest(t *testing.T) (*UserUsecase, *MockUserRepo, *time.Time) {
+	password, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	require.NoError(t, err)
+	admin := &entity.User{ID: accountAdmin, Email: "admin@example.com", Role: string(entity.RoleAdmin)}
+	seeker := &entity.User{ID: accountSeeker, Email: "seeker@example.com", Password: string(password), Role: string(entity.RoleJobseeker)}
+
+	userRepo := new(MockUserRepo).withUsers(admin, seeker)
+	userRepo.On("GetAll", mock.Anything).Return([]*entity.User{admin, seeker}, nil).Maybe()
+	userRepo.On("UpdateAccountState", mock.Anything, mock.AnythingOfType("*entity.User")).Return(nil).Maybe()
+
+	uc := NewUserUsecase(userRepo, &UserConfig{TokenSecret: "secret", TokenExpiration: time.Hour}, NopAuditor{})
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	uc.now = func() time.Time { return now }
+	return uc, userRepo, &now
+}
+
+func TestSuspensionEndsByItself(t *testing.T) {
+	uc, _, now := setupAccountTest(t)
+	ctx := context.Background()
+
+	_, err := uc.Suspend(ctx, accountAdmin, accountSeeker, now.Add(-time.Minute), "")
+	assert.ErrorIs(t, err, entity.ErrInvalidAccountAction, "the suspension has to end in the future")
+	_, err = uc.Suspend(ctx, accountSeeker, accountSeeker, now.Add(time.Hour), "")
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+	_, err = uc.Suspend(ctx, accountAdmin, accountAdmin, now.Add(time.Hour), "")
+	assert.ErrorIs(t, err, ErrPermissionDenied, "admins cannot be restricted")
+
+	user, err := uc.Suspend(ctx, accountAdmin, accountSeeker, now.Add(24*time.Hour), "флуд")
+	require.NoError(t, err)
+	assert.Equal(t, entity.AccountSuspended, user.AccountState(*now))
+	_, err = uc.Login(ctx, "seeker@example.com", "secret")
+	assert.ErrorIs(t, err, entity.ErrAccountSuspended)
+
+	*now = now.Add(24 * time.Hour)
+	_, err = uc.Login(ctx, "seeker@example.com", "secret")
+	assert.NoError(t, err)
+	_, err = uc.Unsuspend(ctx, accountAdmin, accountSeeker)
+	assert.ErrorIs(t, err, entity.ErrInvalidAccountAction, "nothing to lift")
+}
+
+func TestOnlyAdminsListUsers(t *testing.T) {
+	uc, _, _ := setupAccountTest(t)
+	ctx := context.Background()
+
+	_, err := uc.GetAll(ctx, accountSeeker)
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+	users, err := uc.GetAll(ctx, accountAdmin)
+	require.NoError(t, err)
+	assert.Len(t, users, 2)
+}
+
+func TestBanAndSoftDeleteAreReversible(t *testing.T) {
+	uc, userRepo, _ := setupAccountTest(t)
+	ctx := context.Background()
+
+	_, err := uc.Ban(ctx, accountAdmin, accountSeeker, " ")
+	assert.ErrorIs(t, err, entity.ErrInvalidAccountAction, "a ban needs a reason")
+	_, err = uc.Ban(ctx, accountAdmin, accountSeeker, "мошенничество")
+	require.NoError(t, err)
+	_, err = uc.Login(ctx, "seeker@example.com", "secret")
+	assert.ErrorIs(t, err, entity.ErrAccountBanned)
+	_, err = uc.Login(ctx, "seeker@example.com", "wrong")
+	assert.ErrorIs(t, err, ErrInvalidCredentials, "the state is not revealed without the password")
+
+	banned, total, err := uc.ListRestricted(ctx, accountAdmin, "unknown", 10, 0)
+	assert.ErrorIs(t, err, entity.ErrInvalidAccountAction)
+	assert.Nil(t, banned)
+	assert.Zero(t, total)
+
+	user, err := uc.Unban(ctx, accountAdmin, accountSeeker)
+	require.NoError(t, err)
+	assert.Empty(t, user.BanReason)
+
+	_, err = uc.SoftDelete(ctx, accountAdmin, accountSeeker)
+	require.NoError(t, err)
+	userRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	_, err = uc.Login(ctx, "seeker@example.com", "secret")
+	assert.ErrorIs(t, err, entity.ErrAccountDeleted)
+	_, err = uc.Ban(ctx, accountAdmin, accountSeeker, "спам")
+	assert.ErrorIs(t, err, entity.ErrInvalidAccountAction)
+
+	user, err = uc.Restore(ctx, accountAdmin, accountSeeker)
+	require.NoError(t, err)
+	assert.Equal(t, entity.AccountActive, user.AccountState(time.Now()))
+	userRepo.AssertNumberOfCalls(t, "UpdateAccountState", 4)
+}
+
+func TestValidateTokenRejectsRestrictedAccounts(t *testing.T) {
+	uc, userRepo, _ := setupAccountTest(t)
+	ctx := context.Background()
+	auth := NewAuthUsecase(userRepo, &Config{TokenSecret: "secret", TokenExpiration: time.Hour}, zap.NewNop(), NopAuditor{})
+
+	login, err := auth.Login(ctx, &LoginRequest{Email: "seeker@example.com", Password: "secret"})
+	require.NoError(t, err)
+	userID, err := auth.ValidateToken(ctx, &ValidateTokenRequest{Token: login.Token})
+	require.NoError(t, err)
+	assert.Equal(t, int64(accountSeeker), userID)
+
+	_, err = uc.Ban(ctx, accountAdmin, accountSeeker, "спам")
+	require.NoError(t, err)
+	_, err = auth.ValidateToken(ctx, &ValidateTokenRequest{Token: login.Token})
+	assert.ErrorIs(t, err, entity.ErrAccountBanned, "tokens issued before the ban stop working")
+	_, err = auth.Login(ctx, &LoginRequest{Email: "seeker@example.com", Password: "secret"})
+	assert.ErrorIs(t, err, entity.ErrAccountBanned)
+}
@@ -20,20 +20,37 @@ type VacancyUsecaseInterface interface {
 	GetByEmployerID(ctx context.Context, employerID int64) ([]*entity.Vacancy, error)
 	Update(ctx context.Context, vacancy *entity.Vacancy) error
 	Delete(ctx context.Context, id int64, employerID int64) error
+	RemoveByModerator(ctx context.Context, adminID, id int64, reason string) error
 }
 
 type VacancyUsecase struct {
-	vacancyRepo repository.VacancyRepositoryInterface
-	userRepo    repository.UserRepositoryInterface
+	vacancyRepo   repository.VacancyRepositoryInterface
+	userRepo      repository.UserRepositoryInterface
+	orgRepo       repository.OrganizationRepositoryInterface
+	notifications Notifier
+	auditor       Auditor
+	access        *vacancyAccess
 }
 
-func NewVacancyUsecase(vacancyRepo repository.VacancyRepositoryInterface, userRepo repository.UserRepositoryInterface) *VacancyUsecase {
+func NewVacancyUsecase(
+	vacancyRepo repository.VacancyRepositoryInterface,
+	userRepo repository.UserRepositoryInterface,
+	orgRepo repository.OrganizationRepositoryInterface,
+	notifications Notifier,
+	auditor Auditor,
+) *VacancyUsecase {
 	return &VacancyUsecase{
-		vacancyRepo: vacancyRepo,
-		userRepo:    userRepo,
+		vacancyRepo:   vacancyRepo,
+		userRepo:      userRepo,
+		orgRepo:       orgRepo,
+		notifications: notifications,
+		auditor:       auditor,
+		access:        &vacancyAccess{orgRepo: orgRepo},
 	}
 }
 
+// Create publishes a vacancy. A vacancy posted by a member of an
+// organization belongs to the organization and carries its name.
 func (uc *VacancyUsecase) Create(ctx context.Context, vacancy *entity.Vacancy) error {
 	fmt.Printf("Checking user with ID %d\n", vacancy.EmployerID)
 	user, err := uc.userRepo.GetByID(ctx, vacancy.EmployerID)
@@ -52,6 +69,26 @@ func (uc *VacancyUsecase) Create(ctx context.Context, vacancy *entity.Vacancy) e
 		return ErrPermissionDenied
 	}
 
+	if uc.orgRepo != nil {
+		member, err := uc.orgRepo.GetMembership(ctx, vacancy.EmployerID)
+		if err != nil {
+			return err
+		}
+		if member != nil {
+			if !entity.CanManageVacancies(member.Role) {
+				return ErrPermissionDenied
+			}
+			organization, err := uc.orgRepo.GetByID(ctx, member.OrganizationID)
+			if err != nil {
+				return err
+			}
+			vacancy.OrganizationID = &member.OrganizationID
+			if organization != nil {
+				vacancy.Company = organization.Name
+			}
+		}
+	}
+
 	fmt.Printf("Creating vacancy in repository\n")
 	err = uc.vacancyRepo.Create(ctx, vacancy)
 	if err != nil {
@@ -82,6 +119,8 @@ func (uc *VacancyUsecase) GetByEmployerID(ctx context.Context, employerID int64)
 	return uc.vacancyRepo.GetByEmployerID(ctx, employerID)
 }
 
+// Update changes a vacancy on behalf of its owner or a recruiter of its
+// organization. The vacancy keeps its owner and organization.
 func (uc *VacancyUsecase) Update(ctx context.Context, vacancy *entity.Vacancy) error {
 	fmt.Printf("Starting vacancy update in usecase for ID: %d\n", vacancy.ID)
 
@@ -97,7 +136,11 @@ func (uc *VacancyUsecase) Update(ctx context.Context, vacancy *entity.Vacancy) e
 	}
 
 	// Проверяем права доступа
-	if existingVacancy.EmployerID != vacancy.EmployerID {
+	allowed, err := uc.access.canManage(ctx, vacancy.EmployerID, existingVacancy)
+	if err != nil {
+		return err
+	}
+	if !allowed {
 		fmt.Printf("Permission denied: employer ID mismatch\n")
 		return ErrPermissionDenied
 	}
@@ -118,6 +161,11 @@ func (uc *VacancyUsecase) Update(ctx context.Context, vacancy *entity.Vacancy) e
 		return ErrPermissionDenied
 	}
 
+	vacancy.EmployerID = existingVacancy.EmployerID
+	vacancy.OrganizationID = existingVacancy.OrganizationID
+	if vacancy.OrganizationID != nil {
+		vacancy.Company = existingVacancy.Company
+	}
 	fmt.Printf("Updating vacancy in repository\n")
 	err = uc.vacancyRepo.Update(ctx, vacancy)
 	if err != nil {
@@ -144,7 +192,11 @@ func (uc *VacancyUsecase) Delete(ctx context.Context, id int64, employerID int64
 	}
 
 	// Проверяем права доступа
-	if existingVacancy.EmployerID != employerID {
+	allowed, err := uc.access.canManage(ctx, employerID, existingVacancy)
+	if err != nil {
+		return err
+	}
+	if !allowed {
 		fmt.Printf("Permission denied: employer ID mismatch\n")
 		return ErrPermissionDenied
 	}
@@ -175,3 +227,46 @@ func (uc *VacancyUsecase) Delete(ctx context.Context, id int64, employerID int64
 	fmt.Printf("Vacancy deleted successfully\n")
 	return nil
 }
+
+// RemoveByModerator deletes a vacancy that breaks the rules and tells the
+// employer why.
+func (uc *VacancyUsecase) RemoveByModerator(ctx context.Context, adminID, id int64, reason string) error {
+	admin, err := uc.userRepo.GetByID(ctx, adminID)
+	if err != nil {
+		return err
+	}
+	if admin == nil || admin.Role != string(entity.RoleAdmin) {
+		return ErrPermissionDenied
+	}
+
+	vacancy, err := uc.vacancyRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if vacancy == nil {
+		return entity.ErrVacancyNotFound
+	}
+	if err := uc.auditor.Record(ctx, auditEntry(adminID, entity.AuditVacancyDelete, entity.AuditTargetVacancy, id, vacancy, moderationReason{Reason: reason})); err != nil {
+		return err
+	}
+	if err := uc.vacancyRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Вакансия «%s» снята с публикации модератором.", vacancy.Title)
+	if reason != "" {
+		body += "\n\nПричина: " + reason
+	}
+	uc.notifications.Publish(ctx, &entity.Notification{
+		UserID: vacancy.EmployerID,
+		Type:   entity.NotificationVacancyModerated,
+		Title:  "Вакансия снята с публикации: " + vacancy.Title,
+		Body:   body,
+	})
+	return nil
+}
+
+// moderationReason is the audit snapshot after a moderator removes content.
+type moderationReason struct {
+	Reason string `json:"reason,omitempty"`
+}
@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/repository"
+)
+
+// vacancyAccess decides what an employer may do with a vacancy. A vacancy of
+// an organization belongs to its current members, each with their member
+// role, the one who posted it included: removed or downgraded members lose
+// access right away. A vacancy outside any organization belongs to the
+// account that posted it.
+type vacancyAccess struct {
+	orgRepo repository.OrganizationRepositoryInterface
+}
+
+// role returns the user's role for the vacancy, or "" without access.
+func (a *vacancyAccess) role(ctx context.Context, userID int64, vacancy *entity.Vacancy) (string, error) {
+	if vacancy.OrganizationID == nil {
+		if vacancy.EmployerID == userID {
+			return entity.OrganizationRoleOwner, nil
+		}
+		return "", nil
+	}
+	if a == nil || a.orgRepo == nil {
+		return "", nil
+	}
+	member, err := a.orgRepo.GetMembership(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if member == nil || member.OrganizationID != *vacancy.OrganizationID {
+		return "", nil
+	}
+	return member.Role, nil
+}
+
+// canView reports whether the user sees the vacancy's applications.
+func (a *vacancyAccess) canView(ctx context.Context, userID int64, vacancy *entity.Vacancy) (bool, error) {
+	role, err := a.role(ctx, userID, vacancy)
+	return role != "", err
+}
+
+// canManage reports whether the user may change the vacancy and act on its
+// applications.
+func (a *vacancyAccess) canManage(ctx context.Context, userID int64, vacancy *entity.Vacancy) (bool, error) {
+	role, err := a.role(ctx, userID, vacancy)
+	return entity.CanManageVacancies(role), err
+}
@@ -0,0 +1,379 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/attachment"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/notify"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/repository"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/storage"
+)
+
+// DocumentFile is an uploaded document of a verification request.
+type DocumentFile struct {
+	Name string
+	Data []byte
+}
+
+type VerificationUsecaseInterface interface {
+	Submit(ctx context.Context, employerID int64, verification *entity.EmployerVerification, files []DocumentFile) (*entity.EmployerVerification, error)
+	GetMine(ctx context.Context, employerID int64) (*entity.EmployerVerification, error)
+	ConfirmEmail(ctx context.Context, token string) error
+	ListQueue(ctx context.Context, adminID int64, status string, limit, offset int) ([]*entity.EmployerVerification, int, error)
+	Get(ctx context.Context, adminID, id int64) (*entity.EmployerVerification, error)
+	Review(ctx context.Context, adminID, id int64, status, comment string) (*entity.EmployerVerification, error)
+	DownloadDocument(ctx context.Context, id int64, link attachment.Link) (*entity.VerificationDocument, io.ReadCloser, error)
+}
+
+// VerificationUsecase runs employer verification: employers submit their
+// company details and documents, admins approve, reject or ask for more
+// information. An approved employer gets the verified badge.
+type VerificationUsecase struct {
+	verificationRepo repository.VerificationRepositoryInterface
+	userRepo         repository.UserRepositoryInterface
+	store            storage.BlobStore
+	scanner          attachment.Scanner
+	signer           *attachment.Signer
+	mailer           notify.Notifier
+	notifications    Notifier
+	auditor          Auditor
+}
+
+func NewVerificationUsecase(
+	verificationRepo repository.VerificationRepositoryInterface,
+	userRepo repository.UserRepositoryInterface,
+	store storage.BlobStore,
+	scanner attachment.Scanner,
+	signer *attachment.Signer,
+	mailer notify.Notifier,
+	notifications Notifier,
+	auditor Auditor,
+) *VerificationUsecase {
+	if scanner == nil {
+		scanner = attachment.NopScanner{}
+	}
+	return &VerificationUsecase{
+		verificationRepo: verificationRepo,
+		userRepo:         userRepo,
+		store:            store,
+		scanner:          scanner,
+		signer:           signer,
+		mailer:           mailer,
+		notifications:    notifications,
+		auditor:          auditor,
+	}
+}
+
+// Submit sends the employer's details for review. A request an admin sent
+// back for more information is resubmitted with the corrected details and
+// any added documents; otherwise a new request needs at least one document.
+// Until the company email is confirmed, a confirmation link is sent to it.
+func (uc *VerificationUsecase) Submit(ctx context.Context, employerID int64, verification *entity.EmployerVerification, files []DocumentFile) (*entity.EmployerVerification, error) {
+	user, err := uc.userRepo.GetByID(ctx, employerID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || user.Role != string(entity.RoleEmployer) {
+		return nil, ErrPermissionDenied
+	}
+	if user.IsVerified {
+		return nil, entity.ErrAlreadyVerified
+	}
+	if err := verification.Validate(); err != nil {
+		return nil, err
+	}
+
+	latest, err := uc.verificationRepo.GetLatest(ctx, employerID)
+	if err != nil {
+		return nil, err
+	}
+	if latest != nil && latest.Status == entity.VerificationPending {
+		return nil, entity.ErrVerificationPending
+	}
+	resubmit := latest != nil && latest.Status == entity.VerificationInfoRequested
+
+	total := len(files)
+	if resubmit {
+		total += len(latest.Documents)
+	}
+	if total == 0 || total > entity.MaxVerificationDocuments {
+		return nil, fmt.Errorf("%w: attach 1 to %d documents", entity.ErrInvalidVerification, entity.MaxVerificationDocuments)
+	}
+
+	documents := make([]*entity.VerificationDocument, 0, len(files))
+	for _, file := range files {
+		document, err := uc.prepareDocument(ctx, employerID, file)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, document)
+	}
+
+	// Документы загружаются до записи заявки и удаляются, если она не удалась
+	stored := make([]string, 0, len(documents))
+	cleanup := func() {
+		for _, key := range stored {
+			_ = uc.store.Delete(ctx, key)
+		}
+	}
+	for i, document := range documents {
+		if err := uc.store.Put(ctx, document.StorageKey, bytes.NewReader(files[i].Data), document.Size, document.ContentType); err != nil {
+			cleanup()
+			return nil, err
+		}
+		stored = append(stored, document.StorageKey)
+	}
+
+	if verification.EmailToken, err = newPublicToken(); err != nil {
+		cleanup()
+		return nil, err
+	}
+	verification.EmployerID = employerID
+	if resubmit {
+		latest.RegistrationNumber = verification.RegistrationNumber
+		latest.CompanyEmail = verification.CompanyEmail
+		latest.EmailToken = verification.EmailToken
+		err = uc.verificationRepo.Resubmit(ctx, latest, documents)
+		verification = latest
+	} else {
+		verification.Documents = documents
+		err = uc.verificationRepo.Create(ctx, verification)
+	}
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+
+	if verification.EmailConfirmedAt == nil {
+		uc.sendEmailConfirmation(ctx, verification)
+	}
+	uc.sign(verification, employerID)
+	return verification, nil
+}
+
+// prepareDocument validates and scans a file and picks the key it is stored
+// under. Scans and photos of documents are accepted besides PDF and DOCX.
+func (uc *VerificationUsecase) prepareDocument(ctx context.Context, employerID int64, file DocumentFile) (*entity.VerificationDocument, error) {
+	fileName := filepath.Base(strings.ReplaceAll(file.Name, `\`, "/"))
+	contentType, err := attachment.DetectMessageType(fileName, file.Data)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.scanner.Scan(ctx, fileName, file.Data); err != nil {
+		return nil, err
+	}
+	token, err := newPublicToken()
+	if err != nil {
+		return nil, err
+	}
+	return &entity.VerificationDocument{
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        int64(len(file.Data)),
+		StorageKey:  fmt.Sprintf("verifications/%d/%s%s", employerID, token, strings.ToLower(filepath.Ext(fileName))),
+	}, nil
+}
+
+func (uc *VerificationUsecase) sendEmailConfirmation(ctx context.Context, verification *entity.EmployerVerification) {
+	msg := notify.Message{
+		To:      []string{verification.CompanyEmail},
+		Subject: "Подтверждение адреса компании",
+		Body: "Этот адрес указан в заявке на верификацию работодателя. " +
+			"Чтобы подтвердить, что он принадлежит компании, откройте ссылку:\n\n" +
+			"/verification/confirm-email?token=" + verification.EmailToken,
+	}
+	if err := uc.mailer.Send(ctx, msg); err != nil {
+		fmt.Printf("Failed to send company email confirmation for verification %d: %v\n", verification.ID, err)
+	}
+}
+
+func (uc *VerificationUsecase) sign(verification *entity.EmployerVerification, viewerID int64) {
+	for _, document := range verification.Documents {
+		document.DownloadURL = uc.signer.DocumentURL(document.ID, viewerID)
+	}
+}
+
+// GetMine returns the employer's latest request.
+func (uc *VerificationUsecase) GetMine(ctx context.Context, employerID int64) (*entity.EmployerVerification, error) {
+	verification, err := uc.verificationRepo.GetLatest(ctx, employerID)
+	if err != nil {
+		return nil, err
+	}
+	if verification == nil {
+		return nil, entity.ErrVerificationNotFound
+	}
+	uc.sign(verification, employerID)
+	return verification, nil
+}
+
+// ConfirmEmail confirms the company email with the token from the link sent
+// to it. The link works without signing in, since the address may not be the
+// one the employer signs in with.
+func (uc *VerificationUsecase) ConfirmEmail(ctx context.Context, token string) error {
+	found, err := uc.verificationRepo.ConfirmEmail(ctx, token)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return entity.ErrVerificationNotFound
+	}
+	return nil
+}
+
+func (uc *VerificationUsecase) requireAdmin(ctx context.Context, adminID int64) error {
+	admin, err := uc.userRepo.GetByID(ctx, adminID)
+	if err != nil {
+		return err
+	}
+	if admin == nil || admin.Role != string(entity.RoleAdmin) {
+		return ErrPermissionDenied
+	}
+	return nil
+}
+
+// ListQueue returns a page of requests with the status, pending ones by
+// default, the longest waiting first.
+func (uc *VerificationUsecase) ListQueue(ctx context.Context, adminID int64, status string, limit, offset int) ([]*entity.EmployerVerification, int, error) {
+	if err := uc.requireAdmin(ctx, adminID); err != nil {
+		return nil, 0, err
+	}
+	switch status {
+	case "":
+		status = entity.VerificationPending
+	case entity.VerificationPending, entity.VerificationInfoRequested, entity.VerificationApproved, entity.VerificationRejected:
+	default:
+		return nil, 0, fmt.Errorf("%w: unknown status %q", entity.ErrInvalidVerification, status)
+	}
+
+	verifications, total, err := uc.verificationRepo.ListQueue(ctx, status, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, verification := range verifications {
+		uc.sign(verification, adminID)
+	}
+	return verifications, total, nil
+}
+
+func (uc *VerificationUsecase) Get(ctx context.Context, adminID, id int64) (*entity.EmployerVerification, error) {
+	if err := uc.requireAdmin(ctx, adminID); err != nil {
+		return nil, err
+	}
+	verification, err := uc.verificationRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if verification == nil {
+		return nil, entity.ErrVerificationNotFound
+	}
+	uc.sign(verification, adminID)
+	return verification, nil
+}
+
+// Review records the admin's decision on a pending request: approved,
+// rejected or info_requested. Rejecting and asking for more information
+// need a comment for the employer, and only a request with a confirmed
+// company email can be approved. The employer is notified of the decision.
+func (uc *VerificationUsecase) Review(ctx context.Context, adminID, id int64, status, comment string) (*entity.EmployerVerification, error) {
+	verification, err := uc.Get(ctx, adminID, id)
+	if err != nil {
+		return nil, err
+	}
+	comment = strings.TrimSpace(comment)
+	switch status {
+	case entity.VerificationApproved:
+		if verification.EmailConfirmedAt == nil {
+			return nil, fmt.Errorf("%w: company email is not confirmed", entity.ErrInvalidVerification)
+		}
+	case entity.VerificationRejected, entity.VerificationInfoRequested:
+		if comment == "" {
+			return nil, fmt.Errorf("%w: comment is required", entity.ErrInvalidVerification)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown decision %q", entity.ErrInvalidVerification, status)
+	}
+
+	before := verificationDecision{Status: verification.Status, Comment: verification.AdminComment}
+	err = uc.auditor.Record(ctx, auditEntry(adminID, entity.AuditVerificationReview, entity.AuditTargetVerification, id,
+		before, verificationDecision{Status: status, Comment: comment}))
+	if err != nil {
+		return nil, err
+	}
+	verification, err = uc.verificationRepo.Review(ctx, id, adminID, status, comment)
+	if err != nil {
+		return nil, err
+	}
+
+	var body string
+	switch status {
+	case entity.VerificationApproved:
+		body = "Компания верифицирована: на ваших вакансиях теперь отображается отметка о проверке."
+	case entity.VerificationRejected:
+		body = "Заявка на верификацию отклонена."
+	case entity.VerificationInfoRequested:
+		body = "Для верификации нужны дополнительные сведения. Дополните заявку и отправьте ее повторно."
+	}
+	if comment != "" {
+		body += "\n\nКомментарий модератора: " + comment
+	}
+	uc.notifications.Publish(ctx, &entity.Notification{
+		UserID: verification.EmployerID,
+		Type:   entity.NotificationVerification,
+		Title:  "Верификация работодателя",
+		Body:   body,
+		Link:   "/verification",
+	})
+
+	uc.sign(verification, adminID)
+	return verification, nil
+}
+
+// DownloadDocument checks the signed link and that the user it was issued
+// to is the employer who submitted the document or an admin.
+func (uc *VerificationUsecase) DownloadDocument(ctx context.Context, id int64, link attachment.Link) (*entity.VerificationDocument, io.ReadCloser, error) {
+	if err := uc.signer.VerifyDocument(id, link); err != nil {
+		return nil, nil, err
+	}
+
+	document, err := uc.verificationRepo.GetDocument(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if document == nil {
+		return nil, nil, entity.ErrVerificationNotFound
+	}
+	verification, err := uc.verificationRepo.GetByID(ctx, document.VerificationID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if verification == nil {
+		return nil, nil, entity.ErrVerificationNotFound
+	}
+	if verification.EmployerID != link.ViewerID {
+		if err := uc.requireAdmin(ctx, link.ViewerID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	body, err := uc.store.Get(ctx, document.StorageKey)
+	if err == storage.ErrBlobNotFound {
+		return nil, nil, entity.ErrVerificationNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return document, body, nil
+}
+
+// verificationDecision is the audit snapshot of a reviewed request. Documents
+// and signed links stay out of the log.
+type verificationDecision struct {
+	Status  string `json:"status"`
+	Comment string `json:"comment,omitempty"`
+}
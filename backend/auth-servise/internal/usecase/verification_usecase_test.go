@@ -0,0 +1,275 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/attachment"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockVerificationRepo struct {
+	mock.Mock
+}
+
+func (m *MockVerificationRepo) Create(ctx context.Context, verification *entity.EmployerVerification) error {
+	args := m.Called(ctx, verification)
+	return args.Error(0)
+}
+
+func (m *MockVerificationRepo) Resubmit(ctx context.Context, verification *entity.EmployerVerification, documents []*entity.VerificationDocument) error {
+	args := m.Called(ctx, verification, documents)
+	return args.Error(0)
+}
+
+func (m *MockVerificationRepo) GetByID(ctx context.Context, id int64) (*entity.EmployerVerification, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.EmployerVerification), args.Error(1)
+}
+
+func (m *MockVerificationRepo) GetLatest(ctx context.Context, employerID int64) (*entity.EmployerVerification, error) {
+	args := m.Called(ctx, employerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.EmployerVerification), args.Error(1)
+}
+
+func (m *MockVerificationRepo) ConfirmEmail(ctx context.Context, token string) (bool, error) {
+	args := m.Called(ctx, token)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockVerificationRepo) ListQueue(ctx context.Context, status string, limit, offset int) ([]*entity.EmployerVerification, int, error) {
+	args := m.Called(ctx, status, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*entity.EmployerVerification), args.Int(1), args.Error(2)
+}
+
+func (m *MockVerificationRepo) Review(ctx context.Context, id, adminID int64, status, comment string) (*entity.EmployerVerification, error) {
+	args := m.Called(ctx, id, adminID, status, comment)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.EmployerVerification), args.Error(1)
+}
+
+func (m *MockVerificationRepo) GetDocument(ctx context.Context, id int64) (*entity.VerificationDocument, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.VerificationDocument), args.Error(1)
+}
+
+// expectCreate stores the next new request the way the database does: it
+// gets an id, the pending status and ids for its documents.
+func (m *MockVerificationRepo) expectCreate(id int64) {
+	m.On("Create", mock.Anything, mock.AnythingOfType("*entity.EmployerVerification")).Run(func(args mock.Arguments) {
+		verification := args.Get(1).(*entity.EmployerVerification)
+		verification.ID = id
+		verification.Status = entity.VerificationPending
+		for i, document := range verification.Documents {
+			document.ID = id*100 + int64(i)
+			document.VerificationID = id
+		}
+	}).Return(nil).Once()
+}
+
+// MockNotifier accepts every notification; tests read them back with
+// published.
+type MockNotifier struct {
+	mock.Mock
+}
+
+func newMockNotifier() *MockNotifier {
+	m := new(MockNotifier)
+	m.On("Publish", mock.Anything, mock.Anything).Return().Maybe()
+	return m
+}
+
+func (m *MockNotifier) Publish(ctx context.Context, notification *entity.Notification) {
+	m.Called(ctx, notification)
+}
+
+// published returns the notifications published so far, oldest first.
+func (m *MockNotifier) published() []*entity.Notification {
+	var notifications []*entity.Notification
+	for _, call := range m.Calls {
+		if call.Method == "Publish" {
+			notifications = append(notifications, call.Arguments.Get(1).(*entity.Notification))
+		}
+	}
+	return notifications
+}
+
+func verificationUsers() (employer, other, admin *entity.User) {
+	return &entity.User{ID: 1, Role: string(entity.RoleEmployer)},
+		&entity.User{ID: 2, Role: string(entity.RoleEmployer)},
+		&entity.User{ID: 9, Role: string(entity.RoleAdmin)}
+}
+
+func setupVerificationTest(t *testing.T, users ...*entity.User) (*VerificationUsecase, *MockVerificationRepo, *MockMailer, *MockNotifier) {
+	verificationRepo := new(MockVerificationRepo)
+	store, err := storage.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+	mailer := newMockMailer()
+	notifications := newMockNotifier()
+	uc := NewVerificationUsecase(
+		verificationRepo, new(MockUserRepo).withUsers(users...), store, nil,
+		attachment.NewSigner("secret", time.Minute), mailer, notifications, NopAuditor{},
+	)
+	return uc, verificationRepo, mailer, notifications
+}
+
+func verificationRequest() *entity.EmployerVerification {
+	return &entity.EmployerVerification{RegistrationNumber: "7707083893", CompanyEmail: "hr@acme.ru"}
+}
+
+func registrationCertificate() []DocumentFile {
+	return []DocumentFile{{Name: "certificate.pdf", Data: []byte("%PDF-1.4 certificate")}}
+}
+
+func confirmCompanyEmail(t *testing.T, uc *VerificationUsecase, mailer *MockMailer) {
+	sent := mailer.sent()
+	require.NotEmpty(t, sent)
+	_, token, found := strings.Cut(sent[len(sent)-1].Body, "token=")
+	require.True(t, found)
+	require.NoError(t, uc.ConfirmEmail(context.Background(), strings.TrimSpace(token)))
+}
+
+func TestVerificationApproval(t *testing.T) {
+	employer, other, admin := verificationUsers()
+	uc, verificationRepo, mailer, notifications := setupVerificationTest(t, employer, other, admin)
+	ctx := context.Background()
+
+	verificationRepo.On("GetLatest", ctx, employer.ID).Return(nil, nil).Twice()
+	_, err := uc.Submit(ctx, employer.ID, verificationRequest(), nil)
+	assert.ErrorIs(t, err, entity.ErrInvalidVerification, "a new request needs documents")
+
+	verificationRepo.expectCreate(1)
+	verification, err := uc.Submit(ctx, employer.ID, verificationRequest(), registrationCertificate())
+	require.NoError(t, err)
+	require.Len(t, verification.Documents, 1)
+	assert.Equal(t, attachment.ContentTypePDF, verification.Documents[0].ContentType)
+	require.Len(t, mailer.sent(), 1)
+	assert.Equal(t, []string{"hr@acme.ru"}, mailer.sent()[0].To)
+
+	verificationRepo.On("GetLatest", ctx, employer.ID).Return(verification, nil)
+	_, err = uc.Submit(ctx, employer.ID, verificationRequest(), registrationCertificate())
+	assert.ErrorIs(t, err, entity.ErrVerificationPending)
+
+	_, err = uc.Review(ctx, other.ID, verification.ID, entity.VerificationApproved, "")
+	assert.ErrorIs(t, err, ErrPermissionDenied, "only admins review")
+	verificationRepo.On("GetByID", ctx, verification.ID).Return(verification, nil).Once()
+	_, err = uc.Review(ctx, admin.ID, verification.ID, entity.VerificationApproved, "")
+	assert.ErrorIs(t, err, entity.ErrInvalidVerification, "the company email is not confirmed yet")
+
+	verificationRepo.On("ConfirmEmail", ctx, verification.EmailToken).Return(true, nil)
+	confirmCompanyEmail(t, uc, mailer)
+	confirmed := *verification
+	now := time.Now()
+	confirmed.EmailConfirmedAt = &now
+	approved := confirmed
+	approved.Status = entity.VerificationApproved
+	verificationRepo.On("GetByID", ctx, verification.ID).Return(&confirmed, nil)
+	verificationRepo.On("Review", ctx, verification.ID, admin.ID, entity.VerificationApproved, "").Return(&approved, nil)
+
+	verification, err = uc.Review(ctx, admin.ID, verification.ID, entity.VerificationApproved, "")
+	require.NoError(t, err)
+	assert.Equal(t, entity.VerificationApproved, verification.Status)
+	require.Len(t, notifications.published(), 1)
+	assert.Equal(t, entity.NotificationVerification, notifications.published()[0].Type)
+	verificationRepo.AssertExpectations(t)
+
+	// Отметку о проверке ставит репозиторий вместе с решением
+	employer.IsVerified = true
+	_, err = uc.Submit(ctx, employer.ID, verificationRequest(), registrationCertificate())
+	assert.ErrorIs(t, err, entity.ErrAlreadyVerified)
+}
+
+func TestVerificationRequestInfoAndResubmit(t *testing.T) {
+	employer, _, admin := verificationUsers()
+	uc, verificationRepo, mailer, notifications := setupVerificationTest(t, employer, admin)
+	ctx := context.Background()
+
+	verificationRepo.On("GetLatest", ctx, employer.ID).Return(nil, nil).Once()
+	verificationRepo.expectCreate(1)
+	verification, err := uc.Submit(ctx, employer.ID, verificationRequest(), registrationCertificate())
+	require.NoError(t, err)
+
+	verificationRepo.On("GetByID", ctx, verification.ID).Return(verification, nil)
+	_, err = uc.Review(ctx, admin.ID, verification.ID, entity.VerificationInfoRequested, " ")
+	assert.ErrorIs(t, err, entity.ErrInvalidVerification, "the employer has to learn what is missing")
+
+	infoRequested := *verification
+	infoRequested.Status = entity.VerificationInfoRequested
+	verificationRepo.On("Review", ctx, verification.ID, admin.ID, entity.VerificationInfoRequested, "Приложите выписку из ЕГРЮЛ").
+		Return(&infoRequested, nil)
+	_, err = uc.Review(ctx, admin.ID, verification.ID, entity.VerificationInfoRequested, "Приложите выписку из ЕГРЮЛ")
+	require.NoError(t, err)
+	require.Len(t, notifications.published(), 1)
+	assert.Contains(t, notifications.published()[0].Body, "выписку из ЕГРЮЛ")
+
+	verificationRepo.On("GetLatest", ctx, employer.ID).Return(&infoRequested, nil)
+	verificationRepo.On("Resubmit", ctx, &infoRequested, mock.MatchedBy(func(documents []*entity.VerificationDocument) bool {
+		return len(documents) == 1 && documents[0].ContentType == attachment.ContentTypePNG
+	})).Return(nil)
+
+	resubmitted, err := uc.Submit(ctx, employer.ID,
+		&entity.EmployerVerification{RegistrationNumber: "1027700132195", CompanyEmail: "hr@acme.ru"},
+		[]DocumentFile{{Name: "egrul.png", Data: []byte("\x89PNG\r\n\x1a\nscan")}})
+	require.NoError(t, err)
+	assert.Equal(t, verification.ID, resubmitted.ID, "the same request goes back to the queue")
+	assert.Equal(t, "1027700132195", resubmitted.RegistrationNumber)
+	assert.Len(t, mailer.sent(), 2, "the unconfirmed email gets a new link")
+	verificationRepo.AssertExpectations(t)
+}
+
+func TestVerificationDocumentDownload(t *testing.T) {
+	employer, other, admin := verificationUsers()
+	uc, verificationRepo, _, _ := setupVerificationTest(t, employer, other, admin)
+	ctx := context.Background()
+
+	verificationRepo.On("GetLatest", ctx, employer.ID).Return(nil, nil).Once()
+	verificationRepo.expectCreate(1)
+	verification, err := uc.Submit(ctx, employer.ID, verificationRequest(), registrationCertificate())
+	require.NoError(t, err)
+	document := verification.Documents[0]
+	verificationRepo.On("GetDocument", ctx, document.ID).Return(document, nil)
+	verificationRepo.On("GetByID", ctx, verification.ID).Return(verification, nil)
+
+	link := func(viewerID int64) attachment.Link {
+		u, err := url.Parse(uc.signer.DocumentURL(document.ID, viewerID))
+		require.NoError(t, err)
+		expires, err := strconv.ParseInt(u.Query().Get("expires"), 10, 64)
+		require.NoError(t, err)
+		return attachment.Link{ViewerID: viewerID, Expires: expires, Signature: u.Query().Get("signature")}
+	}
+
+	for _, viewerID := range []int64{employer.ID, admin.ID} {
+		_, body, err := uc.DownloadDocument(ctx, document.ID, link(viewerID))
+		require.NoError(t, err)
+		data, err := io.ReadAll(body)
+		body.Close()
+		require.NoError(t, err)
+		assert.Equal(t, "%PDF-1.4 certificate", string(data))
+	}
+
+	_, _, err = uc.DownloadDocument(ctx, document.ID, link(other.ID))
+	assert.ErrorIs(t, err, ErrPermissionDenied, "another employer")
+}
@@ -0,0 +1,237 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/repository"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/webhook"
+)
+
+const (
+	// webhookBatchSize is how many deliveries one dispatch sends at once.
+	webhookBatchSize = 20
+	// webhookLease is how long a claimed delivery is hidden from other
+	// instances of the service. It must be longer than the send timeout.
+	webhookLease = time.Minute
+)
+
+type WebhookUsecaseInterface interface {
+	CreateEndpoint(ctx context.Context, employerID int64, endpoint *entity.WebhookEndpoint) error
+	ListEndpoints(ctx context.Context, employerID int64) ([]*entity.WebhookEndpoint, error)
+	GetEndpoint(ctx context.Context, employerID, id int64) (*entity.WebhookEndpoint, error)
+	UpdateEndpoint(ctx context.Context, employerID int64, endpoint *entity.WebhookEndpoint) error
+	DeleteEndpoint(ctx context.Context, employerID, id int64) error
+	ListDeliveries(ctx context.Context, employerID, endpointID int64, status string, limit, offset int) ([]*entity.WebhookDelivery, error)
+	GetDelivery(ctx context.Context, employerID, id int64) (*entity.WebhookDelivery, error)
+	Redeliver(ctx context.Context, employerID, id int64) (*entity.WebhookDelivery, error)
+}
+
+// WebhookUsecase manages the employer's webhook endpoints and sends the
+// queued deliveries. Events are queued by the repositories in the same
+// transaction as the change they describe.
+type WebhookUsecase struct {
+	webhookRepo repository.WebhookRepositoryInterface
+	userRepo    repository.UserRepositoryInterface
+	sender      *webhook.Sender
+	now         func() time.Time
+}
+
+func NewWebhookUsecase(
+	webhookRepo repository.WebhookRepositoryInterface,
+	userRepo repository.UserRepositoryInterface,
+	sender *webhook.Sender,
+) *WebhookUsecase {
+	return &WebhookUsecase{
+		webhookRepo: webhookRepo,
+		userRepo:    userRepo,
+		sender:      sender,
+		now:         time.Now,
+	}
+}
+
+func newWebhookSecret() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
+
+// CreateEndpoint registers an endpoint with a new signing secret. The secret
+// is returned in endpoint this once; later reads never show it.
+func (uc *WebhookUsecase) CreateEndpoint(ctx context.Context, employerID int64, endpoint *entity.WebhookEndpoint) error {
+	user, err := uc.userRepo.GetByID(ctx, employerID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil || user.Role != string(entity.RoleEmployer) {
+		return ErrPermissionDenied
+	}
+
+	endpoint.ID = 0
+	endpoint.EmployerID = employerID
+	endpoint.Active = true
+	if err := endpoint.Validate(); err != nil {
+		return err
+	}
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return err
+	}
+	endpoint.Secret = secret
+
+	return uc.webhookRepo.CreateEndpoint(ctx, endpoint)
+}
+
+func (uc *WebhookUsecase) ListEndpoints(ctx context.Context, employerID int64) ([]*entity.WebhookEndpoint, error) {
+	endpoints, err := uc.webhookRepo.ListEndpoints(ctx, employerID)
+	if err != nil {
+		return nil, err
+	}
+	for _, endpoint := range endpoints {
+		endpoint.Secret = ""
+	}
+	return endpoints, nil
+}
+
+// endpoint returns the endpoint with its secret if it belongs to the employer.
+func (uc *WebhookUsecase) endpoint(ctx context.Context, employerID, id int64) (*entity.WebhookEndpoint, error) {
+	endpoint, err := uc.webhookRepo.GetEndpoint(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if endpoint == nil || endpoint.EmployerID != employerID {
+		return nil, entity.ErrWebhookNotFound
+	}
+	return endpoint, nil
+}
+
+func (uc *WebhookUsecase) GetEndpoint(ctx context.Context, employerID, id int64) (*entity.WebhookEndpoint, error) {
+	endpoint, err := uc.endpoint(ctx, employerID, id)
+	if err != nil {
+		return nil, err
+	}
+	endpoint.Secret = ""
+	return endpoint, nil
+}
+
+// UpdateEndpoint changes the URL, the events and whether the endpoint is
+// active. Deliveries of an inactive endpoint wait until it is activated.
+func (uc *WebhookUsecase) UpdateEndpoint(ctx context.Context, employerID int64, endpoint *entity.WebhookEndpoint) error {
+	existing, err := uc.endpoint(ctx, employerID, endpoint.ID)
+	if err != nil {
+		return err
+	}
+
+	endpoint.EmployerID = employerID
+	endpoint.CreatedAt = existing.CreatedAt
+	if err := endpoint.Validate(); err != nil {
+		return err
+	}
+	if err := uc.webhookRepo.UpdateEndpoint(ctx, endpoint); err != nil {
+		return err
+	}
+	endpoint.Secret = ""
+	return nil
+}
+
+func (uc *WebhookUsecase) DeleteEndpoint(ctx context.Context, employerID, id int64) error {
+	if _, err := uc.endpoint(ctx, employerID, id); err != nil {
+		return err
+	}
+	return uc.webhookRepo.DeleteEndpoint(ctx, id)
+}
+
+func (uc *WebhookUsecase) ListDeliveries(ctx context.Context, employerID, endpointID int64, status string, limit, offset int) ([]*entity.WebhookDelivery, error) {
+	switch status {
+	case "", entity.DeliveryStatusPending, entity.DeliveryStatusSucceeded, entity.DeliveryStatusFailed:
+	default:
+		return nil, fmt.Errorf("%w: unknown delivery status %q", entity.ErrInvalidWebhook, status)
+	}
+	if _, err := uc.endpoint(ctx, employerID, endpointID); err != nil {
+		return nil, err
+	}
+	return uc.webhookRepo.ListDeliveries(ctx, endpointID, status, limit, offset)
+}
+
+// GetDelivery returns the delivery with its attempt log if it was sent to
+// one of the employer's endpoints.
+func (uc *WebhookUsecase) GetDelivery(ctx context.Context, employerID, id int64) (*entity.WebhookDelivery, error) {
+	delivery, err := uc.webhookRepo.GetDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if delivery == nil {
+		return nil, entity.ErrWebhookDeliveryNotFound
+	}
+	if _, err := uc.endpoint(ctx, employerID, delivery.EndpointID); err != nil {
+		return nil, entity.ErrWebhookDeliveryNotFound
+	}
+	return delivery, nil
+}
+
+// Redeliver queues the delivery's event again, whatever its status, and
+// returns the new delivery. It is sent with the next dispatch.
+func (uc *WebhookUsecase) Redeliver(ctx context.Context, employerID, id int64) (*entity.WebhookDelivery, error) {
+	if _, err := uc.GetDelivery(ctx, employerID, id); err != nil {
+		return nil, err
+	}
+	delivery, err := uc.webhookRepo.Redeliver(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if delivery == nil {
+		return nil, entity.ErrWebhookDeliveryNotFound
+	}
+	return delivery, nil
+}
+
+// Dispatch sends one batch of due deliveries and returns how many were
+// attempted. Failed deliveries are rescheduled with exponential backoff.
+func (uc *WebhookUsecase) Dispatch(ctx context.Context) (int, error) {
+	deliveries, err := uc.webhookRepo.ClaimDue(ctx, webhookBatchSize, webhookLease)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func(delivery *entity.WebhookDelivery) {
+			defer wg.Done()
+			attempt := uc.sender.Send(ctx, delivery)
+			delivery.Record(attempt, uc.now())
+			// Если запись не сохранилась, доставка повторится после истечения аренды
+			if err := uc.webhookRepo.RecordAttempt(ctx, delivery, attempt); err != nil {
+				fmt.Printf("Failed to record webhook attempt for delivery %d: %v\n", delivery.ID, err)
+			}
+		}(delivery)
+	}
+	wg.Wait()
+	return len(deliveries), nil
+}
+
+// Run dispatches deliveries until ctx is cancelled. A full batch is followed
+// by the next one right away; otherwise Run waits for interval.
+func (uc *WebhookUsecase) Run(ctx context.Context, interval time.Duration) {
+	for {
+		sent, err := uc.Dispatch(ctx)
+		if err != nil {
+			fmt.Printf("Failed to dispatch webhooks: %v\n", err)
+		}
+		if sent == webhookBatchSize {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
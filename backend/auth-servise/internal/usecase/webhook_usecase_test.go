@@ -0,0 +1,273 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockWebhookRepo struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepo) CreateEndpoint(ctx context.Context, endpoint *entity.WebhookEndpoint) error {
+	args := m.Called(ctx, endpoint)
+	return args.Error(0)
+}
+
+// GetEndpoint returns a copy of the endpoint, as each read from the database
+// does, so the usecase hiding the secret leaves the test's endpoint alone.
+func (m *MockWebhookRepo) GetEndpoint(ctx context.Context, id int64) (*entity.WebhookEndpoint, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	endpoint := *args.Get(0).(*entity.WebhookEndpoint)
+	return &endpoint, args.Error(1)
+}
+
+func (m *MockWebhookRepo) ListEndpoints(ctx context.Context, employerID int64) ([]*entity.WebhookEndpoint, error) {
+	args := m.Called(ctx, employerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.WebhookEndpoint), args.Error(1)
+}
+
+func (m *MockWebhookRepo) UpdateEndpoint(ctx context.Context, endpoint *entity.WebhookEndpoint) error {
+	args := m.Called(ctx, endpoint)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepo) DeleteEndpoint(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepo) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*entity.WebhookDelivery, error) {
+	args := m.Called(ctx, limit, lease)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepo) RecordAttempt(ctx context.Context, delivery *entity.WebhookDelivery, attempt *entity.WebhookAttempt) error {
+	args := m.Called(ctx, delivery, attempt)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepo) ListDeliveries(ctx context.Context, endpointID int64, status string, limit, offset int) ([]*entity.WebhookDelivery, error) {
+	args := m.Called(ctx, endpointID, status, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepo) GetDelivery(ctx context.Context, id int64) (*entity.WebhookDelivery, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepo) Redeliver(ctx context.Context, id int64) (*entity.WebhookDelivery, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.WebhookDelivery), args.Error(1)
+}
+
+// expectClaim makes the next dispatch claim the deliveries.
+func (m *MockWebhookRepo) expectClaim(deliveries ...*entity.WebhookDelivery) {
+	m.On("ClaimDue", mock.Anything, webhookBatchSize, webhookLease).Return(deliveries, nil).Once()
+}
+
+// receiver is an employer's endpoint that fails the first failures requests
+// and checks the signature of every request.
+type receiver struct {
+	mu       sync.Mutex
+	secret   string
+	failures int
+	bodies   [][]byte
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	body, _ := io.ReadAll(r.Body)
+	err := webhook.Verify(rc.secret, r.Header.Get(webhook.HeaderTimestamp), r.Header.Get(webhook.HeaderSignature), body, time.Minute, time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	rc.bodies = append(rc.bodies, body)
+	if rc.failures > 0 {
+		rc.failures--
+		http.Error(w, "try later", http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func setupWebhookTest(t *testing.T, failures int) (*WebhookUsecase, *MockWebhookRepo, *receiver, *entity.WebhookEndpoint) {
+	repo := new(MockWebhookRepo)
+	repo.On("CreateEndpoint", mock.Anything, mock.AnythingOfType("*entity.WebhookEndpoint")).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(1).(*entity.WebhookEndpoint).ID = 1
+	}).Once()
+	repo.On("RecordAttempt", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	users := new(MockUserRepo).withUsers(
+		&entity.User{ID: 1, Role: string(entity.RoleEmployer)},
+		&entity.User{ID: 2, Role: string(entity.RoleEmployer)},
+		&entity.User{ID: 99, Role: string(entity.RoleJobseeker)},
+	)
+	rc := &receiver{failures: failures}
+	server := httptest.NewTLSServer(rc)
+	t.Cleanup(server.Close)
+
+	uc := NewWebhookUsecase(repo, users, webhook.NewSenderWithClient(server.Client()))
+	endpoint := &entity.WebhookEndpoint{URL: "https://ats.example.com/hooks", Events: []string{entity.EventVacancyCreated}}
+	require.NoError(t, uc.CreateEndpoint(context.Background(), 1, endpoint))
+	// Локальный приемник не прошел бы проверку адреса, подставляем его напрямую
+	endpoint.URL = server.URL
+	repo.On("GetEndpoint", mock.Anything, endpoint.ID).Return(endpoint, nil).Maybe()
+	rc.secret = endpoint.Secret
+	return uc, repo, rc, endpoint
+}
+
+// queuedDelivery plays the part of the repositories queuing an event for the
+// endpoint.
+func queuedDelivery(id int64, endpoint *entity.WebhookEndpoint, event string, now time.Time) *entity.WebhookDelivery {
+	payload, _ := json.Marshal(entity.WebhookEvent{ID: "evt_test", Type: event, Data: entity.VacancyEventData{VacancyID: 1}})
+	return &entity.WebhookDelivery{
+		ID:            id,
+		EndpointID:    endpoint.ID,
+		Event:         event,
+		Payload:       payload,
+		Status:        entity.DeliveryStatusPending,
+		NextAttemptAt: &now,
+		URL:           endpoint.URL,
+		Secret:        endpoint.Secret,
+	}
+}
+
+func TestWebhookCreateEndpoint(t *testing.T) {
+	uc, repo, _, endpoint := setupWebhookTest(t, 0)
+	ctx := context.Background()
+
+	assert.Regexp(t, `^whsec_[0-9a-f]{48}$`, endpoint.Secret)
+	assert.True(t, endpoint.Active)
+	repo.AssertCalled(t, "CreateEndpoint", mock.Anything, endpoint)
+
+	got, err := uc.GetEndpoint(ctx, 1, endpoint.ID)
+	require.NoError(t, err)
+	assert.Empty(t, got.Secret, "the secret is only shown once")
+
+	_, err = uc.GetEndpoint(ctx, 2, endpoint.ID)
+	assert.ErrorIs(t, err, entity.ErrWebhookNotFound, "another employer's endpoint")
+
+	err = uc.CreateEndpoint(ctx, 99, &entity.WebhookEndpoint{URL: "https://example.com", Events: []string{entity.EventVacancyCreated}})
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+
+	err = uc.CreateEndpoint(ctx, 1, &entity.WebhookEndpoint{URL: "ftp://example.com", Events: []string{entity.EventVacancyCreated}})
+	assert.ErrorIs(t, err, entity.ErrInvalidWebhook)
+	repo.AssertNumberOfCalls(t, "CreateEndpoint", 1)
+}
+
+func TestWebhookDispatchRetriesWithBackoff(t *testing.T) {
+	uc, repo, rc, endpoint := setupWebhookTest(t, 2)
+	ctx := context.Background()
+	now := time.Now()
+	uc.now = func() time.Time { return now }
+	delivery := queuedDelivery(1, endpoint, entity.EventVacancyCreated, now)
+
+	repo.expectClaim(delivery)
+	sent, err := uc.Dispatch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, entity.DeliveryStatusPending, delivery.Status)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, http.StatusServiceUnavailable, *delivery.LastStatusCode)
+	assert.Equal(t, now.Add(30*time.Second), *delivery.NextAttemptAt)
+
+	repo.expectClaim()
+	sent, err = uc.Dispatch(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, sent, "the retry is not due yet")
+
+	now = now.Add(30 * time.Second)
+	repo.expectClaim(delivery)
+	_, err = uc.Dispatch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, delivery.Attempts)
+	assert.Equal(t, now.Add(time.Minute), *delivery.NextAttemptAt)
+
+	now = now.Add(time.Minute)
+	repo.expectClaim(delivery)
+	_, err = uc.Dispatch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, entity.DeliveryStatusSucceeded, delivery.Status)
+	assert.Equal(t, 3, delivery.Attempts)
+	assert.NotNil(t, delivery.DeliveredAt)
+
+	require.Len(t, rc.bodies, 3)
+	assert.JSONEq(t, string(delivery.Payload), string(rc.bodies[2]))
+	repo.AssertNumberOfCalls(t, "RecordAttempt", 3)
+}
+
+func TestWebhookDeliveryGivesUp(t *testing.T) {
+	uc, repo, _, endpoint := setupWebhookTest(t, entity.MaxWebhookAttempts)
+	delivery := queuedDelivery(1, endpoint, entity.EventVacancyCreated, time.Now())
+
+	for i := 0; i < entity.MaxWebhookAttempts; i++ {
+		repo.expectClaim(delivery)
+		_, err := uc.Dispatch(context.Background())
+		require.NoError(t, err)
+	}
+	assert.Equal(t, entity.DeliveryStatusFailed, delivery.Status)
+	assert.Equal(t, entity.MaxWebhookAttempts, delivery.Attempts)
+	assert.Nil(t, delivery.NextAttemptAt)
+}
+
+func TestWebhookRedeliver(t *testing.T) {
+	uc, repo, rc, endpoint := setupWebhookTest(t, 0)
+	ctx := context.Background()
+	delivery := queuedDelivery(1, endpoint, entity.EventVacancyCreated, time.Now())
+	repo.On("GetDelivery", mock.Anything, delivery.ID).Return(delivery, nil)
+	repo.expectClaim(delivery)
+	_, err := uc.Dispatch(ctx)
+	require.NoError(t, err)
+
+	_, err = uc.Redeliver(ctx, 2, delivery.ID)
+	assert.ErrorIs(t, err, entity.ErrWebhookDeliveryNotFound, "another employer's delivery")
+	repo.AssertNotCalled(t, "Redeliver", mock.Anything, delivery.ID)
+
+	queued := queuedDelivery(2, endpoint, entity.EventVacancyCreated, time.Now())
+	queued.RedeliveryOf = &delivery.ID
+	repo.On("Redeliver", mock.Anything, delivery.ID).Return(queued, nil).Once()
+	again, err := uc.Redeliver(ctx, 1, delivery.ID)
+	require.NoError(t, err)
+	assert.Equal(t, delivery.ID, *again.RedeliveryOf)
+	assert.Equal(t, entity.DeliveryStatusPending, again.Status)
+
+	repo.expectClaim(again)
+	_, err = uc.Dispatch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, entity.DeliveryStatusSucceeded, again.Status)
+	require.Len(t, rc.bodies, 2)
+	assert.Equal(t, rc.bodies[0], rc.bodies[1], "the same event is sent again")
+}
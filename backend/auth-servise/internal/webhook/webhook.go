@@ -0,0 +1,160 @@
+// Package webhook signs event payloads and posts them to employers'
+// endpoints.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+)
+
+// Headers sent with every delivery.
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// maxResponseSize limits the part of the endpoint's response kept in the
+// delivery log.
+const maxResponseSize = 4 << 10
+
+var (
+	ErrSignature = errors.New("invalid webhook signature")
+	ErrExpired   = errors.New("webhook timestamp is too old")
+	// ErrForbiddenAddress is returned when an endpoint resolves to a local or
+	// private address.
+	ErrForbiddenAddress = errors.New("webhook endpoint address is not public")
+)
+
+// Sign returns the signature of a payload sent at the given Unix time: the
+// hex HMAC-SHA256 of "timestamp.payload" prefixed with "sha256=". Signing
+// the timestamp lets receivers reject replayed deliveries.
+func Sign(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a delivery the way a receiver should: the signature must
+// match and the timestamp must be within tolerance of now.
+func Verify(secret, timestamp, signature string, payload []byte, tolerance time.Duration, now time.Time) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrSignature
+	}
+	if !hmac.Equal([]byte(Sign(secret, ts, payload)), []byte(signature)) {
+		return ErrSignature
+	}
+	if now.Sub(time.Unix(ts, 0)).Abs() > tolerance {
+		return ErrExpired
+	}
+	return nil
+}
+
+// Sender posts deliveries over HTTP.
+type Sender struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewSender returns a Sender that only connects to public addresses. The
+// check runs on the resolved address of every connection, so a host that
+// passed validation cannot later be pointed at the internal network through
+// DNS. Proxies from the environment are not used for the same reason.
+func NewSender(timeout time.Duration) *Sender {
+	dialer := &net.Dialer{Timeout: timeout, Control: refuseNonPublic}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return NewSenderWithClient(&http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		// Переадресация могла бы увести подписанный запрос на чужой адрес
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	})
+}
+
+// NewSenderWithClient returns a Sender that posts through the client as is,
+// without the address check of NewSender. Tests use it to reach local
+// receivers.
+func NewSenderWithClient(client *http.Client) *Sender {
+	return &Sender{client: client, now: time.Now}
+}
+
+// refuseNonPublic is a net.Dialer Control hook that aborts connections to
+// addresses webhooks must not reach.
+func refuseNonPublic(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, address)
+	}
+	if !entity.PublicAddress(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, addrPort.Addr())
+	}
+	return nil
+}
+
+// Send makes one attempt to deliver and returns its log entry. Transport
+// errors are recorded in the entry rather than returned.
+func (s *Sender) Send(ctx context.Context, delivery *entity.WebhookDelivery) *entity.WebhookAttempt {
+	started := s.now()
+	attempt := &entity.WebhookAttempt{
+		DeliveryID: delivery.ID,
+		Number:     delivery.Attempts + 1,
+		CreatedAt:  started,
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	// Адреса, сохраненные до запрета http, тоже не получают событий открытым текстом
+	if req.URL.Scheme != "https" {
+		attempt.Error = "endpoint url must use https"
+		return attempt
+	}
+	timestamp := started.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "job-platform-webhooks/1.0")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(delivery.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	attempt.DurationMS = s.now().Sub(started).Milliseconds()
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	attempt.StatusCode = &resp.StatusCode
+	attempt.Response = strings.ToValidUTF8(string(body), "")
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		attempt.Error = fmt.Sprintf("endpoint responded with %s", resp.Status)
+	}
+	return attempt
+}
@@ -0,0 +1,126 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Mandarinka0707/newRepoGOODarhit/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	payload := []byte(`{"type":"application.created"}`)
+	ts := strconv.FormatInt(now.Unix(), 10)
+	signature := Sign("secret", now.Unix(), payload)
+
+	assert.NoError(t, Verify("secret", ts, signature, payload, 5*time.Minute, now))
+	assert.ErrorIs(t, Verify("other", ts, signature, payload, 5*time.Minute, now), ErrSignature)
+	assert.ErrorIs(t, Verify("secret", ts, signature, []byte(`{}`), 5*time.Minute, now), ErrSignature)
+	assert.ErrorIs(t, Verify("secret", "later", signature, payload, 5*time.Minute, now), ErrSignature)
+	assert.ErrorIs(t, Verify("secret", ts, signature, payload, 5*time.Minute, now.Add(10*time.Minute)), ErrExpired)
+}
+
+func testDelivery(url string) *entity.WebhookDelivery {
+	payload, _ := json.Marshal(entity.WebhookEvent{
+		ID:   "evt_1",
+		Type: entity.EventApplicationStatusChanged,
+		Data: entity.ApplicationEventData{ApplicationID: 7, Status: entity.ApplicationStatusInterview},
+	})
+	return &entity.WebhookDelivery{
+		ID:       42,
+		Event:    entity.EventApplicationStatusChanged,
+		Payload:  payload,
+		Attempts: 2,
+		URL:      url,
+		Secret:   "whsec_test",
+	}
+}
+
+// localSender is NewSender allowed to reach the local test receiver.
+func localSender(receiver *httptest.Server) *Sender {
+	sender := NewSender(5 * time.Second)
+	sender.client.Transport = receiver.Client().Transport
+	return sender
+}
+
+func TestSenderRefusesNonPublicAddresses(t *testing.T) {
+	hit := false
+	receiver := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit = true
+	}))
+	defer receiver.Close()
+
+	attempt := NewSender(5*time.Second).Send(context.Background(), testDelivery(receiver.URL))
+	assert.False(t, attempt.Succeeded())
+	assert.Nil(t, attempt.StatusCode)
+	assert.Contains(t, attempt.Error, ErrForbiddenAddress.Error())
+	assert.False(t, hit, "the request never reaches the loopback receiver")
+
+	attempt = NewSender(5*time.Second).Send(context.Background(), testDelivery("http://ats.example.com/hooks"))
+	assert.False(t, attempt.Succeeded())
+	assert.Contains(t, attempt.Error, "https")
+}
+
+func TestSenderDeliversSignedPayload(t *testing.T) {
+	var received *http.Request
+	var body []byte
+	receiver := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		err := Verify("whsec_test", r.Header.Get(HeaderTimestamp), r.Header.Get(HeaderSignature), body, time.Minute, time.Now())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer receiver.Close()
+
+	delivery := testDelivery(receiver.URL)
+	attempt := localSender(receiver).Send(context.Background(), delivery)
+
+	require.True(t, attempt.Succeeded(), attempt.Error)
+	assert.Equal(t, 3, attempt.Number)
+	assert.Equal(t, "ok", attempt.Response)
+	assert.Equal(t, http.MethodPost, received.Method)
+	assert.Equal(t, "application/json", received.Header.Get("Content-Type"))
+	assert.Equal(t, entity.EventApplicationStatusChanged, received.Header.Get(HeaderEvent))
+	assert.Equal(t, "42", received.Header.Get(HeaderDelivery))
+	assert.JSONEq(t, string(delivery.Payload), string(body))
+}
+
+func TestSenderRecordsFailures(t *testing.T) {
+	receiver := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "/elsewhere", http.StatusFound)
+			return
+		}
+		http.Error(w, "maintenance", http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+	sender := localSender(receiver)
+
+	attempt := sender.Send(context.Background(), testDelivery(receiver.URL))
+	assert.False(t, attempt.Succeeded())
+	require.NotNil(t, attempt.StatusCode)
+	assert.Equal(t, http.StatusServiceUnavailable, *attempt.StatusCode)
+	assert.Contains(t, attempt.Response, "maintenance")
+
+	attempt = sender.Send(context.Background(), testDelivery(receiver.URL+"/redirect"))
+	assert.False(t, attempt.Succeeded(), "redirects are not followed")
+	assert.Equal(t, http.StatusFound, *attempt.StatusCode)
+
+	receiver.Close()
+	attempt = sender.Send(context.Background(), testDelivery(receiver.URL))
+	assert.False(t, attempt.Succeeded())
+	assert.Nil(t, attempt.StatusCode)
+	assert.NotEmpty(t, attempt.Error)
+}
@@ -0,0 +1,15 @@
+-- Удаляем индексы
+DROP INDEX IF EXISTS idx_resume_certifications_resume_id;
+DROP INDEX IF EXISTS idx_resume_languages_resume_id;
+DROP INDEX IF EXISTS idx_resume_educations_resume_id;
+DROP INDEX IF EXISTS idx_resume_work_experiences_resume_id;
+
+-- Удаляем таблицы разделов резюме
+DROP TABLE IF EXISTS resume_certifications;
+DROP TABLE IF EXISTS resume_languages;
+DROP TABLE IF EXISTS resume_educations;
+DROP TABLE IF EXISTS resume_work_experiences;
+
+-- Возвращаем исходные названия колонок
+ALTER TABLE resumes RENAME COLUMN legacy_education TO education;
+ALTER TABLE resumes RENAME COLUMN legacy_experience TO experience;
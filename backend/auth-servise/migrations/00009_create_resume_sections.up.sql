@@ -0,0 +1,61 @@
+-- Переносим текстовые поля опыта и образования в legacy-колонки
+ALTER TABLE resumes RENAME COLUMN experience TO legacy_experience;
+ALTER TABLE resumes RENAME COLUMN education TO legacy_education;
+
+-- Создаем таблицу мест работы
+CREATE TABLE resume_work_experiences (
+    id BIGSERIAL PRIMARY KEY,
+    resume_id INTEGER NOT NULL REFERENCES resumes(id) ON DELETE CASCADE,
+    company VARCHAR(255) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (end_date IS NULL OR end_date >= start_date)
+);
+
+-- Создаем таблицу образования
+CREATE TABLE resume_educations (
+    id BIGSERIAL PRIMARY KEY,
+    resume_id INTEGER NOT NULL REFERENCES resumes(id) ON DELETE CASCADE,
+    institution VARCHAR(255) NOT NULL,
+    degree VARCHAR(255) NOT NULL DEFAULT '',
+    field_of_study VARCHAR(255) NOT NULL DEFAULT '',
+    start_year INTEGER NOT NULL,
+    end_year INTEGER,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (end_year IS NULL OR end_year >= start_year)
+);
+
+-- Создаем таблицу языков
+CREATE TABLE resume_languages (
+    id BIGSERIAL PRIMARY KEY,
+    resume_id INTEGER NOT NULL REFERENCES resumes(id) ON DELETE CASCADE,
+    language VARCHAR(100) NOT NULL,
+    level VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (resume_id, language)
+);
+
+-- Создаем таблицу сертификатов
+CREATE TABLE resume_certifications (
+    id BIGSERIAL PRIMARY KEY,
+    resume_id INTEGER NOT NULL REFERENCES resumes(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    issuer VARCHAR(255) NOT NULL DEFAULT '',
+    issued_at DATE,
+    expires_at DATE,
+    credential_url VARCHAR(1024) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Создаем индексы
+CREATE INDEX idx_resume_work_experiences_resume_id ON resume_work_experiences(resume_id);
+CREATE INDEX idx_resume_educations_resume_id ON resume_educations(resume_id);
+CREATE INDEX idx_resume_languages_resume_id ON resume_languages(resume_id);
+CREATE INDEX idx_resume_certifications_resume_id ON resume_certifications(resume_id);
@@ -0,0 +1,9 @@
+-- Удаляем индексы
+DROP INDEX IF EXISTS idx_resumes_status;
+DROP INDEX IF EXISTS idx_resumes_skills;
+
+-- Возвращаем навыки в JSONB
+ALTER TABLE resumes ADD COLUMN skills_json JSONB DEFAULT '[]'::jsonb;
+UPDATE resumes SET skills_json = to_jsonb(skills);
+ALTER TABLE resumes DROP COLUMN skills;
+ALTER TABLE resumes RENAME COLUMN skills_json TO skills;
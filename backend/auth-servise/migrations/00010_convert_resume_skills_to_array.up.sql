@@ -0,0 +1,34 @@
+-- Переводим навыки резюме с JSONB на TEXT[], как у вакансий
+ALTER TABLE resumes ADD COLUMN skills_array TEXT[] NOT NULL DEFAULT '{}';
+
+-- Восстанавливаем существующие данные: JSON-массивы переносим поэлементно,
+-- строки, записанные старым кодом через запятую, разбиваем, прочее отбрасываем
+UPDATE resumes
+SET skills_array = COALESCE((
+    SELECT array_agg(skill ORDER BY ord)
+    FROM (
+        SELECT DISTINCT ON (lower(btrim(value))) btrim(value) AS skill, ord
+        FROM (
+            SELECT value, ord
+            FROM jsonb_array_elements_text(
+                CASE jsonb_typeof(skills) WHEN 'array' THEN skills ELSE '[]'::jsonb END
+            ) WITH ORDINALITY AS e(value, ord)
+            UNION ALL
+            SELECT btrim(value, ' "'), ord
+            FROM unnest(string_to_array(
+                CASE jsonb_typeof(skills) WHEN 'string' THEN btrim(skills #>> '{}', '{}') ELSE '' END,
+                ','
+            )) WITH ORDINALITY AS s(value, ord)
+        ) raw
+        WHERE btrim(value) <> ''
+        ORDER BY lower(btrim(value)), ord
+    ) cleaned
+), '{}')
+WHERE skills IS NOT NULL;
+
+ALTER TABLE resumes DROP COLUMN skills;
+ALTER TABLE resumes RENAME COLUMN skills_array TO skills;
+
+-- Создаем GIN-индексы для поиска по навыкам (@> и &&)
+CREATE INDEX idx_resumes_skills ON resumes USING GIN (skills);
+CREATE INDEX idx_resumes_status ON resumes(status);
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS contact_requests;
+DROP TABLE IF EXISTS resume_employer_blocks;
+
+ALTER TABLE users DROP COLUMN IF EXISTS is_verified;
+
+ALTER TABLE resumes
+    DROP COLUMN IF EXISTS public_token,
+    DROP COLUMN IF EXISTS visibility;
@@ -0,0 +1,32 @@
+-- Добавляем видимость резюме и публичную ссылку
+ALTER TABLE resumes
+    ADD COLUMN visibility VARCHAR(20) NOT NULL DEFAULT 'employers'
+        CHECK (visibility IN ('private', 'applied', 'employers', 'public')),
+    ADD COLUMN public_token VARCHAR(64) UNIQUE;
+
+-- Отмечаем проверенных работодателей
+ALTER TABLE users ADD COLUMN is_verified BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Создаем таблицу работодателей, от которых соискатель скрывает резюме
+CREATE TABLE resume_employer_blocks (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    employer_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, employer_id)
+);
+
+-- Создаем таблицу запросов контактов
+CREATE TABLE contact_requests (
+    id BIGSERIAL PRIMARY KEY,
+    employer_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    jobseeker_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    resume_id BIGINT REFERENCES resumes(id) ON DELETE SET NULL,
+    message TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'accepted', 'declined')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    responded_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (employer_id, jobseeker_id)
+);
+
+CREATE INDEX idx_contact_requests_jobseeker_id ON contact_requests(jobseeker_id);
@@ -0,0 +1,12 @@
+-- Создаем таблицу файлов, прикрепленных к резюме
+CREATE TABLE resume_attachments (
+    id BIGSERIAL PRIMARY KEY,
+    resume_id BIGINT NOT NULL REFERENCES resumes(id) ON DELETE CASCADE,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL CHECK (size > 0),
+    storage_key VARCHAR(512) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_resume_attachments_resume_id ON resume_attachments(resume_id);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_resumes_experience_years;
+DROP INDEX IF EXISTS idx_resumes_updated_at;
+
+ALTER TABLE resumes
+    DROP COLUMN IF EXISTS experience_years,
+    DROP COLUMN IF EXISTS location;
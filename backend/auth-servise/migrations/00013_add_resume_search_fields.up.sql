@@ -0,0 +1,16 @@
+-- Добавляем поля для поиска кандидатов
+ALTER TABLE resumes
+    ADD COLUMN location VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN experience_years NUMERIC(4, 1) NOT NULL DEFAULT 0;
+
+-- Заполняем стаж по уже указанным местам работы. Пересечения периодов здесь
+-- не учитываются; точное значение сохраняется при следующем изменении опыта.
+UPDATE resumes r
+SET experience_years = LEAST(999.9, COALESCE((
+    SELECT ROUND((SUM(COALESCE(w.end_date, CURRENT_DATE) - w.start_date) / 365.25)::numeric, 1)
+    FROM resume_work_experiences w
+    WHERE w.resume_id = r.id AND COALESCE(w.end_date, CURRENT_DATE) > w.start_date
+), 0));
+
+CREATE INDEX idx_resumes_updated_at ON resumes(updated_at DESC);
+CREATE INDEX idx_resumes_experience_years ON resumes(experience_years);
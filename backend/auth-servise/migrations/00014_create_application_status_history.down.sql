@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS application_status_history;
+
+ALTER TABLE applications DROP CONSTRAINT IF EXISTS applications_status_check;
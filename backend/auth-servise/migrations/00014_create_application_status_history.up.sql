@@ -0,0 +1,29 @@
+-- Приводим статусы откликов к новому набору. Прежний "accepted" означал,
+-- что работодатель заинтересовался кандидатом.
+UPDATE applications SET status = 'shortlisted' WHERE status = 'accepted';
+UPDATE applications SET status = 'pending'
+WHERE status NOT IN ('pending', 'viewed', 'shortlisted', 'interview', 'offer', 'hired', 'rejected', 'withdrawn');
+
+ALTER TABLE applications ADD CONSTRAINT applications_status_check
+    CHECK (status IN ('pending', 'viewed', 'shortlisted', 'interview', 'offer', 'hired', 'rejected', 'withdrawn'));
+
+-- Создаем таблицу истории статусов откликов
+CREATE TABLE application_status_history (
+    id BIGSERIAL PRIMARY KEY,
+    application_id BIGINT NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    from_status VARCHAR(50) NOT NULL DEFAULT '',
+    to_status VARCHAR(50) NOT NULL,
+    actor_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_application_status_history_application_id
+    ON application_status_history(application_id, created_at);
+
+-- Переносим текущее состояние существующих откликов в историю
+INSERT INTO application_status_history (application_id, from_status, to_status, actor_id, created_at)
+SELECT id, '', 'pending', user_id, created_at FROM applications;
+
+INSERT INTO application_status_history (application_id, from_status, to_status, actor_id, created_at)
+SELECT id, 'pending', status, NULL, updated_at FROM applications WHERE status <> 'pending';
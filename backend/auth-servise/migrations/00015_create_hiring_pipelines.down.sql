@@ -0,0 +1,5 @@
+ALTER TABLE applications DROP COLUMN IF EXISTS stage_id;
+ALTER TABLE vacancies DROP COLUMN IF EXISTS pipeline_id;
+
+DROP TABLE IF EXISTS pipeline_stages;
+DROP TABLE IF EXISTS hiring_pipelines;
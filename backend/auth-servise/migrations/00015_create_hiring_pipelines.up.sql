@@ -0,0 +1,29 @@
+-- Шаблоны воронок найма работодателя
+CREATE TABLE hiring_pipelines (
+    id BIGSERIAL PRIMARY KEY,
+    employer_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_hiring_pipelines_employer_id ON hiring_pipelines(employer_id);
+
+-- Этапы воронки в порядке position
+CREATE TABLE pipeline_stages (
+    id BIGSERIAL PRIMARY KEY,
+    pipeline_id BIGINT NOT NULL REFERENCES hiring_pipelines(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    position INT NOT NULL,
+    UNIQUE (pipeline_id, position) DEFERRABLE INITIALLY DEFERRED
+);
+
+-- Воронка, назначенная вакансии
+ALTER TABLE vacancies
+    ADD COLUMN pipeline_id BIGINT REFERENCES hiring_pipelines(id) ON DELETE SET NULL;
+
+-- Текущий этап отклика в воронке вакансии
+ALTER TABLE applications
+    ADD COLUMN stage_id BIGINT REFERENCES pipeline_stages(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_applications_stage_id ON applications(stage_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS interview_slots;
+DROP TABLE IF EXISTS interviews;
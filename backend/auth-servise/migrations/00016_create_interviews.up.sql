@@ -0,0 +1,34 @@
+-- Интервью по отклику. Пока кандидат не выбрал слот, starts_at пустой
+CREATE TABLE interviews (
+    id BIGSERIAL PRIMARY KEY,
+    application_id BIGINT NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    employer_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    candidate_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL,
+    duration_minutes INT NOT NULL CHECK (duration_minutes BETWEEN 15 AND 480),
+    location VARCHAR(500) NOT NULL DEFAULT '',
+    video_link VARCHAR(1000) NOT NULL DEFAULT '',
+    -- Адреса электронной почты интервьюеров
+    interviewers TEXT[] NOT NULL DEFAULT '{}',
+    notes TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'proposed'
+        CHECK (status IN ('proposed', 'scheduled', 'cancelled')),
+    starts_at TIMESTAMP WITH TIME ZONE,
+    ends_at TIMESTAMP WITH TIME ZONE,
+    -- SEQUENCE из RFC 5545, растет при каждом изменении приглашения
+    sequence INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_interviews_application_id ON interviews(application_id);
+CREATE INDEX idx_interviews_scheduled ON interviews(starts_at, ends_at) WHERE status = 'scheduled';
+CREATE INDEX idx_interviews_interviewers ON interviews USING GIN (interviewers);
+
+-- Слоты, предложенные работодателем
+CREATE TABLE interview_slots (
+    id BIGSERIAL PRIMARY KEY,
+    interview_id BIGINT NOT NULL REFERENCES interviews(id) ON DELETE CASCADE,
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    UNIQUE (interview_id, starts_at)
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS application_tags;
+DROP TABLE IF EXISTS application_ratings;
+DROP TABLE IF EXISTS application_notes;
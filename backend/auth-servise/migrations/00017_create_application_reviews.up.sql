@@ -0,0 +1,32 @@
+-- Заметки работодателя об откликах
+CREATE TABLE application_notes (
+    id BIGSERIAL PRIMARY KEY,
+    application_id BIGINT NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    author_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_application_notes_application_id ON application_notes(application_id, created_at);
+
+-- Оценка кандидата: одна на каждого проверяющего
+CREATE TABLE application_ratings (
+    application_id BIGINT NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    reviewer_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (application_id, reviewer_id)
+);
+
+-- Произвольные теги откликов
+CREATE TABLE application_tags (
+    application_id BIGINT NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    tag VARCHAR(50) NOT NULL,
+    created_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (application_id, tag)
+);
+
+CREATE INDEX idx_application_tags_tag ON application_tags(tag);
@@ -0,0 +1,3 @@
+ALTER TABLE applications DROP COLUMN IF EXISTS screening_flagged;
+DROP TABLE IF EXISTS application_screening_answers;
+DROP TABLE IF EXISTS vacancy_screening_questions;
@@ -0,0 +1,38 @@
+-- Отсеивающие вопросы вакансии
+CREATE TABLE vacancy_screening_questions (
+    id BIGSERIAL PRIMARY KEY,
+    vacancy_id BIGINT NOT NULL REFERENCES vacancies(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('yes_no', 'single_choice', 'multi_choice', 'number', 'text')),
+    text TEXT NOT NULL,
+    options TEXT[] NOT NULL DEFAULT '{}',
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    -- Что делать с откликом, не прошедшим вопрос
+    knockout_action VARCHAR(10) NOT NULL DEFAULT 'none' CHECK (knockout_action IN ('none', 'reject', 'flag')),
+    expected_bool BOOLEAN,
+    accepted_options TEXT[] NOT NULL DEFAULT '{}',
+    min_value DOUBLE PRECISION,
+    max_value DOUBLE PRECISION
+);
+
+CREATE INDEX idx_vacancy_screening_questions_vacancy_id ON vacancy_screening_questions(vacancy_id, position);
+
+-- Ответы соискателя. Текст вопроса копируется, чтобы ответ оставался
+-- понятным после изменения вопросов вакансии.
+CREATE TABLE application_screening_answers (
+    id BIGSERIAL PRIMARY KEY,
+    application_id BIGINT NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    question_id BIGINT REFERENCES vacancy_screening_questions(id) ON DELETE SET NULL,
+    question_text TEXT NOT NULL,
+    question_type VARCHAR(20) NOT NULL,
+    bool_value BOOLEAN,
+    number_value DOUBLE PRECISION,
+    text_value TEXT NOT NULL DEFAULT '',
+    options TEXT[] NOT NULL DEFAULT '{}',
+    passed BOOLEAN NOT NULL DEFAULT TRUE
+);
+
+CREATE INDEX idx_application_screening_answers_application_id ON application_screening_answers(application_id);
+
+-- Отклик, не прошедший вопрос с пометкой "flag"
+ALTER TABLE applications ADD COLUMN screening_flagged BOOLEAN NOT NULL DEFAULT FALSE;
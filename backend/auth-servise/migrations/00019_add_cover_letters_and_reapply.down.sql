@@ -0,0 +1,3 @@
+ALTER TABLE vacancies DROP COLUMN IF EXISTS reapply_after_days;
+ALTER TABLE vacancies DROP COLUMN IF EXISTS allow_reapply;
+ALTER TABLE applications DROP COLUMN IF EXISTS cover_letter;
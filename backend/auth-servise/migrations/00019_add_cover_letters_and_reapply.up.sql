@@ -0,0 +1,6 @@
+-- Сопроводительное письмо к отклику
+ALTER TABLE applications ADD COLUMN cover_letter TEXT NOT NULL DEFAULT '';
+
+-- Правила повторного отклика после отзыва
+ALTER TABLE vacancies ADD COLUMN allow_reapply BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE vacancies ADD COLUMN reapply_after_days INTEGER NOT NULL DEFAULT 0 CHECK (reapply_after_days >= 0);
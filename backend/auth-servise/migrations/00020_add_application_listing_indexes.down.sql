@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_applications_user_id;
+DROP INDEX IF EXISTS idx_applications_vacancy_created_at;
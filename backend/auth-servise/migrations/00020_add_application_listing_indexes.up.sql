@@ -0,0 +1,4 @@
+-- Индексы для списка откликов работодателя: отбор по вакансии и дате,
+-- сортировка по дате подачи
+CREATE INDEX IF NOT EXISTS idx_applications_vacancy_created_at ON applications(vacancy_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_applications_user_id ON applications(user_id);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS applications_user_vacancy_key;
@@ -0,0 +1,22 @@
+-- Закрываем старые дубли: оставляем самый ранний открытый отклик
+-- пользователя на вакансию, остальные считаем отозванными
+WITH duplicates AS (
+    SELECT id, status, ROW_NUMBER() OVER (PARTITION BY user_id, vacancy_id ORDER BY created_at, id) AS n
+    FROM applications
+    WHERE status <> 'withdrawn'
+), closed AS (
+    UPDATE applications a
+    SET status = 'withdrawn', updated_at = CURRENT_TIMESTAMP
+    FROM duplicates d
+    WHERE a.id = d.id AND d.n > 1
+    RETURNING a.id, d.status AS from_status
+)
+INSERT INTO application_status_history (application_id, from_status, to_status, actor_id, comment)
+SELECT id, from_status, 'withdrawn', NULL, 'Повторный отклик на ту же вакансию'
+FROM closed;
+
+-- Один действующий отклик на вакансию. Отозванные не учитываются,
+-- чтобы можно было откликнуться повторно, если вакансия это разрешает.
+CREATE UNIQUE INDEX applications_user_vacancy_key
+    ON applications(user_id, vacancy_id)
+    WHERE status <> 'withdrawn';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS application_thread_reads;
+DROP TABLE IF EXISTS application_message_attachments;
+DROP TABLE IF EXISTS application_messages;
@@ -0,0 +1,33 @@
+-- Переписка работодателя и кандидата по отклику
+CREATE TABLE application_messages (
+    id BIGSERIAL PRIMARY KEY,
+    application_id BIGINT NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    -- NULL у системных сообщений о смене статуса
+    sender_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    kind VARCHAR(20) NOT NULL DEFAULT 'user' CHECK (kind IN ('user', 'system')),
+    body TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX application_messages_thread_idx ON application_messages(application_id, id);
+
+CREATE TABLE application_message_attachments (
+    id BIGSERIAL PRIMARY KEY,
+    message_id BIGINT NOT NULL REFERENCES application_messages(id) ON DELETE CASCADE,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL,
+    storage_key VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX application_message_attachments_message_idx ON application_message_attachments(message_id);
+
+-- Последнее прочитанное сообщение и время последнего визита участника
+CREATE TABLE application_thread_reads (
+    application_id BIGINT NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    last_read_message_id BIGINT NOT NULL DEFAULT 0,
+    last_seen_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (application_id, user_id)
+);
@@ -0,0 +1,17 @@
+-- Фоновые выгрузки откликов работодателя в CSV и XLSX
+CREATE TABLE application_exports (
+    id BIGSERIAL PRIMARY KEY,
+    employer_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    format VARCHAR(10) NOT NULL CHECK (format IN ('csv', 'xlsx')),
+    filter JSONB NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'done', 'failed')),
+    row_count INTEGER NOT NULL DEFAULT 0,
+    file_name VARCHAR(255) NOT NULL DEFAULT '',
+    storage_key VARCHAR(255) NOT NULL DEFAULT '',
+    size BIGINT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMP
+);
+
+CREATE INDEX application_exports_employer_idx ON application_exports(employer_id, created_at DESC);
@@ -0,0 +1,29 @@
+-- Уведомления пользователей. Строка хранится, если уведомление показывается
+-- в приложении или ждет ежедневной сводки
+CREATE TABLE notifications (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL DEFAULT '',
+    link VARCHAR(255) NOT NULL DEFAULT '',
+    in_app BOOLEAN NOT NULL DEFAULT TRUE,
+    digest BOOLEAN NOT NULL DEFAULT FALSE,
+    read_at TIMESTAMP,
+    digested_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX notifications_user_idx ON notifications(user_id, id DESC) WHERE in_app;
+CREATE INDEX notifications_unread_idx ON notifications(user_id) WHERE in_app AND read_at IS NULL;
+CREATE INDEX notifications_digest_idx ON notifications(user_id, id) WHERE digest AND digested_at IS NULL;
+
+-- Каналы доставки уведомлений каждого типа; без строки действуют значения по умолчанию
+CREATE TABLE notification_preferences (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    in_app BOOLEAN NOT NULL,
+    email BOOLEAN NOT NULL,
+    digest BOOLEAN NOT NULL,
+    PRIMARY KEY (user_id, type)
+);
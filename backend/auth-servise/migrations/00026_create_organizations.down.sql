@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS vacancies_organization_idx;
+ALTER TABLE vacancies DROP COLUMN IF EXISTS organization_id;
+DROP TABLE IF EXISTS organization_invites;
+DROP TABLE IF EXISTS organization_members;
+DROP TABLE IF EXISTS organizations;
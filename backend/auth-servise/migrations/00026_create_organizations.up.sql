@@ -0,0 +1,40 @@
+-- Компании, аккаунты которых совместно ведут вакансии и отклики
+CREATE TABLE organizations (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    logo_url TEXT NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    website TEXT NOT NULL DEFAULT '',
+    size VARCHAR(20) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Аккаунт состоит не более чем в одной компании
+CREATE TABLE organization_members (
+    organization_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('owner', 'recruiter', 'viewer')),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (organization_id, user_id),
+    CONSTRAINT organization_members_user_key UNIQUE (user_id)
+);
+
+CREATE TABLE organization_invites (
+    id BIGSERIAL PRIMARY KEY,
+    organization_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('recruiter', 'viewer')),
+    token VARCHAR(64) NOT NULL UNIQUE,
+    invited_by BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP NOT NULL,
+    accepted_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX organization_invites_organization_idx ON organization_invites(organization_id, created_at DESC);
+
+-- Вакансия без компании доступна только разместившему ее аккаунту
+ALTER TABLE vacancies ADD COLUMN organization_id BIGINT REFERENCES organizations(id) ON DELETE SET NULL;
+
+CREATE INDEX vacancies_organization_idx ON vacancies(organization_id) WHERE organization_id IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS verification_documents;
+DROP TABLE IF EXISTS employer_verifications;
@@ -0,0 +1,35 @@
+-- Заявки работодателей на верификацию
+CREATE TABLE employer_verifications (
+    id BIGSERIAL PRIMARY KEY,
+    employer_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    registration_number VARCHAR(15) NOT NULL,
+    company_email VARCHAR(255) NOT NULL,
+    email_token VARCHAR(64) NOT NULL UNIQUE,
+    email_confirmed_at TIMESTAMP,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'info_requested', 'approved', 'rejected')),
+    admin_comment TEXT NOT NULL DEFAULT '',
+    reviewed_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- У работодателя не больше одной незакрытой заявки
+CREATE UNIQUE INDEX employer_verifications_open_idx ON employer_verifications(employer_id)
+    WHERE status IN ('pending', 'info_requested');
+
+-- Очередь модерации: сначала самые давние заявки
+CREATE INDEX employer_verifications_queue_idx ON employer_verifications(status, updated_at);
+
+CREATE TABLE verification_documents (
+    id BIGSERIAL PRIMARY KEY,
+    verification_id BIGINT NOT NULL REFERENCES employer_verifications(id) ON DELETE CASCADE,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL,
+    storage_key TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX verification_documents_verification_idx ON verification_documents(verification_id);
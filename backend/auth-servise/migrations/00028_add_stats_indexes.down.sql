@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_applications_created_at;
+DROP INDEX IF EXISTS idx_vacancies_created_at;
+DROP INDEX IF EXISTS idx_users_created_at;
@@ -0,0 +1,4 @@
+-- Индексы для статистики админ-панели по периодам
+CREATE INDEX IF NOT EXISTS idx_users_created_at ON users(created_at);
+CREATE INDEX IF NOT EXISTS idx_vacancies_created_at ON vacancies(created_at);
+CREATE INDEX IF NOT EXISTS idx_applications_created_at ON applications(created_at);
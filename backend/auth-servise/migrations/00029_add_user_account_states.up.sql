@@ -0,0 +1,11 @@
+-- Блокировки пользователей: временная приостановка, бан и мягкое удаление
+ALTER TABLE users
+    ADD COLUMN suspended_until TIMESTAMPTZ,
+    ADD COLUMN suspension_reason TEXT NOT NULL DEFAULT '',
+    ADD COLUMN banned_at TIMESTAMPTZ,
+    ADD COLUMN ban_reason TEXT NOT NULL DEFAULT '',
+    ADD COLUMN deleted_at TIMESTAMPTZ;
+
+-- Для списков ограниченных аккаунтов в админ-панели
+CREATE INDEX IF NOT EXISTS idx_users_restricted ON users(id)
+    WHERE suspended_until IS NOT NULL OR banned_at IS NOT NULL OR deleted_at IS NOT NULL;
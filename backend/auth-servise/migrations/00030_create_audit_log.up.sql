@@ -0,0 +1,38 @@
+-- Журнал действий администраторов и событий безопасности. Записи только
+-- добавляются; каждая хранит хеш предыдущей, поэтому правка или удаление
+-- записи в обход сервиса обнаруживается при проверке цепочки.
+-- Внешних ключей нет: журнал должен пережить удаление пользователей.
+CREATE TABLE audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    actor_id BIGINT,
+    action VARCHAR(50) NOT NULL,
+    target_type VARCHAR(30) NOT NULL DEFAULT '',
+    target_id BIGINT,
+    -- JSON, а не JSONB: текст снимка хранится как есть и входит в хеш
+    before_state JSON,
+    after_state JSON,
+    ip VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL,
+    prev_hash VARCHAR(64) NOT NULL DEFAULT '',
+    hash VARCHAR(64) NOT NULL
+);
+
+CREATE INDEX audit_log_actor_idx ON audit_log(actor_id, id DESC);
+CREATE INDEX audit_log_target_idx ON audit_log(target_type, target_id, id DESC);
+CREATE INDEX audit_log_action_idx ON audit_log(action, id DESC);
+CREATE INDEX audit_log_created_at_idx ON audit_log(created_at);
+
+CREATE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_no_update
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+
+CREATE TRIGGER audit_log_no_truncate
+    BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
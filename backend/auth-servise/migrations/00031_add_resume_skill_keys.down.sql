@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_resumes_skill_keys;
+CREATE INDEX idx_resumes_skills ON resumes USING GIN (skills);
+
+ALTER TABLE resumes DROP COLUMN skill_keys;
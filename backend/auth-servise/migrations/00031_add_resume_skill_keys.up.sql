@@ -0,0 +1,8 @@
+-- Поиск по навыкам не зависит от регистра: ищем по копии навыков в нижнем регистре
+ALTER TABLE resumes ADD COLUMN skill_keys TEXT[] NOT NULL DEFAULT '{}';
+
+UPDATE resumes
+SET skill_keys = ARRAY(SELECT DISTINCT lower(skill) FROM unnest(skills) AS skill);
+
+DROP INDEX IF EXISTS idx_resumes_skills;
+CREATE INDEX idx_resumes_skill_keys ON resumes USING GIN (skill_keys);
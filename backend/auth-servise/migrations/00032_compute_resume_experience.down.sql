@@ -0,0 +1,5 @@
+ALTER TABLE resumes ADD COLUMN experience_years NUMERIC(4, 1) NOT NULL DEFAULT 0;
+UPDATE resumes SET experience_years = resume_experience_years(id);
+CREATE INDEX idx_resumes_experience_years ON resumes(experience_years);
+
+DROP FUNCTION IF EXISTS resume_experience_years(BIGINT);
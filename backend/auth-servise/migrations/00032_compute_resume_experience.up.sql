@@ -0,0 +1,30 @@
+-- Стаж считается при запросе: сохраненное значение устаревало для текущих мест
+-- работы и не учитывало пересечения периодов. Пересекающиеся и смежные
+-- периоды сливаются, незавершенные длятся до сегодняшнего дня, как в
+-- entity.TotalExperienceYears.
+CREATE FUNCTION resume_experience_years(p_resume_id BIGINT) RETURNS NUMERIC(4, 1)
+LANGUAGE sql STABLE AS $$
+    SELECT LEAST(999.9, ROUND(COALESCE(SUM(island_end - island_start), 0) / 365.25, 1))
+    FROM (
+        SELECT MIN(start_date) AS island_start, MAX(end_date) AS island_end
+        FROM (
+            SELECT start_date, end_date,
+                SUM(starts_island) OVER (ORDER BY start_date, end_date) AS island
+            FROM (
+                SELECT start_date, end_date,
+                    CASE WHEN start_date <= MAX(end_date) OVER (
+                        ORDER BY start_date, end_date ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING
+                    ) THEN 0 ELSE 1 END AS starts_island
+                FROM (
+                    SELECT start_date, COALESCE(end_date, CURRENT_DATE) AS end_date
+                    FROM resume_work_experiences
+                    WHERE resume_id = p_resume_id AND COALESCE(end_date, CURRENT_DATE) > start_date
+                ) periods
+            ) flagged
+        ) grouped
+        GROUP BY island
+    ) islands
+$$;
+
+DROP INDEX IF EXISTS idx_resumes_experience_years;
+ALTER TABLE resumes DROP COLUMN experience_years;
@@ -24,7 +24,7 @@ import {
 } from '@mui/material';
 import { useAuth } from '../contexts/AuthContext';
 import { applications, vacancies } from '../services/api';
-import { Application, Vacancy } from '../types';
+import { Application, ApplicationStatus, Vacancy } from '../types';
 
 const transformApplicationData = (data: any): Application => {
   return {
@@ -60,6 +60,15 @@ const transformVacancyData = (data: any): Vacancy => {
   };
 };
 
+// Переходы, доступные работодателю; совпадают с entity.employerTransitions на бэкенде
+const employerTransitions: Partial<Record<ApplicationStatus, ApplicationStatus[]>> = {
+  pending: ['viewed', 'shortlisted', 'interview', 'rejected'],
+  viewed: ['shortlisted', 'interview', 'rejected'],
+  shortlisted: ['interview', 'offer', 'rejected'],
+  interview: ['shortlisted', 'offer', 'rejected'],
+  offer: ['hired', 'rejected'],
+};
+
 const Applications: React.FC = () => {
   const navigate = useNavigate();
   const { user } = useAuth();
@@ -69,7 +78,7 @@ const Applications: React.FC = () => {
   const [loading, setLoading] = useState(true);
   const [selectedApplication, setSelectedApplication] = useState<Application | null>(null);
   const [statusDialogOpen, setStatusDialogOpen] = useState(false);
-  const [newStatus, setNewStatus] = useState<'pending' | 'accepted' | 'rejected'>('pending');
+  const [newStatus, setNewStatus] = useState<ApplicationStatus>('pending');
   const [updateLoading, setUpdateLoading] = useState(false);
 
   useEffect(() => {
@@ -170,7 +179,7 @@ const Applications: React.FC = () => {
 
   const getStatusColor = (status: string) => {
     switch (status) {
-      case 'accepted':
+      case 'shortlisted':
         return 'success';
       case 'rejected':
         return 'error';
@@ -183,8 +192,18 @@ const Applications: React.FC = () => {
     switch (status) {
       case 'pending':
         return 'На рассмотрении';
-      case 'accepted':
+      case 'viewed':
+        return 'Просмотрено';
+      case 'shortlisted':
         return 'Принято';
+      case 'interview':
+        return 'Собеседование';
+      case 'offer':
+        return 'Предложение';
+      case 'hired':
+        return 'Принят на работу';
+      case 'withdrawn':
+        return 'Отозвано';
       case 'rejected':
         return 'Отклонено';
       default:
@@ -292,11 +311,11 @@ const Applications: React.FC = () => {
             <Select
               value={newStatus}
               label="Статус"
-              onChange={(e) => setNewStatus(e.target.value as 'pending' | 'accepted' | 'rejected')}
+              onChange={(e) => setNewStatus(e.target.value as ApplicationStatus)}
             >
-              <MenuItem value="pending">На рассмотрении</MenuItem>
-              <MenuItem value="accepted">Принято</MenuItem>
-              <MenuItem value="rejected">Отклонено</MenuItem>
+              {selectedApplication && [selectedApplication.status, ...(employerTransitions[selectedApplication.status] ?? [])].map(status => (
+                <MenuItem key={status} value={status}>{getStatusLabel(status)}</MenuItem>
+              ))}
             </Select>
           </FormControl>
         </DialogContent>
@@ -304,7 +323,7 @@ const Applications: React.FC = () => {
           <Button onClick={() => setStatusDialogOpen(false)}>Отмена</Button>
           <Button 
             onClick={handleStatusUpdate} 
-            disabled={updateLoading}
+            disabled={updateLoading || newStatus === selectedApplication?.status}
             variant="contained"
           >
             {updateLoading ? <CircularProgress size={24} /> : 'Обновить'}
@@ -13,6 +13,7 @@ import {
 } from '@mui/material';
 import { useAuth } from '../contexts/AuthContext';
 import api from '../services/api';
+import { toResumePayload } from '../utils/resume';
 
 const CreateResume: React.FC = () => {
   const navigate = useNavigate();
@@ -60,7 +61,7 @@ const CreateResume: React.FC = () => {
 
     try {
       const response = await api.post('/resumes', {
-        ...formData,
+        ...toResumePayload(formData),
         status: 'active'
       });
 
@@ -24,6 +24,7 @@ import {
 } from '@mui/material';
 import { useAuth } from '../contexts/AuthContext';
 import { vacancies, applications as applicationsApi, resumes } from '../services/api';
+import { resumeEducationText, resumeExperienceText } from '../utils/resume';
 import { Application, Vacancy, Resume } from '../types';
 
 interface TabPanelProps {
@@ -59,8 +60,8 @@ const transformResumeData = (data: any): Resume => {
     title: data.title || data.Title,
     description: data.description || data.Description,
     skills: Array.isArray(data.skills || data.Skills) ? data.skills || data.Skills : [],
-    experience: data.experience || data.Experience,
-    education: data.education || data.Education,
+    experience: resumeExperienceText(data),
+    education: resumeEducationText(data),
     status: data.status || data.Status || 'active',
     createdAt: data.created_at || data.createdAt || data.CreatedAt,
     updatedAt: data.updated_at || data.updatedAt || data.UpdatedAt
@@ -167,7 +168,8 @@ const EmployerDashboard: React.FC = () => {
 
       const rawApplications = await applicationsApi.getAll({ 
         employer_id: user?.id,
-        vacancy_ids: vacancyIds.join(',')
+        vacancy_ids: vacancyIds.join(','),
+        limit: 100
       });
       
       console.log('Raw applications response:', rawApplications);
@@ -218,12 +220,23 @@ const EmployerDashboard: React.FC = () => {
     setTabValue(newValue);
   };
 
-  const handleApplicationClick = (application: Application & { resume?: Resume }) => {
+  const handleApplicationClick = async (application: Application & { resume?: Resume }) => {
     setSelectedApplication(application);
     setOpenDialog(true);
+    if (application.status !== 'pending') {
+      return;
+    }
+    // Открытие отклика само по себе его не меняет, просмотр отмечаем явно
+    try {
+      const { status } = await applicationsApi.markViewed(application.id);
+      setApplications(prev => prev.map(app => (app.id === application.id ? { ...app, status } : app)));
+      setSelectedApplication({ ...application, status });
+    } catch (err) {
+      console.error('Error marking application as viewed:', err);
+    }
   };
 
-  const handleStatusChange = async (applicationId: number, newStatus: 'accepted' | 'rejected') => {
+  const handleStatusChange = async (applicationId: number, newStatus: 'shortlisted' | 'rejected') => {
     try {
       await applicationsApi.updateStatus(applicationId, newStatus);
       await fetchApplications();
@@ -237,8 +250,18 @@ const EmployerDashboard: React.FC = () => {
     switch (status) {
       case 'pending':
         return 'На рассмотрении';
-      case 'accepted':
+      case 'viewed':
+        return 'Просмотрено';
+      case 'shortlisted':
         return 'Принято';
+      case 'interview':
+        return 'Собеседование';
+      case 'offer':
+        return 'Предложение';
+      case 'hired':
+        return 'Принят на работу';
+      case 'withdrawn':
+        return 'Отозвано';
       case 'rejected':
         return 'Отклонено';
       default:
@@ -250,7 +273,7 @@ const EmployerDashboard: React.FC = () => {
     switch (status) {
       case 'pending':
         return 'warning';
-      case 'accepted':
+      case 'shortlisted':
         return 'success';
       case 'rejected':
         return 'error';
@@ -348,7 +371,7 @@ const EmployerDashboard: React.FC = () => {
                         <Button
                           size="small"
                           color="success"
-                          onClick={() => handleStatusChange(application.id, 'accepted')}
+                          onClick={() => handleStatusChange(application.id, 'shortlisted')}
                         >
                           Принять
                         </Button>
@@ -481,7 +504,7 @@ const EmployerDashboard: React.FC = () => {
                   <Button
                     color="success"
                     onClick={() => {
-                      handleStatusChange(selectedApplication.id, 'accepted');
+                      handleStatusChange(selectedApplication.id, 'shortlisted');
                       setOpenDialog(false);
                     }}
                   >
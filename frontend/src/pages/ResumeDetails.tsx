@@ -20,6 +20,7 @@ import {
   Edit as EditIcon,
 } from '@mui/icons-material';
 import { resumes } from '../api';
+import { resumeEducationText, resumeExperienceText } from '../utils/resume';
 import { Resume } from '../types';
 
 const ResumeDetails: React.FC = () => {
@@ -36,7 +37,7 @@ const ResumeDetails: React.FC = () => {
       try {
         setLoading(true);
         const data = await resumes.get(parseInt(id));
-        setResume(data);
+        setResume({ ...data, experience: resumeExperienceText(data), education: resumeEducationText(data) });
       } catch (err) {
         console.error('Error loading resume:', err);
         setError('Failed to load resume details');
@@ -139,7 +140,7 @@ const ResumeDetails: React.FC = () => {
               <WorkIcon sx={{ mr: 1 }} />
               Опыт работы
             </Typography>
-            <Typography paragraph>
+            <Typography paragraph sx={{ whiteSpace: 'pre-line' }}>
               {resume.experience}
             </Typography>
           </Box>
@@ -149,7 +150,7 @@ const ResumeDetails: React.FC = () => {
               <SchoolIcon sx={{ mr: 1 }} />
               Образование
             </Typography>
-            <Typography paragraph>
+            <Typography paragraph sx={{ whiteSpace: 'pre-line' }}>
               {resume.education}
             </Typography>
           </Box>
@@ -3,6 +3,7 @@ import { useNavigate, useParams } from 'react-router-dom';
 import { useAuth } from '../contexts/AuthContext';
 import { resumes } from '../api';
 import { Resume } from '../types';
+import { toResumePayload } from '../utils/resume';
 import {
   Container,
   Paper,
@@ -54,8 +55,9 @@ const ResumeEdit: React.FC = () => {
       if (id) {
         try {
           setLoading(true);
-          const data = await resumes.get(parseInt(id));
-          setResume(data);
+          const data: any = await resumes.get(parseInt(id));
+          // В форме правится только текстовая часть, записи опыта и образования редактируются отдельно
+          setResume({ ...data, experience: data.legacy_experience ?? '', education: data.legacy_education ?? '' });
         } catch (err) {
           setError('Failed to load resume');
           console.error('Error loading resume:', err);
@@ -86,9 +88,9 @@ const ResumeEdit: React.FC = () => {
       setError(null);
 
       if (id) {
-        await resumes.update(parseInt(id), resume);
+        await resumes.update(parseInt(id), toResumePayload(resume));
       } else {
-        await resumes.create(resume as Omit<Resume, 'id' | 'user_id' | 'created_at' | 'updated_at'>);
+        await resumes.create(toResumePayload(resume));
       }
 
       navigate('/resumes');
@@ -115,7 +115,7 @@ const ResumeView: React.FC = () => {
           <Typography variant="h6" gutterBottom>
             Experience
           </Typography>
-          <Typography paragraph>
+          <Typography paragraph sx={{ whiteSpace: 'pre-line' }}>
             {resume.experience}
           </Typography>
         </Box>
@@ -124,7 +124,7 @@ const ResumeView: React.FC = () => {
           <Typography variant="h6" gutterBottom>
             Education
           </Typography>
-          <Typography paragraph>
+          <Typography paragraph sx={{ whiteSpace: 'pre-line' }}>
             {resume.education}
           </Typography>
         </Box>
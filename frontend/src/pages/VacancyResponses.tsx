@@ -21,7 +21,7 @@ import {
 } from '@mui/material';
 import { useAuth } from '../contexts/AuthContext';
 import { vacancies, applications as applicationsApi, resumes } from '../services/api';
-import { Application, Vacancy, Resume } from '../types';
+import { Application, ApplicationStatus, Vacancy, Resume } from '../types';
 
 const transformApplicationData = (data: any): Application => {
   return {
@@ -29,7 +29,7 @@ const transformApplicationData = (data: any): Application => {
     vacancy_id: data.VacancyID || data.vacancyId || data.vacancy_id,
     resume_id: data.ResumeID || data.resumeId || data.resume_id,
     user_id: data.UserID || data.userId || data.user_id,
-    status: (data.Status || data.status || 'pending') as 'pending' | 'accepted' | 'rejected',
+    status: (data.Status || data.status || 'pending') as ApplicationStatus,
     created_at: data.CreatedAt || data.createdAt || data.created_at,
     updated_at: data.UpdatedAt || data.updatedAt || data.updated_at,
     applicant_name: data.ApplicantName || data.applicantName || data.applicant_name || '',
@@ -102,12 +102,23 @@ const VacancyResponses: React.FC = () => {
     }
   };
 
-  const handleApplicationClick = (application: Application & { resume?: Resume }) => {
+  const handleApplicationClick = async (application: Application & { resume?: Resume }) => {
     setSelectedApplication(application);
     setOpenDialog(true);
+    if (application.status !== 'pending') {
+      return;
+    }
+    // Открытие отклика само по себе его не меняет, просмотр отмечаем явно
+    try {
+      const { status } = await applicationsApi.markViewed(application.id);
+      setApplications(prev => prev.map(app => (app.id === application.id ? { ...app, status } : app)));
+      setSelectedApplication({ ...application, status });
+    } catch (err) {
+      console.error('Error marking application as viewed:', err);
+    }
   };
 
-  const handleStatusChange = async (applicationId: number, newStatus: 'accepted' | 'rejected') => {
+  const handleStatusChange = async (applicationId: number, newStatus: 'shortlisted' | 'rejected') => {
     try {
       await applicationsApi.updateStatus(applicationId, newStatus);
       await fetchData();
@@ -121,8 +132,18 @@ const VacancyResponses: React.FC = () => {
     switch (status) {
       case 'pending':
         return 'На рассмотрении';
-      case 'accepted':
+      case 'viewed':
+        return 'Просмотрено';
+      case 'shortlisted':
         return 'Принято';
+      case 'interview':
+        return 'Собеседование';
+      case 'offer':
+        return 'Предложение';
+      case 'hired':
+        return 'Принят на работу';
+      case 'withdrawn':
+        return 'Отозвано';
       case 'rejected':
         return 'Отклонено';
       default:
@@ -134,7 +155,7 @@ const VacancyResponses: React.FC = () => {
     switch (status) {
       case 'pending':
         return 'warning';
-      case 'accepted':
+      case 'shortlisted':
         return 'success';
       case 'rejected':
         return 'error';
@@ -224,7 +245,7 @@ const VacancyResponses: React.FC = () => {
                       <Button
                         size="small"
                         color="success"
-                        onClick={() => handleStatusChange(application.id, 'accepted')}
+                        onClick={() => handleStatusChange(application.id, 'shortlisted')}
                       >
                         Принять
                       </Button>
@@ -315,7 +336,7 @@ const VacancyResponses: React.FC = () => {
                   <Button
                     color="success"
                     onClick={() => {
-                      handleStatusChange(selectedApplication.id, 'accepted');
+                      handleStatusChange(selectedApplication.id, 'shortlisted');
                       setOpenDialog(false);
                     }}
                   >
@@ -1,5 +1,6 @@
 import axios from 'axios';
-import { AuthResponse, User, Vacancy, Application } from '../types';
+import { AuthResponse, User, Vacancy, Application, ApplicationStatus } from '../types';
+import { resumeEducationText, resumeExperienceText } from '../utils/resume';
 
 // Use a default URL if environment variable is not set
 const API_URL = 'http://localhost:8080/api/v1';
@@ -181,8 +182,8 @@ const transformResumeData = (data: any) => {
     title: data.Title || data.title,
     description: data.Description || data.description,
     skills: data.Skills || data.skills || [],
-    experience: data.Experience || data.experience,
-    education: data.Education || data.education,
+    experience: resumeExperienceText(data),
+    education: resumeEducationText(data),
     status: data.Status || data.status,
     createdAt: data.CreatedAt || data.createdAt || data.created_at,
     updatedAt: data.UpdatedAt || data.updatedAt || data.updated_at
@@ -283,7 +284,15 @@ export const applications = {
         const ids = params.vacancy_ids.split(',').map((id: string) => id.trim());
         queryParams.vacancy_ids = ids.join(',');
       }
-      
+
+      // Список откликов работодателя постраничный
+      if (params?.limit) {
+        queryParams.limit = params.limit;
+      }
+      if (params?.offset) {
+        queryParams.offset = params.offset;
+      }
+
       console.log('Request params:', queryParams);
       console.log('Request URL:', `${API_URL}/applications`);
       console.log('Full request URL with params:', `${API_URL}/applications?${new URLSearchParams(queryParams).toString()}`);
@@ -402,10 +411,49 @@ export const applications = {
     return transformApplication(response.data);
   },
 
-  updateStatus: async (id: number, status: 'pending' | 'accepted' | 'rejected'): Promise<Application> => {
-    const response = await api.put(`/applications/${id}/status`, { status });
+  markViewed: async (id: number): Promise<{ id: number; status: ApplicationStatus }> => {
+    const response = await api.post(`/applications/${id}/view`);
+    return response.data;
+  },
+
+  updateStatus: async (id: number, status: ApplicationStatus, comment?: string): Promise<Application> => {
+    const response = await api.put(`/applications/${id}/status`, { status, comment });
     return transformApplication(response.data);
   }
 };
 
+// Переписка по отклику между кандидатом и работодателем
+export const messages = {
+  getThreads: async (params?: { limit?: number; offset?: number }) => {
+    const response = await api.get('/applications/threads', { params });
+    return response.data.threads;
+  },
+
+  getUnreadCount: async (): Promise<number> => {
+    const response = await api.get('/applications/threads/unread');
+    return response.data.unread;
+  },
+
+  getAll: async (applicationId: number, before?: number) => {
+    const response = await api.get(`/applications/${applicationId}/messages`, {
+      params: before ? { before } : undefined
+    });
+    return response.data.messages;
+  },
+
+  send: async (applicationId: number, body: string, files?: File[]) => {
+    if (files && files.length > 0) {
+      const form = new FormData();
+      form.append('body', body);
+      files.forEach((file) => form.append('files', file));
+      const response = await api.post(`/applications/${applicationId}/messages`, form, {
+        headers: { 'Content-Type': 'multipart/form-data' }
+      });
+      return response.data;
+    }
+    const response = await api.post(`/applications/${applicationId}/messages`, { body });
+    return response.data;
+  }
+};
+
 export default api;
\ No newline at end of file
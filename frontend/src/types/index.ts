@@ -48,14 +48,36 @@ export interface AuthResponse {
   updatedAt: string;
 }
 
+export type ApplicationStatus =
+  | 'pending'
+  | 'viewed'
+  | 'shortlisted'
+  | 'interview'
+  | 'offer'
+  | 'hired'
+  | 'rejected'
+  | 'withdrawn';
+
+export interface ApplicationStatusChange {
+  id: number;
+  application_id: number;
+  from_status: ApplicationStatus | '';
+  to_status: ApplicationStatus;
+  actor_id?: number;
+  actor_name?: string;
+  comment: string;
+  created_at: string;
+}
+
 export interface Application {
   id: number;
   user_id: number;
   vacancy_id: number;
   resume_id: number;
-  status: 'pending' | 'accepted' | 'rejected';
+  status: ApplicationStatus;
   created_at: string;
   updated_at: string;
   applicant_name?: string;
   applicant_email?: string;
+  timeline?: ApplicationStatusChange[];
 } 
\ No newline at end of file
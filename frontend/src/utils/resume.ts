@@ -0,0 +1,46 @@
+// Бэкенд хранит опыт и образование списками записей, а старый текст
+// резюме лежит в legacy_experience/legacy_education. Страницы пока
+// показывают и редактируют текст, поэтому собираем его здесь.
+
+const yearOf = (date?: string) => (date ? new Date(date).getFullYear() : undefined);
+
+const experienceFromPositions = (positions: any[]): string =>
+  positions
+    .map((p) => {
+      const end = yearOf(p.end_date) ?? 'по настоящее время';
+      const header = [p.title, p.company].filter(Boolean).join(', ');
+      return [`${header} (${yearOf(p.start_date)} — ${end})`, p.description].filter(Boolean).join('\n');
+    })
+    .join('\n\n');
+
+const educationFromEntries = (entries: any[]): string =>
+  entries
+    .map((e) => {
+      const details = [e.degree, e.field_of_study].filter(Boolean).join(', ');
+      const years = `${e.start_year} — ${e.end_year ?? 'по настоящее время'}`;
+      return [e.institution, details, `(${years})`].filter(Boolean).join(' ');
+    })
+    .join('\n');
+
+export const resumeExperienceText = (data: any): string => {
+  if (Array.isArray(data?.work_experience) && data.work_experience.length > 0) {
+    return [data.legacy_experience, experienceFromPositions(data.work_experience)].filter(Boolean).join('\n\n');
+  }
+  const text = data?.legacy_experience ?? data?.experience ?? data?.Experience;
+  return typeof text === 'string' ? text : '';
+};
+
+export const resumeEducationText = (data: any): string => {
+  if (Array.isArray(data?.education) && data.education.length > 0) {
+    return [data.legacy_education, educationFromEntries(data.education)].filter(Boolean).join('\n');
+  }
+  const text = data?.legacy_education ?? data?.education ?? data?.Education;
+  return typeof text === 'string' ? text : '';
+};
+
+// Текстовые поля формы уходят на бэкенд в legacy_experience/legacy_education.
+export const toResumePayload = ({ experience, education, ...rest }: any) => ({
+  ...rest,
+  legacy_experience: experience ?? '',
+  legacy_education: education ?? '',
+});
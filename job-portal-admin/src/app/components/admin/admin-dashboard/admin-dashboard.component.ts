@@ -504,8 +504,9 @@ export class AdminDashboardComponent implements OnInit {
         description: resume.Description || resume.description,
         userId: resume.UserID || resume.userId,
         skills: resume.Skills || resume.skills || [],
-        education: resume.Education || resume.education,
-        experience: resume.Experience || resume.experience,
+        // Структурированные записи приходят отдельными списками, здесь нужен только текст
+        education: resume.legacy_education ?? resume.Education ?? '',
+        experience: resume.legacy_experience ?? resume.Experience ?? '',
         createdAt: resume.CreatedAt || resume.createdAt,
         updatedAt: resume.UpdatedAt || resume.updatedAt
       }));
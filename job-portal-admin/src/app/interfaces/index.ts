@@ -68,6 +68,8 @@ export interface Resume {
   Experience?: string;
   CreatedAt?: string;
   UpdatedAt?: string;
+  legacy_education?: string;
+  legacy_experience?: string;
 }
 
 export interface UserStats {